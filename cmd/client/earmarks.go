@@ -37,6 +37,7 @@ const earmarkDetailTpl = `
 type EarmarksCreateCmd struct {
 	EventItemRefID string `name:"event-item-ref-id" arg:"" required:"" help:"event item ref-id"`
 	Note           string `name:"note" required:"" help:"earmark note"`
+	IdempotencyKey string `name:"idempotency-key" help:"key to make retries of this request safe"`
 }
 
 func (cmd *EarmarksCreateCmd) Run(meta *RunArgs) error {
@@ -45,7 +46,7 @@ func (cmd *EarmarksCreateCmd) Run(meta *RunArgs) error {
 		EventItemRefId: cmd.EventItemRefID,
 		Note:           cmd.Note,
 	}.Build()
-	resp, err := client.EarmarkCreate(meta.ctx, connect.NewRequest(req))
+	resp, err := client.EarmarkCreate(meta.ctx, idempotentRequest(req, cmd.IdempotencyKey))
 	if err != nil {
 		return fmt.Errorf("client request: %w", err)
 	}
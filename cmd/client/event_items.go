@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"html/template"
 	"os"
+	"slices"
 
 	"connectrpc.com/connect"
 	"github.com/Masterminds/sprig/v3"
@@ -20,11 +21,13 @@ const eventItemTpl = `
 - event_item_ref_id: {{.GetRefId}}
   description: {{.GetDescription}}
   created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
+  version: {{.GetVersion}}
 `
 
 type EventItemsAddCmd struct {
-	EventRefId  string `name:"event-ref-id" arg:"" required:"" help:"event ref-id"`
-	Description string `name:"description" required:"" help:"event item description"`
+	EventRefId     string `name:"event-ref-id" arg:"" required:"" help:"event ref-id"`
+	Description    string `name:"description" required:"" help:"event item description"`
+	IdempotencyKey string `name:"idempotency-key" help:"key to make retries of this request safe"`
 }
 
 func (cmd *EventItemsAddCmd) Run(meta *RunArgs) error {
@@ -33,7 +36,7 @@ func (cmd *EventItemsAddCmd) Run(meta *RunArgs) error {
 		EventRefId:  cmd.EventRefId,
 		Description: cmd.Description,
 	}.Build()
-	resp, err := client.EventAddItem(meta.ctx, connect.NewRequest(req))
+	resp, err := client.EventAddItem(meta.ctx, idempotentRequest(req, cmd.IdempotencyKey))
 	if err != nil {
 		return fmt.Errorf("client request: %w", err)
 	}
@@ -48,8 +51,9 @@ func (cmd *EventItemsAddCmd) Run(meta *RunArgs) error {
 }
 
 type EventItemsUpdateCmd struct {
-	RefId       string `name:"ref-id" arg:"" required:"" help:"event-item ref-id"`
-	Description string `name:"description" required:"" help:"event item description"`
+	RefId       string  `name:"ref-id" arg:"" required:"" help:"event-item ref-id"`
+	Description string  `name:"description" required:"" help:"event item description"`
+	Version     *string `name:"version" help:"fail if the event item version has changed"`
 }
 
 func (cmd *EventItemsUpdateCmd) Run(meta *RunArgs) error {
@@ -58,6 +62,9 @@ func (cmd *EventItemsUpdateCmd) Run(meta *RunArgs) error {
 		RefId:       cmd.RefId,
 		Description: cmd.Description,
 	}.Build()
+	if cmd.Version != nil {
+		req.SetVersion(*cmd.Version)
+	}
 
 	resp, err := client.EventUpdateItem(meta.ctx, connect.NewRequest(req))
 	if err != nil {
@@ -87,3 +94,71 @@ func (cmd *EventItemsRemoveCmd) Run(meta *RunArgs) error {
 	}
 	return nil
 }
+
+type EventItemsMoveCmd struct {
+	EventRefId string `name:"event-ref-id" arg:"" required:"" help:"event ref-id"`
+	RefId      string `name:"ref-id" arg:"" required:"" help:"event-item ref-id to move"`
+	Before     string `name:"before" xor:"position" required:"" help:"place item before this event-item ref-id"`
+	After      string `name:"after" xor:"position" required:"" help:"place item after this event-item ref-id"`
+}
+
+func (cmd *EventItemsMoveCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	listReq := icbt.EventListItemsRequest_builder{
+		RefId: cmd.EventRefId,
+	}.Build()
+	listResp, err := client.EventListItems(meta.ctx, connect.NewRequest(listReq))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	target := cmd.Before
+	if cmd.After != "" {
+		target = cmd.After
+	}
+	if target == cmd.RefId {
+		return fmt.Errorf("cannot move an item relative to itself")
+	}
+
+	// current order, without the item being moved
+	order := make([]string, 0, len(listResp.Msg.GetItems()))
+	found := false
+	for _, item := range listResp.Msg.GetItems() {
+		if item.GetRefId() == cmd.RefId {
+			found = true
+			continue
+		}
+		order = append(order, item.GetRefId())
+	}
+	if !found {
+		return fmt.Errorf("event item not found: %s", cmd.RefId)
+	}
+
+	idx := slices.Index(order, target)
+	if idx < 0 {
+		return fmt.Errorf("event item not found: %s", target)
+	}
+	if cmd.After != "" {
+		idx++
+	}
+	order = slices.Insert(order, idx, cmd.RefId)
+
+	req := icbt.EventUpdateItemSortingRequest_builder{
+		EventRefId: cmd.EventRefId,
+		ItemRefIds: order,
+	}.Build()
+	resp, err := client.EventUpdateItemSorting(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("eventItemTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(eventItemTpl))
+	for _, item := range resp.Msg.GetItems() {
+		if err := t.Execute(os.Stdout, item); err != nil {
+			return fmt.Errorf("executing template: %w", err)
+		}
+	}
+	return nil
+}
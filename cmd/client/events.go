@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"os"
@@ -27,6 +28,7 @@ const eventTpl = `
   when: {{.GetWhen.GetTs.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
   tz: {{.GetWhen.GetTz}}
   created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
+  version: {{.GetVersion}}
 `
 
 type EventsListCmd struct {
@@ -55,10 +57,11 @@ func (cmd *EventsListCmd) Run(meta *RunArgs) error {
 }
 
 type EventsCreateCmd struct {
-	Name        string    `name:"name" required:"" help:"event name"`
-	Description string    `name:"description" required:"" help:"event description"`
-	When        time.Time `name:"when" required:"" help:"event start time"`
-	Tz          string    `name:"tz" required:"" help:"event timezone"`
+	Name           string    `name:"name" required:"" help:"event name"`
+	Description    string    `name:"description" required:"" help:"event description"`
+	When           time.Time `name:"when" required:"" help:"event start time"`
+	Tz             string    `name:"tz" required:"" help:"event timezone"`
+	IdempotencyKey string    `name:"idempotency-key" help:"key to make retries of this request safe"`
 }
 
 func (cmd *EventsCreateCmd) Run(meta *RunArgs) error {
@@ -71,7 +74,7 @@ func (cmd *EventsCreateCmd) Run(meta *RunArgs) error {
 			Tz: cmd.Tz,
 		}.Build(),
 	}.Build()
-	resp, err := client.EventCreate(meta.ctx, connect.NewRequest(req))
+	resp, err := client.EventCreate(meta.ctx, idempotentRequest(req, cmd.IdempotencyKey))
 	if err != nil {
 		return fmt.Errorf("client request: %w", err)
 	}
@@ -90,6 +93,7 @@ type EventsUpdateCmd struct {
 	Description *string    `name:"description" help:"event description"`
 	When        *time.Time `name:"when" help:"event start time"`
 	Tz          *string    `name:"tz" help:"event timezone"`
+	Version     *string    `name:"version" help:"fail if the event version has changed"`
 	RefID       string     `name:"ref-id" arg:"" required:""`
 }
 
@@ -119,6 +123,9 @@ func (cmd *EventsUpdateCmd) Run(meta *RunArgs) error {
 	if cmd.Name == nil && cmd.Description == nil && cmd.When == nil {
 		return fmt.Errorf("at least one field must be included to update anything")
 	}
+	if cmd.Version != nil {
+		req.SetVersion(*cmd.Version)
+	}
 
 	if _, err := client.EventUpdate(meta.ctx, connect.NewRequest(req)); err != nil {
 		return fmt.Errorf("client request: %w", err)
@@ -254,3 +261,42 @@ func (cmd *EventsListEarmarksCmd) Run(meta *RunArgs) error {
 	}
 	return nil
 }
+
+const eventChangeTpl = `
+{{- /* whitespace fix */ -}}
+- kind: {{.GetKind}}
+  {{- with .GetRefId}}
+  ref_id: {{.}}
+  {{- end}}
+  {{- with .GetCursor}}
+  cursor: {{.}}
+  {{- end}}
+`
+
+type EventsWatchCmd struct {
+	RefID  string `name:"ref-id" arg:"" required:""`
+	Cursor string `name:"cursor" help:"resume after this cursor"`
+}
+
+func (cmd *EventsWatchCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	t := util.Must(template.New("eventChangeTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(eventChangeTpl))
+
+	return watchStream(meta.ctx, cmd.Cursor,
+		func(ctx context.Context, cursor string) (*connect.ServerStreamForClient[icbt.WatchEventResponse], error) {
+			req := icbt.WatchEventRequest_builder{
+				RefId:  cmd.RefID,
+				Cursor: cursor,
+			}.Build()
+			return client.WatchEvent(ctx, connect.NewRequest(req))
+		},
+		func(msg *icbt.WatchEventResponse) (string, error) {
+			if err := t.Execute(os.Stdout, msg); err != nil {
+				return "", fmt.Errorf("executing template: %w", err)
+			}
+			return msg.GetCursor(), nil
+		},
+	)
+}
@@ -48,7 +48,8 @@ func (cmd *FavoritesListCmd) Run(meta *RunArgs) error {
 }
 
 type FavoritesAddCmd struct {
-	EventRefID string `name:"event-ref-id" arg:"" required:""`
+	EventRefID     string `name:"event-ref-id" arg:"" required:""`
+	IdempotencyKey string `name:"idempotency-key" help:"key to make retries of this request safe"`
 }
 
 func (cmd *FavoritesAddCmd) Run(meta *RunArgs) error {
@@ -56,7 +57,7 @@ func (cmd *FavoritesAddCmd) Run(meta *RunArgs) error {
 	req := icbt.FavoriteAddRequest_builder{
 		EventRefId: cmd.EventRefID,
 	}.Build()
-	resp, err := client.FavoriteAdd(meta.ctx, connect.NewRequest(req))
+	resp, err := client.FavoriteAdd(meta.ctx, idempotentRequest(req, cmd.IdempotencyKey))
 	if err != nil {
 		return fmt.Errorf("client request: %w", err)
 	}
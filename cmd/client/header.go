@@ -35,3 +35,13 @@ func NewAddHeadersInterceptor(headers http.Header) connect.UnaryInterceptorFunc
 	}
 	return connect.UnaryInterceptorFunc(interceptor)
 }
+
+// idempotentRequest wraps msg in a request, setting the idempotency key
+// header if key is not empty.
+func idempotentRequest[T any](msg *T, key string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	if key != "" {
+		req.Header().Set("Idempotency-Key", key)
+	}
+	return req
+}
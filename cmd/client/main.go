@@ -51,12 +51,14 @@ type CLI struct { // betteralign:ignore
 		Detail         EventsGetDetailsCmd   `cmd:"" aliases:"info,details" help:"get event details"`
 		ListEventItems EventsListItemsCmd    `cmd:"" aliases:"items,ls-items" help:"list event items"`
 		ListEarmarks   EventsListEarmarksCmd `cmd:"" aliases:"earmarks,ls-earmarks" help:"list event earmarks"`
+		Watch          EventsWatchCmd        `cmd:"" help:"watch event for changes"`
 	} `cmd:"" help:"events"`
 
 	EventItems struct { // betteralign:ignore
 		Add    EventItemsAddCmd    `cmd:"" help:"add item to event"`
 		Update EventItemsUpdateCmd `cmd:"" help:"update event item"`
 		Remove EventItemsRemoveCmd `cmd:"" aliases:"rm" help:"remove event item"`
+		Move   EventItemsMoveCmd   `cmd:"" help:"move event item before or after another item"`
 	} `cmd:"" help:"event-items"`
 
 	Earmarks struct { // betteralign:ignore
@@ -73,10 +75,32 @@ type CLI struct { // betteralign:ignore
 	} `cmd:"" help:"favorites"`
 
 	Notifications struct { // betteralign:ignore
-		Delete    NotificationsDeleteCmd    `cmd:"" aliases:"rm" help:"Delete a single notification."`
-		DeleteAll NotificationsDeleteAllCmd `cmd:"" aliases:"clear" help:"Delete all notifications."`
-		List      NotificationsListCmd      `cmd:"" aliases:"ls" help:"List notifications."`
+		Delete      NotificationsDeleteCmd      `cmd:"" aliases:"rm" help:"Delete a single notification."`
+		DeleteAll   NotificationsDeleteAllCmd   `cmd:"" aliases:"clear" help:"Delete all notifications."`
+		List        NotificationsListCmd        `cmd:"" aliases:"ls" help:"List notifications."`
+		MarkRead    NotificationsMarkReadCmd    `cmd:"" help:"Mark a single notification as read."`
+		MarkUnread  NotificationsMarkUnreadCmd  `cmd:"" help:"Mark a single notification as unread."`
+		MarkAllRead NotificationsMarkAllReadCmd `cmd:"" help:"Mark all notifications as read."`
+		Watch       NotificationsWatchCmd       `cmd:"" help:"Watch for notification changes."`
 	} `cmd:"" help:"notifications"`
+
+	User struct { // betteralign:ignore
+		Me               UserGetMeCmd            `cmd:"" aliases:"info" help:"show current user"`
+		Update           UserUpdateCmd           `cmd:"" help:"update name, email or password"`
+		Settings         UserSettingsGetCmd      `cmd:"" help:"show user settings"`
+		SettingsUpdate   UserSettingsUpdateCmd   `cmd:"" help:"update user settings"`
+		Credentials      UserCredentialsListCmd  `cmd:"" aliases:"passkeys" help:"list passkeys"`
+		DeleteCredential UserCredentialDeleteCmd `cmd:"" aliases:"rm-credential" help:"delete passkey"`
+		Export           UserExportCmd           `cmd:"" help:"request an export of all account data"`
+	} `cmd:"" help:"user account"`
+
+	Webhooks struct { // betteralign:ignore
+		Create   WebhooksCreateCmd       `cmd:"" aliases:"add" help:"create webhook"`
+		Update   WebhooksUpdateCmd       `cmd:"" help:"update webhook"`
+		Delete   WebhooksDeleteCmd       `cmd:"" aliases:"rm" help:"delete webhook"`
+		List     WebhooksListCmd         `cmd:"" aliases:"ls" help:"list webhooks"`
+		Attempts WebhooksListAttemptsCmd `cmd:"" help:"list recent delivery attempts"`
+	} `cmd:"" help:"webhooks"`
 }
 
 func main() {
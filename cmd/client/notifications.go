@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"os"
@@ -18,15 +19,30 @@ import (
 const notifTpl = `
 {{- /* whitespace fix */ -}}
 - ref_id: {{.GetRefId}}
+  kind: {{.GetKind}}
   message: {{.GetMessage}}
+  read: {{.GetRead}}
+  {{- with .GetEventRefId}}
+  event_ref_id: {{.}}
+  {{- end}}
+  {{- with .GetEventItemRefId}}
+  event_item_ref_id: {{.}}
+  {{- end}}
+  {{- with .GetEarmarkRefId}}
+  earmark_ref_id: {{.}}
+  {{- end}}
   created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00" }}
 `
 
-type NotificationsListCmd struct{}
+type NotificationsListCmd struct {
+	Unread bool `name:"unread" help:"show only unread notifications"`
+}
 
 func (cmd *NotificationsListCmd) Run(meta *RunArgs) error {
 	client := meta.client
-	req := icbt.NotificationsListRequest_builder{}.Build()
+	req := icbt.NotificationsListRequest_builder{
+		UnreadOnly: cmd.Unread,
+	}.Build()
 	resp, err := client.NotificationsList(meta.ctx, connect.NewRequest(req))
 	if err != nil {
 		return fmt.Errorf("client request: %w", err)
@@ -69,3 +85,81 @@ func (cmd *NotificationsDeleteAllCmd) Run(meta *RunArgs) error {
 	}
 	return nil
 }
+
+type NotificationsMarkReadCmd struct {
+	RefID string `name:"ref-id" arg:"" required:""`
+}
+
+func (cmd *NotificationsMarkReadCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.NotificationMarkReadRequest_builder{
+		RefId: cmd.RefID,
+	}.Build()
+	if _, err := client.NotificationMarkRead(meta.ctx, connect.NewRequest(req)); err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	return nil
+}
+
+type NotificationsMarkUnreadCmd struct {
+	RefID string `name:"ref-id" arg:"" required:""`
+}
+
+func (cmd *NotificationsMarkUnreadCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.NotificationMarkUnreadRequest_builder{
+		RefId: cmd.RefID,
+	}.Build()
+	if _, err := client.NotificationMarkUnread(meta.ctx, connect.NewRequest(req)); err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	return nil
+}
+
+type NotificationsMarkAllReadCmd struct{}
+
+func (cmd *NotificationsMarkAllReadCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.NotificationsMarkAllReadRequest_builder{}.Build()
+	if _, err := client.NotificationsMarkAllRead(meta.ctx, connect.NewRequest(req)); err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	return nil
+}
+
+const notifChangeTpl = `
+{{- /* whitespace fix */ -}}
+- kind: {{.GetKind}}
+  {{- with .GetRefId}}
+  ref_id: {{.}}
+  {{- end}}
+  {{- with .GetCursor}}
+  cursor: {{.}}
+  {{- end}}
+`
+
+type NotificationsWatchCmd struct {
+	Cursor string `name:"cursor" help:"resume after this cursor"`
+}
+
+func (cmd *NotificationsWatchCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	t := util.Must(template.New("notifChangeTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(notifChangeTpl))
+
+	return watchStream(meta.ctx, cmd.Cursor,
+		func(ctx context.Context, cursor string) (*connect.ServerStreamForClient[icbt.WatchNotificationsResponse], error) {
+			req := icbt.WatchNotificationsRequest_builder{
+				Cursor: cursor,
+			}.Build()
+			return client.WatchNotifications(ctx, connect.NewRequest(req))
+		},
+		func(msg *icbt.WatchNotificationsResponse) (string, error) {
+			if err := t.Execute(os.Stdout, msg); err != nil {
+				return "", fmt.Errorf("executing template: %w", err)
+			}
+			return msg.GetCursor(), nil
+		},
+	)
+}
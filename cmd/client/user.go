@@ -0,0 +1,246 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+const userTpl = `
+{{- /* whitespace fix */ -}}
+ref_id: {{.GetRefId}}
+name: {{.GetName}}
+email: {{.GetEmail}}
+verified: {{.GetVerified}}
+pw_auth: {{.GetPwAuth}}
+webauthn: {{.GetWebauthn}}
+api_access: {{.GetApiAccess}}
+created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
+`
+
+const userSettingsTpl = `
+{{- /* whitespace fix */ -}}
+enable_reminders: {{.GetEnableReminders}}
+reminder_threshold_hours: {{.GetReminderThresholdHours}}
+nudge_guests: {{.GetNudgeGuests}}
+timezone: {{or .GetTimezone "UTC"}}
+quiet_hours_enabled: {{.GetQuietHoursEnabled}}
+quiet_hours_start: {{.GetQuietHoursStart}}
+quiet_hours_end: {{.GetQuietHoursEnd}}
+digest_frequency: {{or .GetDigestFrequency "none"}}
+`
+
+const userCredentialTpl = `
+{{- /* whitespace fix */ -}}
+- ref_id: {{.GetRefId}}
+  key_name: {{.GetKeyName}}
+  created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
+`
+
+type UserGetMeCmd struct{}
+
+func (cmd *UserGetMeCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.UserGetMeRequest{}
+	resp, err := client.UserGetMe(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("userTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(userTpl, "\n")))
+	if err := t.Execute(os.Stdout, resp.Msg.GetUser()); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+type UserUpdateCmd struct {
+	Name        *string `name:"name" help:"display name"`
+	Email       *string `name:"email" help:"new email address (changed once confirmed from the new address)"`
+	Password    *string `name:"password" env:"NEW_PASSWORD" help:"new password"`
+	OldPassword *string `name:"old-password" env:"OLD_PASSWORD" help:"current password, required to change password"`
+}
+
+func (cmd *UserUpdateCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.UserUpdateRequest{}
+	if cmd.Name != nil {
+		req.SetName(*cmd.Name)
+	}
+	if cmd.Email != nil {
+		req.SetEmail(*cmd.Email)
+	}
+	if cmd.Password != nil {
+		if cmd.OldPassword == nil {
+			return fmt.Errorf("old-password is required to change password")
+		}
+		req.SetPassword(*cmd.Password)
+		req.SetOldPassword(*cmd.OldPassword)
+	}
+	if !req.HasName() && !req.HasEmail() && !req.HasPassword() {
+		return fmt.Errorf("at least one field must be included to update anything")
+	}
+
+	resp, err := client.UserUpdate(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("userTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(userTpl, "\n")))
+	if err := t.Execute(os.Stdout, resp.Msg.GetUser()); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+type UserSettingsGetCmd struct{}
+
+func (cmd *UserSettingsGetCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.UserSettingsGetRequest{}
+	resp, err := client.UserSettingsGet(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("userSettingsTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(userSettingsTpl, "\n")))
+	if err := t.Execute(os.Stdout, resp.Msg.GetSettings()); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+type UserSettingsUpdateCmd struct {
+	EnableReminders   *bool   `name:"reminders" negatable:"" help:"enable or disable reminder emails"`
+	ReminderThreshold *uint32 `name:"reminder-threshold" help:"hours before an event to send reminders"`
+	NudgeGuests       *bool   `name:"nudge-guests" negatable:"" help:"include unclaimed items in guest reminders"`
+	Timezone          *string `name:"timezone" help:"iana timezone name"`
+	QuietHours        *bool   `name:"quiet-hours" negatable:"" help:"enable or disable quiet hours"`
+	QuietHoursStart   *uint32 `name:"quiet-hours-start" help:"hour of the day quiet hours start"`
+	QuietHoursEnd     *uint32 `name:"quiet-hours-end" help:"hour of the day quiet hours end"`
+	DigestFrequency   *string `name:"digest" enum:"none,daily,weekly" help:"activity digest frequency"`
+}
+
+func (cmd *UserSettingsUpdateCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.UserSettingsUpdateRequest{}
+	changes := false
+	if cmd.EnableReminders != nil {
+		changes = true
+		req.SetEnableReminders(*cmd.EnableReminders)
+	}
+	if cmd.ReminderThreshold != nil {
+		changes = true
+		req.SetReminderThresholdHours(*cmd.ReminderThreshold)
+	}
+	if cmd.NudgeGuests != nil {
+		changes = true
+		req.SetNudgeGuests(*cmd.NudgeGuests)
+	}
+	if cmd.Timezone != nil {
+		changes = true
+		req.SetTimezone(*cmd.Timezone)
+	}
+	if cmd.QuietHours != nil {
+		changes = true
+		req.SetQuietHoursEnabled(*cmd.QuietHours)
+	}
+	if cmd.QuietHoursStart != nil {
+		changes = true
+		req.SetQuietHoursStart(*cmd.QuietHoursStart)
+	}
+	if cmd.QuietHoursEnd != nil {
+		changes = true
+		req.SetQuietHoursEnd(*cmd.QuietHoursEnd)
+	}
+	if cmd.DigestFrequency != nil {
+		changes = true
+		digest := *cmd.DigestFrequency
+		if digest == "none" {
+			digest = ""
+		}
+		req.SetDigestFrequency(digest)
+	}
+	if !changes {
+		return fmt.Errorf("at least one field must be included to update anything")
+	}
+
+	resp, err := client.UserSettingsUpdate(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("userSettingsTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(userSettingsTpl, "\n")))
+	if err := t.Execute(os.Stdout, resp.Msg.GetSettings()); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+type UserCredentialsListCmd struct{}
+
+func (cmd *UserCredentialsListCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.UserCredentialsListRequest{}
+	resp, err := client.UserCredentialsList(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("userCredentialTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(userCredentialTpl, "\n")))
+	for _, credential := range resp.Msg.GetCredentials() {
+		if err := t.Execute(os.Stdout, credential); err != nil {
+			return fmt.Errorf("executing template: %w", err)
+		}
+	}
+	return nil
+}
+
+type UserCredentialDeleteCmd struct {
+	RefID string `name:"ref-id" arg:"" required:""`
+}
+
+func (cmd *UserCredentialDeleteCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.UserCredentialDeleteRequest_builder{
+		RefId: cmd.RefID,
+	}.Build()
+	if _, err := client.UserCredentialDelete(meta.ctx, connect.NewRequest(req)); err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	return nil
+}
+
+type UserExportCmd struct{}
+
+func (cmd *UserExportCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.AccountExportRequest{}
+	resp, err := client.AccountExport(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	fmt.Printf("export %s requested. a download link will be emailed once it is ready.\n",
+		resp.Msg.GetRefId())
+	return nil
+}
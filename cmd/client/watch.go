@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// how long to wait before reconnecting a dropped watch stream
+const watchRetryDelay = 2 * time.Second
+
+// watchStream opens a stream with open, and calls handle for each message
+// received. handle returns the cursor of the message, which is used to
+// resume if the stream has to be reopened. Runs until interrupted, or
+// the server rejects the request outright.
+func watchStream[T any](ctx context.Context, cursor string,
+	open func(context.Context, string) (*connect.ServerStreamForClient[T], error),
+	handle func(*T) (string, error),
+) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	for {
+		stream, err := open(ctx, cursor)
+		if err == nil {
+			for stream.Receive() {
+				next, err := handle(stream.Msg())
+				if err != nil {
+					_ = stream.Close()
+					return err
+				}
+				if next != "" {
+					cursor = next
+				}
+			}
+			err = stream.Err()
+			_ = stream.Close()
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		switch connect.CodeOf(err) {
+		case connect.CodeInvalidArgument, connect.CodeNotFound,
+			connect.CodePermissionDenied, connect.CodeUnauthenticated:
+			return fmt.Errorf("client request: %w", err)
+		}
+
+		slog.Info("watch interrupted, reconnecting",
+			"cursor", cursor, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
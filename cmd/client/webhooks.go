@@ -0,0 +1,168 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+const webhookTpl = `
+{{- /* whitespace fix */ -}}
+- ref_id: {{.GetRefId}}
+  url: {{.GetUrl}}
+  event_types: {{if .GetEventTypes}}{{join ", " .GetEventTypes}}{{else}}all{{end}}
+  enabled: {{.GetEnabled}}
+  created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
+`
+
+const webhookAttemptTpl = `
+{{- /* whitespace fix */ -}}
+- event_type: {{.GetEventType}}
+  status_code: {{.GetStatusCode}}
+  {{- if .GetError}}
+  error: {{.GetError}}
+  {{- end}}
+  created: {{.GetCreated.AsTime.Format "2006-01-02T15:04:05Z07:00"}}
+`
+
+type WebhooksListCmd struct{}
+
+func (cmd *WebhooksListCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := &icbt.WebhooksListRequest{}
+	resp, err := client.WebhooksList(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("webhookTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(webhookTpl, "\n")))
+	for _, webhook := range resp.Msg.GetWebhooks() {
+		if err := t.Execute(os.Stdout, webhook); err != nil {
+			return fmt.Errorf("executing template: %w", err)
+		}
+	}
+	return nil
+}
+
+type WebhooksCreateCmd struct {
+	Secret     string   `name:"secret" help:"signing secret (generated if omitted)"`
+	URL        string   `name:"url" arg:"" required:""`
+	EventTypes []string `name:"event-type" help:"change types to send (all if omitted)"`
+}
+
+func (cmd *WebhooksCreateCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.WebhookCreateRequest_builder{
+		Url:        cmd.URL,
+		Secret:     cmd.Secret,
+		EventTypes: cmd.EventTypes,
+	}.Build()
+	resp, err := client.WebhookCreate(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("webhookTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(webhookTpl, "\n")))
+	if err := t.Execute(os.Stdout, resp.Msg.GetWebhook()); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	fmt.Printf("  secret: %s\n", resp.Msg.GetSecret())
+	return nil
+}
+
+type WebhooksUpdateCmd struct {
+	URL        *string  `name:"url" help:"webhook url"`
+	Secret     *string  `name:"secret" help:"signing secret"`
+	Enabled    *bool    `name:"enabled" negatable:"" help:"enable or disable deliveries"`
+	RefID      string   `name:"ref-id" arg:"" required:""`
+	EventTypes []string `name:"event-type" help:"change types to send"`
+	AllEvents  bool     `name:"all-events" help:"send all change types"`
+}
+
+func (cmd *WebhooksUpdateCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.WebhookUpdateRequest_builder{
+		RefId: cmd.RefID,
+	}.Build()
+	if cmd.URL != nil {
+		req.SetUrl(*cmd.URL)
+	}
+	if cmd.Secret != nil {
+		req.SetSecret(*cmd.Secret)
+	}
+	if cmd.Enabled != nil {
+		req.SetEnabled(*cmd.Enabled)
+	}
+	if len(cmd.EventTypes) > 0 && cmd.AllEvents {
+		return fmt.Errorf("event-type and all-events are mutually exclusive")
+	}
+	if len(cmd.EventTypes) > 0 || cmd.AllEvents {
+		req.SetEventTypes(icbt.WebhookEventTypeList_builder{
+			EventTypes: cmd.EventTypes,
+		}.Build())
+	}
+	if !req.HasUrl() && !req.HasSecret() && !req.HasEnabled() && !req.HasEventTypes() {
+		return fmt.Errorf("at least one field must be included to update anything")
+	}
+
+	if _, err := client.WebhookUpdate(meta.ctx, connect.NewRequest(req)); err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	return nil
+}
+
+type WebhooksDeleteCmd struct {
+	RefID string `name:"ref-id" arg:"" required:""`
+}
+
+func (cmd *WebhooksDeleteCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.WebhookDeleteRequest_builder{
+		RefId: cmd.RefID,
+	}.Build()
+	if _, err := client.WebhookDelete(meta.ctx, connect.NewRequest(req)); err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+	return nil
+}
+
+type WebhooksListAttemptsCmd struct {
+	RefID string `name:"ref-id" arg:"" required:""`
+	Limit uint32 `name:"limit" default:"20" help:"max attempts to show"`
+}
+
+func (cmd *WebhooksListAttemptsCmd) Run(meta *RunArgs) error {
+	client := meta.client
+	req := icbt.WebhookListAttemptsRequest_builder{
+		RefId: cmd.RefID,
+		Limit: cmd.Limit,
+	}.Build()
+	resp, err := client.WebhookListAttempts(meta.ctx, connect.NewRequest(req))
+	if err != nil {
+		return fmt.Errorf("client request: %w", err)
+	}
+
+	t := util.Must(template.New("webhookAttemptTpl").
+		Funcs(sprig.FuncMap()).
+		Parse(strings.TrimLeft(webhookAttemptTpl, "\n")))
+	for _, attempt := range resp.Msg.GetAttempts() {
+		if err := t.Execute(os.Stdout, attempt); err != nil {
+			return fmt.Errorf("executing template: %w", err)
+		}
+	}
+	return nil
+}
@@ -37,8 +37,18 @@ func (jl *JobList) AddByName(jobnames ...string) error {
 			jl.Add(NotifierJob)
 		case "archiver":
 			jl.Add(ArchiverJob)
+		case "digest":
+			jl.Add(DigestJob)
+		case "webhooks":
+			jl.Add(WebhookJob)
+		case "cleanup":
+			jl.Add(CleanupJob)
+		case "exports":
+			jl.Add(ExportJob)
+		case "deletions":
+			jl.Add(DeletionJob)
 		case "all":
-			jl.Add(NotifierJob, ArchiverJob)
+			jl.Add(NotifierJob, ArchiverJob, DigestJob, WebhookJob, CleanupJob, ExportJob, DeletionJob)
 		default:
 			return fmt.Errorf("unknown job: %s", v)
 		}
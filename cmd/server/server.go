@@ -24,6 +24,8 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/envconfig"
 	"github.com/dropwhile/icanbringthat/internal/logger"
 	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+	"github.com/dropwhile/icanbringthat/internal/session"
 	"github.com/dropwhile/icanbringthat/internal/util"
 )
 
@@ -90,15 +92,18 @@ func (c *ServerCmd) Run() error {
 	}
 	defer db.Close()
 
-	redisOpt, err := redis.ParseURL(config.RedisDSN)
-	if err != nil {
-		slog.With("error", err).
-			Error("failed to connect to redis")
-		return fmt.Errorf("failed to connect to redis")
-	}
+	var rdb *redis.Client
+	if config.RedisDSN != "" {
+		redisOpt, err := redis.ParseURL(config.RedisDSN)
+		if err != nil {
+			slog.With("error", err).
+				Error("failed to connect to redis")
+			return fmt.Errorf("failed to connect to redis")
+		}
 
-	rdb := redis.NewClient(redisOpt)
-	defer rdb.Close()
+		rdb = redis.NewClient(redisOpt)
+		defer rdb.Close()
+	}
 
 	// configure mailer
 	mailConfig := &mail.Config{
@@ -119,6 +124,21 @@ func (c *ServerCmd) Run() error {
 		BaseURL:        config.BaseURL,
 		RequestLogging: config.LogTrace,
 		RpcApi:         config.RpcApi,
+		RateLimit:      config.RateLimit,
+		SessionBackend: config.SessionBackend,
+		Session: session.Config{
+			Lifetime:    config.SessionLifetime,
+			IdleTimeout: config.SessionIdleTimeout,
+			RememberMe:  config.SessionRememberMe,
+		},
+	}
+	if config.OIDCIssuer != "" {
+		appConfig.OIDC = &oidc.Config{
+			Name:         config.OIDCName,
+			Issuer:       config.OIDCIssuer,
+			ClientID:     config.OIDCClientID,
+			ClientSecret: config.OIDCClientSecret,
+		}
 	}
 	r, err := app.New(db, rdb, templates, mailer, appConfig)
 	if err != nil {
@@ -31,6 +31,11 @@ type Job string
 const (
 	NotifierJob Job = "notifier"
 	ArchiverJob Job = "archiver"
+	DigestJob   Job = "digest"
+	WebhookJob  Job = "webhooks"
+	CleanupJob  Job = "cleanup"
+	ExportJob   Job = "exports"
+	DeletionJob Job = "deletions"
 )
 
 type WorkerConfig struct {
@@ -100,7 +105,12 @@ func (c *WorkerCmd) Run() error {
 		return fmt.Errorf("failed to connect to database")
 	}
 	defer db.Close()
-	service := service.New(service.Options{Db: db})
+	// outside of production, allow webhooks to local/private addresses
+	webhookClient := service.NewWebhookClient(!config.Production)
+	service := service.New(service.Options{
+		Db:           db,
+		HMACKeyBytes: config.HMACKeyBytes,
+	})
 
 	//----------------//
 	// configure jobs //
@@ -141,6 +151,17 @@ func (c *WorkerCmd) Run() error {
 	timer := time.NewTimer(0)
 	defer timer.Stop()
 
+	// webhook deliveries are retried on a much shorter interval
+	webhookInterval := 30 * time.Second
+	webhookTimer := time.NewTimer(0)
+	defer webhookTimer.Stop()
+
+	// requested data exports are built on a short interval too, as the
+	// user is waiting on the download link
+	exportInterval := time.Minute
+	exportTimer := time.NewTimer(0)
+	defer exportTimer.Stop()
+
 	vinfo, _ := util.GetVersion()
 	slog.
 		With("version", vinfo.Version).
@@ -165,13 +186,63 @@ func (c *WorkerCmd) Run() error {
 							Error("notifier error!!")
 					}
 				}
+				if jobList.Contains(DigestJob) {
+					if err := service.SendUserDigests(
+						context.Background(), mailer, templates, config.BaseURL,
+					); err != nil {
+						slog.With("error", err).
+							Error("digest error!!")
+					}
+				}
 				if jobList.Contains(ArchiverJob) {
 					if err := service.ArchiveOldEvents(context.Background()); err != nil {
 						slog.With("erorr", err).
 							Error("archiver error!!")
 					}
 				}
+				if jobList.Contains(DeletionJob) {
+					if err := service.DeleteScheduledUsers(context.Background()); err != nil {
+						slog.With("error", err).
+							Error("deletion error!!")
+					}
+				}
+				if jobList.Contains(CleanupJob) {
+					if err := service.DeleteExpiredIdempotencyKeys(context.Background()); err != nil {
+						slog.With("error", err).
+							Error("cleanup error!!")
+					}
+					if err := service.DeleteOldLoginAttempts(context.Background()); err != nil {
+						slog.With("error", err).
+							Error("cleanup error!!")
+					}
+					if err := service.DeleteExpiredUserDataExports(context.Background()); err != nil {
+						slog.With("error", err).
+							Error("cleanup error!!")
+					}
+				}
 				timer.Reset(timerInterval)
+			case <-webhookTimer.C:
+				if !jobList.Contains(WebhookJob) {
+					continue
+				}
+				if err := service.DeliverWebhooks(
+					context.Background(), webhookClient,
+				); err != nil {
+					slog.With("error", err).
+						Error("webhook error!!")
+				}
+				webhookTimer.Reset(webhookInterval)
+			case <-exportTimer.C:
+				if !jobList.Contains(ExportJob) {
+					continue
+				}
+				if err := service.ProcessUserDataExports(
+					context.Background(), mailer, templates, config.BaseURL,
+				); err != nil {
+					slog.With("error", err).
+						Error("export error!!")
+				}
+				exportTimer.Reset(exportInterval)
 			}
 		}
 	})
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_digest_ (
+    user_id integer NOT NULL,
+    last_sent timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE,
+    UNIQUE(user_id)
+);
+ALTER TABLE notification_ ADD COLUMN digested BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE notification_ DROP COLUMN digested;
+DROP TABLE IF EXISTS user_digest_;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE notification_ ADD COLUMN kind text NOT NULL DEFAULT 'message';
+ALTER TABLE notification_ ADD COLUMN payload JSONB NOT NULL DEFAULT '{}'::jsonb;
+-- older account verification notices used an inline settings link
+UPDATE notification_ SET kind = 'account_unverified'
+    WHERE message LIKE 'Account is not currently verified.%';
+UPDATE notification_ SET
+        kind = 'reminders_disabled',
+        payload = jsonb_build_object('reason', substring(message FROM '''(.*)'''))
+    WHERE message LIKE 'email notifications disabled due to %';
+
+-- +goose Down
+ALTER TABLE notification_ DROP COLUMN payload;
+ALTER TABLE notification_ DROP COLUMN kind;
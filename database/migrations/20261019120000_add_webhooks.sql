@@ -0,0 +1,63 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhook_endpoint_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ref_id refid_bytea NOT NULL,
+    user_id integer NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    -- empty means all event types
+    event_types text[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    last_modified timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX webhook_endpoint_ref_idx ON webhook_endpoint_(ref_id);
+CREATE INDEX webhook_endpoint_user_idx ON webhook_endpoint_(user_id);
+CREATE TRIGGER last_mod_webhook_endpoint
+	BEFORE UPDATE ON webhook_endpoint_
+	FOR EACH ROW
+    EXECUTE PROCEDURE update_last_modified();
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    endpoint_id integer NOT NULL,
+    event_type text NOT NULL,
+    payload JSONB NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt timestamp NOT NULL DEFAULT timezone('utc', now()),
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    last_modified timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT endpoint_fk FOREIGN KEY(endpoint_id) REFERENCES webhook_endpoint_(id) ON DELETE CASCADE
+);
+CREATE INDEX webhook_delivery_pending_idx ON webhook_delivery_(next_attempt) WHERE status = 'pending';
+CREATE TRIGGER last_mod_webhook_delivery
+	BEFORE UPDATE ON webhook_delivery_
+	FOR EACH ROW
+    EXECUTE PROCEDURE update_last_modified();
+
+CREATE TABLE IF NOT EXISTS webhook_attempt_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    endpoint_id integer NOT NULL,
+    delivery_id integer NOT NULL,
+    event_type text NOT NULL,
+    -- zero if no response was received
+    status_code integer NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT endpoint_fk FOREIGN KEY(endpoint_id) REFERENCES webhook_endpoint_(id) ON DELETE CASCADE,
+    CONSTRAINT delivery_fk FOREIGN KEY(delivery_id) REFERENCES webhook_delivery_(id) ON DELETE CASCADE
+);
+CREATE INDEX webhook_attempt_endpoint_idx ON webhook_attempt_(endpoint_id, created);
+
+-- +goose Down
+DROP INDEX IF EXISTS webhook_attempt_endpoint_idx;
+DROP TABLE IF EXISTS webhook_attempt_;
+DROP TRIGGER IF EXISTS last_mod_webhook_delivery ON webhook_delivery_;
+DROP INDEX IF EXISTS webhook_delivery_pending_idx;
+DROP TABLE IF EXISTS webhook_delivery_;
+DROP TRIGGER IF EXISTS last_mod_webhook_endpoint ON webhook_endpoint_;
+DROP INDEX IF EXISTS webhook_endpoint_user_idx;
+DROP INDEX IF EXISTS webhook_endpoint_ref_idx;
+DROP TABLE IF EXISTS webhook_endpoint_;
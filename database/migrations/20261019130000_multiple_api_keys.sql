@@ -0,0 +1,34 @@
+-- +goose Up
+DROP INDEX IF EXISTS api_key_user_id_idx;
+ALTER TABLE api_key_ ADD COLUMN id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY;
+-- the public part of a token, used to identify a key
+ALTER TABLE api_key_ ADD COLUMN prefix varchar(26);
+UPDATE api_key_ SET prefix = split_part(token, ':', 1);
+ALTER TABLE api_key_ ALTER COLUMN prefix SET NOT NULL;
+CREATE UNIQUE INDEX api_key_prefix_idx ON api_key_(prefix);
+CREATE INDEX api_key_user_idx ON api_key_(user_id);
+ALTER TABLE api_key_ ADD COLUMN name varchar(255) NOT NULL DEFAULT 'default';
+ALTER TABLE api_key_ ALTER COLUMN name DROP DEFAULT;
+-- pre-existing keys retain full access
+ALTER TABLE api_key_ ADD COLUMN scopes text[] NOT NULL
+    DEFAULT '{read,events:write,earmarks:write,notifications,webhooks}';
+ALTER TABLE api_key_ ALTER COLUMN scopes DROP DEFAULT;
+ALTER TABLE api_key_ ADD COLUMN expires timestamp;
+ALTER TABLE api_key_ ADD COLUMN last_used timestamp;
+ALTER TABLE api_key_ ADD COLUMN last_used_ip varchar(45) NOT NULL DEFAULT '';
+
+-- +goose Down
+-- only one key per user can be retained
+DELETE FROM api_key_ a
+    USING api_key_ b
+    WHERE a.user_id = b.user_id AND a.id < b.id;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS last_used_ip;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS last_used;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS expires;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS scopes;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS name;
+DROP INDEX IF EXISTS api_key_user_idx;
+DROP INDEX IF EXISTS api_key_prefix_idx;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS prefix;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS id;
+CREATE UNIQUE INDEX api_key_user_id_idx ON api_key_(user_id);
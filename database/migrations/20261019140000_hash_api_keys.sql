@@ -0,0 +1,12 @@
+-- +goose Up
+-- tokens are stored as a keyed hash. existing plaintext tokens are
+-- rehashed by the application at startup (or on first use), after
+-- which the plaintext token is cleared.
+ALTER TABLE api_key_ ADD COLUMN token_hash bytea;
+ALTER TABLE api_key_ ALTER COLUMN token DROP NOT NULL;
+
+-- +goose Down
+-- hashed tokens cannot be recovered, so those keys are removed
+DELETE FROM api_key_ WHERE token IS NULL;
+ALTER TABLE api_key_ ALTER COLUMN token SET NOT NULL;
+ALTER TABLE api_key_ DROP COLUMN IF EXISTS token_hash;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS idempotency_key_ (
+    user_id integer NOT NULL,
+    key varchar(255) NOT NULL,
+    procedure text NOT NULL,
+    request_hash bytea NOT NULL,
+    -- null while the original request is still in flight
+    response bytea,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    PRIMARY KEY (user_id, key),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE INDEX idempotency_key_created_idx ON idempotency_key_(created);
+
+-- +goose Down
+DROP INDEX IF EXISTS idempotency_key_created_idx;
+DROP TABLE IF EXISTS idempotency_key_;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS login_attempt_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id integer NOT NULL,
+    method text NOT NULL,
+    success boolean NOT NULL,
+    ip_address text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE INDEX login_attempt_user_created_idx ON login_attempt_(user_id, created);
+CREATE INDEX login_attempt_created_idx ON login_attempt_(created);
+
+-- +goose Down
+DROP INDEX IF EXISTS login_attempt_created_idx;
+DROP INDEX IF EXISTS login_attempt_user_created_idx;
+DROP TABLE IF EXISTS login_attempt_;
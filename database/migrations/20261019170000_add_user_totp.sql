@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_totp_ (
+    user_id integer PRIMARY KEY,
+    -- encrypted at rest
+    secret bytea NOT NULL,
+    -- false until enrollment is confirmed with a valid code
+    enabled boolean NOT NULL DEFAULT false,
+    -- last accepted time step, so codes can only be used once
+    last_step bigint NOT NULL DEFAULT 0,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    last_modified timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE TRIGGER last_mod_user_totp
+    BEFORE UPDATE ON user_totp_
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_last_modified();
+
+CREATE TABLE IF NOT EXISTS user_recovery_code_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id integer NOT NULL,
+    code_hash bytea NOT NULL,
+    used timestamp,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_recovery_code_user_hash_idx ON user_recovery_code_(user_id, code_hash);
+
+-- +goose Down
+DROP INDEX IF EXISTS user_recovery_code_user_hash_idx;
+DROP TABLE IF EXISTS user_recovery_code_;
+DROP TRIGGER IF EXISTS last_mod_user_totp ON user_totp_;
+DROP TABLE IF EXISTS user_totp_;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_identity_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ref_id refid_bytea NOT NULL,
+    user_id integer NOT NULL,
+    issuer text NOT NULL,
+    subject text NOT NULL,
+    email text NOT NULL DEFAULT '',
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_identity_ref_idx ON user_identity_(ref_id);
+CREATE UNIQUE INDEX user_identity_subject_idx ON user_identity_(issuer, subject);
+CREATE INDEX user_identity_user_idx ON user_identity_(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS user_identity_user_idx;
+DROP INDEX IF EXISTS user_identity_subject_idx;
+DROP INDEX IF EXISTS user_identity_ref_idx;
+DROP TABLE IF EXISTS user_identity_;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_session_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ref_id refid_bytea NOT NULL,
+    user_id integer NOT NULL,
+    ip_address text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    last_seen timestamp NOT NULL DEFAULT timezone('utc', now()),
+    expires timestamp NOT NULL,
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_session_ref_idx ON user_session_(ref_id);
+CREATE INDEX user_session_user_idx ON user_session_(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS user_session_user_idx;
+DROP INDEX IF EXISTS user_session_ref_idx;
+DROP TABLE IF EXISTS user_session_;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_email_change_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ref_id refid_bytea NOT NULL,
+    user_id integer NOT NULL,
+    old_email varchar(255) NOT NULL,
+    new_email varchar(255) NOT NULL,
+    confirmed timestamp,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_email_change_ref_idx ON user_email_change_(ref_id);
+CREATE INDEX user_email_change_user_idx ON user_email_change_(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS user_email_change_user_idx;
+DROP INDEX IF EXISTS user_email_change_ref_idx;
+DROP TABLE IF EXISTS user_email_change_;
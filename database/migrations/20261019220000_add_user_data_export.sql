@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_data_export_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ref_id refid_bytea NOT NULL,
+    user_id integer NOT NULL,
+    data bytea,
+    completed timestamp,
+    emailed timestamp,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_data_export_ref_idx ON user_data_export_(ref_id);
+CREATE INDEX user_data_export_user_idx ON user_data_export_(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS user_data_export_user_idx;
+DROP INDEX IF EXISTS user_data_export_ref_idx;
+DROP TABLE IF EXISTS user_data_export_;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_deletion_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    ref_id refid_bytea NOT NULL,
+    user_id integer NOT NULL,
+    scheduled timestamp NOT NULL,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_deletion_ref_idx ON user_deletion_(ref_id);
+CREATE UNIQUE INDEX user_deletion_user_idx ON user_deletion_(user_id);
+CREATE INDEX user_deletion_scheduled_idx ON user_deletion_(scheduled);
+
+CREATE TABLE IF NOT EXISTS user_deletion_transfer_ (
+    id integer PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    deletion_id integer NOT NULL,
+    event_id integer NOT NULL,
+    user_id integer NOT NULL,
+    created timestamp NOT NULL DEFAULT timezone('utc', now()),
+    CONSTRAINT deletion_fk FOREIGN KEY(deletion_id) REFERENCES user_deletion_(id) ON DELETE CASCADE,
+    CONSTRAINT event_fk FOREIGN KEY(event_id) REFERENCES event_(id) ON DELETE CASCADE,
+    CONSTRAINT user_fk FOREIGN KEY(user_id) REFERENCES user_(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX user_deletion_transfer_event_idx ON user_deletion_transfer_(deletion_id, event_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS user_deletion_transfer_event_idx;
+DROP TABLE IF EXISTS user_deletion_transfer_;
+DROP INDEX IF EXISTS user_deletion_scheduled_idx;
+DROP INDEX IF EXISTS user_deletion_user_idx;
+DROP INDEX IF EXISTS user_deletion_ref_idx;
+DROP TABLE IF EXISTS user_deletion_;
@@ -4,8 +4,12 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -22,6 +26,9 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/middleware/debug"
 	"github.com/dropwhile/icanbringthat/internal/middleware/header"
 	"github.com/dropwhile/icanbringthat/internal/middleware/strip"
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/ratelimit"
 	"github.com/dropwhile/icanbringthat/internal/session"
 )
 
@@ -50,21 +57,67 @@ func New(
 	mailer mail.MailSender,
 	conf *Config,
 ) (*App, error) {
-	service := service.New(service.Options{Db: db})
+	// redis is optional. without it, a single instance keeps its
+	// pubsub and rate limit state in memory.
+	var broker pubsub.Broker
+	var limitStore ratelimit.Store
+	if rdb != nil {
+		broker = pubsub.NewRedisBroker(rdb)
+		limitStore = ratelimit.NewRedisStore(rdb)
+	} else {
+		broker = pubsub.NewMemoryBroker()
+		limitStore = ratelimit.NewMemoryStore()
+	}
+	service := service.New(service.Options{
+		Db:           db,
+		Broker:       broker,
+		HMACKeyBytes: conf.HMACKeyBytes,
+	})
+	// hash any api key tokens still stored in plaintext
+	if count, errx := service.RehashApiKeys(context.Background()); errx != nil {
+		return nil, fmt.Errorf("error rehashing api keys: %w", errx)
+	} else if count > 0 {
+		slog.Info("rehashed legacy api keys", "count", count)
+	}
 	baseURL := strings.TrimSuffix(conf.BaseURL, "/")
 	isProd := conf.Production
-	sessMgr := session.NewRedisSessionManager(rdb, conf.Production)
+	sessConf := conf.Session
+	sessConf.Secure = isProd
+	var sessMgr *session.SessionMgr
+	switch conf.SessionBackend {
+	case SessionBackendPostgres:
+		sessMgr = session.NewDBSessionManager(db, sessConf)
+	case SessionBackendMemory:
+		sessMgr = session.NewMemorySessionManager(sessConf)
+	case SessionBackendRedis, "":
+		if rdb == nil {
+			return nil, fmt.Errorf("redis session backend requires redis")
+		}
+		sessMgr = session.NewRedisSessionManager(rdb, sessConf)
+	default:
+		return nil, fmt.Errorf("unknown session backend: %s", conf.SessionBackend)
+	}
+
+	var oidcProvider *oidc.Provider
+	if conf.OIDC != nil {
+		oidcConf := *conf.OIDC
+		oidcConf.RedirectURL = baseURL + "/login/oidc/callback"
+		oidcProvider = oidc.NewProvider(oidcConf, nil)
+	}
 
 	zh, err := handler.New(
 		handler.Options{
 			Db:           db,
 			Redis:        rdb,
+			Broker:       broker,
 			Templates:    templates,
 			SessMgr:      sessMgr,
 			Mailer:       mailer,
 			HMACKeyBytes: conf.HMACKeyBytes,
 			BaseURL:      baseURL,
+			OIDC:         oidcProvider,
 			IsProd:       isProd,
+			RememberMe:   conf.Session.RememberMe,
 		},
 	)
 	if err != nil {
@@ -74,6 +127,17 @@ func New(
 	app := &App{Mux: chi.NewRouter(), handler: zh}
 	app.OnClose(sessMgr.Close)
 
+	// rate limiting
+	limit := func(respond ratelimit.Responder, policies []ratelimit.Policy) func(http.Handler) http.Handler {
+		if !conf.RateLimit {
+			return func(next http.Handler) http.Handler { return next }
+		}
+		return ratelimit.Limit(limitStore, respond, policies...)
+	}
+	tooManyRequests := func(w http.ResponseWriter, r *http.Request, _ time.Duration) {
+		zh.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+
 	// Router/Middleware //
 	r := app.Mux
 	r.Use(middleware.Logger)
@@ -105,8 +169,27 @@ func New(
 			r.Post("/settings", zh.SettingsUpdate)
 			r.Post("/settings/auth", zh.SettingsAuthUpdate)
 			r.Post("/settings/auth/api", zh.SettingsAuthApiUpdate)
+			r.Post("/settings/auth/api/keys", zh.ApiKeyCreate)
+			r.Get("/settings/auth/totp", zh.TOTPEnrollShowForm)
+			r.With(limit(tooManyRequests, totpLimits)).
+				Post("/settings/auth/totp", zh.TOTPEnroll)
+			r.With(limit(tooManyRequests, totpLimits)).
+				Post("/settings/auth/totp/disable", zh.TOTPDisable)
+			r.With(limit(tooManyRequests, totpLimits)).
+				Post("/settings/auth/totp/recovery-codes", zh.TOTPRecoveryCodesRegenerate)
+			r.Delete("/settings/auth/api/keys/{kPrefix:[0-9a-z]+}", zh.ApiKeyDelete)
+			r.Post("/settings/auth/identities", zh.OIDCLinkStart)
+			r.Delete("/settings/auth/identities/{iRefID:[0-9a-z]+}", zh.OIDCUnlink)
+			r.Delete("/settings/sessions/{sRefID:[0-9a-z]+}", zh.UserSessionDelete)
+			r.Post("/settings/sessions/revoke-all", zh.UserSessionDeleteAll)
 			r.Post("/settings/reminders", zh.SettingsRemindersUpdate)
 			r.Delete("/settings", zh.AccountDelete)
+			r.Post("/settings/deletion/cancel", zh.AccountDeletionCancel)
+			r.Post("/settings/webhooks", zh.WebhookEndpointCreate)
+			r.Post("/settings/webhooks/{wRefID:[0-9a-z]+}", zh.WebhookEndpointUpdate)
+			r.Delete("/settings/webhooks/{wRefID:[0-9a-z]+}", zh.WebhookEndpointDelete)
+			r.Post("/settings/export", zh.AccountExportRequest)
+			r.Get("/settings/export/{exRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.AccountExportDownload)
 			// logout
 			r.Post("/logout", zh.Logout)
 			// dashboard
@@ -142,9 +225,16 @@ func New(
 			r.Get("/notifications", zh.NotificationsList)
 			r.Delete("/notifications", zh.NotificationsDeleteAll)
 			r.Delete("/notifications/{nRefID:[0-9a-z]+}", zh.NotificationDelete)
+			r.Post("/notifications/read", zh.NotificationsMarkAllRead)
+			r.Post("/notifications/{nRefID:[0-9a-z]+}/read", zh.NotificationMarkRead)
+			r.Post("/notifications/{nRefID:[0-9a-z]+}/unread", zh.NotificationMarkUnread)
+			// real-time updates
+			r.Get("/stream", zh.Stream)
 			// account verification
-			r.Post("/verify", zh.VerifySendEmail)
+			r.With(limit(tooManyRequests, verifyLimits)).
+				Post("/verify", zh.VerifySendEmail)
 			r.Get("/verify/{uvRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.VerifyEmail)
+			r.Get("/settings/email/{ecRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.EmailChangeConfirm)
 			// webauthn
 			r.Get("/webauthn/register", zh.WebAuthnBeginRegistration)
 			r.Post("/webauthn/register", zh.WebAuthnFinishRegistration)
@@ -155,18 +245,39 @@ func New(
 		r.Group(func(r chi.Router) {
 			r.Get("/", zh.IndexShow)
 			// login
-			r.Post("/login", zh.Login)
+			r.With(limit(tooManyRequests, loginLimits)).
+				Post("/login", zh.Login)
 			r.Get("/login", zh.LoginShowForm)
+			r.With(limit(tooManyRequests, loginLimits)).
+				Post("/login/totp", zh.LoginTOTP)
+			r.Get("/login/totp", zh.LoginTOTPShowForm)
+			r.Get("/login/link", zh.LoginLinkShowForm)
+			r.With(limit(tooManyRequests, loginLinkLimits)).
+				Post("/login/link", zh.LoginLinkSendEmail)
+			r.Get("/login/link/{lnkRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.LoginLinkShowConfirm)
+			r.Post("/login/link/{lnkRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.LoginLink)
+			r.Get("/login/oidc", zh.OIDCLogin)
+			r.With(limit(tooManyRequests, oidcLimits)).
+				Get("/login/oidc/callback", zh.OIDCCallback)
 			r.Get("/webauthn/login", zh.WebAuthnBeginLogin)
-			r.Post("/webauthn/login", zh.WebAuthnFinishLogin)
+			r.With(limit(tooManyRequests, loginLimits)).
+				Post("/webauthn/login", zh.WebAuthnFinishLogin)
 			// forgot password
 			r.Get("/forgot-password", zh.ForgotPasswordShowForm)
-			r.Post("/forgot-password", zh.ResetPasswordSendEmail)
+			r.With(limit(tooManyRequests, pwResetLimits)).
+				Post("/forgot-password", zh.ResetPasswordSendEmail)
 			r.Get("/forgot-password/{upwRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.PasswordResetShowForm)
 			r.Post("/forgot-password/{upwRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.PasswordReset)
+			// email change revert, from the previous address
+			r.Get("/email-change/{ecRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.EmailChangeShowRevert)
+			r.Post("/email-change/{ecRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.EmailChangeRevert)
+			// scheduled account deletion cancel
+			r.Get("/account-deletion/{udRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.AccountDeletionShowCancel)
+			r.Post("/account-deletion/{udRefID:[0-9a-z]+}-{hmac:[0-9a-z]+}", zh.AccountDeletionCancelByLink)
 			// account creation
 			r.Get("/create-account", zh.AccountShowCreate)
-			r.Post("/create-account", zh.AccountCreate)
+			r.With(limit(tooManyRequests, signupLimits)).
+				Post("/create-account", zh.AccountCreate)
 			// local only debug stuff
 			if !conf.Production {
 				r.Route("/debug", func(r chi.Router) {
@@ -188,6 +299,7 @@ func New(
 			rpc.Options{
 				Db:           db,
 				Redis:        rdb,
+				Broker:       broker,
 				Templates:    templates,
 				Mailer:       mailer,
 				HMACKeyBytes: conf.HMACKeyBytes,
@@ -206,6 +318,7 @@ func New(
 			r.NotFound(http.NotFound)
 			r.Use(middleware.NoCache)
 			r.Use(auth.LoadAuthToken)
+			r.Use(limit(ratelimit.ResourceExhausted, rpcLimits))
 			r.Use(strip.StripPrefix(RpcPrefix))
 			r.Use(rpc.RequireApiKey(service))
 			r.Mount("/", rpcServer.GenHandler())
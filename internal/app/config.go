@@ -3,11 +3,28 @@
 // license that can be found in the LICENSE file.
 package app
 
+import (
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+	"github.com/dropwhile/icanbringthat/internal/session"
+)
+
+// session store backends
+const (
+	SessionBackendRedis    = "redis"
+	SessionBackendPostgres = "postgres"
+	SessionBackendMemory   = "memory"
+)
+
 type Config struct {
+	// OIDC configures login with an external provider. Nil disables it.
+	OIDC           *oidc.Config
 	WebhookCreds   map[string]string
+	SessionBackend string
 	BaseURL        string
+	Session        session.Config
 	HMACKeyBytes   []byte
 	Production     bool
 	RequestLogging bool
 	RpcApi         bool
+	RateLimit      bool
 }
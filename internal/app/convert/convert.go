@@ -15,6 +15,7 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
 )
 
@@ -72,9 +73,10 @@ func ToConnectRpcError(src errs.Error) *connect.Error {
 
 func ToPbPagination(src *service.Pagination) *icbt.PaginationResult {
 	dst := icbt.PaginationResult_builder{
-		Limit:  uint32(min(max(src.Limit, 0), math.MaxUint32)),  // #nosec G115 -- safe conversion
-		Offset: uint32(min(max(src.Offset, 0), math.MaxUint32)), // #nosec G115 -- safe conversion
-		Count:  uint32(min(max(src.Count, 0), math.MaxUint32)),  // #nosec G115 -- safe conversion
+		Limit:      uint32(min(max(src.Limit, 0), math.MaxUint32)),  // #nosec G115 -- safe conversion
+		Offset:     uint32(min(max(src.Offset, 0), math.MaxUint32)), // #nosec G115 -- safe conversion
+		Count:      uint32(min(max(src.Count, 0), math.MaxUint32)),  // #nosec G115 -- safe conversion
+		NextCursor: src.NextCursor,
 	}.Build()
 	return dst
 }
@@ -87,6 +89,7 @@ func ToPbEvent(src *model.Event) *icbt.Event {
 		When:        TimeToTimestampTZ(src.When()),
 		Archived:    src.Archived,
 		Created:     TimeToTimestamp(src.Created),
+		Version:     src.Version(),
 	}.Build()
 	return dst
 }
@@ -96,6 +99,7 @@ func ToPbEventItem(src *model.EventItem) *icbt.EventItem {
 		RefId:       src.RefID.String(),
 		Description: src.Description,
 		Created:     TimeToTimestamp(src.Created),
+		Version:     src.Version(),
 	}.Build()
 
 	return dst
@@ -103,14 +107,135 @@ func ToPbEventItem(src *model.EventItem) *icbt.EventItem {
 
 func ToPbNotification(src *model.Notification) *icbt.Notification {
 	dst := icbt.Notification_builder{
+		RefId:          src.RefID.String(),
+		Message:        src.Message,
+		Created:        TimeToTimestamp(src.Created),
+		Read:           src.Read,
+		Kind:           ToPbNotificationKind(src.Kind),
+		EventRefId:     src.Payload.EventRefID,
+		EventItemRefId: src.Payload.EventItemRefID,
+		EarmarkRefId:   src.Payload.EarmarkRefID,
+	}.Build()
+
+	return dst
+}
+
+func ToPbWebhook(src *model.WebhookEndpoint) *icbt.Webhook {
+	dst := icbt.Webhook_builder{
+		RefId:      src.RefID.String(),
+		Url:        src.URL,
+		EventTypes: src.EventTypes,
+		Enabled:    src.Enabled,
+		Created:    TimeToTimestamp(src.Created),
+	}.Build()
+	return dst
+}
+
+func ToPbWebhookAttempt(src *model.WebhookAttempt) *icbt.WebhookAttempt {
+	dst := icbt.WebhookAttempt_builder{
+		EventType:  src.EventType,
+		StatusCode: uint32(min(max(src.StatusCode, 0), math.MaxUint32)), // #nosec G115 -- safe conversion
+		Error:      src.Error,
+		Created:    TimeToTimestamp(src.Created),
+	}.Build()
+	return dst
+}
+
+func ToPbUser(src *model.User) *icbt.User {
+	dst := icbt.User_builder{
+		RefId:     src.RefID.String(),
+		Email:     src.Email,
+		Name:      src.Name,
+		Verified:  src.Verified,
+		PwAuth:    src.PWAuth,
+		Webauthn:  src.WebAuthn,
+		ApiAccess: src.ApiAccess,
+		Created:   TimeToTimestamp(src.Created),
+	}.Build()
+	return dst
+}
+
+func ToPbUserSettings(src *model.UserSettings) *icbt.UserSettings {
+	dst := icbt.UserSettings_builder{
+		ReminderThresholdHours: uint32(src.ReminderThresholdHours),
+		EnableReminders:        src.EnableReminders,
+		NudgeGuests:            src.NudgeGuests,
+		Timezone:               src.Timezone,
+		QuietHoursEnabled:      src.QuietHoursEnabled,
+		QuietHoursStart:        uint32(src.QuietHoursStart),
+		QuietHoursEnd:          uint32(src.QuietHoursEnd),
+		DigestFrequency:        src.DigestFrequency,
+	}.Build()
+	return dst
+}
+
+func ToPbUserCredential(src *model.UserCredential) *icbt.UserCredential {
+	dst := icbt.UserCredential_builder{
 		RefId:   src.RefID.String(),
-		Message: src.Message,
+		KeyName: src.KeyName,
 		Created: TimeToTimestamp(src.Created),
 	}.Build()
-
 	return dst
 }
 
+func ToPbNotificationKind(src model.NotificationKind) icbt.NotificationKind {
+	switch src {
+	case model.NotificationKindMessage:
+		return icbt.NotificationKind_NOTIFICATION_KIND_MESSAGE
+	case model.NotificationKindAccountUnverified:
+		return icbt.NotificationKind_NOTIFICATION_KIND_ACCOUNT_UNVERIFIED
+	case model.NotificationKindRemindersDisabled:
+		return icbt.NotificationKind_NOTIFICATION_KIND_REMINDERS_DISABLED
+	case model.NotificationKindEarmarkClaimed:
+		return icbt.NotificationKind_NOTIFICATION_KIND_EARMARK_CLAIMED
+	case model.NotificationKindEventChanged:
+		return icbt.NotificationKind_NOTIFICATION_KIND_EVENT_CHANGED
+	case model.NotificationKindNewSignIn:
+		return icbt.NotificationKind_NOTIFICATION_KIND_NEW_SIGN_IN
+	case model.NotificationKindEventTransferred:
+		return icbt.NotificationKind_NOTIFICATION_KIND_EVENT_TRANSFERRED
+	}
+	return icbt.NotificationKind_NOTIFICATION_KIND_UNSPECIFIED
+}
+
+func ToPbEventChangeKind(change string) icbt.EventChangeKind {
+	switch change {
+	case pubsub.ChangeItemAdded:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_ITEM_ADDED
+	case pubsub.ChangeItemUpdated:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_ITEM_UPDATED
+	case pubsub.ChangeItemRemoved:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_ITEM_REMOVED
+	case pubsub.ChangeEarmarkCreated:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_EARMARK_CREATED
+	case pubsub.ChangeEarmarkDeleted:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_EARMARK_DELETED
+	case pubsub.ChangeItemsSorted:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_ITEMS_SORTED
+	case pubsub.ChangeEventUpdated:
+		return icbt.EventChangeKind_EVENT_CHANGE_KIND_EVENT_UPDATED
+	}
+	return icbt.EventChangeKind_EVENT_CHANGE_KIND_UNSPECIFIED
+}
+
+func ToPbNotificationChangeKind(change string) icbt.NotificationChangeKind {
+	switch change {
+	case pubsub.ChangeNotificationCreated:
+		return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_CREATED
+	case pubsub.ChangeNotificationDeleted:
+		return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_DELETED
+	case pubsub.ChangeNotificationRead:
+		return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_READ
+	case pubsub.ChangeNotificationUnread:
+		return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_UNREAD
+	case pubsub.ChangeNotificationsRead:
+		return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_ALL_READ
+	case pubsub.ChangeNotificationsDeleted:
+		return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_ALL_DELETED
+	}
+	return icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_UNSPECIFIED
+}
+
 func ToPbEarmark(ctx context.Context, svc service.Servicer, src *model.Earmark) (*icbt.Earmark, error) {
 	eventItem, err := svc.GetEventItemByID(ctx, src.EventItemID)
 	if err != nil {
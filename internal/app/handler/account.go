@@ -4,9 +4,11 @@
 package handler
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/samber/mo"
 
@@ -60,28 +62,98 @@ func (x *Handler) SettingsShow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	apikey, errx := x.svc.GetApiKeyByUser(ctx, user.ID)
+	apikeys, errx := x.svc.GetApiKeysByUser(ctx, user.ID)
 	if errx != nil {
-		if errx.Code() != errs.NotFound {
+		x.DBError(w, errx)
+		return
+	}
+
+	webhooks, errx := x.svc.GetWebhookEndpoints(ctx, user.ID)
+	if errx != nil {
+		x.DBError(w, errx)
+		return
+	}
+
+	notifCount, errx := x.svc.GetNotificationsUnreadCount(ctx, user.ID)
+	if errx != nil {
+		x.DBError(w, errx)
+		return
+	}
+
+	totpEnabled, errx := x.svc.IsTOTPEnabled(ctx, user.ID)
+	if errx != nil {
+		x.DBError(w, errx)
+		return
+	}
+
+	recoveryCodesRemaining := 0
+	if totpEnabled {
+		recoveryCodesRemaining, errx = x.svc.GetRecoveryCodesRemaining(ctx, user.ID)
+		if errx != nil {
 			x.DBError(w, errx)
 			return
 		}
 	}
 
-	notifCount, errx := x.svc.GetNotificationsCount(ctx, user.ID)
+	var identities []*model.UserIdentity
+	oidcName := ""
+	if x.oidc != nil {
+		oidcName = x.oidc.Name()
+		identities, errx = x.svc.GetUserIdentities(ctx, user.ID)
+		if errx != nil {
+			x.DBError(w, errx)
+			return
+		}
+	}
+
+	sessions, errx := x.svc.GetUserSessions(ctx, user.ID)
 	if errx != nil {
 		x.DBError(w, errx)
 		return
 	}
 
+	emailChange, errx := x.svc.GetPendingUserEmailChange(ctx, user.ID)
+	if errx != nil && errx.Code() != errs.NotFound {
+		x.DBError(w, errx)
+		return
+	}
+
+	// events can only be handed over while deletion is not yet scheduled
+	var transferOptions []*service.EventTransferOption
+	deletion, errx := x.svc.GetUserDeletion(ctx, user.ID)
+	switch {
+	case errx == nil:
+	case errx.Code() == errs.NotFound:
+		transferOptions, errx = x.svc.GetEventTransferOptions(ctx, user.ID)
+		if errx != nil {
+			x.DBError(w, errx)
+			return
+		}
+	default:
+		x.DBError(w, errx)
+		return
+	}
+
 	// parse user-id url param
 	tplVars := MapSA{
-		"user":        user,
-		"credentials": credentials,
-		"apikey":      apikey,
-		"title":       "Settings",
-		"notifCount":  notifCount,
-		"flashes":     x.sessMgr.FlashPopAll(ctx),
+		"user":                   user,
+		"credentials":            credentials,
+		"apikeys":                apikeys,
+		"apikeyScopes":           model.ApiKeyScopes,
+		"webhooks":               webhooks,
+		"webhookEventTypes":      service.WebhookEventTypes,
+		"title":                  "Settings",
+		"notifCount":             notifCount,
+		"totpEnabled":            totpEnabled,
+		"recoveryCodesRemaining": recoveryCodesRemaining,
+		"oidcName":               oidcName,
+		"identities":             identities,
+		"sessions":               sessions,
+		"currentSessionID":       x.sessMgr.GetString(ctx, "session-id"),
+		"emailChange":            emailChange,
+		"deletion":               deletion,
+		"transferOptions":        transferOptions,
+		"flashes":                x.sessMgr.FlashPopAll(ctx),
 	}
 	// render user profile view
 	w.Header().Set("content-type", "text/html")
@@ -127,8 +199,9 @@ func (x *Handler) AccountCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, errx = x.svc.NewNotification(ctx, user.ID,
-		`Account is not currently verified. Please verify account in link:/settings.`,
+	_, errx = x.svc.NewTypedNotification(ctx, user.ID,
+		model.NotificationKindAccountUnverified,
+		model.NotificationPayload{},
 	)
 	if errx != nil {
 		// this is a nonfatal error
@@ -147,7 +220,10 @@ func (x *Handler) AccountCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Then make the privilege-level change.
-	x.sessMgr.Put(r.Context(), "user-id", user.ID)
+	if errx := x.startUserSession(r, user); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
 	x.sessMgr.FlashAppend(ctx, "success", "Account created. You are now logged in.")
 
 	target := "/dashboard"
@@ -179,10 +255,24 @@ func (x *Handler) SettingsUpdate(w http.ResponseWriter, r *http.Request) {
 
 	email := r.PostFormValue("email")
 	if email != "" && email != user.Email {
-		changes = true
-		updateVals.Email = mo.Some(email)
-		updateVals.Verified = mo.Some(false)
-		successMsgs = append(successMsgs, "Email update successfull")
+		// the address is only changed once confirmed from the new address
+		errx := x.startEmailChange(ctx, user, email)
+		if errx != nil {
+			switch errx.Code() {
+			case errs.InvalidArgument:
+				warnings = append(warnings, "'Email' was a bad value")
+			case errs.AlreadyExists:
+				warnings = append(warnings, "Email is already in use")
+			default:
+				slog.ErrorContext(ctx, "error starting email change",
+					logger.Err(errx))
+				x.InternalServerError(w, "error updating user")
+				return
+			}
+		} else {
+			successMsgs = append(successMsgs,
+				"A confirmation link was sent to "+email)
+		}
 	} else if email == user.Email {
 		warnings = append(warnings, "Same Email specified was already present")
 	}
@@ -233,6 +323,14 @@ func (x *Handler) SettingsUpdate(w http.ResponseWriter, r *http.Request) {
 				x.InternalServerError(w, "error updating user")
 				return
 			}
+		} else if updateVals.PwUpdate.IsPresent() {
+			// a changed password logs out every other device
+			if errx := x.revokeOtherSessions(ctx, user.ID); errx != nil {
+				slog.ErrorContext(ctx, "error revoking sessions",
+					logger.Err(errx))
+				x.InternalServerError(w, "error revoking sessions")
+				return
+			}
 		}
 	}
 	if updateVals.PwUpdate.IsPresent() {
@@ -367,12 +465,6 @@ func (x *Handler) SettingsAuthApiUpdate(w http.ResponseWriter, r *http.Request)
 	}
 
 	apiAccess := r.PostFormValue("api_access")
-	rotateApiKey := r.PostFormValue("rotate_apikey")
-
-	if apiAccess == "" && rotateApiKey == "" {
-		x.BadFormDataError(w, nil, "bad params")
-		return
-	}
 
 	changes := false
 	updateVals := &service.UserUpdateValues{}
@@ -392,45 +484,23 @@ func (x *Handler) SettingsAuthApiUpdate(w http.ResponseWriter, r *http.Request)
 			}
 			changes = true
 			updateVals.ApiAccess = mo.Some(true)
-			_, errx := x.svc.NewApiKeyIfNotExists(ctx, user.ID)
-			if errx != nil {
-				x.DBError(w, errx)
-				return
-			}
 		}
-	case "":
-		// nothing
 	default:
 		x.BadFormDataError(w, nil, "api_access")
 		return
 	}
 
-	if rotateApiKey == "true" {
-		changes = true
-	}
-
 	if !changes {
 		x.sessMgr.FlashAppend(ctx, "error", "no changes made")
 		http.Redirect(w, r, "/settings", http.StatusSeeOther)
 		return
 	}
 
-	if rotateApiKey == "true" {
-		if _, errx := x.svc.NewApiKey(ctx, user.ID); errx != nil {
-			slog.ErrorContext(ctx, "error rotating api key",
-				logger.Err(errx))
-			x.InternalServerError(w, "error rotating api key")
-			return
-		}
-	}
-
-	if apiAccess != "" {
-		if errx := x.svc.UpdateUser(ctx, user, updateVals); errx != nil {
-			slog.ErrorContext(ctx, "error updating user auth",
-				logger.Err(errx))
-			x.InternalServerError(w, "error updating user auth")
-			return
-		}
+	if errx := x.svc.UpdateUser(ctx, user, updateVals); errx != nil {
+		slog.ErrorContext(ctx, "error updating user auth",
+			logger.Err(errx))
+		x.InternalServerError(w, "error updating user auth")
+		return
 	}
 
 	http.Redirect(w, r, "/settings", http.StatusSeeOther)
@@ -453,7 +523,13 @@ func (x *Handler) SettingsRemindersUpdate(w http.ResponseWriter, r *http.Request
 
 	changes := false
 	enableReminders := r.PostFormValue("enable_reminders")
+	nudgeGuests := r.PostFormValue("nudge_guests")
 	notifThreshold := r.PostFormValue("notification_threshold")
+	quietHours := r.PostFormValue("quiet_hours")
+	quietHoursStart := r.PostFormValue("quiet_hours_start")
+	quietHoursEnd := r.PostFormValue("quiet_hours_end")
+	timezone := r.PostFormValue("timezone")
+	digestFrequency, digestSet := r.PostForm["digest_frequency"]
 
 	switch enableReminders {
 	case "off":
@@ -479,6 +555,25 @@ func (x *Handler) SettingsRemindersUpdate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	switch nudgeGuests {
+	case "off":
+		if user.Settings.NudgeGuests {
+			changes = true
+			user.Settings.NudgeGuests = false
+		}
+	case "on":
+		if !user.Settings.NudgeGuests {
+			changes = true
+			user.Settings.NudgeGuests = true
+		}
+	case "":
+		// nothing
+	default:
+		x.sessMgr.FlashAppend(ctx, "error", "Bad value for guest nudges toggle")
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		return
+	}
+
 	if notifThreshold != "" {
 		v, err := strconv.ParseUint(notifThreshold, 10, 8)
 		if err != nil {
@@ -498,6 +593,84 @@ func (x *Handler) SettingsRemindersUpdate(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	switch quietHours {
+	case "off":
+		if user.Settings.QuietHoursEnabled {
+			changes = true
+			user.Settings.QuietHoursEnabled = false
+		}
+	case "on":
+		if !user.Settings.QuietHoursEnabled {
+			changes = true
+			user.Settings.QuietHoursEnabled = true
+		}
+	case "":
+		// nothing
+	default:
+		x.sessMgr.FlashAppend(ctx, "error", "Bad value for quiet hours toggle")
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		return
+	}
+
+	for _, qh := range []struct {
+		dst   *uint8
+		value string
+	}{
+		{&user.Settings.QuietHoursStart, quietHoursStart},
+		{&user.Settings.QuietHoursEnd, quietHoursEnd},
+	} {
+		if qh.value == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(qh.value, 10, 8)
+		if err != nil {
+			x.sessMgr.FlashAppend(ctx, "error", "Bad value for quiet hours")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+			return
+		}
+		val, err := model.ValidateQuietHour(v)
+		if err != nil {
+			x.sessMgr.FlashAppend(ctx, "error", "Bad value for quiet hours")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+			return
+		}
+		if *qh.dst != val {
+			changes = true
+			*qh.dst = val
+		}
+	}
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			x.sessMgr.FlashAppend(ctx, "error", "Bad value for timezone")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+			return
+		}
+		if user.Settings.Timezone != loc.String() {
+			changes = true
+			user.Settings.Timezone = loc.String()
+		}
+	}
+
+	if digestSet && len(digestFrequency) > 0 {
+		val, err := model.ValidateDigestFrequency(digestFrequency[0])
+		if err != nil {
+			x.sessMgr.FlashAppend(ctx, "error", "Bad value for digest frequency")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+			return
+		}
+		if val != model.DigestNone && !user.Verified {
+			x.sessMgr.FlashAppend(ctx, "error", "Account must be verified before enabling digest emails")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+			return
+		}
+		if user.Settings.DigestFrequency != val {
+			changes = true
+			user.Settings.DigestFrequency = val
+		}
+	}
+
 	if !changes {
 		x.sessMgr.FlashAppend(ctx, "error", "no changes made")
 		http.Redirect(w, r, "/settings", http.StatusSeeOther)
@@ -526,20 +699,52 @@ func (x *Handler) AccountDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if errx := x.svc.DeleteUser(ctx, user.ID); errx != nil {
-		x.DBError(w, errx)
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return
+	}
+	transfers, err := eventTransfersFromForm(r.Form)
+	if err != nil {
+		x.BadFormDataError(w, err, "transfer")
 		return
 	}
+
+	deletion, errx := x.svc.ScheduleUserDeletion(ctx, user.ID, transfers)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.AlreadyExists:
+			x.BadRequestError(w, "Account deletion already scheduled")
+		case errs.InvalidArgument:
+			x.BadFormDataError(w, errx, "transfer")
+		case errs.NotFound:
+			x.NotFoundError(w)
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	if errx := x.sendAccountDeletionNotice(ctx, user, deletion); errx != nil {
+		// not a fatal error, since the deletion can still be cancelled
+		// by signing in again. just log the oddity
+		slog.ErrorContext(ctx, "error sending account deletion notice",
+			logger.Err(errx))
+	}
+
 	// destroy session
 	err = x.sessMgr.Destroy(ctx)
 	if err != nil {
-		// not a fatal error (do not return 500 to user), since the user deleted
-		// sucessfully already. just log the oddity
+		// not a fatal error (do not return 500 to user), since the deletion
+		// was scheduled sucessfully already. just log the oddity
 		slog.ErrorContext(ctx, "error destroying session",
 			logger.Err(err))
 	}
 	if htmx.Request(r).IsRequest() {
-		x.sessMgr.FlashAppend(ctx, "success", "Account deleted. Sorry to see you go.")
+		x.sessMgr.FlashAppend(ctx, "success",
+			fmt.Sprintf("Account scheduled for deletion on %s. Sign in again before then to cancel.",
+				deletion.Scheduled.Format("Jan 2, 2006")))
 		htmx.Response(w).HxLocation("/login")
 	}
 	w.WriteHeader(200)
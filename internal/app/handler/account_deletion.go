@@ -0,0 +1,232 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/k3a/html2text"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+const eventTransferFieldPrefix = "transfer-"
+
+// cancel links are signed over a prefixed refid, so the hmac of another
+// kind of link can not be used as a cancel link
+const accountDeletionCancelPrefix = "deletion-"
+
+// eventTransfersFromForm collects the requested event handovers. Each
+// is a form field named for the event refid, with the refid of the new
+// owner as the value. An empty value keeps the event for deletion.
+func eventTransfersFromForm(form url.Values) ([]*service.EventTransfer, error) {
+	transfers := make([]*service.EventTransfer, 0)
+	for key := range form {
+		eventRefIDStr, ok := strings.CutPrefix(key, eventTransferFieldPrefix)
+		if !ok {
+			continue
+		}
+		userRefIDStr := form.Get(key)
+		if userRefIDStr == "" {
+			continue
+		}
+		eventRefID, err := service.ParseEventRefID(eventRefIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("bad event ref-id: %w", err)
+		}
+		userRefID, err := service.ParseUserRefID(userRefIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("bad user ref-id: %w", err)
+		}
+		transfers = append(transfers, &service.EventTransfer{
+			EventRefID: eventRefID,
+			UserRefID:  userRefID,
+		})
+	}
+	return transfers, nil
+}
+
+// sendAccountDeletionNotice emails the user a link to cancel the
+// scheduled deletion of their account.
+func (x *Handler) sendAccountDeletionNotice(ctx context.Context,
+	user *model.User, deletion *model.UserDeletion,
+) errs.Error {
+	refIDStr := deletion.RefID.String()
+	macStr := encoder.Base32EncodeToString(
+		x.cMAC.Generate([]byte(accountDeletionCancelPrefix + refIDStr)))
+	cancelUrl, err := url.JoinPath(x.baseURL,
+		fmt.Sprintf("/account-deletion/%s-%s", refIDStr, macStr))
+	if err != nil {
+		return errs.Internal.Error("processing error")
+	}
+
+	subject := "Your account is scheduled for deletion"
+	var buf bytes.Buffer
+	err = x.TemplateExecute(&buf, "mail_account_deletion.gohtml",
+		MapSA{
+			"Subject":   subject,
+			"Scheduled": deletion.Scheduled.Format("Jan 2, 2006"),
+			"CancelUrl": cancelUrl,
+		},
+	)
+	if err != nil {
+		return errs.Internal.Error("template error")
+	}
+	messageHtml := buf.String()
+	messagePlain := html2text.HTML2Text(messageHtml)
+
+	slog.DebugContext(ctx, "email content",
+		slog.String("plain", messagePlain),
+		slog.String("html", messageHtml),
+	)
+
+	x.mailer.SendAsync("", []string{user.Email},
+		subject, messagePlain, messageHtml,
+		mail.MailHeader{
+			"X-PM-Message-Stream": "outbound",
+		},
+	)
+	return nil
+}
+
+func (x *Handler) AccountDeletionCancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	deletion, errx := x.svc.GetUserDeletion(ctx, user.ID)
+	if errx != nil {
+		if errx.Code() == errs.NotFound {
+			x.NotFoundError(w)
+			return
+		}
+		x.DBError(w, errx)
+		return
+	}
+
+	if errx := x.svc.CancelUserDeletion(ctx, deletion); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", "Account deletion cancelled.")
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+// deletionFromRequest checks the signature of a deletion cancel link, and
+// looks up the deletion it refers to.
+func (x *Handler) deletionFromRequest(w http.ResponseWriter, r *http.Request,
+) (*model.UserDeletion, bool) {
+	ctx := r.Context()
+
+	hmacStr := r.PathValue("hmac")
+	refIDStr := r.PathValue("udRefID")
+	if hmacStr == "" || refIDStr == "" {
+		slog.DebugContext(ctx, "missing url query data")
+		x.NotFoundError(w)
+		return nil, false
+	}
+
+	// decode hmac
+	hmacBytes, err := encoder.Base32DecodeString(hmacStr)
+	if err != nil {
+		slog.DebugContext(ctx, "error decoding hmac data", logger.Err(err))
+		x.BadRequestError(w, "Bad Request Data")
+		return nil, false
+	}
+	// check hmac
+	if !x.cMAC.Validate([]byte(accountDeletionCancelPrefix+refIDStr), hmacBytes) {
+		slog.DebugContext(ctx, "invalid hmac!")
+		x.BadRequestError(w, "Bad Request Data")
+		return nil, false
+	}
+
+	// hmac checks out. ok to parse refid now.
+	refID, err := service.ParseUserDeletionRefID(refIDStr)
+	if err != nil {
+		x.BadRefIDError(w, "account-deletion", err)
+		return nil, false
+	}
+
+	deletion, errx := x.svc.GetUserDeletionByRefID(ctx, refID)
+	if errx != nil {
+		if errx.Code() != errs.NotFound {
+			x.DBError(w, errx)
+			return nil, false
+		}
+		slog.DebugContext(ctx, "no deletion match", logger.Err(errx))
+		x.sessMgr.FlashAppend(ctx, "error",
+			"Cancel link is invalid, or the deletion was already cancelled.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil, false
+	}
+	return deletion, true
+}
+
+// AccountDeletionShowCancel shows a button to cancel the deletion, rather
+// than cancelling directly, so mail scanners that prefetch links do not
+// cancel it.
+func (x *Handler) AccountDeletionShowCancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deletion, ok := x.deletionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	user, errx := x.svc.GetUserByID(ctx, deletion.UserID)
+	if errx != nil {
+		x.DBError(w, errx)
+		return
+	}
+
+	tplVars := MapSA{
+		"title":     "Cancel Account Deletion",
+		"flashes":   x.sessMgr.FlashPopAll(ctx),
+		"email":     user.Email,
+		"scheduled": deletion.Scheduled,
+		"refID":     r.PathValue("udRefID"),
+		"hmac":      r.PathValue("hmac"),
+	}
+	w.Header().Set("content-type", "text/html")
+	err := x.TemplateExecute(w, "account-deletion-cancel.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
+
+func (x *Handler) AccountDeletionCancelByLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deletion, ok := x.deletionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if errx := x.svc.CancelUserDeletion(ctx, deletion); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", "Account deletion cancelled.")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
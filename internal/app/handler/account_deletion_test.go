@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dropwhile/assert"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_AccountDeletionCancel(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	deletion := &model.UserDeletion{
+		ID:        2,
+		RefID:     util.Must(model.NewUserDeletionRefID()),
+		UserID:    user.ID,
+		Scheduled: tstTs.Add(model.UserDeletionGracePeriod),
+		Created:   tstTs,
+	}
+
+	t.Run("cancel should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			GetUserDeletion(ctx, user.ID).
+			Return(deletion, nil)
+		mock.EXPECT().
+			CancelUserDeletion(ctx, deletion).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/deletion/cancel", nil)
+		rr := httptest.NewRecorder()
+		handler.AccountDeletionCancel(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "success"),
+			[]string{"Account deletion cancelled."})
+	})
+
+	t.Run("cancel with nothing scheduled should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			GetUserDeletion(ctx, user.ID).
+			Return(nil, errs.NotFound.Error("deletion not found"))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/deletion/cancel", nil)
+		rr := httptest.NewRecorder()
+		handler.AccountDeletionCancel(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+}
+
+func TestHandler_AccountDeletionCancelByLink(t *testing.T) {
+	t.Parallel()
+
+	deletion := &model.UserDeletion{
+		ID:        2,
+		RefID:     util.Must(model.NewUserDeletionRefID()),
+		UserID:    1,
+		Scheduled: tstTs.Add(model.UserDeletionGracePeriod),
+		Created:   tstTs,
+	}
+
+	t.Run("cancel by link should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		refIDStr := deletion.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(accountDeletionCancelPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserDeletionByRefID(ctx, deletion.RefID).
+			Return(deletion, nil)
+		mock.EXPECT().
+			CancelUserDeletion(ctx, deletion).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account-deletion", nil)
+		req.SetPathValue("udRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountDeletionCancelByLink(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "success"),
+			[]string{"Account deletion cancelled."})
+	})
+
+	t.Run("cancel by link with bad hmac should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		refIDStr := deletion.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte("hodor")))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account-deletion", nil)
+		req.SetPathValue("udRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountDeletionCancelByLink(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("cancel by link with unprefixed hmac should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		refIDStr := deletion.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refIDStr)))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account-deletion", nil)
+		req.SetPathValue("udRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountDeletionCancelByLink(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("cancel by link already cancelled should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		refIDStr := deletion.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(accountDeletionCancelPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserDeletionByRefID(ctx, deletion.RefID).
+			Return(nil, errs.NotFound.Error("deletion not found"))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account-deletion", nil)
+		req.SetPathValue("udRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountDeletionCancelByLink(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "error"),
+			[]string{"Cancel link is invalid, or the deletion was already cancelled."})
+	})
+}
@@ -5,10 +5,12 @@ package handler
 
 import (
 	"context"
+	"html/template"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/dropwhile/assert"
@@ -16,8 +18,10 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
 	"github.com/dropwhile/icanbringthat/internal/crypto"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 	"github.com/dropwhile/icanbringthat/internal/util"
@@ -88,15 +92,26 @@ func TestHandler_Account_Update(t *testing.T) {
 		user := &u
 		ctx = auth.ContextSet(ctx, "user", user)
 
+		handler.templates = &resources.TemplateMap{
+			"mail_email_change_confirm.gohtml": util.Must(
+				template.New("").Parse(`confirm: {{.ConfirmUrl}}`)),
+			"mail_email_change_notice.gohtml": util.Must(
+				template.New("").Parse(`revert: {{.RevertUrl}}`)),
+		}
+
 		email := "user2@example.com"
-		euvs := &service.UserUpdateValues{
-			Email:    mo.Some(email),
-			Verified: mo.Some(false),
+		change := &model.UserEmailChange{
+			ID:       1,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: user.Email,
+			NewEmail: email,
 		}
 
+		// the address is not updated until confirmed
 		mock.EXPECT().
-			UpdateUser(ctx, user, euvs).
-			Return(nil)
+			NewUserEmailChange(ctx, user, email).
+			Return(change, nil)
 
 		data := url.Values{"email": {email}}
 
@@ -113,10 +128,35 @@ func TestHandler_Account_Update(t *testing.T) {
 		assert.Equal(t,
 			messages,
 			map[string][]string{
-				"success": {"Email update successfull"},
+				"success": {"A confirmation link was sent to " + email},
 			},
 		)
 
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 2)
+		// confirmation link to the new address
+		assert.Equal(t, tm.Sent[0].To, []string{email})
+		after, found := strings.CutPrefix(tm.Sent[0].BodyPlain,
+			"confirm: http://example.com/settings/email/")
+		assert.True(t, found)
+		refIDStr, macStr, _ := strings.Cut(after, "-")
+		assert.Equal(t, refIDStr, change.RefID.String())
+		hmacBytes, err := encoder.Base32DecodeString(macStr)
+		assert.Nil(t, err)
+		assert.True(t, handler.cMAC.Validate([]byte(refIDStr), hmacBytes))
+		// revert link to the old address
+		assert.Equal(t, tm.Sent[1].To, []string{user.Email})
+		after, found = strings.CutPrefix(tm.Sent[1].BodyPlain,
+			"revert: http://example.com/email-change/")
+		assert.True(t, found)
+		refIDStr, macStr, _ = strings.Cut(after, "-")
+		assert.Equal(t, refIDStr, change.RefID.String())
+		hmacBytes, err = encoder.Base32DecodeString(macStr)
+		assert.Nil(t, err)
+		// a confirmation link is not a revert link
+		assert.Equal(t, handler.cMAC.Validate([]byte(refIDStr), hmacBytes), false)
+		assert.True(t, handler.cMAC.Validate([]byte("revert-"+refIDStr), hmacBytes))
+
 		// Check the status code is what we expect.
 		AssertStatusEqual(t, rr, http.StatusSeeOther)
 		assert.Equal(t, rr.Header().Get("location"), "/settings",
@@ -349,11 +389,17 @@ func TestHandler_Account_Update(t *testing.T) {
 		u := *user
 		user := &u
 		ctx = auth.ContextSet(ctx, "user", user)
+		sessRefID := util.Must(model.NewUserSessionRefID())
+		handler.sessMgr.Put(ctx, "session-id", sessRefID.String())
 
 		mock.EXPECT().
 			UpdateUser(ctx, user,
 				gomock.AssignableToTypeOf(&service.UserUpdateValues{})).
 			Return(nil)
+		// other sessions are logged out, the current one is kept
+		mock.EXPECT().
+			DeleteOtherUserSessions(ctx, user.ID, sessRefID).
+			Return(nil)
 
 		data := url.Values{
 			"password":         {"hodor"},
@@ -384,6 +430,48 @@ func TestHandler_Account_Update(t *testing.T) {
 			"handler returned wrong redirect")
 		// we make sure that all expectations were met
 	})
+
+	t.Run("update passwd with session revoke failure", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		// copy user to avoid context user being modified
+		// impacting future tests
+		u := *user
+		user := &u
+		ctx = auth.ContextSet(ctx, "user", user)
+		sessRefID := util.Must(model.NewUserSessionRefID())
+		handler.sessMgr.Put(ctx, "session-id", sessRefID.String())
+
+		mock.EXPECT().
+			UpdateUser(ctx, user,
+				gomock.AssignableToTypeOf(&service.UserUpdateValues{})).
+			Return(nil)
+		mock.EXPECT().
+			DeleteOtherUserSessions(ctx, user.ID, sessRefID).
+			Return(errs.Internal.Error("db error"))
+
+		data := url.Values{
+			"password":         {"hodor"},
+			"confirm_password": {"hodor"},
+			"old_password":     {"00x00"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.SettingsUpdate(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusInternalServerError)
+		// we make sure that all expectations were met
+	})
 }
 
 func TestHandler_Account_Update_Auth(t *testing.T) {
@@ -573,7 +661,7 @@ func TestHandler_Account_Update_Auth(t *testing.T) {
 			"handler returned wrong redirect")
 	})
 
-	t.Run("enable api access with no existing key should succeed", func(t *testing.T) {
+	t.Run("enable api access should succeed", func(t *testing.T) {
 		t.Parallel()
 
 		user := &model.User{
@@ -599,70 +687,6 @@ func TestHandler_Account_Update_Auth(t *testing.T) {
 			ApiAccess: mo.Some(true),
 		}
 
-		mock.EXPECT().
-			NewApiKeyIfNotExists(ctx, user.ID).
-			Return(&model.ApiKey{
-				UserID:  user.ID,
-				Token:   "some-token",
-				Created: tstTs,
-			}, nil)
-		mock.EXPECT().
-			UpdateUser(ctx, user, euvs).
-			Return(nil)
-
-		data := url.Values{"api_access": {"on"}}
-
-		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account", FormData(data))
-		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-		rr := httptest.NewRecorder()
-		handler.SettingsAuthApiUpdate(rr, req)
-
-		response := rr.Result()
-		_, err := io.ReadAll(response.Body)
-		assert.Nil(t, err)
-
-		messages := handler.sessMgr.FlashPopAll(ctx)
-		assert.Equal(t, len(messages["error"]), 0)
-
-		// Check the status code is what we expect.
-		AssertStatusEqual(t, rr, http.StatusSeeOther)
-		assert.Equal(t, rr.Header().Get("location"), "/settings",
-			"handler returned wrong redirect")
-	})
-
-	t.Run("enable api access with existing key should succeed", func(t *testing.T) {
-		t.Parallel()
-
-		user := &model.User{
-			ID:           1,
-			RefID:        refID,
-			Email:        "user@example.com",
-			Name:         "user",
-			PWHash:       pwhash,
-			Verified:     true,
-			PWAuth:       true,
-			ApiAccess:    false,
-			WebAuthn:     false,
-			Created:      ts,
-			LastModified: ts,
-		}
-
-		ctx := context.TODO()
-		mock, _, handler := SetupHandler(t, ctx)
-		ctx, _ = handler.sessMgr.Load(ctx, "")
-		ctx = auth.ContextSet(ctx, "user", user)
-
-		euvs := &service.UserUpdateValues{
-			ApiAccess: mo.Some(true),
-		}
-
-		mock.EXPECT().
-			NewApiKeyIfNotExists(ctx, user.ID).
-			Return(&model.ApiKey{
-				UserID:  user.ID,
-				Token:   "some-token",
-				Created: tstTs,
-			}, nil)
 		mock.EXPECT().
 			UpdateUser(ctx, user, euvs).
 			Return(nil)
@@ -729,7 +753,7 @@ func TestHandler_Account_Update_Auth(t *testing.T) {
 			"handler returned wrong redirect")
 	})
 
-	t.Run("rotate api key should succeed", func(t *testing.T) {
+	t.Run("disable api access should succeed", func(t *testing.T) {
 		t.Parallel()
 
 		user := &model.User{
@@ -740,7 +764,7 @@ func TestHandler_Account_Update_Auth(t *testing.T) {
 			PWHash:       pwhash,
 			Verified:     true,
 			PWAuth:       true,
-			ApiAccess:    false,
+			ApiAccess:    true,
 			WebAuthn:     false,
 			Created:      ts,
 			LastModified: ts,
@@ -751,15 +775,15 @@ func TestHandler_Account_Update_Auth(t *testing.T) {
 		ctx, _ = handler.sessMgr.Load(ctx, "")
 		ctx = auth.ContextSet(ctx, "user", user)
 
+		euvs := &service.UserUpdateValues{
+			ApiAccess: mo.Some(false),
+		}
+
 		mock.EXPECT().
-			NewApiKey(ctx, user.ID).
-			Return(&model.ApiKey{
-				UserID:  user.ID,
-				Token:   "new-api-key",
-				Created: tstTs,
-			}, nil)
+			UpdateUser(ctx, user, euvs).
+			Return(nil)
 
-		data := url.Values{"rotate_apikey": {"true"}}
+		data := url.Values{"api_access": {"off"}}
 
 		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/account", FormData(data))
 		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
@@ -794,27 +818,145 @@ func TestHandler_Account_Delete(t *testing.T) {
 		Created:      ts,
 		LastModified: ts,
 	}
+	deletion := &model.UserDeletion{
+		ID:        2,
+		RefID:     util.Must(model.NewUserDeletionRefID()),
+		UserID:    user.ID,
+		Scheduled: ts.Add(model.UserDeletionGracePeriod),
+		Created:   ts,
+	}
+	deletionTpl := util.Must(template.New("").Parse(
+		`{{.Scheduled}}: {{.CancelUrl}}`))
+
+	t.Run("schedule deletion", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		handler.templates = &resources.TemplateMap{
+			"mail_account_deletion.gohtml": deletionTpl,
+		}
+
+		mock.EXPECT().
+			ScheduleUserDeletion(ctx, user.ID, []*service.EventTransfer{}).
+			Return(deletion, nil)
 
-	ctx := context.TODO()
-	mock, _, handler := SetupHandler(t, ctx)
-	ctx, _ = handler.sessMgr.Load(ctx, "")
-	ctx = auth.ContextSet(ctx, "user", user)
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/account", nil)
+		rr := httptest.NewRecorder()
+		handler.AccountDelete(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 1)
+		assert.Equal(t, tm.Sent[0].To, []string{user.Email})
+		after, found := strings.CutPrefix(tm.Sent[0].BodyPlain,
+			deletion.Scheduled.Format("Jan 2, 2006")+": http://example.com/account-deletion/")
+		assert.True(t, found)
+		refIDStr, macStr, _ := strings.Cut(after, "-")
+		assert.Equal(t, refIDStr, deletion.RefID.String())
+		hmacBytes, err := encoder.Base32DecodeString(macStr)
+		assert.Nil(t, err)
+		assert.True(t, handler.cMAC.Validate(
+			[]byte(accountDeletionCancelPrefix+refIDStr), hmacBytes))
+		// we make sure that all expectations were met
+	})
+
+	t.Run("schedule deletion with transfer", func(t *testing.T) {
+		t.Parallel()
 
-	mock.EXPECT().
-		DeleteUser(ctx, user.ID).
-		Return(nil)
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		handler.templates = &resources.TemplateMap{
+			"mail_account_deletion.gohtml": deletionTpl,
+		}
+
+		eventRefID := util.Must(model.NewEventRefID())
+		guestRefID := util.Must(model.NewUserRefID())
 
-	req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/account", nil)
-	rr := httptest.NewRecorder()
-	handler.AccountDelete(rr, req)
+		mock.EXPECT().
+			ScheduleUserDeletion(ctx, user.ID, []*service.EventTransfer{
+				{EventRefID: eventRefID, UserRefID: guestRefID},
+			}).
+			Return(deletion, nil)
 
-	response := rr.Result()
-	_, err := io.ReadAll(response.Body)
-	assert.Nil(t, err)
+		data := url.Values{
+			"transfer-" + eventRefID.String(): {guestRefID.String()},
+		}
+		req, _ := http.NewRequestWithContext(ctx, "DELETE",
+			"http://example.com/account?"+data.Encode(), nil)
+		rr := httptest.NewRecorder()
+		handler.AccountDelete(rr, req)
 
-	// Check the status code is what we expect.
-	AssertStatusEqual(t, rr, http.StatusOK)
-	// we make sure that all expectations were met
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+		// we make sure that all expectations were met
+	})
+
+	t.Run("schedule deletion with bad transfer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		data := url.Values{
+			"transfer-" + util.Must(model.NewEventRefID()).String(): {"hodor"},
+		}
+		req, _ := http.NewRequestWithContext(ctx, "DELETE",
+			"http://example.com/account?"+data.Encode(), nil)
+		rr := httptest.NewRecorder()
+		handler.AccountDelete(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+		// we make sure that all expectations were met
+	})
+
+	t.Run("schedule deletion already scheduled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			ScheduleUserDeletion(ctx, user.ID, []*service.EventTransfer{}).
+			Return(nil, errs.AlreadyExists.Error("deletion already scheduled"))
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/account", nil)
+		rr := httptest.NewRecorder()
+		handler.AccountDelete(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 0)
+		// we make sure that all expectations were met
+	})
 }
 
 func TestHandler_Account_Create(t *testing.T) {
@@ -841,17 +983,28 @@ func TestHandler_Account_Create(t *testing.T) {
 		mock, _, handler := SetupHandler(t, ctx)
 		ctx, _ = handler.sessMgr.Load(ctx, "")
 
-		msg := `Account is not currently verified. Please verify account in link:/settings.`
+		msg := `Account is not currently verified. Please verify account in Account Settings.`
 
 		mock.EXPECT().
 			NewUser(ctx, user.Email, user.Name, []byte("00x00")).
 			Return(user, nil)
 		mock.EXPECT().
-			NewNotification(ctx, user.ID, msg).
+			NewUserSession(ctx, user.ID, "", "", gomock.Any()).
+			Return(&model.UserSession{
+				ID:     1,
+				UserID: user.ID,
+				RefID:  util.Must(model.NewUserSessionRefID()),
+			}, nil)
+		mock.EXPECT().
+			NewTypedNotification(ctx, user.ID,
+				model.NotificationKindAccountUnverified,
+				model.NotificationPayload{},
+			).
 			Return(&model.Notification{
 				ID:      1,
 				RefID:   model.NotificationRefID{},
 				UserID:  user.ID,
+				Kind:    model.NotificationKindAccountUnverified,
 				Message: msg,
 				Read:    false,
 			}, nil)
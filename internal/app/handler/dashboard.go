@@ -21,7 +21,7 @@ func (x *Handler) DashboardShow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notifCount, errx := x.svc.GetNotificationsCount(ctx, user.ID)
+	notifCount, errx := x.svc.GetNotificationsUnreadCount(ctx, user.ID)
 	if errx != nil {
 		x.InternalServerError(w, errx.Msg())
 		return
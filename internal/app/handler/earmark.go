@@ -30,7 +30,7 @@ func (x *Handler) EarmarksList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notifCount, errx := x.svc.GetNotificationsCount(ctx, user.ID)
+	notifCount, errx := x.svc.GetNotificationsUnreadCount(ctx, user.ID)
 	if errx != nil {
 		x.DBError(w, errx)
 		return
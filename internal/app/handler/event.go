@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/samber/mo"
@@ -34,7 +35,7 @@ func (x *Handler) EventsList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notifCount, errx := x.svc.GetNotificationsCount(ctx, user.ID)
+	notifCount, errx := x.svc.GetNotificationsUnreadCount(ctx, user.ID)
 	if errx != nil {
 		x.DBError(w, errx)
 		return
@@ -137,7 +138,7 @@ func (x *Handler) EventShow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notifCount, errx := x.svc.GetNotificationsCount(ctx, user.ID)
+	notifCount, errx := x.svc.GetNotificationsUnreadCount(ctx, user.ID)
 	if errx != nil {
 		x.DBError(w, errx)
 		return
@@ -168,20 +169,7 @@ func (x *Handler) EventShow(w http.ResponseWriter, r *http.Request) {
 					return fmt.Sprintf("%v", event.ItemSortOrder)
 				})),
 		)
-		sortSet := util.ToSetIndexed(event.ItemSortOrder)
-		sortedList := make([]*model.EventItem, len(event.ItemSortOrder))
-		unsortedList := make([]*model.EventItem, 0)
-
-		eventItemLen := len(eventItems)
-		for j := range eventItems {
-			if idx, ok := sortSet[eventItems[j].ID]; ok && idx < eventItemLen {
-				sortedList[idx] = eventItems[j]
-			} else {
-				unsortedList = append(unsortedList, eventItems[j])
-			}
-		}
-		// put unsorted (likely new) items at the front of the list
-		eventItems = append(unsortedList, sortedList...)
+		eventItems = service.SortEventItems(eventItems, event.ItemSortOrder)
 	}
 
 	earmarks, errx := x.svc.GetEarmarksByEventID(ctx, event.ID)
@@ -317,6 +305,7 @@ func (x *Handler) EventShowEditForm(w http.ResponseWriter, r *http.Request) {
 	}
 	// render user profile view
 	w.Header().Set("content-type", "text/html")
+	w.Header().Set("ETag", strconv.Quote(event.Version()))
 	if htmx.Request(r).Target() == "modalbody" {
 		err = x.TemplateExecuteSub(w, "edit-event-form.gohtml", "form", tplVars)
 	} else {
@@ -440,11 +429,16 @@ func (x *Handler) EventUpdate(w http.ResponseWriter, r *http.Request) {
 		euvs.Tz = mo.Some(loc.String())
 	}
 
+	version, ifMatch := expectedVersion(r)
+	euvs.Version = version
+
 	errx := x.svc.UpdateEvent(ctx, user.ID, refID, euvs)
 	if errx != nil {
 		switch errx.Code() {
 		case errs.NotFound:
 			x.NotFoundError(w)
+		case errs.Aborted:
+			x.StaleVersionError(w, errx.Meta("version"), ifMatch)
 		case errs.FailedPrecondition:
 			x.BadFormDataError(w, errx)
 		case errs.PermissionDenied:
@@ -460,6 +454,20 @@ func (x *Handler) EventUpdate(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, fmt.Sprintf("/events/%s", refID), http.StatusSeeOther)
 }
 
+// expectedVersion returns the version a client expects to be updating,
+// from an If-Match header or else the "version" form field. ifMatch
+// reports whether it came from the header.
+func expectedVersion(r *http.Request) (version mo.Option[string], ifMatch bool) {
+	if v := r.Header.Get("If-Match"); v != "" && v != "*" {
+		v = strings.TrimPrefix(v, "W/")
+		return mo.Some(strings.Trim(v, `"`)), true
+	}
+	if v := r.PostFormValue("version"); v != "" {
+		return mo.Some(v), false
+	}
+	return mo.None[string](), false
+}
+
 func (x *Handler) EventItemSortingUpdate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
@@ -133,6 +134,7 @@ func (x *Handler) EventItemShowEditForm(w http.ResponseWriter, r *http.Request)
 	}
 	// render user profile view
 	w.Header().Set("content-type", "text/html")
+	w.Header().Set("ETag", strconv.Quote(eventItem.Version()))
 	if htmx.Request(r).Target() == "modalbody" {
 		err = x.TemplateExecuteSub(w, "edit-eventitem-form.gohtml", "form", tplVars)
 	} else {
@@ -235,8 +237,9 @@ func (x *Handler) EventItemUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ifMatch := expectedVersion(r)
 	_, errx = x.svc.UpdateEventItem(
-		ctx, user.ID, eventItemRefID, description,
+		ctx, user.ID, eventItemRefID, description, version,
 		func(ei *model.EventItem) bool {
 			return ei.EventID != event.ID
 		},
@@ -255,6 +258,8 @@ func (x *Handler) EventItemUpdate(w http.ResponseWriter, r *http.Request) {
 			x.NotFoundError(w)
 		case errs.NotFound:
 			x.NotFoundError(w)
+		case errs.Aborted:
+			x.StaleVersionError(w, errx.Meta("version"), ifMatch)
 		case errs.PermissionDenied:
 			x.AccessDeniedError(w)
 		default:
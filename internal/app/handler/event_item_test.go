@@ -11,9 +11,10 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/dropwhile/assert"
 	"github.com/go-chi/chi/v5"
+	"github.com/samber/mo"
 	"go.uber.org/mock/gomock"
-	"github.com/dropwhile/assert"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
@@ -252,7 +253,7 @@ func TestHandler_EventItem_Update(t *testing.T) {
 			GetEvent(ctx, event.RefID).
 			Return(event, nil)
 		mock.EXPECT().
-			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, gomock.Any()).
+			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), gomock.Any()).
 			Return(eventItem, nil)
 
 		data := url.Values{"description": {description}}
@@ -346,7 +347,7 @@ func TestHandler_EventItem_Update(t *testing.T) {
 			GetEvent(ctx, event.RefID).
 			Return(event, nil)
 		mock.EXPECT().
-			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, gomock.Any()).
+			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), gomock.Any()).
 			Return(nil, errs.NotFound.Error("event-item not found"))
 
 		data := url.Values{"description": {description}}
@@ -382,7 +383,7 @@ func TestHandler_EventItem_Update(t *testing.T) {
 			GetEvent(ctx, event.RefID).
 			Return(event, nil)
 		mock.EXPECT().
-			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, gomock.Any()).
+			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), gomock.Any()).
 			Return(nil, errs.PermissionDenied.Error("not event owner"))
 
 		data := url.Values{"description": {description}}
@@ -418,7 +419,7 @@ func TestHandler_EventItem_Update(t *testing.T) {
 			GetEvent(ctx, event.RefID).
 			Return(event, nil)
 		mock.EXPECT().
-			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, gomock.Any()).
+			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), gomock.Any()).
 			Return(nil, errs.PermissionDenied.Error("event is archived"))
 
 		data := url.Values{"description": {description}}
@@ -454,7 +455,7 @@ func TestHandler_EventItem_Update(t *testing.T) {
 			GetEvent(ctx, event.RefID).
 			Return(event, nil)
 		mock.EXPECT().
-			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, gomock.Any()).
+			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), gomock.Any()).
 			Return(nil, errs.PermissionDenied.Error("earmarked by other user"))
 
 		data := url.Values{"description": {description}}
@@ -528,12 +529,13 @@ func TestHandler_EventItem_Update(t *testing.T) {
 			GetEvent(ctx, event.RefID).
 			Return(event, nil)
 		mock.EXPECT().
-			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, gomock.Any()).
+			UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), gomock.Any()).
 			DoAndReturn(
 				func(
 					_ctx context.Context, _userID int,
 					_eventItemRefID model.EventItemRefID,
 					_description string,
+					_version mo.Option[string],
 					f func(*model.EventItem) bool,
 				) (*model.EventItem, errs.Error) {
 					if f(eventItem) {
@@ -418,6 +418,83 @@ func TestHandler_Event_Update(t *testing.T) {
 		// we make sure that all expectations were met
 	})
 
+	t.Run("update event with stale if-match should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		euvs := &service.EventUpdateValues{
+			Name:    mo.Some("new name"),
+			Version: mo.Some("stale"),
+		}
+
+		mock.EXPECT().
+			UpdateEvent(ctx, user.ID, event.RefID, euvs).
+			Return(errs.Aborted.Error("event was modified").
+				WithMeta("version", event.Version()))
+
+		data := url.Values{
+			"name": {"new name"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/event", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("If-Match", `"stale"`)
+		req.SetPathValue("eRefID", event.RefID.String())
+		rr := httptest.NewRecorder()
+		handler.EventUpdate(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusPreconditionFailed)
+		assert.Equal(t, rr.Header().Get("ETag"), `"`+event.Version()+`"`)
+	})
+
+	t.Run("update event with stale form version should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		euvs := &service.EventUpdateValues{
+			Name:    mo.Some("new name"),
+			Version: mo.Some("stale"),
+		}
+
+		mock.EXPECT().
+			UpdateEvent(ctx, user.ID, event.RefID, euvs).
+			Return(errs.Aborted.Error("event was modified").
+				WithMeta("version", event.Version()))
+
+		data := url.Values{
+			"name":    {"new name"},
+			"version": {"stale"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/event", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("eRefID", event.RefID.String())
+		rr := httptest.NewRecorder()
+		handler.EventUpdate(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusConflict)
+	})
+
 	t.Run("update event bad refid should fail", func(t *testing.T) {
 		t.Parallel()
 
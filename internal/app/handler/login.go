@@ -4,13 +4,26 @@
 package handler
 
 import (
+	"bytes"
+	"context"
 	"log/slog"
+	"net"
 	"net/http"
+	"time"
 
+	"github.com/k3a/html2text"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/mail"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 )
 
+// how long a user has to provide a second factor, after the password
+// step of a login
+const totpLoginTimeout = 5 * time.Minute
+
 func (x *Handler) LoginShowForm(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -22,10 +35,17 @@ func (x *Handler) LoginShowForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	oidcName := ""
+	if x.oidc != nil {
+		oidcName = x.oidc.Name()
+	}
+
 	tplVars := MapSA{
-		"title":   "Login",
-		"next":    r.FormValue("next"),
-		"flashes": x.sessMgr.FlashPopAll(ctx),
+		"title":      "Login",
+		"next":       r.FormValue("next"),
+		"oidcName":   oidcName,
+		"rememberMe": x.rememberMe,
+		"flashes":    x.sessMgr.FlashPopAll(ctx),
 	}
 	// render user profile view
 	w.Header().Set("content-type", "text/html")
@@ -69,6 +89,20 @@ func (x *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a locked out account gets the same response as an unknown email,
+	// so the lockout does not reveal which addresses have an account
+	if errx := x.svc.CheckLoginAllowed(ctx, user.ID); errx != nil {
+		if errx.Code() != errs.ResourceExhausted {
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+		slog.DebugContext(ctx, "invalid credentials: locked out",
+			slog.Int("userID", user.ID))
+		x.sessMgr.FlashAppend(ctx, "error", "Invalid credentials")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
 	if !user.PWAuth {
 		// no valid auth flow
 		slog.WarnContext(ctx,
@@ -82,12 +116,24 @@ func (x *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		// validate credentials...
 		if !service.CheckPass(ctx, user.PWHash, []byte(passwd)) {
 			slog.DebugContext(ctx, "invalid credentials: pass check fail", "error", err)
+			x.recordLoginFailure(r, user.ID, model.LoginMethodPassword)
 			x.sessMgr.FlashAppend(ctx, "error", "Invalid credentials")
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
 		}
 	}
 
+	target := "/dashboard"
+	if r.PostFormValue("next") != "" {
+		target = r.FormValue("next")
+	}
+
+	totpEnabled, errx := x.svc.IsTOTPEnabled(ctx, user.ID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
 	// renew sesmgr token to help prevent session fixation. ref:
 	//   https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/Session_Management_Cheat_Sheet.md
 	//   #renew-the-session-id-after-any-privilege-level-change
@@ -96,12 +142,160 @@ func (x *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		x.InternalServerError(w, "Session Error")
 		return
 	}
+	x.rememberLogin(ctx, r)
+
+	if totpEnabled {
+		// password checked out, but a second factor is still needed
+		// before the user is logged in.
+		x.startTOTPLogin(ctx, user.ID, target)
+		http.Redirect(w, r, "/login/totp", http.StatusSeeOther)
+		return
+	}
+
 	// Then make the privilege-level change.
-	x.sessMgr.Put(r.Context(), "user-id", user.ID)
-	target := "/dashboard"
-	if r.PostFormValue("next") != "" {
-		target = r.FormValue("next")
+	if errx := x.startUserSession(r, user); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+	x.recordLoginSuccess(r, user, model.LoginMethodPassword)
+	x.sessMgr.FlashAppend(ctx, "success", "Login successful")
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// startTOTPLogin records that userID passed the first step of a login,
+// and must provide a second factor at /login/totp before being logged in.
+func (x *Handler) startTOTPLogin(ctx context.Context, userID int, target string) {
+	x.sessMgr.Put(ctx, "totp-user-id", userID)
+	x.sessMgr.Put(ctx, "totp-started", time.Now().UTC())
+	x.sessMgr.Put(ctx, "totp-next", target)
+}
+
+// pendingTOTPUserID returns the id of the user that passed the password
+// step of a login, and has yet to provide a second factor. Zero is
+// returned if there is no such login in progress, or it took too long.
+func (x *Handler) pendingTOTPUserID(ctx context.Context) int {
+	userID := x.sessMgr.GetInt(ctx, "totp-user-id")
+	if userID == 0 {
+		return 0
+	}
+	started := x.sessMgr.GetTime(ctx, "totp-started")
+	if time.Since(started) > totpLoginTimeout {
+		x.clearPendingTOTP(ctx)
+		return 0
+	}
+	return userID
+}
+
+func (x *Handler) clearPendingTOTP(ctx context.Context) {
+	x.sessMgr.Remove(ctx, "totp-user-id")
+	x.sessMgr.Remove(ctx, "totp-started")
+	x.sessMgr.Remove(ctx, "totp-next")
+}
+
+func (x *Handler) LoginTOTPShowForm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	if x.pendingTOTPUserID(ctx) == 0 {
+		x.sessMgr.FlashAppend(ctx, "error", "Login expired. Please log in again.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	tplVars := MapSA{
+		"title":   "Two-factor authentication",
+		"flashes": x.sessMgr.FlashPopAll(ctx),
+	}
+	w.Header().Set("content-type", "text/html")
+	err = x.TemplateExecute(w, "login-totp-form.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
+
+func (x *Handler) LoginTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return
 	}
+
+	code := r.PostFormValue("code")
+	if code == "" {
+		x.BadFormDataError(w, nil, "code")
+		return
+	}
+
+	userID := x.pendingTOTPUserID(ctx)
+	if userID == 0 {
+		x.sessMgr.FlashAppend(ctx, "error", "Login expired. Please log in again.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !x.checkLoginAllowed(w, r, userID) {
+		x.clearPendingTOTP(ctx)
+		return
+	}
+
+	errx := x.svc.VerifyTOTP(ctx, userID, code)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied, errs.InvalidArgument:
+			slog.DebugContext(ctx, "invalid credentials: totp check fail",
+				"userID", userID)
+			x.recordLoginFailure(r, userID, model.LoginMethodTOTP)
+			x.sessMgr.FlashAppend(ctx, "error", "Invalid two-factor code")
+			http.Redirect(w, r, "/login/totp", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	user, errx := x.svc.GetUserByID(ctx, userID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	target := x.sessMgr.GetString(ctx, "totp-next")
+	if target == "" {
+		target = "/dashboard"
+	}
+	x.clearPendingTOTP(ctx)
+
+	// renew sesmgr token to help prevent session fixation. ref:
+	//   https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/Session_Management_Cheat_Sheet.md
+	//   #renew-the-session-id-after-any-privilege-level-change
+	err = x.sessMgr.RenewToken(ctx)
+	if err != nil {
+		x.InternalServerError(w, "Session Error")
+		return
+	}
+	// Then make the privilege-level change.
+	if errx := x.startUserSession(r, user); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+	x.recordLoginSuccess(r, user, model.LoginMethodTOTP)
 	x.sessMgr.FlashAppend(ctx, "success", "Login successful")
 	http.Redirect(w, r, target, http.StatusSeeOther)
 }
@@ -109,6 +303,17 @@ func (x *Handler) Login(w http.ResponseWriter, r *http.Request) {
 func (x *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// drop the session from the index, so it no longer shows as active
+	if user, err := auth.UserFromContext(ctx); err == nil {
+		if refID, ok := x.currentSessionRefID(ctx); ok {
+			errx := x.svc.DeleteUserSession(ctx, user.ID, refID)
+			if errx != nil && errx.Code() != errs.NotFound {
+				slog.ErrorContext(ctx, "error removing session",
+					"userID", user.ID, "error", errx)
+			}
+		}
+	}
+
 	if err := x.sessMgr.Clear(r.Context()); err != nil {
 		x.InternalServerError(w, "Session Error")
 		return
@@ -124,3 +329,91 @@ func (x *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 	x.sessMgr.FlashAppend(ctx, "success", "Logout successful")
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
+
+// loginSource returns the client address and user agent that a login
+// attempt is recorded against.
+func loginSource(r *http.Request) (string, string) {
+	ipAddress, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ipAddress = r.RemoteAddr
+	}
+	userAgent := r.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	return ipAddress, userAgent
+}
+
+// checkLoginAllowed refuses the login, if recent failed attempts have
+// locked userID out for now.
+func (x *Handler) checkLoginAllowed(w http.ResponseWriter, r *http.Request, userID int) bool {
+	ctx := r.Context()
+	errx := x.svc.CheckLoginAllowed(ctx, userID)
+	switch {
+	case errx == nil:
+		return true
+	case errx.Code() == errs.ResourceExhausted:
+		x.sessMgr.FlashAppend(ctx, "error",
+			"Too many failed login attempts. Please try again later.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	default:
+		x.InternalServerError(w, errx.Msg())
+	}
+	return false
+}
+
+func (x *Handler) recordLoginFailure(r *http.Request, userID int, method model.LoginMethod) {
+	ctx := r.Context()
+	ipAddress, userAgent := loginSource(r)
+	errx := x.svc.RecordLoginFailure(ctx, userID, method, ipAddress, userAgent)
+	if errx != nil {
+		slog.ErrorContext(ctx, "error recording login failure",
+			"userID", userID, "error", errx)
+	}
+}
+
+// recordLoginSuccess records a successful login, and lets the user know
+// by email if it came from a new device or location.
+func (x *Handler) recordLoginSuccess(r *http.Request, user *model.User, method model.LoginMethod) {
+	ctx := r.Context()
+	ipAddress, userAgent := loginSource(r)
+	newSource, errx := x.svc.RecordLoginSuccess(
+		ctx, user.ID, method, ipAddress, userAgent)
+	if errx != nil {
+		slog.ErrorContext(ctx, "error recording login success",
+			"userID", user.ID, "error", errx)
+		return
+	}
+	if newSource {
+		x.sendNewSignInEmail(ctx, user, ipAddress, userAgent)
+	}
+}
+
+func (x *Handler) sendNewSignInEmail(
+	ctx context.Context, user *model.User, ipAddress, userAgent string,
+) {
+	subject := "New sign-in to your account"
+	var buf bytes.Buffer
+	err := x.TemplateExecute(&buf, "mail_new_sign_in.gohtml",
+		MapSA{
+			"Subject":     subject,
+			"IPAddress":   ipAddress,
+			"UserAgent":   userAgent,
+			"Time":        time.Now().UTC().Format(time.RFC1123),
+			"SettingsUrl": x.baseURL + "/settings",
+		},
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "error rendering new sign-in email", "error", err)
+		return
+	}
+	messageHtml := buf.String()
+	messagePlain := html2text.HTML2Text(messageHtml)
+
+	x.mailer.SendAsync("", []string{user.Email},
+		subject, messagePlain, messageHtml,
+		mail.MailHeader{
+			"X-PM-Message-Stream": "outbound",
+		},
+	)
+}
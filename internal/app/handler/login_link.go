@@ -0,0 +1,305 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/k3a/html2text"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+func (x *Handler) LoginLinkShowForm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	tplVars := MapSA{
+		"title":    "Email Sign-in Link",
+		"next":     r.FormValue("next"),
+		"remember": r.FormValue("remember") != "",
+		"flashes":  x.sessMgr.FlashPopAll(ctx),
+	}
+	w.Header().Set("content-type", "text/html")
+	err = x.TemplateExecute(w, "login-link-form.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
+
+func (x *Handler) LoginLinkSendEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// attempt to get user from session
+	if _, err := auth.UserFromContext(ctx); err == nil {
+		// already a logged in user
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	email := r.PostFormValue("email")
+	if email == "" {
+		x.BadFormDataError(w, nil, "email")
+		return
+	}
+
+	// don't leak existence of user. if email doesn't match,
+	// behave like we sent a link anyway...
+	doFake := false
+	user, errx := x.svc.GetUserByEmail(ctx, email)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.NotFound:
+			slog.InfoContext(ctx, "no user found", "error", errx)
+			doFake = true
+		default:
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+	}
+
+	// an emailed link is as good as a password reset, so only allow it
+	// when password auth is enabled. otherwise behave the same as
+	// faking it.
+	if !doFake && !user.PWAuth {
+		doFake = true
+	}
+
+	if doFake {
+		slog.InfoContext(ctx,
+			"pretending to send login link email",
+			slog.String("email", email),
+		)
+	} else {
+		link, errx := x.svc.NewUserLoginLink(ctx, user.ID)
+		if errx != nil {
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+		linkRefIDStr := link.RefID.String()
+
+		// generate hmac
+		macBytes := x.cMAC.Generate([]byte(linkRefIDStr))
+		// base32 encode hmac
+		macStr := encoder.Base32EncodeToString(macBytes)
+
+		// construct url. built from the configured base url, never the
+		// request host, as the link is as good as a password.
+		loginLinkUrl, err := url.JoinPath(x.baseURL,
+			fmt.Sprintf("/login/link/%s-%s", linkRefIDStr, macStr))
+		if err != nil {
+			x.InternalServerError(w, "processing error")
+			return
+		}
+		if next := localTarget(r.PostFormValue("next"), ""); next != "" {
+			loginLinkUrl += "?" + url.Values{"next": {next}}.Encode()
+		}
+
+		// construct email
+		subject := "Your sign-in link"
+		var buf bytes.Buffer
+		err = x.TemplateExecute(&buf, "mail_login_link.gohtml",
+			MapSA{
+				"Subject":      subject,
+				"LoginLinkUrl": loginLinkUrl,
+			},
+		)
+		if err != nil {
+			x.TemplateError(w)
+			return
+		}
+		messageHtml := buf.String()
+		messagePlain := html2text.HTML2Text(messageHtml)
+
+		x.mailer.SendAsync("", []string{user.Email},
+			subject, messagePlain, messageHtml,
+			mail.MailHeader{
+				"X-PM-Message-Stream": "outbound",
+			},
+		)
+	}
+
+	// only applies if the link is opened in this same browser
+	x.rememberLogin(ctx, r)
+	x.sessMgr.FlashAppend(ctx, "success", "Sign-in link sent. Please check your email.")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// loginLinkFromRequest checks the hmac of the login link in the request
+// path, returning the parsed login link ref id if it is valid.
+func (x *Handler) loginLinkFromRequest(w http.ResponseWriter, r *http.Request) (model.UserLoginLinkRefID, bool) {
+	ctx := r.Context()
+	var refID model.UserLoginLinkRefID
+
+	hmacStr := r.PathValue("hmac")
+	refIDStr := r.PathValue("lnkRefID")
+	if hmacStr == "" || refIDStr == "" {
+		slog.DebugContext(ctx, "missing url query data")
+		x.NotFoundError(w)
+		return refID, false
+	}
+
+	// decode hmac
+	hmacBytes, err := encoder.Base32DecodeString(hmacStr)
+	if err != nil {
+		slog.DebugContext(ctx, "error decoding hmac data", "error", err)
+		x.BadRequestError(w, "Bad Request Data")
+		return refID, false
+	}
+	// check hmac
+	if !x.cMAC.Validate([]byte(refIDStr), hmacBytes) {
+		slog.DebugContext(ctx, "invalid hmac!")
+		x.BadRequestError(w, "Bad Request Data")
+		return refID, false
+	}
+
+	// hmac checks out. ok to parse refid now.
+	refID, err = service.ParseUserLoginLinkRefID(refIDStr)
+	if err != nil {
+		x.BadRefIDError(w, "login-link", err)
+		return refID, false
+	}
+	return refID, true
+}
+
+// LoginLinkShowConfirm shows a button to complete the login, rather than
+// logging in directly. Mail scanners that prefetch links would otherwise
+// use up the single-use link.
+func (x *Handler) LoginLinkShowConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	refID, ok := x.loginLinkFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	link, errx := x.svc.GetUserLoginLinkByRefID(ctx, refID)
+	if errx != nil || service.IsTimerExpired(link.RefID, model.UserLoginLinkExpiry) {
+		slog.DebugContext(ctx, "login link not found or expired", "error", errx)
+		x.sessMgr.FlashAppend(ctx, "error", "Sign-in link is invalid or has expired.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	tplVars := MapSA{
+		"title":   "Sign In",
+		"next":    r.FormValue("next"),
+		"flashes": x.sessMgr.FlashPopAll(ctx),
+		"refID":   r.PathValue("lnkRefID"),
+		"hmac":    r.PathValue("hmac"),
+	}
+	w.Header().Set("content-type", "text/html")
+	err = x.TemplateExecute(w, "login-link-confirm.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
+
+func (x *Handler) LoginLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	refID, ok := x.loginLinkFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	link, errx := x.svc.ConsumeUserLoginLink(ctx, refID)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.NotFound:
+			slog.DebugContext(ctx, "login link not usable", "error", errx)
+			x.sessMgr.FlashAppend(ctx, "error", "Sign-in link is invalid or has expired.")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	user, errx := x.svc.GetUserByID(ctx, link.UserID)
+	if errx != nil {
+		slog.DebugContext(ctx, "no user match", "error", errx)
+		x.BadRequestError(w, "Bad Request Data")
+		return
+	}
+
+	// password auth may have been disabled since the link was sent
+	if !user.PWAuth {
+		slog.InfoContext(ctx, "login link used but pw auth disabled")
+		x.sessMgr.FlashAppend(ctx, "error", "Sign-in link is invalid or has expired.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !x.checkLoginAllowed(w, r, user.ID) {
+		return
+	}
+
+	target := localTarget(r.FormValue("next"), "/dashboard")
+
+	totpEnabled, errx := x.svc.IsTOTPEnabled(ctx, user.ID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	// renew sesmgr token to help prevent session fixation. ref:
+	//   https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/Session_Management_Cheat_Sheet.md
+	//   #renew-the-session-id-after-any-privilege-level-change
+	err = x.sessMgr.RenewToken(ctx)
+	if err != nil {
+		x.InternalServerError(w, "Session Error")
+		return
+	}
+
+	if totpEnabled {
+		// the link stands in for the password, not the second factor
+		x.startTOTPLogin(ctx, user.ID, target)
+		http.Redirect(w, r, "/login/totp", http.StatusSeeOther)
+		return
+	}
+
+	// Then make the privilege-level change.
+	if errx := x.startUserSession(r, user); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+	x.recordLoginSuccess(r, user, model.LoginMethodEmail)
+	x.sessMgr.FlashAppend(ctx, "success", "Login successful")
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
@@ -0,0 +1,415 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_LoginLinkSendEmail(t *testing.T) {
+	t.Parallel()
+
+	ts := tstTs
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		PWAuth:       true,
+		Created:      ts,
+		LastModified: ts,
+	}
+
+	link := &model.UserLoginLink{
+		RefID:   util.Must(model.NewUserLoginLinkRefID()),
+		UserID:  user.ID,
+		Created: ts,
+	}
+
+	loginLinkTpl := util.Must(template.New("").Parse(
+		`{{.Subject}}: <a href="{{.LoginLinkUrl}}">{{.LoginLinkUrl}}</a>`))
+
+	t.Run("send login link email", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		handler.templates = &resources.TemplateMap{
+			"mail_login_link.gohtml": loginLinkTpl,
+		}
+
+		mock.EXPECT().
+			GetUserByEmail(ctx, user.Email).
+			Return(user, nil)
+		mock.EXPECT().
+			NewUserLoginLink(ctx, user.ID).
+			Return(link, nil)
+
+		data := url.Values{
+			"email":    {"user@example.com"},
+			"remember": {"on"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.LoginLinkSendEmail(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.True(t, handler.sessMgr.GetBool(ctx, "remember-me"))
+
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 1)
+		assert.Equal(t, tm.Sent[0].To, []string{user.Email})
+		after, found := strings.CutPrefix(tm.Sent[0].BodyPlain,
+			"Your sign-in link: http://example.com/login/link/")
+		assert.True(t, found)
+		refIDStr, macStr, _ := strings.Cut(after, "-")
+		assert.Equal(t, refIDStr, link.RefID.String())
+		hmacBytes, err := encoder.Base32DecodeString(macStr)
+		assert.Nil(t, err)
+		assert.True(t, handler.cMAC.Validate([]byte(refIDStr), hmacBytes))
+	})
+
+	t.Run("send login link email ignores request host", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		handler.templates = &resources.TemplateMap{
+			"mail_login_link.gohtml": loginLinkTpl,
+		}
+
+		mock.EXPECT().
+			GetUserByEmail(ctx, user.Email).
+			Return(user, nil)
+		mock.EXPECT().
+			NewUserLoginLink(ctx, user.ID).
+			Return(link, nil)
+
+		data := url.Values{
+			"email": {"user@example.com"},
+			"next":  {"//evil.example.net/phish"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://evil.example.net/login/link", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.LoginLinkSendEmail(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 1)
+		assert.True(t, strings.HasPrefix(tm.Sent[0].BodyPlain,
+			"Your sign-in link: http://example.com/login/link/"))
+		assert.True(t, !strings.Contains(tm.Sent[0].BodyPlain, "evil"))
+	})
+
+	t.Run("send login link email no user", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		handler.templates = &resources.TemplateMap{
+			"mail_login_link.gohtml": loginLinkTpl,
+		}
+
+		mock.EXPECT().
+			GetUserByEmail(ctx, user.Email).
+			Return(nil, errs.NotFound.Error("user not found"))
+
+		data := url.Values{"email": {"user@example.com"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.LoginLinkSendEmail(rr, req)
+
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 0)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("send login link email pw auth disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+
+		passkeyUser := *user
+		passkeyUser.PWAuth = false
+		passkeyUser.WebAuthn = true
+		mock.EXPECT().
+			GetUserByEmail(ctx, user.Email).
+			Return(&passkeyUser, nil)
+
+		data := url.Values{"email": {"user@example.com"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.LoginLinkSendEmail(rr, req)
+
+		tm := handler.mailer.(*TestMailer)
+		assert.Equal(t, len(tm.Sent), 0)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+	})
+}
+
+func TestHandler_LoginLink(t *testing.T) {
+	t.Parallel()
+
+	ts := tstTs
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		PWAuth:       true,
+		Created:      ts,
+		LastModified: ts,
+	}
+
+	link := &model.UserLoginLink{
+		RefID:   util.Must(model.NewUserLoginLinkRefID()),
+		UserID:  user.ID,
+		Created: ts,
+	}
+
+	t.Run("login link", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		// remember me was asked for when the link was sent
+		handler.sessMgr.Put(ctx, "remember-me", true)
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(link.RefID.String())))
+
+		mock.EXPECT().
+			ConsumeUserLoginLink(ctx, link.RefID).
+			Return(link, nil)
+		mock.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			IsTOTPEnabled(ctx, user.ID).
+			Return(false, nil)
+		mock.EXPECT().
+			NewUserSession(ctx, user.ID, "192.0.2.1", "", gomock.Any()).
+			Return(&model.UserSession{
+				ID:     1,
+				UserID: user.ID,
+				RefID:  util.Must(model.NewUserSessionRefID()),
+			}, nil)
+		mock.EXPECT().
+			RecordLoginSuccess(ctx, user.ID, model.LoginMethodEmail,
+				"192.0.2.1", "").
+			Return(false, nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", nil)
+		req.SetPathValue("lnkRefID", link.RefID.String())
+		req.SetPathValue("hmac", macStr)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr := httptest.NewRecorder()
+		handler.LoginLink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/dashboard",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), user.ID)
+		assert.True(t, handler.sessMgr.GetBool(ctx, "__rememberMe"))
+	})
+
+	t.Run("login link with offsite next", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(link.RefID.String())))
+
+		mock.EXPECT().
+			ConsumeUserLoginLink(ctx, link.RefID).
+			Return(link, nil)
+		mock.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			IsTOTPEnabled(ctx, user.ID).
+			Return(false, nil)
+		mock.EXPECT().
+			NewUserSession(ctx, user.ID, "192.0.2.1", "", gomock.Any()).
+			Return(&model.UserSession{
+				ID:     1,
+				UserID: user.ID,
+				RefID:  util.Must(model.NewUserSessionRefID()),
+			}, nil)
+		mock.EXPECT().
+			RecordLoginSuccess(ctx, user.ID, model.LoginMethodEmail,
+				"192.0.2.1", "").
+			Return(false, nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST",
+			"http://example.com/login/link?next=//evil.example.net/phish", nil)
+		req.SetPathValue("lnkRefID", link.RefID.String())
+		req.SetPathValue("hmac", macStr)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr := httptest.NewRecorder()
+		handler.LoginLink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/dashboard",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("login link with totp enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(link.RefID.String())))
+
+		mock.EXPECT().
+			ConsumeUserLoginLink(ctx, link.RefID).
+			Return(link, nil)
+		mock.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			IsTOTPEnabled(ctx, user.ID).
+			Return(true, nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", nil)
+		req.SetPathValue("lnkRefID", link.RefID.String())
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.LoginLink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login/totp",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "totp-user-id"), user.ID)
+	})
+
+	t.Run("login link already used", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(link.RefID.String())))
+
+		mock.EXPECT().
+			ConsumeUserLoginLink(ctx, link.RefID).
+			Return(nil, errs.NotFound.Error("login link not found"))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", nil)
+		req.SetPathValue("lnkRefID", link.RefID.String())
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.LoginLink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+	})
+
+	t.Run("login link with bad hmac", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		refID := util.Must(model.NewUserLoginLinkRefID())
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refID.String())))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", nil)
+		req.SetPathValue("lnkRefID", link.RefID.String())
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.LoginLink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+	})
+
+	t.Run("login link wrong refid type", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		// a password reset refid, with a valid hmac
+		refID := util.Must(model.NewUserPWResetRefID())
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refID.String())))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/link", nil)
+		req.SetPathValue("lnkRefID", refID.String())
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.LoginLink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+	})
+}
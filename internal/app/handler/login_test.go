@@ -5,6 +5,7 @@ package handler
 
 import (
 	"context"
+	"html/template"
 	"io"
 	"log/slog"
 	"net/http"
@@ -12,11 +13,13 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dropwhile/assert"
 	"go.uber.org/mock/gomock"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
 	"github.com/dropwhile/icanbringthat/internal/crypto"
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/util"
@@ -43,6 +46,9 @@ func TestHandler_Login_InvalidCredentials(t *testing.T) {
 		mock.EXPECT().
 			GetUserByEmail(gomock.Any(), user.Email).
 			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(gomock.Any(), user.ID).
+			Return(nil)
 
 		// bad password
 		data := url.Values{
@@ -68,6 +74,79 @@ func TestHandler_Login_InvalidCredentials(t *testing.T) {
 			"handler returned wrong redirect")
 	})
 
+	t.Run("wrong password records failure", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+
+		pwUser := *user
+		pwUser.PWAuth = true
+
+		mock.EXPECT().
+			GetUserByEmail(gomock.Any(), user.Email).
+			Return(&pwUser, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(gomock.Any(), user.ID).
+			Return(nil)
+		mock.EXPECT().
+			RecordLoginFailure(gomock.Any(), user.ID,
+				model.LoginMethodPassword, "192.0.2.1", "test-agent").
+			Return(nil)
+
+		data := url.Values{
+			"email":    {user.Email},
+			"password": {"00x01"},
+		}
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("User-Agent", "test-agent")
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr := httptest.NewRecorder()
+		handler.Login(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("locked out account", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+
+		pwUser := *user
+		pwUser.PWAuth = true
+
+		mock.EXPECT().
+			GetUserByEmail(gomock.Any(), user.Email).
+			Return(&pwUser, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(gomock.Any(), user.ID).
+			Return(errs.ResourceExhausted.Error("too many failed login attempts"))
+
+		// correct password is still refused
+		data := url.Values{
+			"email":    {user.Email},
+			"password": {"00x00"},
+		}
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.Login(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		// same response as for an unknown email
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "error"),
+			[]string{"Invalid credentials"})
+	})
+
 	t.Run("no matching user", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.TODO()
@@ -97,6 +176,8 @@ func TestHandler_Login_InvalidCredentials(t *testing.T) {
 		AssertStatusEqual(t, rr, http.StatusSeeOther)
 		assert.Equal(t, rr.Header().Get("location"), "/login",
 			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "error"),
+			[]string{"Invalid credentials"})
 
 		// we make sure that all expectations were met
 	})
@@ -144,10 +225,31 @@ func TestHandler_Login_ValidCredentials(t *testing.T) {
 	mock, _, handler := SetupHandler(t, ctx)
 	// inject session into context
 	ctx, _ = handler.sessMgr.Load(ctx, "")
+	handler.templates = &resources.TemplateMap{
+		"mail_new_sign_in.gohtml": util.Must(template.New("").Parse(
+			`New sign-in from {{.IPAddress}} ({{.UserAgent}})`)),
+	}
 
 	mock.EXPECT().
 		GetUserByEmail(ctx, user.Email).
 		Return(user, nil)
+	mock.EXPECT().
+		CheckLoginAllowed(ctx, user.ID).
+		Return(nil)
+	mock.EXPECT().
+		IsTOTPEnabled(ctx, user.ID).
+		Return(false, nil)
+	mock.EXPECT().
+		NewUserSession(ctx, user.ID, "192.0.2.1", "test-agent", gomock.Any()).
+		Return(&model.UserSession{
+			ID:     1,
+			UserID: user.ID,
+			RefID:  util.Must(model.NewUserSessionRefID()),
+		}, nil)
+	mock.EXPECT().
+		RecordLoginSuccess(ctx, user.ID, model.LoginMethodPassword,
+			"192.0.2.1", "test-agent").
+		Return(true, nil)
 
 	data := url.Values{
 		"email":    {"user@example.com"},
@@ -156,6 +258,8 @@ func TestHandler_Login_ValidCredentials(t *testing.T) {
 
 	req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login", strings.NewReader(data.Encode()))
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "test-agent")
+	req.RemoteAddr = "192.0.2.1:1234"
 	rr := httptest.NewRecorder()
 	handler.Login(rr, req)
 
@@ -169,4 +273,169 @@ func TestHandler_Login_ValidCredentials(t *testing.T) {
 	AssertStatusEqual(t, rr, http.StatusSeeOther)
 	assert.Equal(t, rr.Header().Get("location"), "/dashboard",
 		"handler returned wrong redirect")
+
+	// new sign-in alert was sent
+	mailer := handler.mailer.(*TestMailer)
+	assert.Equal(t, len(mailer.Sent), 1)
+	assert.Equal(t, mailer.Sent[0].To, []string{user.Email})
+	assert.Equal(t, mailer.Sent[0].Subject, "New sign-in to your account")
+	assert.True(t, strings.Contains(mailer.Sent[0].BodyPlain, "192.0.2.1"))
+}
+
+func TestHandler_Login_TOTP(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:       1,
+		RefID:    util.Must(model.NewUserRefID()),
+		Email:    "user@example.com",
+		Name:     "user",
+		PWHash:   util.Must(crypto.HashPW([]byte("00x00"))),
+		PWAuth:   true,
+		Verified: true,
+	}
+
+	t.Run("password login redirects to second factor", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+
+		mock.EXPECT().
+			GetUserByEmail(ctx, user.Email).
+			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			IsTOTPEnabled(ctx, user.ID).
+			Return(true, nil)
+
+		data := url.Values{
+			"email":    {"user@example.com"},
+			"password": {"00x00"},
+			"next":     {"/events"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.Login(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login/totp",
+			"handler returned wrong redirect")
+		// not logged in yet
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "totp-user-id"), user.ID)
+		assert.Equal(t, handler.sessMgr.GetString(ctx, "totp-next"), "/events")
+	})
+
+	t.Run("valid code logs in", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		handler.sessMgr.Put(ctx, "totp-user-id", user.ID)
+		handler.sessMgr.Put(ctx, "totp-started", time.Now().UTC())
+		handler.sessMgr.Put(ctx, "totp-next", "/events")
+
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			VerifyTOTP(ctx, user.ID, "123456").
+			Return(nil)
+		mock.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+		mock.EXPECT().
+			NewUserSession(ctx, user.ID, "192.0.2.1", "test-agent", gomock.Any()).
+			Return(&model.UserSession{
+				ID:     1,
+				UserID: user.ID,
+				RefID:  util.Must(model.NewUserSessionRefID()),
+			}, nil)
+		mock.EXPECT().
+			RecordLoginSuccess(ctx, user.ID, model.LoginMethodTOTP,
+				"192.0.2.1", "test-agent").
+			Return(false, nil)
+
+		data := url.Values{"code": {"123456"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/totp", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("User-Agent", "test-agent")
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr := httptest.NewRecorder()
+		handler.LoginTOTP(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/events",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), user.ID)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "totp-user-id"), 0)
+	})
+
+	t.Run("invalid code records failure", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		handler.sessMgr.Put(ctx, "totp-user-id", user.ID)
+		handler.sessMgr.Put(ctx, "totp-started", time.Now().UTC())
+
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			VerifyTOTP(ctx, user.ID, "000000").
+			Return(errs.PermissionDenied.Error("invalid two-factor code"))
+		mock.EXPECT().
+			RecordLoginFailure(ctx, user.ID, model.LoginMethodTOTP,
+				"192.0.2.1", "test-agent").
+			Return(nil)
+
+		data := url.Values{"code": {"000000"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/totp", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("User-Agent", "test-agent")
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr := httptest.NewRecorder()
+		handler.LoginTOTP(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login/totp",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		// can try again
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "totp-user-id"), user.ID)
+	})
+
+	t.Run("expired pending login", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		handler.sessMgr.Put(ctx, "totp-user-id", user.ID)
+		handler.sessMgr.Put(ctx, "totp-started", time.Now().UTC().Add(-time.Hour))
+
+		data := url.Values{"code": {"123456"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/login/totp", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.LoginTOTP(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "totp-user-id"), 0)
+	})
 }
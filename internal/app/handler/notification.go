@@ -6,6 +6,7 @@ package handler
 import (
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/dropwhile/icanbringthat/internal/app/resources"
@@ -25,14 +26,29 @@ func (x *Handler) NotificationsList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notifCount, err := x.svc.GetNotificationsCount(ctx, user.ID)
-	if err != nil {
-		x.DBError(w, err)
+	notifCount, errx := x.svc.GetNotificationsUnreadCount(ctx, user.ID)
+	if errx != nil {
+		x.DBError(w, errx)
+		return
+	}
+
+	notifTotalCount, errx := x.svc.GetNotificationsCount(ctx, user.ID)
+	if errx != nil {
+		x.DBError(w, errx)
 		return
 	}
 
+	extraQargs := url.Values{}
+	maxCount := notifTotalCount
+	unreadOnly := false
+	if r.FormValue("unread") == "1" {
+		maxCount = notifCount
+		extraQargs.Add("unread", "1")
+		unreadOnly = true
+	}
+
 	pageNum := 1
-	maxPageNum := resources.CalculateMaxPageNum(notifCount, 10)
+	maxPageNum := resources.CalculateMaxPageNum(maxCount, 10)
 	pageNumParam := r.FormValue("page")
 	if pageNumParam != "" {
 		if v, err := strconv.ParseInt(pageNumParam, 10, 0); err == nil {
@@ -43,20 +59,27 @@ func (x *Handler) NotificationsList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	offset := pageNum - 1
-	notifs, _, errx := x.svc.GetNotificationsPaginated(ctx, user.ID, 10, offset*10)
+	notifs, _, errx := x.svc.GetNotificationsPaginated(
+		ctx, user.ID, 10, offset*10, unreadOnly)
 	if errx != nil {
 		x.DBError(w, errx)
 		return
 	}
 
+	title := "Notifications"
+	if unreadOnly {
+		title += " (Unread)"
+	}
 	tplVars := MapSA{
-		"user":       user,
-		"notifs":     notifs,
-		"notifCount": notifCount,
-		"title":      "Notifications",
-		"nav":        "notifications",
+		"user":            user,
+		"notifs":          notifs,
+		"notifCount":      notifCount,
+		"notifTotalCount": notifTotalCount,
+		"unreadOnly":      unreadOnly,
+		"title":           title,
+		"nav":             "notifications",
 		"pgInput": resources.NewPgInput(
-			notifCount, 10, pageNum, "/notifications", nil,
+			maxCount, 10, pageNum, "/notifications", extraQargs,
 		),
 	}
 
@@ -114,6 +137,86 @@ func (x *Handler) NotificationDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (x *Handler) NotificationMarkRead(w http.ResponseWriter, r *http.Request) {
+	x.notificationUpdateRead(w, r, true)
+}
+
+func (x *Handler) NotificationMarkUnread(w http.ResponseWriter, r *http.Request) {
+	x.notificationUpdateRead(w, r, false)
+}
+
+func (x *Handler) notificationUpdateRead(w http.ResponseWriter, r *http.Request, read bool) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	refID, err := service.ParseNotificationRefID(r.PathValue("nRefID"))
+	if err != nil {
+		x.BadRefIDError(w, "notification", err)
+		return
+	}
+
+	errx := x.svc.UpdateNotificationRead(ctx, user.ID, refID, read)
+	if errx != nil {
+		slog.InfoContext(ctx, "error updating notification", "error", errx)
+		switch errx.Code() {
+		case errs.Internal:
+			x.InternalServerError(w, errx.Msg())
+		case errs.NotFound:
+			x.NotFoundError(w)
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		case errs.Unauthenticated:
+			x.BadSessionDataError(w)
+		default:
+			x.InternalServerError(w, "unexpected error")
+		}
+		return
+	}
+
+	w.Header().Set("content-type", "text/html")
+	if htmx.Request(r).IsRequest() {
+		htmx.Response(w).HxRefesh()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (x *Handler) NotificationsMarkAllRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	errx := x.svc.MarkAllNotificationsRead(ctx, user.ID)
+	if errx != nil {
+		slog.InfoContext(ctx, "error marking all notifications read", "error", errx)
+		switch errx.Code() {
+		case errs.Internal:
+			x.InternalServerError(w, errx.Msg())
+		case errs.Unauthenticated:
+			x.BadSessionDataError(w)
+		default:
+			x.InternalServerError(w, "unexpected error")
+		}
+		return
+	}
+
+	w.Header().Set("content-type", "text/html")
+	if htmx.Request(r).IsRequest() {
+		htmx.Response(w).HxRefesh()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (x *Handler) NotificationsDeleteAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
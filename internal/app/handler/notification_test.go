@@ -252,3 +252,135 @@ func TestHandler_Notification_DeleteAll(t *testing.T) {
 		// we make sure that all expectations were met
 	})
 }
+
+func TestHandler_Notification_MarkRead(t *testing.T) {
+	t.Parallel()
+
+	ts := tstTs
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      ts,
+		LastModified: ts,
+	}
+	notification := &model.Notification{
+		ID:           2,
+		RefID:        util.Must(model.NewNotificationRefID()),
+		UserID:       user.ID,
+		Message:      "",
+		Read:         false,
+		Created:      ts,
+		LastModified: ts,
+	}
+
+	t.Run("mark notification read", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			UpdateNotificationRead(ctx, user.ID, notification.RefID, true).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/notification/read", nil)
+		req.SetPathValue("nRefID", notification.RefID.String())
+		rr := httptest.NewRecorder()
+		handler.NotificationMarkRead(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+
+	t.Run("mark notification unread", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			UpdateNotificationRead(ctx, user.ID, notification.RefID, false).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/notification/unread", nil)
+		req.SetPathValue("nRefID", notification.RefID.String())
+		rr := httptest.NewRecorder()
+		handler.NotificationMarkUnread(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+
+	t.Run("mark notification read wrong user", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			UpdateNotificationRead(ctx, user.ID, notification.RefID, true).
+			Return(errs.PermissionDenied.Error("permission denied"))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/notification/read", nil)
+		req.SetPathValue("nRefID", notification.RefID.String())
+		rr := httptest.NewRecorder()
+		handler.NotificationMarkRead(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusForbidden)
+	})
+
+	t.Run("mark all notifications read", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			MarkAllNotificationsRead(ctx, user.ID).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/notifications/read", nil)
+		rr := httptest.NewRecorder()
+		handler.NotificationsMarkAllRead(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+}
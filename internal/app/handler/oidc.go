@@ -0,0 +1,331 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+)
+
+// how long a user has to complete a login at the external provider
+const oidcLoginTimeout = 10 * time.Minute
+
+// localTarget returns next if it is a path on this site, so the
+// provider round trip can't be used as an open redirect.
+func localTarget(next, fallback string) string {
+	if !strings.HasPrefix(next, "/") ||
+		strings.HasPrefix(next, "//") ||
+		strings.HasPrefix(next, "/\\") {
+		return fallback
+	}
+	return next
+}
+
+// startOIDC sends the user to the external provider to authenticate.
+// If linkUserID is set, the resulting identity is linked to that user
+// rather than logged in.
+func (x *Handler) startOIDC(w http.ResponseWriter, r *http.Request,
+	next string, linkUserID int, failTarget string,
+) {
+	ctx := r.Context()
+
+	state := oidc.GenerateState()
+	nonce := oidc.GenerateState()
+	verifier := oidc.GenerateVerifier()
+	authURL, err := x.oidc.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		slog.ErrorContext(ctx, "oidc provider unavailable", "error", err)
+		x.sessMgr.FlashAppend(ctx, "error",
+			"Sign in with "+x.oidc.Name()+" is unavailable. Please try again later.")
+		http.Redirect(w, r, failTarget, http.StatusSeeOther)
+		return
+	}
+
+	x.sessMgr.Put(ctx, "oidc-state", state)
+	x.sessMgr.Put(ctx, "oidc-nonce", nonce)
+	x.sessMgr.Put(ctx, "oidc-verifier", verifier)
+	x.sessMgr.Put(ctx, "oidc-next", next)
+	x.sessMgr.Put(ctx, "oidc-link", linkUserID)
+	x.sessMgr.Put(ctx, "oidc-started", time.Now().UTC())
+	http.Redirect(w, r, authURL, http.StatusSeeOther)
+}
+
+type oidcPending struct {
+	started  time.Time
+	state    string
+	nonce    string
+	verifier string
+	next     string
+	linkID   int
+}
+
+// popOIDC removes the in progress provider login from the session, so a
+// callback can only be used once.
+func (x *Handler) popOIDC(ctx context.Context) *oidcPending {
+	return &oidcPending{
+		state:    x.sessMgr.PopString(ctx, "oidc-state"),
+		nonce:    x.sessMgr.PopString(ctx, "oidc-nonce"),
+		verifier: x.sessMgr.PopString(ctx, "oidc-verifier"),
+		next:     x.sessMgr.PopString(ctx, "oidc-next"),
+		linkID:   x.sessMgr.PopInt(ctx, "oidc-link"),
+		started:  x.sessMgr.PopTime(ctx, "oidc-started"),
+	}
+}
+
+func (x *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if x.oidc == nil {
+		x.NotFoundError(w)
+		return
+	}
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	x.rememberLogin(ctx, r)
+	x.startOIDC(w, r, localTarget(r.FormValue("next"), "/dashboard"), 0, "/login")
+}
+
+func (x *Handler) OIDCLinkStart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if x.oidc == nil {
+		x.NotFoundError(w)
+		return
+	}
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	x.startOIDC(w, r, "/settings", user.ID, "/settings")
+}
+
+func (x *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if x.oidc == nil {
+		x.NotFoundError(w)
+		return
+	}
+
+	pending := x.popOIDC(ctx)
+	failTarget := "/login"
+	if pending.linkID != 0 {
+		failTarget = "/settings"
+	}
+
+	if pending.state == "" || time.Since(pending.started) > oidcLoginTimeout {
+		slog.DebugContext(ctx, "no oidc login in progress")
+		x.sessMgr.FlashAppend(ctx, "error", "Sign-in request expired. Please try again.")
+		http.Redirect(w, r, failTarget, http.StatusSeeOther)
+		return
+	}
+
+	if subtle.ConstantTimeCompare(
+		[]byte(pending.state), []byte(r.FormValue("state"))) != 1 {
+		slog.InfoContext(ctx, "oidc state mismatch")
+		x.BadRequestError(w, "Bad Request Data")
+		return
+	}
+
+	if providerErr := r.FormValue("error"); providerErr != "" {
+		slog.InfoContext(ctx, "oidc provider returned error",
+			slog.String("error", providerErr),
+			slog.String("description", r.FormValue("error_description")),
+		)
+		x.sessMgr.FlashAppend(ctx, "error",
+			"Sign in with "+x.oidc.Name()+" was cancelled or failed.")
+		http.Redirect(w, r, failTarget, http.StatusSeeOther)
+		return
+	}
+
+	code := r.FormValue("code")
+	if code == "" {
+		x.BadRequestError(w, "Bad Request Data")
+		return
+	}
+
+	claims, err := x.oidc.Exchange(ctx, code, pending.verifier, pending.nonce)
+	if err != nil {
+		slog.WarnContext(ctx, "oidc code exchange failed", "error", err)
+		x.sessMgr.FlashAppend(ctx, "error",
+			"Sign in with "+x.oidc.Name()+" failed. Please try again.")
+		http.Redirect(w, r, failTarget, http.StatusSeeOther)
+		return
+	}
+
+	ident := &service.ExternalIdentity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		Name:          claims.Name,
+		EmailVerified: claims.EmailVerified,
+	}
+
+	if pending.linkID != 0 {
+		x.oidcLink(w, r, pending.linkID, ident)
+		return
+	}
+	x.oidcLogin(w, r, localTarget(pending.next, "/dashboard"), ident)
+}
+
+func (x *Handler) oidcLink(w http.ResponseWriter, r *http.Request,
+	linkUserID int, ident *service.ExternalIdentity,
+) {
+	ctx := r.Context()
+
+	// the link must complete in the session of the user that started it
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user.ID != linkUserID {
+		slog.InfoContext(ctx, "oidc link user mismatch")
+		x.sessMgr.FlashAppend(ctx, "error", "Sign-in request expired. Please try again.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	_, errx := x.svc.LinkExternalIdentity(ctx, user.ID, ident)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.AlreadyExists:
+			x.sessMgr.FlashAppend(ctx, "error",
+				"That "+x.oidc.Name()+" account is linked to another user.")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", x.oidc.Name()+" account linked")
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+func (x *Handler) oidcLogin(w http.ResponseWriter, r *http.Request,
+	target string, ident *service.ExternalIdentity,
+) {
+	ctx := r.Context()
+
+	// get user from session
+	_, err := auth.UserFromContext(ctx)
+	// already a logged in user
+	if err == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	user, errx := x.svc.ResolveExternalIdentity(ctx, ident)
+	if errx != nil {
+		slog.InfoContext(ctx, "oidc identity not usable", "error", errx)
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.sessMgr.FlashAppend(ctx, "error",
+				"Your "+x.oidc.Name()+" email address is not verified.")
+		case errs.FailedPrecondition:
+			x.sessMgr.FlashAppend(ctx, "error",
+				"An account with that email already exists. Log in, then link your "+
+					x.oidc.Name()+" account from settings.")
+		case errs.InvalidArgument, errs.AlreadyExists:
+			x.sessMgr.FlashAppend(ctx, "error",
+				"Sign in with "+x.oidc.Name()+" failed. Please try again.")
+		default:
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !x.checkLoginAllowed(w, r, user.ID) {
+		return
+	}
+
+	totpEnabled, errx := x.svc.IsTOTPEnabled(ctx, user.ID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	// renew sesmgr token to help prevent session fixation. ref:
+	//   https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/Session_Management_Cheat_Sheet.md
+	//   #renew-the-session-id-after-any-privilege-level-change
+	err = x.sessMgr.RenewToken(ctx)
+	if err != nil {
+		x.InternalServerError(w, "Session Error")
+		return
+	}
+
+	if totpEnabled {
+		// the provider stands in for the password, not the second factor
+		x.startTOTPLogin(ctx, user.ID, target)
+		http.Redirect(w, r, "/login/totp", http.StatusSeeOther)
+		return
+	}
+
+	// Then make the privilege-level change.
+	if errx := x.startUserSession(r, user); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+	x.recordLoginSuccess(r, user, model.LoginMethodOIDC)
+	x.sessMgr.FlashAppend(ctx, "success", "Login successful")
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+func (x *Handler) OIDCUnlink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	refID, err := service.ParseUserIdentityRefID(r.PathValue("iRefID"))
+	if err != nil {
+		x.BadRefIDError(w, "identity", err)
+		return
+	}
+
+	errx := x.svc.DeleteUserIdentity(ctx, user, refID)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		case errs.NotFound:
+			slog.InfoContext(ctx, "identity not found", "error", errx)
+			x.NotFoundError(w)
+		case errs.FailedPrecondition:
+			slog.
+				With("error", errx).
+				DebugContext(ctx, "pre-conditional failed")
+			x.BadRequestError(w, "pre-condition failed")
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,397 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+	"github.com/dropwhile/icanbringthat/internal/oidc/oidctest"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func setupOIDC(t *testing.T, handler *Handler) *oidctest.Issuer {
+	t.Helper()
+	issuer := oidctest.NewIssuer()
+	t.Cleanup(issuer.Close)
+	handler.oidc = oidc.NewProvider(oidc.Config{
+		Name:        "Test SSO",
+		Issuer:      issuer.URL,
+		ClientID:    "client-id",
+		RedirectURL: "http://example.com/login/oidc/callback",
+	}, issuer.Client())
+	return issuer
+}
+
+// oidcAuthorize runs the redirect to the provider, returning the
+// callback url the provider sends the user back to.
+func oidcAuthorize(t *testing.T, issuer *oidctest.Issuer,
+	rr *httptest.ResponseRecorder, user oidctest.User,
+) string {
+	t.Helper()
+	AssertStatusEqual(t, rr, http.StatusSeeOther)
+	code, state := issuer.Authorize(rr.Header().Get("location"), user)
+	return "http://example.com/login/oidc/callback?" + url.Values{
+		"code":  {code},
+		"state": {state},
+	}.Encode()
+}
+
+func TestHandler_OIDCLogin(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	idpUser := oidctest.User{
+		Subject:       "subject-1",
+		Email:         user.Email,
+		Name:          user.Name,
+		EmailVerified: true,
+	}
+
+	t.Run("oidc login", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		issuer := setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/login/oidc?next=/events&remember=on", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLogin(rr, req)
+		callbackURL := oidcAuthorize(t, issuer, rr, idpUser)
+
+		mock.EXPECT().
+			ResolveExternalIdentity(ctx, &service.ExternalIdentity{
+				Issuer:        issuer.URL,
+				Subject:       idpUser.Subject,
+				Email:         idpUser.Email,
+				Name:          idpUser.Name,
+				EmailVerified: true,
+			}).
+			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			IsTOTPEnabled(ctx, user.ID).
+			Return(false, nil)
+		mock.EXPECT().
+			NewUserSession(ctx, user.ID, "192.0.2.1", "", gomock.Any()).
+			Return(&model.UserSession{
+				ID:     1,
+				UserID: user.ID,
+				RefID:  util.Must(model.NewUserSessionRefID()),
+			}, nil)
+		mock.EXPECT().
+			RecordLoginSuccess(ctx, user.ID, model.LoginMethodOIDC,
+				"192.0.2.1", "").
+			Return(false, nil)
+
+		req, _ = http.NewRequestWithContext(ctx, "GET", callbackURL, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr = httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/events",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), user.ID)
+		// remember me choice from the start of the login was applied
+		assert.True(t, handler.sessMgr.GetBool(ctx, "__rememberMe"))
+	})
+
+	t.Run("oidc login with totp enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		issuer := setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/login/oidc", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLogin(rr, req)
+		callbackURL := oidcAuthorize(t, issuer, rr, idpUser)
+
+		mock.EXPECT().
+			ResolveExternalIdentity(ctx, &service.ExternalIdentity{
+				Issuer:        issuer.URL,
+				Subject:       idpUser.Subject,
+				Email:         idpUser.Email,
+				Name:          idpUser.Name,
+				EmailVerified: true,
+			}).
+			Return(user, nil)
+		mock.EXPECT().
+			CheckLoginAllowed(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			IsTOTPEnabled(ctx, user.ID).
+			Return(true, nil)
+
+		req, _ = http.NewRequestWithContext(ctx, "GET", callbackURL, nil)
+		rr = httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login/totp",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "totp-user-id"), user.ID)
+	})
+
+	t.Run("oidc login external next is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET",
+			"http://example.com/login/oidc?next=//evil.example.com/", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLogin(rr, req)
+
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, handler.sessMgr.GetString(ctx, "oidc-next"), "/dashboard")
+	})
+
+	t.Run("oidc login existing account", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		issuer := setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/login/oidc", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLogin(rr, req)
+		callbackURL := oidcAuthorize(t, issuer, rr, idpUser)
+
+		mock.EXPECT().
+			ResolveExternalIdentity(ctx, &service.ExternalIdentity{
+				Issuer:        issuer.URL,
+				Subject:       idpUser.Subject,
+				Email:         idpUser.Email,
+				Name:          idpUser.Name,
+				EmailVerified: true,
+			}).
+			Return(nil, errs.FailedPrecondition.Error("account exists with email"))
+
+		req, _ = http.NewRequestWithContext(ctx, "GET", callbackURL, nil)
+		rr = httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+	})
+
+	t.Run("oidc callback with bad state", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		issuer := setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/login/oidc", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLogin(rr, req)
+		code, _ := issuer.Authorize(rr.Header().Get("location"), idpUser)
+
+		req, _ = http.NewRequestWithContext(ctx, "GET",
+			"http://example.com/login/oidc/callback?"+url.Values{
+				"code":  {code},
+				"state": {"bad-state"},
+			}.Encode(), nil)
+		rr = httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("oidc callback without login in progress", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET",
+			"http://example.com/login/oidc/callback?code=code&state=state", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("oidc login not configured", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/login/oidc", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLogin(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+}
+
+func TestHandler_OIDCLink(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWAuth:       true,
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	idpUser := oidctest.User{
+		Subject:       "subject-1",
+		Email:         "other@example.com",
+		EmailVerified: true,
+	}
+
+	t.Run("link identity", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		issuer := setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/identities", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLinkStart(rr, req)
+		callbackURL := oidcAuthorize(t, issuer, rr, idpUser)
+
+		mock.EXPECT().
+			LinkExternalIdentity(ctx, user.ID, &service.ExternalIdentity{
+				Issuer:        issuer.URL,
+				Subject:       idpUser.Subject,
+				Email:         idpUser.Email,
+				EmailVerified: true,
+			}).
+			Return(&model.UserIdentity{UserID: user.ID}, nil)
+
+		req, _ = http.NewRequestWithContext(ctx, "GET", callbackURL, nil)
+		rr = httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("link identity finished by other user should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		userCtx := auth.ContextSet(ctx, "user", user)
+		issuer := setupOIDC(t, handler)
+
+		req, _ := http.NewRequestWithContext(userCtx, "POST", "http://example.com/settings/auth/identities", nil)
+		rr := httptest.NewRecorder()
+		handler.OIDCLinkStart(rr, req)
+		callbackURL := oidcAuthorize(t, issuer, rr, idpUser)
+
+		// same session, but no longer logged in
+		req, _ = http.NewRequestWithContext(ctx, "GET", callbackURL, nil)
+		rr = httptest.NewRecorder()
+		handler.OIDCCallback(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("unlink identity", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewUserIdentityRefID())
+
+		mock.EXPECT().
+			DeleteUserIdentity(ctx, user, refID).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/auth/identities/"+refID.String(), nil)
+		req.SetPathValue("iRefID", refID.String())
+		rr := httptest.NewRecorder()
+		handler.OIDCUnlink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+
+	t.Run("unlink last identity should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewUserIdentityRefID())
+
+		mock.EXPECT().
+			DeleteUserIdentity(ctx, user, refID).
+			Return(errs.FailedPrecondition.Error("last identity"))
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/auth/identities/"+refID.String(), nil)
+		req.SetPathValue("iRefID", refID.String())
+		rr := httptest.NewRecorder()
+		handler.OIDCUnlink(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+}
@@ -310,6 +310,13 @@ func (x *Handler) PasswordReset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a reset password logs out every device
+	errx = x.svc.DeleteUserSessions(ctx, user.ID)
+	if errx != nil {
+		x.DBError(w, errx)
+		return
+	}
+
 	// renew sesmgr token to help prevent session fixation. ref:
 	//   https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/Session_Management_Cheat_Sheet.md
 	//   #renew-the-session-id-after-any-privilege-level-change
@@ -319,7 +326,10 @@ func (x *Handler) PasswordReset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Then make the privilege-level change.
-	x.sessMgr.Put(r.Context(), "user-id", user.ID)
+	if errx := x.startUserSession(r, user); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
 	target := "/dashboard"
 	http.Redirect(w, r, target, http.StatusSeeOther)
 }
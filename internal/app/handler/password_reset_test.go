@@ -73,6 +73,16 @@ func TestHandler_ResetPassword(t *testing.T) {
 		mock.EXPECT().
 			UpdateUserPWReset(ctx, user, pwr).
 			Return(nil)
+		mock.EXPECT().
+			DeleteUserSessions(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			NewUserSession(ctx, user.ID, "", "", gomock.Any()).
+			Return(&model.UserSession{
+				ID:     1,
+				UserID: user.ID,
+				RefID:  util.Must(model.NewUserSessionRefID()),
+			}, nil)
 
 		data := url.Values{
 			"password":         {"newpass"},
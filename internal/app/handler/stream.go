@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+)
+
+const streamKeepAliveInterval = 30 * time.Second
+
+// Stream is a server-sent-events endpoint. It always carries the
+// notification count changes for the current user, and if an event
+// ref-id is supplied, changes to that event as well.
+func (x *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	topics := []string{pubsub.UserTopic(user.ID)}
+	if eventRefIDParam := r.FormValue("event"); eventRefIDParam != "" {
+		refID, err := service.ParseEventRefID(eventRefIDParam)
+		if err != nil {
+			x.BadRefIDError(w, "event", err)
+			return
+		}
+		event, errx := x.svc.GetEvent(ctx, refID)
+		if errx != nil {
+			switch errx.Code() {
+			case errs.NotFound:
+				x.NotFoundError(w)
+			default:
+				x.DBError(w, errx)
+			}
+			return
+		}
+		topics = append(topics, pubsub.EventTopic(event.ID))
+	}
+
+	sub, err := x.broker.Subscribe(ctx, topics...)
+	if err != nil {
+		slog.ErrorContext(ctx, "error subscribing", "error", err)
+		x.InternalServerError(w, "Stream Error")
+		return
+	}
+	defer sub.Close()
+
+	// the stream is long lived, so lift the server write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("content-type", "text/event-stream")
+	w.Header().Set("cache-control", "no-cache")
+	w.Header().Set("x-accel-buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// tell the client how long to wait before reconnecting
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		slog.ErrorContext(ctx, "streaming unsupported", "error", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
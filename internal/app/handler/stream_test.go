@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+// readStreamLine reads the next non-empty line from an event stream
+func readStreamLine(t *testing.T, rd *bufio.Reader) string {
+	t.Helper()
+	for {
+		line, err := rd.ReadString('\n')
+		assert.Nil(t, err)
+		line = strings.TrimSpace(line)
+		if line != "" {
+			return line
+		}
+	}
+}
+
+func TestHandler_Stream(t *testing.T) {
+	t.Parallel()
+
+	ts := tstTs
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      ts,
+		LastModified: ts,
+	}
+	event := &model.Event{
+		ID:           2,
+		RefID:        util.Must(model.NewEventRefID()),
+		UserID:       3,
+		Name:         "event",
+		Description:  "description",
+		StartTime:    ts,
+		StartTimeTz:  util.Must(service.ParseTimeZone("Etc/UTC")),
+		Created:      ts,
+		LastModified: ts,
+	}
+
+	setupServer := func(t *testing.T, handler *Handler) *httptest.Server {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				ctx := auth.ContextSet(r.Context(), "user", user)
+				handler.Stream(w, r.WithContext(ctx))
+			}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("stream user and event messages", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		broker := pubsub.NewMemoryBroker()
+		handler.broker = broker
+		srv := setupServer(t, handler)
+
+		mock.EXPECT().
+			GetEvent(gomock.Any(), event.RefID).
+			Return(event, nil)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET",
+			srv.URL+"/stream?event="+event.RefID.String(), nil)
+		resp, err := srv.Client().Do(req)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, resp.Header.Get("content-type"), "text/event-stream")
+
+		rd := bufio.NewReader(resp.Body)
+		// the retry preamble is only written once subscribed
+		assert.Equal(t, readStreamLine(t, rd), "retry: 5000")
+
+		assert.Nil(t, broker.Publish(ctx, pubsub.EventTopic(event.ID),
+			&pubsub.Message{
+				Type:   pubsub.TypeEventUpdated,
+				Change: pubsub.ChangeItemAdded,
+				RefID:  "abc",
+			}))
+		assert.Equal(t, readStreamLine(t, rd), "event: event-updated")
+		assert.Equal(t, readStreamLine(t, rd),
+			`data: {"type":"event-updated","change":"item-added","ref_id":"abc","cursor":"1"}`)
+
+		assert.Nil(t, broker.Publish(ctx, pubsub.UserTopic(user.ID),
+			&pubsub.Message{Type: pubsub.TypeNotifCount}))
+		assert.Equal(t, readStreamLine(t, rd), "event: notif-count")
+		assert.Equal(t, readStreamLine(t, rd), `data: {"type":"notif-count","cursor":"2"}`)
+	})
+
+	t.Run("stream with bad event refid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		handler.broker = pubsub.NewMemoryBroker()
+
+		req, _ := http.NewRequestWithContext(ctx, "GET",
+			"http://example.com/stream?event=hodor", nil)
+		req = req.WithContext(auth.ContextSet(req.Context(), "user", user))
+		rr := httptest.NewRecorder()
+		handler.Stream(rr, req)
+
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+
+	t.Run("stream with missing event", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		handler.broker = pubsub.NewMemoryBroker()
+
+		mock.EXPECT().
+			GetEvent(gomock.Any(), event.RefID).
+			Return(nil, errs.NotFound.Error("event not found"))
+
+		req, _ := http.NewRequestWithContext(ctx, "GET",
+			"http://example.com/stream?event="+event.RefID.String(), nil)
+		req = req.WithContext(auth.ContextSet(req.Context(), "user", user))
+		rr := httptest.NewRecorder()
+		handler.Stream(rr, req)
+
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+func (x *Handler) ApiKeyCreate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return
+	}
+
+	name := r.PostFormValue("name")
+	if name == "" {
+		x.BadFormDataError(w, nil, "name")
+		return
+	}
+
+	// expiry is given in days. empty means the key never expires.
+	var expires *time.Time
+	if v := r.PostFormValue("expires"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > 365 {
+			x.BadFormDataError(w, err, "expires")
+			return
+		}
+		t := time.Now().UTC().AddDate(0, 0, days)
+		expires = &t
+	}
+
+	apikey, errx := x.svc.NewApiKey(ctx, user.ID, name, r.PostForm["scopes"], expires)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.InvalidArgument:
+			switch arg := errx.Meta("argument"); arg {
+			case "scopes":
+				x.sessMgr.FlashAppend(ctx, "error",
+					"Api key requires at least one valid scope")
+			default:
+				x.sessMgr.FlashAppend(ctx, "error",
+					"Api key "+arg+" was a bad value")
+			}
+		case errs.ResourceExhausted:
+			x.sessMgr.FlashAppend(ctx, "error", "Too many api keys")
+		default:
+			slog.ErrorContext(ctx, "error creating api key",
+				logger.Err(errx))
+			x.InternalServerError(w, "error creating api key")
+			return
+		}
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success",
+		"Api key created. Copy it now, it will not be shown again: "+apikey.Token)
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+func (x *Handler) ApiKeyDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	errx := x.svc.DeleteApiKey(ctx, user.ID, r.PathValue("kPrefix"))
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		case errs.NotFound:
+			x.NotFoundError(w)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_ApiKeyCreate(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		ApiAccess:    true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	apikey := &model.ApiKey{
+		ID:      2,
+		UserID:  user.ID,
+		Name:    "my key",
+		Prefix:  "some-prefix",
+		Token:   "some-prefix:some-token",
+		Scopes:  []string{model.ApiKeyScopeRead},
+		Created: tstTs,
+	}
+
+	t.Run("create should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewApiKey(ctx, user.ID, apikey.Name, apikey.Scopes, gomock.Any()).
+			DoAndReturn(func(
+				_ context.Context, _ int, _ string, _ []string, expires *time.Time,
+			) (*model.ApiKey, errs.Error) {
+				assert.True(t, expires != nil)
+				assert.True(t, expires.After(time.Now().AddDate(0, 0, 29)))
+				return apikey, nil
+			})
+
+		data := url.Values{
+			"name":    {apikey.Name},
+			"scopes":  apikey.Scopes,
+			"expires": {"30"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/api/keys", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyCreate(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		messages := handler.sessMgr.FlashPopAll(ctx)
+		assert.Equal(t, messages,
+			map[string][]string{
+				"success": {"Api key created. Copy it now, it will not be shown again: " + apikey.Token},
+			},
+		)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("create without expiry should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewApiKey(ctx, user.ID, apikey.Name, apikey.Scopes, (*time.Time)(nil)).
+			Return(apikey, nil)
+
+		data := url.Values{
+			"name":   {apikey.Name},
+			"scopes": apikey.Scopes,
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/api/keys", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyCreate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+	})
+
+	t.Run("create with missing name should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		data := url.Values{"scopes": apikey.Scopes}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/api/keys", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyCreate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("create with bad expires should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		data := url.Values{
+			"name":    {apikey.Name},
+			"scopes":  apikey.Scopes,
+			"expires": {"1000"},
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/api/keys", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyCreate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("create without scopes should flash error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewApiKey(ctx, user.ID, apikey.Name, nil, (*time.Time)(nil)).
+			Return(nil, errs.ArgumentError("scopes", "at least one scope is required"))
+
+		data := url.Values{"name": {apikey.Name}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/api/keys", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyCreate(rr, req)
+
+		messages := handler.sessMgr.FlashPopAll(ctx)
+		assert.Equal(t, messages,
+			map[string][]string{
+				"error": {"Api key requires at least one valid scope"},
+			},
+		)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+	})
+
+	t.Run("create over limit should flash error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewApiKey(ctx, user.ID, apikey.Name, apikey.Scopes, (*time.Time)(nil)).
+			Return(nil, errs.ResourceExhausted.Error("too many api keys"))
+
+		data := url.Values{
+			"name":   {apikey.Name},
+			"scopes": apikey.Scopes,
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/auth/api/keys", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyCreate(rr, req)
+
+		messages := handler.sessMgr.FlashPopAll(ctx)
+		assert.Equal(t, messages,
+			map[string][]string{
+				"error": {"Too many api keys"},
+			},
+		)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+	})
+}
+
+func TestHandler_ApiKeyDelete(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		ApiAccess:    true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("delete should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			DeleteApiKey(ctx, user.ID, "some-prefix").
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/auth/api/keys", nil)
+		req.SetPathValue("kPrefix", "some-prefix")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyDelete(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+
+	t.Run("delete other user key should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			DeleteApiKey(ctx, user.ID, "some-prefix").
+			Return(errs.PermissionDenied.Error("permission denied"))
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/auth/api/keys", nil)
+		req.SetPathValue("kPrefix", "some-prefix")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyDelete(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusForbidden)
+	})
+
+	t.Run("delete missing key should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			DeleteApiKey(ctx, user.ID, "some-prefix").
+			Return(errs.NotFound.Error("api key not found"))
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/auth/api/keys", nil)
+		req.SetPathValue("kPrefix", "some-prefix")
+		rr := httptest.NewRecorder()
+		handler.ApiKeyDelete(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+}
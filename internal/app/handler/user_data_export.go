@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+func (x *Handler) AccountExportRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	if _, errx := x.svc.RequestUserDataExport(ctx, user.ID); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success",
+		"Your data export is being prepared. A download link will be emailed to you once it is ready.")
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+func (x *Handler) AccountExportDownload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	hmacStr := r.PathValue("hmac")
+	refIDStr := r.PathValue("exRefID")
+	if hmacStr == "" || refIDStr == "" {
+		slog.DebugContext(ctx, "missing url query data")
+		x.NotFoundError(w)
+		return
+	}
+
+	// decode hmac
+	hmacBytes, err := encoder.Base32DecodeString(hmacStr)
+	if err != nil {
+		slog.DebugContext(ctx, "error decoding hmac data", logger.Err(err))
+		x.BadRequestError(w, "Bad Request Data")
+		return
+	}
+	// check hmac
+	if !x.cMAC.Validate([]byte(service.DataExportLinkPrefix+refIDStr), hmacBytes) {
+		slog.DebugContext(ctx, "invalid hmac!")
+		x.BadRequestError(w, "Bad Request Data")
+		return
+	}
+
+	// hmac checks out. ok to parse refid now.
+	refID, err := service.ParseUserDataExportRefID(refIDStr)
+	if err != nil {
+		x.BadRefIDError(w, "export", err)
+		return
+	}
+
+	export, errx := x.svc.GetUserDataExport(ctx, user.ID, refID)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+			return
+		case errs.NotFound, errs.FailedPrecondition:
+			slog.DebugContext(ctx, "export not usable", logger.Err(errx))
+			x.sessMgr.FlashAppend(ctx, "error",
+				"Export link is invalid or has expired.")
+		default:
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		return
+	}
+
+	filename := fmt.Sprintf("icanbringthat-export-%s.zip",
+		export.Completed.Format("20060102"))
+	w.Header().Set("content-type", "application/zip")
+	w.Header().Set("content-disposition",
+		fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("content-length", strconv.Itoa(len(export.Data)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(export.Data); err != nil {
+		slog.DebugContext(ctx, "error writing export", logger.Err(err))
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_AccountExportRequest(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("request should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			RequestUserDataExport(ctx, user.ID).
+			Return(&model.UserDataExport{
+				ID:     2,
+				RefID:  util.Must(model.NewUserDataExportRefID()),
+				UserID: user.ID,
+			}, nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/export", nil)
+		rr := httptest.NewRecorder()
+		handler.AccountExportRequest(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+	})
+}
+
+func TestHandler_AccountExportDownload(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("download should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		completed := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		export := &model.UserDataExport{
+			ID:        2,
+			RefID:     util.Must(model.NewUserDataExportRefID()),
+			UserID:    user.ID,
+			Data:      []byte("zip-data"),
+			Completed: &completed,
+		}
+		refIDStr := export.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(service.DataExportLinkPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserDataExport(ctx, user.ID, export.RefID).
+			Return(export, nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/export", nil)
+		req.SetPathValue("exRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountExportDownload(rr, req)
+
+		response := rr.Result()
+		body := util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+		assert.Equal(t, rr.Header().Get("content-type"), "application/zip")
+		assert.Equal(t, rr.Header().Get("content-disposition"),
+			`attachment; filename="icanbringthat-export-20300102.zip"`)
+		assert.Equal(t, body, []byte("zip-data"))
+	})
+
+	t.Run("download with bad hmac should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		refIDStr := util.Must(model.NewUserDataExportRefID()).String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refIDStr)))
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/export", nil)
+		req.SetPathValue("exRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountExportDownload(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("download expired should redirect", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewUserDataExportRefID())
+		refIDStr := refID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(service.DataExportLinkPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserDataExport(ctx, user.ID, refID).
+			Return(nil, errs.NotFound.Error("export expired"))
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/export", nil)
+		req.SetPathValue("exRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountExportDownload(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "error"),
+			[]string{"Export link is invalid or has expired."})
+	})
+
+	t.Run("download export of other user should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewUserDataExportRefID())
+		refIDStr := refID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(service.DataExportLinkPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserDataExport(ctx, user.ID, refID).
+			Return(nil, errs.PermissionDenied.Error("permission denied"))
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/export", nil)
+		req.SetPathValue("exRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.AccountExportDownload(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+// startEmailChange records a pending email change, then sends a
+// confirmation link to the new address and a revert link to the old one.
+func (x *Handler) startEmailChange(ctx context.Context,
+	user *model.User, newEmail string,
+) errs.Error {
+	change, errx := x.svc.NewUserEmailChange(ctx, user, newEmail)
+	if errx != nil {
+		return errx
+	}
+	return service.SendUserEmailChangeMails(ctx,
+		x.mailer, x.templates, x.cMAC, x.baseURL, change)
+}
+
+// emailChangeFromRequest checks the signature of an email change link,
+// and parses the refid from it.
+func (x *Handler) emailChangeFromRequest(w http.ResponseWriter, r *http.Request,
+	prefix string,
+) (model.UserEmailChangeRefID, bool) {
+	ctx := r.Context()
+	var refID model.UserEmailChangeRefID
+
+	hmacStr := r.PathValue("hmac")
+	refIDStr := r.PathValue("ecRefID")
+	if hmacStr == "" || refIDStr == "" {
+		slog.DebugContext(ctx, "missing url query data")
+		x.NotFoundError(w)
+		return refID, false
+	}
+
+	// decode hmac
+	hmacBytes, err := encoder.Base32DecodeString(hmacStr)
+	if err != nil {
+		slog.DebugContext(ctx, "error decoding hmac data", logger.Err(err))
+		x.BadRequestError(w, "Bad Request Data")
+		return refID, false
+	}
+	// check hmac
+	if !x.cMAC.Validate([]byte(prefix+refIDStr), hmacBytes) {
+		slog.DebugContext(ctx, "invalid hmac!")
+		x.BadRequestError(w, "Bad Request Data")
+		return refID, false
+	}
+
+	// hmac checks out. ok to parse refid now.
+	refID, err = service.ParseUserEmailChangeRefID(refIDStr)
+	if err != nil {
+		x.BadRefIDError(w, "email-change", err)
+		return refID, false
+	}
+	return refID, true
+}
+
+func (x *Handler) EmailChangeConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	refID, ok := x.emailChangeFromRequest(w, r, "")
+	if !ok {
+		return
+	}
+
+	change, errx := x.svc.GetUserEmailChangeByRefID(ctx, refID)
+	if errx != nil {
+		slog.DebugContext(ctx, "no email change match", logger.Err(errx))
+		x.NotFoundError(w)
+		return
+	}
+
+	errx = x.svc.ConfirmUserEmailChange(ctx, user, change)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+			return
+		case errs.NotFound, errs.FailedPrecondition:
+			slog.DebugContext(ctx, "email change not usable", logger.Err(errx))
+			x.sessMgr.FlashAppend(ctx, "error",
+				"Email change link is invalid or has expired.")
+		case errs.AlreadyExists:
+			x.sessMgr.FlashAppend(ctx, "error", "Email is already in use")
+		default:
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", "Email update successfull")
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+// EmailChangeShowRevert shows a button to revert the change, rather than
+// reverting directly. Mail scanners that prefetch links would otherwise
+// revert every change.
+func (x *Handler) EmailChangeShowRevert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	refID, ok := x.emailChangeFromRequest(w, r, service.EmailChangeRevertPrefix)
+	if !ok {
+		return
+	}
+
+	change, errx := x.svc.GetUserEmailChangeByRefID(ctx, refID)
+	if errx != nil ||
+		service.IsTimerExpired(change.RefID, model.UserEmailChangeRevertExpiry) {
+		slog.DebugContext(ctx, "email change not found or expired", "error", errx)
+		x.sessMgr.FlashAppend(ctx, "error", "Email change link is invalid or has expired.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	tplVars := MapSA{
+		"title":     "Revert Email Change",
+		"flashes":   x.sessMgr.FlashPopAll(ctx),
+		"oldEmail":  change.OldEmail,
+		"newEmail":  change.NewEmail,
+		"confirmed": change.Confirmed != nil,
+		"refID":     r.PathValue("ecRefID"),
+		"hmac":      r.PathValue("hmac"),
+	}
+	w.Header().Set("content-type", "text/html")
+	err := x.TemplateExecute(w, "email-change-revert.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
+
+func (x *Handler) EmailChangeRevert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	refID, ok := x.emailChangeFromRequest(w, r, service.EmailChangeRevertPrefix)
+	if !ok {
+		return
+	}
+
+	change, errx := x.svc.GetUserEmailChangeByRefID(ctx, refID)
+	if errx == nil {
+		errx = x.svc.RevertUserEmailChange(ctx, change)
+	}
+	if errx != nil {
+		switch errx.Code() {
+		case errs.NotFound:
+			slog.DebugContext(ctx, "email change not usable", logger.Err(errx))
+			x.sessMgr.FlashAppend(ctx, "error",
+				"Email change link is invalid or has expired.")
+		case errs.AlreadyExists:
+			x.sessMgr.FlashAppend(ctx, "error",
+				"The previous email is now used by another account.")
+		default:
+			x.InternalServerError(w, errx.Msg())
+			return
+		}
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if change.Confirmed == nil {
+		x.sessMgr.FlashAppend(ctx, "success", "Email change cancelled.")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	// the change may have been made by someone else, so the current
+	// session is ended too, if it belongs to the same user.
+	if user, err := auth.UserFromContext(ctx); err == nil && user.ID == change.UserID {
+		if err := x.sessMgr.Clear(ctx); err != nil {
+			x.InternalServerError(w, "Session Error")
+			return
+		}
+		if err := x.sessMgr.RenewToken(ctx); err != nil {
+			x.InternalServerError(w, "Session Error")
+			return
+		}
+	}
+	x.sessMgr.FlashAppend(ctx, "success",
+		"Email change reverted, and all sessions signed out. "+
+			"If you did not make the change, reset your password.")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_EmailChangeConfirm(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("confirm should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: user.Email,
+			NewEmail: "new@example.com",
+		}
+		refIDStr := change.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refIDStr)))
+
+		mock.EXPECT().
+			GetUserEmailChangeByRefID(ctx, change.RefID).
+			Return(change, nil)
+		mock.EXPECT().
+			ConfirmUserEmailChange(ctx, user, change).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/email", nil)
+		req.SetPathValue("ecRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.EmailChangeConfirm(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "success"),
+			[]string{"Email update successfull"})
+	})
+
+	t.Run("confirm with revert hmac should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		refIDStr := util.Must(model.NewUserEmailChangeRefID()).String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(service.EmailChangeRevertPrefix + refIDStr)))
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/email", nil)
+		req.SetPathValue("ecRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.EmailChangeConfirm(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("confirm expired should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: user.Email,
+			NewEmail: "new@example.com",
+		}
+		refIDStr := change.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refIDStr)))
+
+		mock.EXPECT().
+			GetUserEmailChangeByRefID(ctx, change.RefID).
+			Return(change, nil)
+		mock.EXPECT().
+			ConfirmUserEmailChange(ctx, user, change).
+			Return(errs.NotFound.Error("email change expired"))
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/settings/email", nil)
+		req.SetPathValue("ecRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.EmailChangeConfirm(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "error"),
+			[]string{"Email change link is invalid or has expired."})
+	})
+}
+
+func TestHandler_EmailChangeRevert(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "new@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("revert pending should cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: "user@example.com",
+			NewEmail: "new@example.com",
+		}
+		refIDStr := change.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(service.EmailChangeRevertPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserEmailChangeByRefID(ctx, change.RefID).
+			Return(change, nil)
+		mock.EXPECT().
+			RevertUserEmailChange(ctx, change).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/email-change", nil)
+		req.SetPathValue("ecRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.EmailChangeRevert(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.FlashPopKey(ctx, "success"),
+			[]string{"Email change cancelled."})
+	})
+
+	t.Run("revert confirmed should log out", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		handler.sessMgr.Put(ctx, "user-id", user.ID)
+		confirmed := time.Now()
+		change := &model.UserEmailChange{
+			ID:        5,
+			RefID:     util.Must(model.NewUserEmailChangeRefID()),
+			UserID:    user.ID,
+			OldEmail:  "user@example.com",
+			NewEmail:  "new@example.com",
+			Confirmed: &confirmed,
+		}
+		refIDStr := change.RefID.String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(service.EmailChangeRevertPrefix + refIDStr)))
+
+		mock.EXPECT().
+			GetUserEmailChangeByRefID(ctx, change.RefID).
+			Return(change, nil)
+		mock.EXPECT().
+			RevertUserEmailChange(ctx, change).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/email-change", nil)
+		req.SetPathValue("ecRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.EmailChangeRevert(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+	})
+
+	t.Run("revert with confirm hmac should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		refIDStr := util.Must(model.NewUserEmailChangeRefID()).String()
+		macStr := encoder.Base32EncodeToString(
+			handler.cMAC.Generate([]byte(refIDStr)))
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/email-change", nil)
+		req.SetPathValue("ecRefID", refIDStr)
+		req.SetPathValue("hmac", macStr)
+		rr := httptest.NewRecorder()
+		handler.EmailChangeRevert(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+// rememberLogin keeps the "remember me" choice made at the start of a
+// sign-in, for startUserSession to apply once the user is logged in.
+func (x *Handler) rememberLogin(ctx context.Context, r *http.Request) {
+	x.sessMgr.Put(ctx, "remember-me", r.FormValue("remember") != "")
+}
+
+// startUserSession logs user in to the current session, and indexes the
+// session so it can be listed and revoked later. The session token
+// should be renewed before calling.
+func (x *Handler) startUserSession(r *http.Request, user *model.User) errs.Error {
+	ctx := r.Context()
+	// only has an effect when session cookies are not persistent by default
+	if remember, ok := x.sessMgr.Pop(ctx, "remember-me").(bool); ok {
+		x.sessMgr.RememberMe(ctx, remember)
+	}
+	ipAddress, userAgent := loginSource(r)
+	sess, errx := x.svc.NewUserSession(ctx,
+		user.ID, ipAddress, userAgent, x.sessMgr.Deadline(ctx))
+	if errx != nil {
+		return errx
+	}
+	x.sessMgr.Put(ctx, "user-id", user.ID)
+	x.sessMgr.Put(ctx, "session-id", sess.RefID.String())
+	return nil
+}
+
+// currentSessionRefID returns the index id of the current session, if
+// there is one.
+func (x *Handler) currentSessionRefID(ctx context.Context) (model.UserSessionRefID, bool) {
+	refID, err := service.ParseUserSessionRefID(x.sessMgr.GetString(ctx, "session-id"))
+	return refID, err == nil
+}
+
+// revokeOtherSessions logs the user out everywhere except the current
+// session.
+func (x *Handler) revokeOtherSessions(ctx context.Context, userID int) errs.Error {
+	if refID, ok := x.currentSessionRefID(ctx); ok {
+		return x.svc.DeleteOtherUserSessions(ctx, userID, refID)
+	}
+	return x.svc.DeleteUserSessions(ctx, userID)
+}
+
+func (x *Handler) UserSessionDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	refID, err := service.ParseUserSessionRefID(r.PathValue("sRefID"))
+	if err != nil {
+		x.BadRefIDError(w, "session", err)
+		return
+	}
+
+	if current, ok := x.currentSessionRefID(ctx); ok && current == refID {
+		x.BadRequestError(w, "use logout to end the current session")
+		return
+	}
+
+	errx := x.svc.DeleteUserSession(ctx, user.ID, refID)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		case errs.NotFound:
+			slog.InfoContext(ctx, "session not found", "error", errx)
+			x.NotFoundError(w)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UserSessionDeleteAll signs the user out everywhere, including the
+// current session.
+func (x *Handler) UserSessionDeleteAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	errx := x.svc.DeleteUserSessions(ctx, user.ID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	if err := x.sessMgr.Clear(ctx); err != nil {
+		x.InternalServerError(w, "Session Error")
+		return
+	}
+	if err := x.sessMgr.RenewToken(ctx); err != nil {
+		x.InternalServerError(w, "Session Error")
+		return
+	}
+	x.sessMgr.FlashAppend(ctx, "success", "Signed out of all sessions")
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dropwhile/assert"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_UserSessionDelete(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("delete should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		current := util.Must(model.NewUserSessionRefID())
+		handler.sessMgr.Put(ctx, "session-id", current.String())
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.EXPECT().
+			DeleteUserSession(ctx, user.ID, refID).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/sessions", nil)
+		req.SetPathValue("sRefID", refID.String())
+		rr := httptest.NewRecorder()
+		handler.UserSessionDelete(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+
+	t.Run("delete current session should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		current := util.Must(model.NewUserSessionRefID())
+		handler.sessMgr.Put(ctx, "session-id", current.String())
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/sessions", nil)
+		req.SetPathValue("sRefID", current.String())
+		rr := httptest.NewRecorder()
+		handler.UserSessionDelete(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("delete session of other user should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.EXPECT().
+			DeleteUserSession(ctx, user.ID, refID).
+			Return(errs.PermissionDenied.Error("permission denied"))
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/sessions", nil)
+		req.SetPathValue("sRefID", refID.String())
+		rr := httptest.NewRecorder()
+		handler.UserSessionDelete(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusForbidden)
+	})
+
+	t.Run("delete with bad refid should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/sessions", nil)
+		req.SetPathValue("sRefID", util.Must(model.NewCredentialRefID()).String())
+		rr := httptest.NewRecorder()
+		handler.UserSessionDelete(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+}
+
+func TestHandler_UserSessionDeleteAll(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("delete all should log out", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		handler.sessMgr.Put(ctx, "user-id", user.ID)
+		handler.sessMgr.Put(ctx, "session-id",
+			util.Must(model.NewUserSessionRefID()).String())
+
+		mock.EXPECT().
+			DeleteUserSessions(ctx, user.ID).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/sessions/revoke-all", nil)
+		rr := httptest.NewRecorder()
+		handler.UserSessionDeleteAll(rr, req)
+
+		response := rr.Result()
+		util.MustReadAll(response.Body)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/login",
+			"handler returned wrong redirect")
+		assert.Equal(t, handler.sessMgr.GetInt(ctx, "user-id"), 0)
+		assert.Equal(t, handler.sessMgr.GetString(ctx, "session-id"), "")
+	})
+}
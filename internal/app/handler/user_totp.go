@@ -0,0 +1,207 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/qrcode"
+)
+
+func (x *Handler) TOTPEnrollShowForm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	enrollment, errx := x.svc.StartTOTPEnrollment(ctx, user)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.FailedPrecondition:
+			x.sessMgr.FlashAppend(ctx, "error", errx.Msg())
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	qr, err := qrcode.Encode([]byte(enrollment.KeyURI))
+	if err != nil {
+		slog.ErrorContext(ctx, "error encoding totp qr code", logger.Err(err))
+		x.InternalServerError(w, "error encoding qr code")
+		return
+	}
+
+	tplVars := MapSA{
+		"user":  user,
+		"title": "Enable Two-Factor Authentication",
+		"nav":   "settings",
+		// svg is generated entirely by the qrcode package
+		"qrcode":  template.HTML(qr.SVG()), // #nosec G203 -- not user input
+		"secret":  enrollment.Secret,
+		"flashes": x.sessMgr.FlashPopAll(ctx),
+	}
+	w.Header().Set("content-type", "text/html")
+	err = x.TemplateExecute(w, "totp-enroll-form.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
+
+func (x *Handler) TOTPEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return
+	}
+
+	code := r.PostFormValue("code")
+	if code == "" {
+		x.BadFormDataError(w, nil, "code")
+		return
+	}
+
+	codes, errx := x.svc.ConfirmTOTPEnrollment(ctx, user.ID, code)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.InvalidArgument:
+			x.sessMgr.FlashAppend(ctx, "error", "Invalid two-factor code. Please try again.")
+			http.Redirect(w, r, "/settings/auth/totp", http.StatusSeeOther)
+		case errs.FailedPrecondition:
+			x.sessMgr.FlashAppend(ctx, "error", errx.Msg())
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", "Two-factor authentication enabled")
+	x.renderRecoveryCodes(w, r, codes)
+}
+
+func (x *Handler) TOTPDisable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	if !x.verifySettingsTOTP(w, r, user.ID) {
+		return
+	}
+
+	errx := x.svc.DisableTOTP(ctx, user.ID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", "Two-factor authentication disabled")
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+func (x *Handler) TOTPRecoveryCodesRegenerate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	if !x.verifySettingsTOTP(w, r, user.ID) {
+		return
+	}
+
+	codes, errx := x.svc.RegenerateRecoveryCodes(ctx, user.ID)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.FailedPrecondition:
+			x.sessMgr.FlashAppend(ctx, "error", errx.Msg())
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success", "New recovery codes generated")
+	x.renderRecoveryCodes(w, r, codes)
+}
+
+// verifySettingsTOTP checks the current two-factor code submitted with
+// a settings change, so a hijacked session alone can't weaken the
+// account's authentication.
+func (x *Handler) verifySettingsTOTP(w http.ResponseWriter, r *http.Request, userID int) bool {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return false
+	}
+
+	code := r.PostFormValue("code")
+	if code == "" {
+		x.BadFormDataError(w, nil, "code")
+		return false
+	}
+
+	errx := x.svc.VerifyTOTP(ctx, userID, code)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied, errs.InvalidArgument, errs.FailedPrecondition:
+			x.sessMgr.FlashAppend(ctx, "error", "Invalid two-factor code")
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return false
+	}
+	return true
+}
+
+func (x *Handler) renderRecoveryCodes(w http.ResponseWriter, r *http.Request, codes []string) {
+	ctx := r.Context()
+	user, _ := auth.UserFromContext(ctx)
+
+	tplVars := MapSA{
+		"user":    user,
+		"title":   "Recovery Codes",
+		"nav":     "settings",
+		"codes":   codes,
+		"flashes": x.sessMgr.FlashPopAll(ctx),
+	}
+	// recovery codes are only ever shown once
+	w.Header().Set("cache-control", "no-store")
+	w.Header().Set("content-type", "text/html")
+	err := x.TemplateExecute(w, "show-recovery-codes.gohtml", tplVars)
+	if err != nil {
+		x.TemplateError(w)
+		return
+	}
+}
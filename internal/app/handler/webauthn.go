@@ -13,6 +13,7 @@ import (
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
 
+	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
@@ -196,6 +197,7 @@ func (x *Handler) WebAuthnBeginLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	x.sessMgr.Put(ctx, "webauthn-session:login", val)
+	x.rememberLogin(ctx, r)
 	x.Json(w, http.StatusOK, options)
 }
 
@@ -229,9 +231,10 @@ func (x *Handler) WebAuthnFinishLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var userID int
+	var loginUser *model.User
+	var lockoutErr errs.Error
 	// needs to be inline here (as opposed to a defined function elsewhere)
-	// so we can capture the discovered userID value
+	// so we can capture the discovered user value
 	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
 		// rawID is the credentialID
 		// userHandler is user.WebauthnID
@@ -246,14 +249,33 @@ func (x *Handler) WebAuthnFinishLogin(w http.ResponseWriter, r *http.Request) {
 		if !user.WebAuthn {
 			return nil, fmt.Errorf("user found but webauthn disabled")
 		}
-		// capture userID for session login operation after auth success
-		userID = user.ID
+		if errx := x.svc.CheckLoginAllowed(ctx, user.ID); errx != nil {
+			lockoutErr = errx
+			return nil, fmt.Errorf("login not allowed: %w", errx)
+		}
+		// capture user for session login operation after auth success
+		loginUser = user
 		authNUser := x.svc.WebAuthnUserFrom(user)
 		return authNUser, nil
 	}
 	_, err = authnInstance.FinishDiscoverableLogin(handler, sessionData, r)
 	if err != nil {
 		slog.InfoContext(ctx, "error finishing webauthn login", "error", err)
+		if lockoutErr != nil {
+			if lockoutErr.Code() == errs.ResourceExhausted {
+				x.Json(w, http.StatusTooManyRequests, MapSA{
+					"error": "Too many failed login attempts. Please try again later.",
+				})
+			} else {
+				x.Json(w, http.StatusInternalServerError,
+					MapSA{"error": "Passkey login failed"},
+				)
+			}
+			return
+		}
+		if loginUser != nil {
+			x.recordLoginFailure(r, loginUser.ID, model.LoginMethodWebAuthn)
+		}
 		x.Json(w, http.StatusForbidden, MapSA{"error": "Passkey login failed"})
 		return
 	}
@@ -267,7 +289,11 @@ func (x *Handler) WebAuthnFinishLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Then make the privilege-level change.
-	x.sessMgr.Put(r.Context(), "user-id", userID)
+	if errx := x.startUserSession(r, loginUser); errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+	x.recordLoginSuccess(r, loginUser, model.LoginMethodWebAuthn)
 	x.sessMgr.FlashAppend(ctx, "success", "Login successful")
 	x.Json(w, http.StatusOK, MapSA{"verified": true})
 }
@@ -307,5 +333,12 @@ func (x *Handler) WebAuthnDeleteKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// sessions started with the removed key should not outlive it
+	errx = x.revokeOtherSessions(ctx, user.ID)
+	if errx != nil {
+		x.InternalServerError(w, errx.Msg())
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
@@ -77,6 +77,9 @@ func TestHandler_DeleteWebAuthnKey(t *testing.T) {
 		mock.EXPECT().
 			DeleteUserCredential(ctx, user, credential.RefID).
 			Return(nil)
+		mock.EXPECT().
+			DeleteUserSessions(ctx, user.ID).
+			Return(nil)
 
 		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/event", nil)
 		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
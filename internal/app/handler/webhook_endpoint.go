@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+func (x *Handler) WebhookEndpointCreate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return
+	}
+
+	endpointURL := r.PostFormValue("url")
+	if endpointURL == "" {
+		x.BadFormDataError(w, nil, "url")
+		return
+	}
+
+	endpoint, errx := x.svc.NewWebhookEndpoint(ctx, user.ID,
+		endpointURL, r.PostFormValue("secret"), r.PostForm["event_types"])
+	if errx != nil {
+		switch errx.Code() {
+		case errs.InvalidArgument:
+			x.sessMgr.FlashAppend(ctx, "error",
+				"Webhook "+errx.Meta("argument")+" was a bad value")
+		case errs.ResourceExhausted:
+			x.sessMgr.FlashAppend(ctx, "error", "Too many webhooks")
+		default:
+			slog.ErrorContext(ctx, "error creating webhook",
+				logger.Err(errx))
+			x.InternalServerError(w, "error creating webhook")
+			return
+		}
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+		return
+	}
+
+	x.sessMgr.FlashAppend(ctx, "success",
+		"Webhook added. Signing secret: "+endpoint.Secret)
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+func (x *Handler) WebhookEndpointUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	endpointRefID, err := service.ParseWebhookEndpointRefID(r.PathValue("wRefID"))
+	if err != nil {
+		x.BadRefIDError(w, "webhook", err)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		x.BadFormDataError(w, err)
+		return
+	}
+
+	updateVals := &service.WebhookEndpointUpdateValues{}
+	switch r.PostFormValue("enabled") {
+	case "on":
+		updateVals.Enabled = mo.Some(true)
+	case "off":
+		updateVals.Enabled = mo.Some(false)
+	case "":
+		// nothing
+	default:
+		x.BadFormDataError(w, nil, "enabled")
+		return
+	}
+	if endpointURL := r.PostFormValue("url"); endpointURL != "" {
+		updateVals.URL = mo.Some(endpointURL)
+	}
+	if _, ok := r.PostForm["event_types"]; ok {
+		updateVals.EventTypes = mo.Some(r.PostForm["event_types"])
+	}
+
+	errx := x.svc.UpdateWebhookEndpoint(ctx, user.ID, endpointRefID, updateVals)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		case errs.NotFound:
+			x.NotFoundError(w)
+		case errs.InvalidArgument:
+			x.BadFormDataError(w, errx, errx.Meta("argument"))
+		default:
+			slog.ErrorContext(ctx, "error updating webhook",
+				logger.Err(errx))
+			x.InternalServerError(w, "error updating webhook")
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/settings", http.StatusSeeOther)
+}
+
+func (x *Handler) WebhookEndpointDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// get user from session
+	user, err := auth.UserFromContext(ctx)
+	if err != nil {
+		x.BadSessionDataError(w)
+		return
+	}
+
+	endpointRefID, err := service.ParseWebhookEndpointRefID(r.PathValue("wRefID"))
+	if err != nil {
+		x.BadRefIDError(w, "webhook", err)
+		return
+	}
+
+	errx := x.svc.DeleteWebhookEndpoint(ctx, user.ID, endpointRefID)
+	if errx != nil {
+		switch errx.Code() {
+		case errs.PermissionDenied:
+			x.AccessDeniedError(w)
+		case errs.NotFound:
+			x.NotFoundError(w)
+		default:
+			x.InternalServerError(w, errx.Msg())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,368 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"github.com/go-chi/chi/v5"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestHandler_WebhookEndpointCreate(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	endpoint := &model.WebhookEndpoint{
+		ID:         2,
+		RefID:      util.Must(model.NewWebhookEndpointRefID()),
+		UserID:     user.ID,
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{"item-added"},
+		Enabled:    true,
+	}
+
+	t.Run("create should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewWebhookEndpoint(ctx, user.ID, endpoint.URL, "", endpoint.EventTypes).
+			Return(endpoint, nil)
+
+		data := url.Values{
+			"url":         {endpoint.URL},
+			"event_types": endpoint.EventTypes,
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointCreate(rr, req)
+
+		response := rr.Result()
+		_, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		messages := handler.sessMgr.FlashPopAll(ctx)
+		assert.Equal(t, messages,
+			map[string][]string{
+				"success": {"Webhook added. Signing secret: " + endpoint.Secret},
+			},
+		)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("create with missing url should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		data := url.Values{"secret": {endpoint.Secret}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointCreate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("create with bad url should flash error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewWebhookEndpoint(ctx, user.ID, "hodor", "", nil).
+			Return(nil, errs.ArgumentError("url", "bad value"))
+
+		data := url.Values{"url": {"hodor"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointCreate(rr, req)
+
+		messages := handler.sessMgr.FlashPopAll(ctx)
+		assert.Equal(t, messages,
+			map[string][]string{
+				"error": {"Webhook url was a bad value"},
+			},
+		)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+	})
+
+	t.Run("create over limit should flash error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewWebhookEndpoint(ctx, user.ID, endpoint.URL, "", nil).
+			Return(nil, errs.ResourceExhausted.Error("too many webhooks"))
+
+		data := url.Values{"url": {endpoint.URL}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointCreate(rr, req)
+
+		messages := handler.sessMgr.FlashPopAll(ctx)
+		assert.Equal(t, messages,
+			map[string][]string{
+				"error": {"Too many webhooks"},
+			},
+		)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+	})
+}
+
+func TestHandler_WebhookEndpointUpdate(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	endpointRefID := util.Must(model.NewWebhookEndpointRefID())
+
+	t.Run("disable should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			UpdateWebhookEndpoint(ctx, user.ID, endpointRefID,
+				&service.WebhookEndpointUpdateValues{Enabled: mo.Some(false)}).
+			Return(nil)
+
+		data := url.Values{"enabled": {"off"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("wRefID", endpointRefID.String())
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointUpdate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+		assert.Equal(t, rr.Header().Get("location"), "/settings",
+			"handler returned wrong redirect")
+	})
+
+	t.Run("enable should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			UpdateWebhookEndpoint(ctx, user.ID, endpointRefID,
+				&service.WebhookEndpointUpdateValues{Enabled: mo.Some(true)}).
+			Return(nil)
+
+		// checkbox followed by hidden fallback input
+		data := url.Values{"enabled": {"on", "off"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("wRefID", endpointRefID.String())
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointUpdate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusSeeOther)
+	})
+
+	t.Run("update with bad enabled value should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		data := url.Values{"enabled": {"hodor"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("wRefID", endpointRefID.String())
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointUpdate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("update other user webhook should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			UpdateWebhookEndpoint(ctx, user.ID, endpointRefID,
+				&service.WebhookEndpointUpdateValues{Enabled: mo.Some(false)}).
+			Return(errs.PermissionDenied.Error("permission denied"))
+
+		data := url.Values{"enabled": {"off"}}
+
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.com/settings/webhooks", FormData(data))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("wRefID", endpointRefID.String())
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointUpdate(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusForbidden)
+	})
+}
+
+func TestHandler_WebhookEndpointDelete(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	endpointRefID := util.Must(model.NewWebhookEndpointRefID())
+
+	t.Run("delete should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			DeleteWebhookEndpoint(ctx, user.ID, endpointRefID).
+			Return(nil)
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/webhooks", nil)
+		req.SetPathValue("wRefID", endpointRefID.String())
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointDelete(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusOK)
+	})
+
+	t.Run("delete missing webhook should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		mock, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		mock.EXPECT().
+			DeleteWebhookEndpoint(ctx, user.ID, endpointRefID).
+			Return(errs.NotFound.Error("webhook not found"))
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/webhooks", nil)
+		req.SetPathValue("wRefID", endpointRefID.String())
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointDelete(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+
+	t.Run("delete with bad refid should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.TODO()
+		_, _, handler := SetupHandler(t, ctx)
+		ctx, _ = handler.sessMgr.Load(ctx, "")
+		ctx = auth.ContextSet(ctx, "user", user)
+		rctx := chi.NewRouteContext()
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+		req, _ := http.NewRequestWithContext(ctx, "DELETE", "http://example.com/settings/webhooks", nil)
+		req.SetPathValue("wRefID", "hodor")
+		rr := httptest.NewRecorder()
+		handler.WebhookEndpointDelete(rr, req)
+
+		// Check the status code is what we expect.
+		AssertStatusEqual(t, rr, http.StatusNotFound)
+	})
+}
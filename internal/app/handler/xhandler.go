@@ -19,30 +19,38 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/crypto"
 	"github.com/dropwhile/icanbringthat/internal/logger"
 	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	"github.com/dropwhile/icanbringthat/internal/session"
 	"github.com/dropwhile/icanbringthat/internal/validate"
 )
 
 type Handler struct {
-	redis     *redis.Client
-	templates resources.TGetter
-	sessMgr   session.SessionManager
-	mailer    mail.MailSender
-	cMAC      crypto.HMACer
-	svc       service.Servicer
-	baseURL   string
-	isProd    bool
+	redis      *redis.Client
+	broker     pubsub.Broker
+	templates  resources.TGetter
+	sessMgr    session.SessionManager
+	mailer     mail.MailSender
+	cMAC       crypto.HMACer
+	svc        service.Servicer
+	oidc       *oidc.Provider
+	baseURL    string
+	isProd     bool
+	rememberMe bool
 }
 
 type Options struct { // betteralign:ignore
-	Db           model.PgxHandle        `validate:"required"`
-	Redis        *redis.Client          `validate:"required"`
+	Db           model.PgxHandle `validate:"required"`
+	Redis        *redis.Client
+	Broker       pubsub.Broker          `validate:"required"`
 	Templates    resources.TGetter      `validate:"required"`
 	SessMgr      session.SessionManager `validate:"required"`
 	Mailer       mail.MailSender        `validate:"required"`
 	HMACKeyBytes []byte                 `validate:"required"`
 	BaseURL      string                 `validate:"required"`
+	OIDC         *oidc.Provider
 	IsProd       bool
+	RememberMe   bool
 }
 
 func New(opts Options) (*Handler, error) {
@@ -58,14 +66,21 @@ func New(opts Options) (*Handler, error) {
 
 	cMAC := crypto.NewMAC(opts.HMACKeyBytes)
 	handler := &Handler{
-		redis:     opts.Redis,
-		templates: opts.Templates,
-		sessMgr:   opts.SessMgr,
-		mailer:    opts.Mailer,
-		cMAC:      cMAC,
-		baseURL:   opts.BaseURL,
-		isProd:    opts.IsProd,
-		svc:       &service.Service{Db: opts.Db},
+		redis:      opts.Redis,
+		broker:     opts.Broker,
+		templates:  opts.Templates,
+		sessMgr:    opts.SessMgr,
+		mailer:     opts.Mailer,
+		cMAC:       cMAC,
+		oidc:       opts.OIDC,
+		baseURL:    opts.BaseURL,
+		isProd:     opts.IsProd,
+		rememberMe: opts.RememberMe,
+		svc: service.New(service.Options{
+			Db:           opts.Db,
+			Broker:       opts.Broker,
+			HMACKeyBytes: opts.HMACKeyBytes,
+		}),
 	}
 	return handler, nil
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/dropwhile/icanbringthat/internal/logger"
@@ -52,6 +53,19 @@ func (x *Handler) NotFoundError(w http.ResponseWriter) {
 	x.Error(w, "Not Found", http.StatusNotFound)
 }
 
+/* conflict */
+
+// StaleVersionError reports an update against an outdated version. Requests
+// that sent If-Match get a 412, form posts get a 409.
+func (x *Handler) StaleVersionError(w http.ResponseWriter, version string, ifMatch bool) {
+	w.Header().Set("ETag", strconv.Quote(version))
+	if ifMatch {
+		x.Error(w, "Precondition Failed", http.StatusPreconditionFailed)
+		return
+	}
+	x.Error(w, "Modified Elsewhere - Reload and Try Again", http.StatusConflict)
+}
+
 /* internal server error */
 
 func (x *Handler) DBError(w http.ResponseWriter, err error) {
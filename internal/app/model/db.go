@@ -118,3 +118,24 @@ func ExecTx[T any](ctx context.Context, db PgxHandle, query string, args ...any)
 	}
 	return err
 }
+
+// ExecTxRows is like ExecTx, but also returns the number of rows affected,
+// for statements whose conditions may legitimately match nothing.
+func ExecTxRows[T any](ctx context.Context, db PgxHandle, query string, args ...any) (int64, error) {
+	var rows int64
+	err := pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		commandTag, errIn := tx.Exec(ctx, query, args...)
+		if errIn != nil {
+			logger.LogSkip(slog.Default(), 1, slog.LevelError,
+				ctx, "inner db tx error", logger.Err(errIn))
+			return errIn
+		}
+		rows = commandTag.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		logger.LogSkip(slog.Default(), 1, slog.LevelError,
+			ctx, "outer db tx error", logger.Err(err))
+	}
+	return rows, err
+}
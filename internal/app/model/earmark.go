@@ -130,6 +130,18 @@ func GetEarmarksByUserPaginated(ctx context.Context, db PgxHandle,
 	return Query[Earmark](ctx, db, q, args)
 }
 
+func GetEarmarksByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*Earmark, error) {
+	q := `
+		SELECT * FROM earmark_
+		WHERE user_id = $1
+		ORDER BY
+			created DESC,
+			id DESC`
+	return Query[Earmark](ctx, db, q, userID)
+}
+
 func GetEarmarksByUserFiltered(
 	ctx context.Context, db PgxHandle,
 	userID int, archived bool,
@@ -182,6 +194,35 @@ func GetEarmarksByUserPaginatedFiltered(
 	return Query[Earmark](ctx, db, q, args)
 }
 
+func GetEarmarksByUserKeysetFiltered(
+	ctx context.Context, db PgxHandle,
+	userID int, limit int, afterTime time.Time, afterID int, archived bool,
+) ([]*Earmark, error) {
+	q := `
+		SELECT em.*
+		FROM earmark_ em
+		JOIN event_item_ ON 
+			event_item_.id = em.event_item_id
+		JOIN event_ ON 
+			event_.id = event_item_.event_id
+		WHERE
+			em.user_id = @userID AND
+			event_.archived = @archived AND
+			(em.created, em.id) < (@afterTime, @afterID)
+		ORDER BY
+			em.created DESC,
+			em.id DESC
+		LIMIT @limit`
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"limit":     limit,
+		"afterTime": afterTime,
+		"afterID":   afterID,
+		"archived":  archived,
+	}
+	return Query[Earmark](ctx, db, q, args)
+}
+
 func GetEarmarkCountByUser(ctx context.Context, db PgxHandle,
 	userID int,
 ) (*BifurcatedRowCounts, error) {
@@ -38,6 +38,10 @@ func (ev *Event) When() time.Time {
 	return ev.StartTime.In(ev.StartTimeTz.Location)
 }
 
+func (ev *Event) Version() string {
+	return Version(ev.LastModified)
+}
+
 func NewEvent(ctx context.Context, db PgxHandle,
 	userID int, name, description string,
 	startTime time.Time,
@@ -85,11 +89,15 @@ type EventUpdateModelValues struct {
 	Name          mo.Option[string]
 	Description   mo.Option[string]
 	ItemSortOrder mo.Option[[]int]
+	// when set, only update if the event is unmodified since
+	LastModified mo.Option[time.Time]
 }
 
+// UpdateEvent returns the number of rows updated, which is zero if
+// vals.LastModified no longer matches the event.
 func UpdateEvent(ctx context.Context, db PgxHandle, eventID int,
 	vals *EventUpdateModelValues,
-) error {
+) (int64, error) {
 	q := `
 		UPDATE event_
 		SET
@@ -98,15 +106,33 @@ func UpdateEvent(ctx context.Context, db PgxHandle, eventID int,
 			item_sort_order = COALESCE(@itemSortOrder, item_sort_order),
 			start_time = COALESCE(@startTime, start_time),
 			start_time_tz = COALESCE(@startTimeTz, start_time_tz)
-		WHERE id = @eventID`
+		WHERE
+			id = @eventID AND
+			last_modified = COALESCE(@lastModified, last_modified)`
 	args := pgx.NamedArgs{
 		"name":          vals.Name,
 		"description":   vals.Description,
 		"itemSortOrder": vals.ItemSortOrder,
 		"startTime":     vals.StartTime,
 		"startTimeTz":   vals.Tz,
+		"lastModified":  vals.LastModified,
 		"eventID":       eventID,
 	}
+	return ExecTxRows[Event](ctx, db, q, args)
+}
+
+// TransferEvent hands the event over to another user.
+func TransferEvent(ctx context.Context, db PgxHandle,
+	eventID int, userID int,
+) error {
+	q := `
+		UPDATE event_
+		SET user_id = @userID
+		WHERE id = @eventID`
+	args := pgx.NamedArgs{
+		"eventID": eventID,
+		"userID":  userID,
+	}
 	return ExecTx[Event](ctx, db, q, args)
 }
 
@@ -150,6 +176,18 @@ func GetEventByEventItemID(ctx context.Context, db PgxHandle,
 	return QueryOne[Event](ctx, db, q, eventItemID)
 }
 
+func GetEventsByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*Event, error) {
+	q := `
+		SELECT * FROM event_
+		WHERE user_id = $1
+		ORDER BY
+			start_time DESC,
+			id DESC`
+	return Query[Event](ctx, db, q, userID)
+}
+
 func GetEventsByUserFiltered(
 	ctx context.Context, db PgxHandle,
 	userID int, archived bool,
@@ -212,6 +250,30 @@ func GetEventsByUserPaginatedFiltered(
 	return Query[Event](ctx, db, q, args)
 }
 
+func GetEventsByUserKeysetFiltered(
+	ctx context.Context, db PgxHandle,
+	userID int, limit int, afterTime time.Time, afterID int, archived bool,
+) ([]*Event, error) {
+	q := `
+		SELECT * FROM event_
+		WHERE
+			event_.user_id = @userID AND
+			archived = @archived AND
+			(start_time, id) < (@afterTime, @afterID)
+		ORDER BY
+			start_time DESC,
+			id DESC
+		LIMIT @limit`
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"limit":     limit,
+		"afterTime": afterTime,
+		"afterID":   afterID,
+		"archived":  archived,
+	}
+	return Query[Event](ctx, db, q, args)
+}
+
 func GetEventsComingSoonByUserPaginated(
 	ctx context.Context, db PgxHandle,
 	userID int, limit, offset int,
@@ -222,7 +284,7 @@ func GetEventsComingSoonByUserPaginated(
 		WHERE
 			event_.user_id = @userID AND
 			start_time > CURRENT_TIMESTAMP(0)
-		ORDER BY 
+		ORDER BY
 			start_time ASC,
 			id ASC
 		LIMIT @limit OFFSET @offset`
@@ -234,6 +296,24 @@ func GetEventsComingSoonByUserPaginated(
 	return Query[Event](ctx, db, q, args)
 }
 
+// GetEventsUpcomingByUser returns the unarchived events of the user that
+// have not started yet, soonest first.
+func GetEventsUpcomingByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*Event, error) {
+	q := `
+		SELECT *
+		FROM event_
+		WHERE
+			event_.user_id = $1 AND
+			archived IS FALSE AND
+			start_time > CURRENT_TIMESTAMP(0)
+		ORDER BY
+			start_time ASC,
+			id ASC`
+	return Query[Event](ctx, db, q, userID)
+}
+
 func GetEventCountsByUser(ctx context.Context, db PgxHandle,
 	userID int,
 ) (*BifurcatedRowCounts, error) {
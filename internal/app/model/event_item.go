@@ -9,6 +9,7 @@ import (
 
 	"github.com/dropwhile/refid/v2/reftag"
 	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
 
 	"github.com/dropwhile/icanbringthat/internal/util"
 )
@@ -28,6 +29,10 @@ type EventItem struct {
 	RefID        EventItemRefID `db:"ref_id"`
 }
 
+func (ei *EventItem) Version() string {
+	return Version(ei.LastModified)
+}
+
 func NewEventItem(ctx context.Context, db PgxHandle,
 	eventID int, description string,
 ) (*EventItem, error) {
@@ -52,18 +57,23 @@ func CreateEventItem(ctx context.Context, db PgxHandle,
 	return QueryOneTx[EventItem](ctx, db, q, args)
 }
 
+// UpdateEventItem returns the number of rows updated, which is zero if
+// lastModified is set and no longer matches the event item.
 func UpdateEventItem(ctx context.Context, db PgxHandle,
-	eventItemID int, description string,
-) error {
+	eventItemID int, description string, lastModified mo.Option[time.Time],
+) (int64, error) {
 	q := `
 		UPDATE event_item_
 		SET description = @description
-		WHERE id = @eventItemID`
+		WHERE
+			id = @eventItemID AND
+			last_modified = COALESCE(@lastModified, last_modified)`
 	args := pgx.NamedArgs{
-		"description": description,
-		"eventItemID": eventItemID,
+		"description":  description,
+		"lastModified": lastModified,
+		"eventItemID":  eventItemID,
 	}
-	return ExecTx[EventItem](ctx, db, q, args)
+	return ExecTxRows[EventItem](ctx, db, q, args)
 }
 
 func DeleteEventItem(ctx context.Context, db PgxHandle,
@@ -58,6 +58,22 @@ func GetFavoriteByUserEvent(ctx context.Context, db PgxHandle,
 	return QueryOne[Favorite](ctx, db, q, args)
 }
 
+func GetFavoriteEventsByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*Event, error) {
+	q := `
+	SELECT event_.*
+	FROM event_ 
+	JOIN favorite_ ON
+		favorite_.event_id = event_.id
+	WHERE
+		favorite_.user_id = $1
+	ORDER BY 
+		event_.start_time DESC,
+		event_.id DESC`
+	return Query[Event](ctx, db, q, userID)
+}
+
 func GetFavoriteEventsByUserFiltered(
 	ctx context.Context, db PgxHandle,
 	userID int, archived bool,
@@ -107,6 +123,34 @@ func GetFavoriteEventsByUserPaginatedFiltered(
 	return Query[Event](ctx, db, q, args)
 }
 
+func GetFavoriteEventsByUserKeysetFiltered(
+	ctx context.Context, db PgxHandle,
+	userID int, limit int, afterTime time.Time, afterID int, archived bool,
+) ([]*Event, error) {
+	q := `
+	SELECT event_.*
+	FROM event_ 
+	JOIN favorite_ ON
+		favorite_.event_id = event_.id
+	WHERE
+		favorite_.user_id = @userID AND
+		event_.archived = @archived AND
+		(event_.start_time, event_.id) < (@afterTime, @afterID)
+	ORDER BY 
+		event_.start_time DESC,
+		event_.id DESC
+	LIMIT @limit
+	`
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"limit":     limit,
+		"afterTime": afterTime,
+		"afterID":   afterID,
+		"archived":  archived,
+	}
+	return Query[Event](ctx, db, q, args)
+}
+
 func GetFavoriteCountByUser(ctx context.Context, db PgxHandle,
 	userID int,
 ) (*BifurcatedRowCounts, error) {
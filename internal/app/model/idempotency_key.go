@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyKey struct {
+	Created     time.Time
+	Key         string
+	Procedure   string
+	RequestHash []byte `db:"request_hash"`
+	// Response is nil while the original request is in flight
+	Response []byte
+	UserID   int `db:"user_id"`
+}
+
+// ReserveIdempotencyKey records key as in flight for userID. A record
+// older than ttl is replaced. If an unexpired record already exists,
+// pgx.ErrNoRows is returned.
+func ReserveIdempotencyKey(ctx context.Context, db PgxHandle,
+	userID int, key, procedure string, requestHash []byte, ttl time.Duration,
+) (*IdempotencyKey, error) {
+	q := `
+		INSERT INTO idempotency_key_ (
+			user_id, key, procedure, request_hash
+		)
+		VALUES (@userID, @key, @procedure, @requestHash)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET
+			procedure = EXCLUDED.procedure,
+			request_hash = EXCLUDED.request_hash,
+			response = NULL,
+			created = timezone('utc', now())
+		WHERE
+			idempotency_key_.created <
+				timezone('utc', now()) - @ttlSeconds * INTERVAL '1 second'
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"userID":      userID,
+		"key":         key,
+		"procedure":   procedure,
+		"requestHash": requestHash,
+		"ttlSeconds":  int(ttl.Seconds()),
+	}
+	return QueryOneTx[IdempotencyKey](ctx, db, q, args)
+}
+
+func GetIdempotencyKey(ctx context.Context, db PgxHandle,
+	userID int, key string,
+) (*IdempotencyKey, error) {
+	q := `SELECT * FROM idempotency_key_ WHERE user_id = $1 AND key = $2`
+	return QueryOne[IdempotencyKey](ctx, db, q, userID, key)
+}
+
+func CompleteIdempotencyKey(ctx context.Context, db PgxHandle,
+	userID int, key string, response []byte,
+) error {
+	q := `
+		UPDATE idempotency_key_
+		SET response = @response
+		WHERE user_id = @userID AND key = @key`
+	args := pgx.NamedArgs{
+		"userID":   userID,
+		"key":      key,
+		"response": response,
+	}
+	return ExecTx[IdempotencyKey](ctx, db, q, args)
+}
+
+func DeleteIdempotencyKey(ctx context.Context, db PgxHandle,
+	userID int, key string,
+) error {
+	q := `DELETE FROM idempotency_key_ WHERE user_id = $1 AND key = $2`
+	return ExecTx[IdempotencyKey](ctx, db, q, userID, key)
+}
+
+func DeleteExpiredIdempotencyKeys(ctx context.Context, db PgxHandle,
+	ttl time.Duration,
+) error {
+	q := `
+		DELETE FROM idempotency_key_
+		WHERE
+			created < timezone('utc', now()) - $1 * INTERVAL '1 second'`
+	return ExecTx[IdempotencyKey](ctx, db, q, int(ttl.Seconds()))
+}
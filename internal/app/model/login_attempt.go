@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodWebAuthn LoginMethod = "webauthn"
+	LoginMethodTOTP     LoginMethod = "totp"
+	LoginMethodEmail    LoginMethod = "email"
+	LoginMethodOIDC     LoginMethod = "oidc"
+)
+
+type LoginAttempt struct {
+	Created   time.Time
+	Method    LoginMethod
+	IPAddress string `db:"ip_address"`
+	UserAgent string `db:"user_agent"`
+	UserID    int    `db:"user_id"`
+	ID        int
+	Success   bool
+}
+
+// LoginFailures summarizes the failed login attempts for a user since
+// their last successful login.
+type LoginFailures struct {
+	Last  *time.Time
+	Count int
+}
+
+// LoginSource reports whether a user logged in successfully before,
+// and whether from a given ip address and user agent.
+type LoginSource struct {
+	AnySuccess bool `db:"any_success"`
+	Known      bool
+}
+
+func CreateLoginAttempt(ctx context.Context, db PgxHandle,
+	userID int, method LoginMethod, success bool, ipAddress, userAgent string,
+) (*LoginAttempt, error) {
+	q := `
+		INSERT INTO login_attempt_ (
+			user_id, method, success, ip_address, user_agent
+		)
+		VALUES (@userID, @method, @success, @ipAddress, @userAgent)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"method":    method,
+		"success":   success,
+		"ipAddress": ipAddress,
+		"userAgent": userAgent,
+	}
+	return QueryOneTx[LoginAttempt](ctx, db, q, args)
+}
+
+func GetLoginFailures(ctx context.Context, db PgxHandle,
+	userID int,
+) (*LoginFailures, error) {
+	q := `
+		SELECT
+			count(*) AS count,
+			max(created) AS last
+		FROM login_attempt_
+		WHERE
+			user_id = $1 AND
+			NOT success AND
+			created > COALESCE(
+				(
+					SELECT max(created) FROM login_attempt_
+					WHERE user_id = $1 AND success
+				),
+				'-infinity'
+			)`
+	return QueryOne[LoginFailures](ctx, db, q, userID)
+}
+
+func GetLoginSource(ctx context.Context, db PgxHandle,
+	userID int, ipAddress, userAgent string,
+) (*LoginSource, error) {
+	q := `
+		SELECT
+			EXISTS(
+				SELECT 1 FROM login_attempt_
+				WHERE user_id = @userID AND success
+			) AS any_success,
+			EXISTS(
+				SELECT 1 FROM login_attempt_
+				WHERE
+					user_id = @userID AND
+					success AND
+					ip_address = @ipAddress AND
+					user_agent = @userAgent
+			) AS known`
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"ipAddress": ipAddress,
+		"userAgent": userAgent,
+	}
+	return QueryOne[LoginSource](ctx, db, q, args)
+}
+
+func DeleteLoginAttemptsBefore(ctx context.Context, db PgxHandle,
+	before time.Time,
+) error {
+	q := `DELETE FROM login_attempt_ WHERE created < $1`
+	return ExecTx[LoginAttempt](ctx, db, q, before)
+}
@@ -3,7 +3,19 @@
 // license that can be found in the LICENSE file.
 package model
 
+import (
+	"strconv"
+	"time"
+)
+
 type BifurcatedRowCounts struct {
 	Current  int
 	Archived int
 }
+
+// Version returns an opaque token for the state of a row as of its
+// last_modified time. Clients send it back with updates so that
+// intervening modifications by someone else can be detected.
+func Version(lastModified time.Time) string {
+	return strconv.FormatInt(lastModified.UnixMicro(), 36)
+}
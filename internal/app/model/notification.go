@@ -5,6 +5,9 @@ package model
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/dropwhile/refid/v2/reftag"
@@ -19,50 +22,144 @@ type NotificationRefID struct {
 
 var NewNotificationRefID = reftag.New[NotificationRefID]
 
+type NotificationKind string
+
+const (
+	// free form message. may contain link:/path style references.
+	NotificationKindMessage           NotificationKind = "message"
+	NotificationKindAccountUnverified NotificationKind = "account_unverified"
+	NotificationKindRemindersDisabled NotificationKind = "reminders_disabled"
+	NotificationKindEarmarkClaimed    NotificationKind = "earmark_claimed"
+	NotificationKindEventChanged      NotificationKind = "event_changed"
+	NotificationKindNewSignIn         NotificationKind = "new_sign_in"
+	NotificationKindEventTransferred  NotificationKind = "event_transferred"
+)
+
+func (k NotificationKind) Valid() bool {
+	switch k {
+	case NotificationKindMessage,
+		NotificationKindAccountUnverified,
+		NotificationKindRemindersDisabled,
+		NotificationKindEarmarkClaimed,
+		NotificationKindEventChanged,
+		NotificationKindNewSignIn,
+		NotificationKindEventTransferred:
+		return true
+	}
+	return false
+}
+
+// NotificationPayload holds the references a notification relates to.
+// Names are captured at creation time, so a notification still renders
+// sensibly if the referenced entity is later renamed or removed.
+type NotificationPayload struct {
+	EventRefID           string `json:"event_ref_id,omitempty"`
+	EventName            string `json:"event_name,omitempty"`
+	EventItemRefID       string `json:"event_item_ref_id,omitempty"`
+	EventItemDescription string `json:"event_item_description,omitempty"`
+	EarmarkRefID         string `json:"earmark_ref_id,omitempty"`
+	UserName             string `json:"user_name,omitempty"`
+	Reason               string `json:"reason,omitempty"`
+	IPAddress            string `json:"ip_address,omitempty"`
+	UserAgent            string `json:"user_agent,omitempty"`
+}
+
+func (p NotificationPayload) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *NotificationPayload) Scan(src any) error {
+	var s []byte
+	switch src := src.(type) {
+	case NotificationPayload:
+		*p = src
+		return nil
+	case *NotificationPayload:
+		*p = *src
+		return nil
+	case []byte:
+		s = src
+	case string:
+		s = []byte(src)
+	case nil:
+		*p = NotificationPayload{}
+		return nil
+	default:
+		return fmt.Errorf("cannot convert %T to NotificationPayload", src)
+	}
+	return json.Unmarshal(s, &p)
+}
+
 type Notification struct {
 	Created      time.Time
 	LastModified time.Time `db:"last_modified"`
 	Message      string
+	Kind         NotificationKind
+	Payload      NotificationPayload
 	UserID       int `db:"user_id"`
 	ID           int
 	RefID        NotificationRefID `db:"ref_id"`
 	Read         bool
+	Digested     bool
 }
 
 func NewNotification(ctx context.Context, db PgxHandle,
-	userID int, message string,
+	userID int, kind NotificationKind, message string,
+	payload NotificationPayload,
 ) (*Notification, error) {
 	refID := util.Must(NewNotificationRefID())
-	return CreateNotification(ctx, db, refID, userID, message)
+	return CreateNotification(ctx, db, refID, userID, kind, message, payload)
 }
 
 func CreateNotification(ctx context.Context, db PgxHandle,
-	refID NotificationRefID, userID int, message string,
+	refID NotificationRefID, userID int, kind NotificationKind,
+	message string, payload NotificationPayload,
 ) (*Notification, error) {
 	q := `
 		INSERT INTO notification_ (
-			ref_id, user_id, message
+			ref_id, user_id, kind, message, payload
 		)
 		VALUES (
-			@refID, @userID, @message
+			@refID, @userID, @kind, @message, @payload
 		)
 		RETURNING *`
 	args := pgx.NamedArgs{
 		"refID":   refID,
 		"userID":  userID,
+		"kind":    kind,
 		"message": message,
+		"payload": payload,
 	}
 	return QueryOneTx[Notification](ctx, db, q, args)
 }
 
 func UpdateNotification(ctx context.Context, db PgxHandle,
 	ID int, read bool,
-) (*Notification, error) {
+) error {
 	q := `
 		UPDATE notification_
-		SET read = $1
-		WHERE id = $2`
-	return QueryOne[Notification](ctx, db, q, read, ID)
+		SET read = @read
+		WHERE id = @notificationID`
+	args := pgx.NamedArgs{
+		"read":           read,
+		"notificationID": ID,
+	}
+	return ExecTx[Notification](ctx, db, q, args)
+}
+
+func UpdateNotificationsReadByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) error {
+	q := `
+		UPDATE notification_
+		SET read = TRUE
+		WHERE
+			user_id = @userID AND
+			read = FALSE`
+	args := pgx.NamedArgs{
+		"userID": userID,
+	}
+	return ExecTx[Notification](ctx, db, q, args)
 }
 
 func DeleteNotification(ctx context.Context, db PgxHandle, ID int) error {
@@ -94,42 +191,103 @@ func GetNotificationCountByUser(ctx context.Context, db PgxHandle,
 	return Get[int](ctx, db, q, userID)
 }
 
+func GetNotificationUnreadCountByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (int, error) {
+	q := `SELECT count(*) FROM notification_ WHERE user_id = $1 AND read = FALSE`
+	return Get[int](ctx, db, q, userID)
+}
+
 func GetNotificationsByUserPaginated(ctx context.Context, db PgxHandle,
-	userID int, limit, offset int,
+	userID int, limit, offset int, unreadOnly bool,
 ) ([]*Notification, error) {
 	q := `
 	SELECT *
 	FROM notification_ 
 	WHERE
 		user_id = @userID AND
-		read = FALSE
+		(@unreadOnly IS FALSE OR read = FALSE)
 	ORDER BY 
-		created DESC
+		created DESC,
+		id DESC
 	LIMIT @limit
 	OFFSET @offset
 	`
 	args := pgx.NamedArgs{
-		"userID": userID,
-		"limit":  limit,
-		"offset": offset,
+		"userID":     userID,
+		"limit":      limit,
+		"offset":     offset,
+		"unreadOnly": unreadOnly,
+	}
+	return Query[Notification](ctx, db, q, args)
+}
+
+func GetNotificationsByUserKeyset(ctx context.Context, db PgxHandle,
+	userID int, limit int, afterTime time.Time, afterID int, unreadOnly bool,
+) ([]*Notification, error) {
+	q := `
+	SELECT *
+	FROM notification_ 
+	WHERE
+		user_id = @userID AND
+		(@unreadOnly IS FALSE OR read = FALSE) AND
+		(created, id) < (@afterTime, @afterID)
+	ORDER BY 
+		created DESC,
+		id DESC
+	LIMIT @limit
+	`
+	args := pgx.NamedArgs{
+		"userID":     userID,
+		"limit":      limit,
+		"afterTime":  afterTime,
+		"afterID":    afterID,
+		"unreadOnly": unreadOnly,
 	}
 	return Query[Notification](ctx, db, q, args)
 }
 
 func GetNotificationsByUser(ctx context.Context, db PgxHandle,
-	userID int,
+	userID int, unreadOnly bool,
 ) ([]*Notification, error) {
 	q := `
 	SELECT *
 	FROM notification_ 
 	WHERE
 		user_id = @userID AND
-		read = FALSE
+		(@unreadOnly IS FALSE OR read = FALSE)
 	ORDER BY 
 		created DESC
 	`
+	args := pgx.NamedArgs{
+		"userID":     userID,
+		"unreadOnly": unreadOnly,
+	}
+	return Query[Notification](ctx, db, q, args)
+}
+
+func GetNotificationsByUserUndigested(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*Notification, error) {
+	q := `
+	SELECT *
+	FROM notification_
+	WHERE
+		user_id = @userID AND
+		read = FALSE AND
+		digested = FALSE
+	ORDER BY
+		created DESC
+	`
 	args := pgx.NamedArgs{
 		"userID": userID,
 	}
 	return Query[Notification](ctx, db, q, args)
 }
+
+func UpdateNotificationsDigested(ctx context.Context, db PgxHandle,
+	IDs []int,
+) error {
+	q := `UPDATE notification_ SET digested = TRUE WHERE id = ANY($1)`
+	return ExecTx[Notification](ctx, db, q, IDs)
+}
@@ -149,6 +149,31 @@ func UpdateUserSettings(ctx context.Context, db PgxHandle,
 	return ExecTx[User](ctx, db, q, args)
 }
 
+// GetUsersEarmarkingEvent returns the users, other than the event owner,
+// with earmarks on items of the event.
+func GetUsersEarmarkingEvent(ctx context.Context, db PgxHandle,
+	eventID int,
+) ([]*User, error) {
+	q := `
+		SELECT user_.*
+		FROM user_
+		WHERE user_.id IN (
+			SELECT earmark_.user_id
+			FROM earmark_
+			JOIN event_item_ ON
+				event_item_.id = earmark_.event_item_id
+			JOIN event_ ON
+				event_.id = event_item_.event_id
+			WHERE
+				event_.id = $1 AND
+				earmark_.user_id != event_.user_id
+		)
+		ORDER BY
+			user_.name ASC,
+			user_.id ASC`
+	return Query[User](ctx, db, q, eventID)
+}
+
 func DeleteUser(ctx context.Context, db PgxHandle, userID int) error {
 	q := `DELETE FROM user_ WHERE id = $1`
 	return ExecTx[User](ctx, db, q, userID)
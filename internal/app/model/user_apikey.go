@@ -6,12 +6,14 @@ package model
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/dropwhile/refid/v2/reftag"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/dropwhile/icanbringthat/internal/crypto"
 	"github.com/dropwhile/icanbringthat/internal/util"
 )
 
@@ -21,67 +23,189 @@ type ApiKeyRefID struct {
 
 var NewApiKeyRefID = reftag.NewRandom[ApiKeyRefID]
 
+// api key scopes
+const (
+	ApiKeyScopeRead          = "read"
+	ApiKeyScopeEventsWrite   = "events:write"
+	ApiKeyScopeEarmarksWrite = "earmarks:write"
+	ApiKeyScopeNotifications = "notifications"
+	ApiKeyScopeWebhooks      = "webhooks"
+	ApiKeyScopeAccountWrite  = "account:write"
+)
+
+var ApiKeyScopes = []string{
+	ApiKeyScopeRead,
+	ApiKeyScopeEventsWrite,
+	ApiKeyScopeEarmarksWrite,
+	ApiKeyScopeNotifications,
+	ApiKeyScopeWebhooks,
+	ApiKeyScopeAccountWrite,
+}
+
 type ApiKey struct {
-	Created time.Time
-	Token   string
-	UserID  int `db:"user_id"`
+	Created  time.Time
+	Expires  *time.Time
+	LastUsed *time.Time `db:"last_used"`
+	// LegacyToken is a plaintext token stored prior to token hashing.
+	// It is cleared once the token has been rehashed.
+	LegacyToken *string `db:"token"`
+	Name        string
+	Prefix      string
+	// Token is the plaintext token. It is never stored, and is only
+	// populated on a newly created key.
+	Token      string `db:"-"`
+	LastUsedIP string `db:"last_used_ip"`
+	TokenHash  []byte `db:"token_hash"`
+	Scopes     []string
+	ID         int
+	UserID     int `db:"user_id"`
 }
 
-func NewApiKey(ctx context.Context, db PgxHandle,
-	userID int,
+func (k *ApiKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+func (k *ApiKey) IsExpired() bool {
+	return k.Expires != nil && !k.Expires.After(time.Now())
+}
+
+// HashApiKeyToken returns the keyed hash of a token, as stored in
+// the database.
+func HashApiKeyToken(mac crypto.HMACer, token string) []byte {
+	return mac.Generate([]byte(token))
+}
+
+// ApiKeyTokenPrefix returns the public prefix identifying the key
+// a token belongs to.
+func ApiKeyTokenPrefix(token string) (string, bool) {
+	prefix, _, ok := strings.Cut(token, ":")
+	return prefix, ok && prefix != ""
+}
+
+func NewApiKey(ctx context.Context, db PgxHandle, mac crypto.HMACer,
+	userID int, name string, scopes []string, expires *time.Time,
 ) (*ApiKey, error) {
 	if userID == 0 {
 		return nil, fmt.Errorf("nil user supplied")
 	}
+	prefix := util.Must(NewApiKeyRefID()).String()
 	token := strings.Join(
 		[]string{
-			util.Must(NewApiKeyRefID()).String(),
+			prefix,
 			util.Must(NewApiKeyRefID()).String(),
 		},
 		":",
 	)
-	return CreateApiKey(ctx, db, userID, token)
+	tokenHash := HashApiKeyToken(mac, token)
+	apiKey, err := CreateApiKey(ctx, db, userID, name, prefix, tokenHash, scopes, expires)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.Token = token
+	return apiKey, nil
 }
 
 func CreateApiKey(ctx context.Context, db PgxHandle,
-	userID int, token string,
+	userID int, name, prefix string, tokenHash []byte,
+	scopes []string, expires *time.Time,
 ) (*ApiKey, error) {
 	q := `
 		INSERT INTO api_key_ (
-			user_id, token
+			user_id, name, prefix, token_hash, scopes, expires
 		)
-		VALUES (@userID, @token)
-		ON CONFLICT (user_id)
-		DO
-			UPDATE SET token = EXCLUDED.token
+		VALUES (@userID, @name, @prefix, @tokenHash, @scopes, @expires)
 		RETURNING *`
-	args := pgx.NamedArgs{"userID": userID, "token": token}
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"name":      name,
+		"prefix":    prefix,
+		"tokenHash": tokenHash,
+		"scopes":    scopes,
+		"expires":   expires,
+	}
 	return QueryOneTx[ApiKey](ctx, db, q, args)
 }
 
-func GetUserByApiKey(ctx context.Context, db PgxHandle,
-	token string,
-) (*User, error) {
+// UpdateApiKeyTokenHash stores the hash of a legacy plaintext token,
+// and clears the plaintext token.
+func UpdateApiKeyTokenHash(ctx context.Context, db PgxHandle,
+	apiKeyID int, tokenHash []byte,
+) error {
 	q := `
-		SELECT user_.*
-		FROM user_
-		JOIN api_key_ ON
-			api_key_.user_id = user_.id
+		UPDATE api_key_
+		SET
+			token_hash = @tokenHash,
+			token = NULL
+		WHERE id = @apiKeyID`
+	args := pgx.NamedArgs{
+		"tokenHash": tokenHash,
+		"apiKeyID":  apiKeyID,
+	}
+	return ExecTx[ApiKey](ctx, db, q, args)
+}
+
+func DeleteApiKey(ctx context.Context, db PgxHandle,
+	apiKeyID int,
+) error {
+	q := `DELETE FROM api_key_ WHERE id = $1`
+	return ExecTx[ApiKey](ctx, db, q, apiKeyID)
+}
+
+// TouchApiKey records api key usage. Updates are skipped if the key was
+// already marked as used within the last minute, to avoid a write on
+// every request.
+func TouchApiKey(ctx context.Context, db PgxHandle,
+	apiKeyID int, remoteIP string,
+) error {
+	q := `
+		UPDATE api_key_
+		SET
+			last_used = timezone('utc', now()),
+			last_used_ip = @remoteIP
 		WHERE
-			user_.api_access = TRUE AND
-			api_key_.token = $1
-		`
-	return QueryOne[User](ctx, db, q, token)
+			id = @apiKeyID AND
+			(
+				last_used IS NULL OR
+				last_used < timezone('utc', now()) - interval '1 minute' OR
+				last_used_ip != @remoteIP
+			)`
+	args := pgx.NamedArgs{
+		"remoteIP": remoteIP,
+		"apiKeyID": apiKeyID,
+	}
+	return ExecTx[ApiKey](ctx, db, q, args)
 }
 
-func GetApiKeyByUser(ctx context.Context, db PgxHandle,
-	userID int,
+func GetApiKeyByPrefix(ctx context.Context, db PgxHandle,
+	prefix string,
 ) (*ApiKey, error) {
+	q := `SELECT * FROM api_key_ WHERE prefix = $1`
+	return QueryOne[ApiKey](ctx, db, q, prefix)
+}
+
+func GetApiKeysByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*ApiKey, error) {
 	q := `
 		SELECT *
 		FROM api_key_
 		WHERE
 			user_id = $1
+		ORDER BY created ASC
 		`
-	return QueryOne[ApiKey](ctx, db, q, userID)
+	return Query[ApiKey](ctx, db, q, userID)
+}
+
+// GetApiKeysWithLegacyToken returns keys still storing a plaintext token.
+func GetApiKeysWithLegacyToken(ctx context.Context, db PgxHandle,
+) ([]*ApiKey, error) {
+	q := `SELECT * FROM api_key_ WHERE token IS NOT NULL`
+	return Query[ApiKey](ctx, db, q)
+}
+
+func GetApiKeyCountByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (int, error) {
+	q := `SELECT count(*) FROM api_key_ WHERE user_id = $1`
+	return Get[int](ctx, db, q, userID)
 }
@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type UserDataExportRefID struct {
+	reftag.IDt15
+}
+
+var NewUserDataExportRefID = reftag.New[UserDataExportRefID]
+
+// UserDataExport is a requested export of all of a user's data. It is
+// pending until the worker has built the archive and set Completed, and
+// emailed the user a link to it and set Emailed.
+type UserDataExport struct {
+	Created   time.Time
+	Completed *time.Time
+	Emailed   *time.Time
+	Data      []byte
+	UserID    int `db:"user_id"`
+	ID        int
+	RefID     UserDataExportRefID `db:"ref_id"`
+}
+
+// how long a completed export can be downloaded
+const UserDataExportExpiry = 48 * time.Hour
+
+func NewUserDataExport(ctx context.Context, db PgxHandle,
+	userID int,
+) (*UserDataExport, error) {
+	refID := util.Must(NewUserDataExportRefID())
+	return CreateUserDataExport(ctx, db, refID, userID)
+}
+
+func CreateUserDataExport(ctx context.Context, db PgxHandle,
+	refID UserDataExportRefID, userID int,
+) (*UserDataExport, error) {
+	q := `
+		INSERT INTO user_data_export_ (
+			ref_id, user_id
+		)
+		VALUES (@refID, @userID)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"refID":  refID,
+		"userID": userID,
+	}
+	return QueryOneTx[UserDataExport](ctx, db, q, args)
+}
+
+func GetUserDataExportByRefID(ctx context.Context, db PgxHandle,
+	refID UserDataExportRefID,
+) (*UserDataExport, error) {
+	q := `SELECT * FROM user_data_export_ WHERE ref_id = $1`
+	return QueryOne[UserDataExport](ctx, db, q, refID)
+}
+
+// GetPendingUserDataExportByUser returns the most recent export of the
+// user that has not been emailed yet.
+func GetPendingUserDataExportByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (*UserDataExport, error) {
+	q := `
+		SELECT * FROM user_data_export_
+		WHERE
+			user_id = $1 AND
+			emailed IS NULL
+		ORDER BY created DESC
+		LIMIT 1`
+	return QueryOne[UserDataExport](ctx, db, q, userID)
+}
+
+// GetPendingUserDataExports returns all exports that have not been
+// emailed yet, oldest first. Some may already be built.
+func GetPendingUserDataExports(ctx context.Context, db PgxHandle,
+) ([]*UserDataExport, error) {
+	q := `
+		SELECT * FROM user_data_export_
+		WHERE emailed IS NULL
+		ORDER BY
+			created ASC,
+			id ASC`
+	return Query[UserDataExport](ctx, db, q)
+}
+
+func CompleteUserDataExport(ctx context.Context, db PgxHandle,
+	exportID int, data []byte,
+) error {
+	q := `
+		UPDATE user_data_export_
+		SET
+			data = @data,
+			completed = timezone('utc', now())
+		WHERE id = @exportID`
+	args := pgx.NamedArgs{
+		"exportID": exportID,
+		"data":     data,
+	}
+	return ExecTx[UserDataExport](ctx, db, q, args)
+}
+
+func MarkUserDataExportEmailed(ctx context.Context, db PgxHandle,
+	exportID int,
+) error {
+	q := `
+		UPDATE user_data_export_
+		SET emailed = timezone('utc', now())
+		WHERE id = $1`
+	return ExecTx[UserDataExport](ctx, db, q, exportID)
+}
+
+// DeleteExpiredUserDataExports removes exports completed before the
+// given time, along with their archives.
+func DeleteExpiredUserDataExports(ctx context.Context, db PgxHandle,
+	before time.Time,
+) error {
+	q := `
+		DELETE FROM user_data_export_
+		WHERE
+			completed IS NOT NULL AND
+			completed < $1`
+	return ExecTx[UserDataExport](ctx, db, q, before)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type UserDeletionRefID struct {
+	reftag.IDt16
+}
+
+var NewUserDeletionRefID = reftag.New[UserDeletionRefID]
+
+// UserDeletion is a scheduled deletion of a user account. Until the
+// scheduled time passes, the deletion can be cancelled.
+type UserDeletion struct {
+	Created   time.Time
+	Scheduled time.Time
+	UserID    int `db:"user_id"`
+	ID        int
+	RefID     UserDeletionRefID `db:"ref_id"`
+}
+
+// UserDeletionTransfer is an event to be handed over to another user
+// when the account of its owner is deleted.
+type UserDeletionTransfer struct {
+	Created    time.Time
+	DeletionID int `db:"deletion_id"`
+	EventID    int `db:"event_id"`
+	UserID     int `db:"user_id"`
+	ID         int
+}
+
+// how long before a scheduled deletion is carried out
+const UserDeletionGracePeriod = 14 * 24 * time.Hour
+
+func NewUserDeletion(ctx context.Context, db PgxHandle,
+	userID int, scheduled time.Time,
+) (*UserDeletion, error) {
+	refID := util.Must(NewUserDeletionRefID())
+	return CreateUserDeletion(ctx, db, refID, userID, scheduled)
+}
+
+func CreateUserDeletion(ctx context.Context, db PgxHandle,
+	refID UserDeletionRefID, userID int, scheduled time.Time,
+) (*UserDeletion, error) {
+	q := `
+		INSERT INTO user_deletion_ (
+			ref_id, user_id, scheduled
+		)
+		VALUES (@refID, @userID, @scheduled)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"refID":     refID,
+		"userID":    userID,
+		"scheduled": scheduled,
+	}
+	return QueryOneTx[UserDeletion](ctx, db, q, args)
+}
+
+func GetUserDeletionByRefID(ctx context.Context, db PgxHandle,
+	refID UserDeletionRefID,
+) (*UserDeletion, error) {
+	q := `SELECT * FROM user_deletion_ WHERE ref_id = $1`
+	return QueryOne[UserDeletion](ctx, db, q, refID)
+}
+
+func GetUserDeletionByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (*UserDeletion, error) {
+	q := `SELECT * FROM user_deletion_ WHERE user_id = $1`
+	return QueryOne[UserDeletion](ctx, db, q, userID)
+}
+
+// GetUserDeletionsDue returns the deletions whose grace period is over.
+func GetUserDeletionsDue(ctx context.Context, db PgxHandle,
+) ([]*UserDeletion, error) {
+	q := `
+		SELECT * FROM user_deletion_
+		WHERE scheduled <= timezone('utc', now())
+		ORDER BY
+			scheduled ASC,
+			id ASC`
+	return Query[UserDeletion](ctx, db, q)
+}
+
+func DeleteUserDeletion(ctx context.Context, db PgxHandle,
+	ID int,
+) error {
+	q := `DELETE FROM user_deletion_ WHERE id = $1`
+	return ExecTx[UserDeletion](ctx, db, q, ID)
+}
+
+func NewUserDeletionTransfer(ctx context.Context, db PgxHandle,
+	deletionID int, eventID int, userID int,
+) (*UserDeletionTransfer, error) {
+	q := `
+		INSERT INTO user_deletion_transfer_ (
+			deletion_id, event_id, user_id
+		)
+		VALUES (@deletionID, @eventID, @userID)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"deletionID": deletionID,
+		"eventID":    eventID,
+		"userID":     userID,
+	}
+	return QueryOneTx[UserDeletionTransfer](ctx, db, q, args)
+}
+
+func GetUserDeletionTransfers(ctx context.Context, db PgxHandle,
+	deletionID int,
+) ([]*UserDeletionTransfer, error) {
+	q := `
+		SELECT * FROM user_deletion_transfer_
+		WHERE deletion_id = $1
+		ORDER BY id ASC`
+	return Query[UserDeletionTransfer](ctx, db, q, deletionID)
+}
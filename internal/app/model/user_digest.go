@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type UserDigest struct {
+	LastSent time.Time `db:"last_sent"`
+	UserID   int       `db:"user_id"`
+}
+
+func UpsertUserDigest(ctx context.Context, db PgxHandle,
+	userID int, lastSent time.Time,
+) (*UserDigest, error) {
+	q := `
+		INSERT INTO user_digest_ (
+			user_id, last_sent
+		)
+		VALUES (@userID, @lastSent)
+		ON CONFLICT (user_id)
+		DO UPDATE SET last_sent = EXCLUDED.last_sent
+		RETURNING *`
+	args := pgx.NamedArgs{"userID": userID, "lastSent": lastSent}
+	return QueryOneTx[UserDigest](ctx, db, q, args)
+}
+
+type UserDigestNeeded struct {
+	LastSent  *time.Time `db:"last_sent"`
+	Frequency string
+	UserID    int `db:"user_id"`
+}
+
+// GetUserDigestNeeded returns the verified users that opted in to a
+// digest and whose last digest is older than their chosen frequency.
+// Users with an account deletion scheduled are skipped.
+func GetUserDigestNeeded(
+	ctx context.Context, db PgxHandle,
+) ([]*UserDigestNeeded, error) {
+	q := `
+		SELECT
+			u.id as user_id,
+			u.settings->>'digest_frequency' as frequency,
+			ud.last_sent
+		FROM user_ u
+		LEFT JOIN user_digest_ ud ON
+			ud.user_id = u.id
+		WHERE
+			u.verified = TRUE AND
+			u.settings->>'digest_frequency' IN ('daily', 'weekly') AND
+			NOT EXISTS (
+				SELECT 1 FROM user_deletion_ udel
+				WHERE udel.user_id = u.id
+			) AND
+			(
+				ud.last_sent IS NULL OR
+				(
+					u.settings->>'digest_frequency' = 'daily' AND
+					ud.last_sent <= timezone('utc', CURRENT_TIMESTAMP) - interval '1 day'
+				) OR
+				(
+					u.settings->>'digest_frequency' = 'weekly' AND
+					ud.last_sent <= timezone('utc', CURRENT_TIMESTAMP) - interval '7 days'
+				)
+			)
+		ORDER BY u.id
+	`
+	return Query[UserDigestNeeded](ctx, db, q)
+}
+
+// GetEventsUpcomingByUserInvolved returns upcoming events starting before
+// until, that the user either owns or has earmarked items for.
+func GetEventsUpcomingByUserInvolved(ctx context.Context, db PgxHandle,
+	userID int, until time.Time,
+) ([]*Event, error) {
+	q := `
+		SELECT DISTINCT ev.*
+		FROM event_ ev
+		LEFT JOIN event_item_ ei ON
+			ei.event_id = ev.id
+		LEFT JOIN earmark_ em ON
+			em.event_item_id = ei.id
+		WHERE
+			(ev.user_id = @userID OR em.user_id = @userID) AND
+			ev.archived IS FALSE AND
+			ev.start_time > CURRENT_TIMESTAMP(0) AND
+			ev.start_time < @until
+		ORDER BY
+			ev.start_time ASC,
+			ev.id ASC
+	`
+	args := pgx.NamedArgs{
+		"userID": userID,
+		"until":  until,
+	}
+	return Query[Event](ctx, db, q, args)
+}
+
+type FavoriteActivity struct {
+	EventName   string     `db:"event_name"`
+	EventRefID  EventRefID `db:"event_ref_id"`
+	EventID     int        `db:"event_id"`
+	NewItems    int        `db:"new_items"`
+	NewEarmarks int        `db:"new_earmarks"`
+}
+
+// GetFavoriteActivityByUserSince returns a summary of items and earmarks
+// added (by other users) since the provided time, on events the user has
+// favorited.
+func GetFavoriteActivityByUserSince(ctx context.Context, db PgxHandle,
+	userID int, since time.Time,
+) ([]*FavoriteActivity, error) {
+	q := `
+		SELECT
+			ev.id as event_id,
+			ev.ref_id as event_ref_id,
+			ev.name as event_name,
+			count(DISTINCT ei.id) filter (WHERE ei.created > @since) as new_items,
+			count(DISTINCT em.id) filter (
+				WHERE em.created > @since AND em.user_id != @userID
+			) as new_earmarks
+		FROM favorite_ fav
+		JOIN event_ ev ON
+			ev.id = fav.event_id
+		LEFT JOIN event_item_ ei ON
+			ei.event_id = ev.id
+		LEFT JOIN earmark_ em ON
+			em.event_item_id = ei.id
+		WHERE
+			fav.user_id = @userID AND
+			ev.archived IS FALSE
+		GROUP BY ev.id
+		HAVING
+			count(DISTINCT ei.id) filter (WHERE ei.created > @since) > 0 OR
+			count(DISTINCT em.id) filter (
+				WHERE em.created > @since AND em.user_id != @userID
+			) > 0
+		ORDER BY ev.start_time ASC
+	`
+	args := pgx.NamedArgs{
+		"userID": userID,
+		"since":  since,
+	}
+	return Query[FavoriteActivity](ctx, db, q, args)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type UserEmailChangeRefID struct {
+	reftag.IDt14
+}
+
+var NewUserEmailChangeRefID = reftag.New[UserEmailChangeRefID]
+
+// UserEmailChange is a change of a user's email address. It is pending
+// until confirmed from the new address, and is kept after that so the
+// change can still be reverted from the old address for a while.
+type UserEmailChange struct {
+	Created   time.Time
+	Confirmed *time.Time
+	OldEmail  string `db:"old_email"`
+	NewEmail  string `db:"new_email"`
+	UserID    int    `db:"user_id"`
+	ID        int
+	RefID     UserEmailChangeRefID `db:"ref_id"`
+}
+
+const (
+	// how long the new address has to confirm the change
+	UserEmailChangeExpiry = 24 * time.Hour
+	// how long the old address can revert the change
+	UserEmailChangeRevertExpiry = 7 * 24 * time.Hour
+)
+
+func NewUserEmailChange(ctx context.Context, db PgxHandle,
+	userID int, oldEmail, newEmail string,
+) (*UserEmailChange, error) {
+	refID := util.Must(NewUserEmailChangeRefID())
+	return CreateUserEmailChange(ctx, db, refID, userID, oldEmail, newEmail)
+}
+
+func CreateUserEmailChange(ctx context.Context, db PgxHandle,
+	refID UserEmailChangeRefID, userID int, oldEmail, newEmail string,
+) (*UserEmailChange, error) {
+	q := `
+		INSERT INTO user_email_change_ (
+			ref_id, user_id, old_email, new_email
+		)
+		VALUES (@refID, @userID, @oldEmail, @newEmail)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"refID":    refID,
+		"userID":   userID,
+		"oldEmail": oldEmail,
+		"newEmail": newEmail,
+	}
+	return QueryOneTx[UserEmailChange](ctx, db, q, args)
+}
+
+func GetUserEmailChangeByRefID(ctx context.Context, db PgxHandle,
+	refID UserEmailChangeRefID,
+) (*UserEmailChange, error) {
+	q := `SELECT * FROM user_email_change_ WHERE ref_id = $1`
+	return QueryOne[UserEmailChange](ctx, db, q, refID)
+}
+
+// GetPendingUserEmailChangeByUser returns the most recent unconfirmed
+// email change of the user.
+func GetPendingUserEmailChangeByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (*UserEmailChange, error) {
+	q := `
+		SELECT * FROM user_email_change_
+		WHERE
+			user_id = $1 AND
+			confirmed IS NULL
+		ORDER BY created DESC
+		LIMIT 1`
+	return QueryOne[UserEmailChange](ctx, db, q, userID)
+}
+
+func ConfirmUserEmailChange(ctx context.Context, db PgxHandle,
+	ID int,
+) error {
+	q := `
+		UPDATE user_email_change_
+		SET confirmed = timezone('utc', now())
+		WHERE id = $1`
+	return ExecTx[UserEmailChange](ctx, db, q, ID)
+}
+
+func DeleteUserEmailChange(ctx context.Context, db PgxHandle,
+	ID int,
+) error {
+	q := `DELETE FROM user_email_change_ WHERE id = $1`
+	return ExecTx[UserEmailChange](ctx, db, q, ID)
+}
+
+// DeletePendingUserEmailChangesByUser removes any unconfirmed email
+// changes of the user, so only the latest requested change can be
+// confirmed.
+func DeletePendingUserEmailChangesByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) error {
+	q := `
+		DELETE FROM user_email_change_
+		WHERE
+			user_id = $1 AND
+			confirmed IS NULL`
+	return ExecTx[UserEmailChange](ctx, db, q, userID)
+}
@@ -60,13 +60,19 @@ func GetUserEventNotification(ctx context.Context, db PgxHandle,
 }
 
 type UserEventNotificationNeeded struct {
-	When         time.Time
-	EventItemIDs []int `db:"items"`
-	UserID       int   `db:"user_id"`
-	EventID      int   `db:"event_id"`
-	Owner        bool
+	When             time.Time
+	EventItemIDs     []int `db:"items"`
+	UnclaimedItemIDs []int `db:"unclaimed_items"`
+	UserID           int   `db:"user_id"`
+	EventID          int   `db:"event_id"`
+	Owner            bool
 }
 
+// GetUserEventNotificationNeeded returns the pending reminders for upcoming
+// events. Event owners always receive the list of items without earmarks.
+// Guests (users with earmarks, or users that favorited the event) only
+// receive it when the event owner has enabled guest nudges. Users with an
+// account deletion scheduled are skipped.
 func GetUserEventNotificationNeeded(
 	ctx context.Context, db PgxHandle,
 ) ([]*UserEventNotificationNeeded, error) {
@@ -101,24 +107,76 @@ func GetUserEventNotificationNeeded(
 			WHERE
 				date_trunc('hour', ev.start_time) AT TIME ZONE 'UTC' > timezone('utc', CURRENT_TIMESTAMP)
 				AND archived IS FALSE
+
+			UNION
+
+			SELECT DISTINCT
+				fav.user_id as user_id,
+				ev.id as event_id,
+				FALSE as owner,
+				date_trunc('hour', ev.start_time) AT TIME ZONE 'UTC' as when,
+				NULL as item
+			FROM favorite_ fav
+			JOIN event_ ev
+				ON fav.event_id = ev.id
+			JOIN user_ o
+				ON ev.user_id = o.id
+			WHERE
+				date_trunc('hour', ev.start_time) AT TIME ZONE 'UTC' > timezone('utc', CURRENT_TIMESTAMP)
+				AND ev.archived IS FALSE
+				AND (o.settings->>'nudge_guests')::boolean = TRUE
+				AND EXISTS (
+					SELECT 1
+					FROM event_item_ ei
+					LEFT JOIN earmark_ em
+						ON em.event_item_id = ei.id
+					WHERE
+						ei.event_id = ev.id
+						AND em.id IS NULL
+				)
+		),
+		unclaimed as (
+			SELECT
+				ei.event_id,
+				ARRAY_AGG(ei.id ORDER BY ei.id) as items
+			FROM event_item_ ei
+			LEFT JOIN earmark_ em
+				ON em.event_item_id = ei.id
+			WHERE em.id IS NULL
+			GROUP BY ei.event_id
 		)
 		SELECT
 			subt.user_id,
 			subt.event_id,
 			subt.when,
 			bool_or(subt.owner) as owner,
-			ARRAY_AGG(subt.item ORDER BY subt.item) FILTER(WHERE subt.item IS NOT NULL) as items
+			ARRAY_AGG(subt.item ORDER BY subt.item) FILTER(WHERE subt.item IS NOT NULL) as items,
+			CASE
+				WHEN bool_or(subt.owner) OR COALESCE((o.settings->>'nudge_guests')::boolean, FALSE)
+				THEN uc.items
+				ELSE NULL
+			END as unclaimed_items
 		FROM subt
 		LEFT JOIN user_event_notification_ uen ON
 			uen.user_id = subt.user_id AND
 			uen.event_id = subt.event_id
 		JOIN user_ u ON
 			u.id = subt.user_id
+		JOIN event_ ev ON
+			ev.id = subt.event_id
+		JOIN user_ o ON
+			o.id = ev.user_id
+		LEFT JOIN unclaimed uc ON
+			uc.event_id = subt.event_id
 		WHERE
 			uen.user_id is NULL AND
 			u.verified = TRUE AND
-			(u.settings->>'enable_reminders')::boolean = TRUE
-		GROUP BY (subt.user_id, subt.event_id, subt.when)
+			(u.settings->>'enable_reminders')::boolean = TRUE AND
+			NOT EXISTS (
+				SELECT 1 FROM user_deletion_ udel
+				WHERE udel.user_id = u.id
+			)
+		GROUP BY (subt.user_id, subt.event_id, subt.when, uc.items, o.settings)
 	`
 	return Query[UserEventNotificationNeeded](ctx, db, q)
 }
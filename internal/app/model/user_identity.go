@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type UserIdentityRefID struct {
+	reftag.IDt12
+}
+
+var NewUserIdentityRefID = reftag.New[UserIdentityRefID]
+
+// UserIdentity links a user to an account at an external OpenID Connect
+// provider.
+type UserIdentity struct {
+	Created time.Time
+	Issuer  string
+	Subject string
+	Email   string
+	UserID  int `db:"user_id"`
+	ID      int
+	RefID   UserIdentityRefID `db:"ref_id"`
+}
+
+func NewUserIdentity(ctx context.Context, db PgxHandle,
+	userID int, issuer, subject, email string,
+) (*UserIdentity, error) {
+	refID := util.Must(NewUserIdentityRefID())
+	return CreateUserIdentity(ctx, db, refID, userID, issuer, subject, email)
+}
+
+func CreateUserIdentity(ctx context.Context, db PgxHandle,
+	refID UserIdentityRefID, userID int, issuer, subject, email string,
+) (*UserIdentity, error) {
+	q := `
+		INSERT INTO user_identity_ (
+			ref_id, user_id, issuer, subject, email
+		)
+		VALUES (@refID, @userID, @issuer, @subject, @email)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"refID":   refID,
+		"userID":  userID,
+		"issuer":  issuer,
+		"subject": subject,
+		"email":   email,
+	}
+	return QueryOneTx[UserIdentity](ctx, db, q, args)
+}
+
+func DeleteUserIdentity(ctx context.Context, db PgxHandle,
+	ID int,
+) error {
+	q := `DELETE FROM user_identity_ WHERE id = $1`
+	return ExecTx[UserIdentity](ctx, db, q, ID)
+}
+
+func GetUserIdentityByRefID(ctx context.Context, db PgxHandle,
+	refID UserIdentityRefID,
+) (*UserIdentity, error) {
+	q := `SELECT * FROM user_identity_ WHERE ref_id = $1`
+	return QueryOne[UserIdentity](ctx, db, q, refID)
+}
+
+func GetUserIdentityBySubject(ctx context.Context, db PgxHandle,
+	issuer, subject string,
+) (*UserIdentity, error) {
+	q := `SELECT * FROM user_identity_ WHERE issuer = $1 AND subject = $2`
+	return QueryOne[UserIdentity](ctx, db, q, issuer, subject)
+}
+
+func GetUserIdentitiesByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*UserIdentity, error) {
+	q := `SELECT * FROM user_identity_ WHERE user_id = $1 ORDER BY created`
+	return Query[UserIdentity](ctx, db, q, userID)
+}
+
+func GetUserIdentityCountByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (int, error) {
+	q := `SELECT count(*) FROM user_identity_ WHERE user_id = $1`
+	return Get[int](ctx, db, q, userID)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type UserLoginLinkRefID struct {
+	reftag.IDt11
+}
+
+var NewUserLoginLinkRefID = reftag.New[UserLoginLinkRefID]
+
+type UserLoginLink struct {
+	Created time.Time
+	UserID  int                `db:"user_id"`
+	RefID   UserLoginLinkRefID `db:"ref_id"`
+}
+
+const UserLoginLinkExpiry = 15 * time.Minute
+
+func NewUserLoginLink(ctx context.Context, db PgxHandle,
+	userID int,
+) (*UserLoginLink, error) {
+	refID := util.Must(NewUserLoginLinkRefID())
+	return CreateUserLoginLink(ctx, db, refID, userID)
+}
+
+func CreateUserLoginLink(ctx context.Context, db PgxHandle,
+	refID UserLoginLinkRefID, userID int,
+) (*UserLoginLink, error) {
+	q := `
+		INSERT INTO user_login_link_ (
+			ref_id, user_id
+		)
+		VALUES (@refID, @userID)
+		RETURNING *`
+	args := pgx.NamedArgs{"refID": refID, "userID": userID}
+	return QueryOneTx[UserLoginLink](ctx, db, q, args)
+}
+
+// ConsumeUserLoginLink deletes the login link, returning it. If the link
+// does not exist (or was already used), pgx.ErrNoRows is returned.
+func ConsumeUserLoginLink(ctx context.Context, db PgxHandle,
+	refID UserLoginLinkRefID,
+) (*UserLoginLink, error) {
+	q := `DELETE FROM user_login_link_ WHERE ref_id = $1 RETURNING *`
+	return QueryOneTx[UserLoginLink](ctx, db, q, refID)
+}
+
+func GetUserLoginLinkByRefID(ctx context.Context, db PgxHandle,
+	refID UserLoginLinkRefID,
+) (*UserLoginLink, error) {
+	q := `SELECT * FROM user_login_link_ WHERE ref_id = $1`
+	return QueryOne[UserLoginLink](ctx, db, q, refID)
+}
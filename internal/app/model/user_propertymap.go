@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"golang.org/x/exp/constraints"
 )
@@ -16,6 +17,20 @@ const (
 	DefaultReminderThresholdHours = 24
 )
 
+const (
+	DigestNone   = ""
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+func ValidateDigestFrequency(v string) (string, error) {
+	switch v {
+	case DigestNone, DigestDaily, DigestWeekly:
+		return v, nil
+	}
+	return "", fmt.Errorf("value outside constraints")
+}
+
 func ValidateReminderThresholdHours[T constraints.Unsigned](v T) (uint8, error) {
 	if v > 168 || v < 2 {
 		return 0, fmt.Errorf("value outside constraints")
@@ -23,11 +38,69 @@ func ValidateReminderThresholdHours[T constraints.Unsigned](v T) (uint8, error)
 	return uint8(v), nil
 }
 
+func ValidateQuietHour[T constraints.Unsigned](v T) (uint8, error) {
+	if v > 23 {
+		return 0, fmt.Errorf("value outside constraints")
+	}
+	return uint8(v), nil
+}
+
 type UserSettings struct {
 	ReminderThresholdHours uint8 `json:"reminder_threshold"`
 	// weird negative name here, so zero value defaults
 	// to enabling reminders
 	EnableReminders bool `json:"enable_reminders"`
+	// include the list of unclaimed items in reminders sent to
+	// guests of events owned by this user
+	NudgeGuests bool `json:"nudge_guests"`
+	// iana timezone name of the user. empty means UTC.
+	Timezone string `json:"timezone"`
+	// hours of the day (in Timezone) during which reminder
+	// emails should not be delivered
+	QuietHoursEnabled bool  `json:"quiet_hours_enabled"`
+	QuietHoursStart   uint8 `json:"quiet_hours_start"`
+	QuietHoursEnd     uint8 `json:"quiet_hours_end"`
+	// how often to send an activity digest email.
+	// one of DigestNone, DigestDaily, DigestWeekly
+	DigestFrequency string `json:"digest_frequency"`
+}
+
+// Location returns the user's configured timezone, falling back
+// to UTC if unset or invalid.
+func (p UserSettings) Location() *time.Location {
+	if p.Timezone != "" {
+		if loc, err := time.LoadLocation(p.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// InQuietHours reports whether t falls inside the user's quiet hours.
+// Windows that wrap past midnight (eg. 22 to 7) are supported.
+func (p UserSettings) InQuietHours(t time.Time) bool {
+	if !p.QuietHoursEnabled || p.QuietHoursStart == p.QuietHoursEnd {
+		return false
+	}
+	hour := uint8(t.In(p.Location()).Hour())
+	if p.QuietHoursStart < p.QuietHoursEnd {
+		return hour >= p.QuietHoursStart && hour < p.QuietHoursEnd
+	}
+	return hour >= p.QuietHoursStart || hour < p.QuietHoursEnd
+}
+
+// QuietHoursEndAfter returns the first end of the quiet hours window
+// after t.
+func (p UserSettings) QuietHoursEndAfter(t time.Time) time.Time {
+	lt := t.In(p.Location())
+	end := time.Date(
+		lt.Year(), lt.Month(), lt.Day(),
+		int(p.QuietHoursEnd), 0, 0, 0, lt.Location(),
+	)
+	if !end.After(lt) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
 }
 
 func (p UserSettings) Value() (driver.Value, error) {
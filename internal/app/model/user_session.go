@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type UserSessionRefID struct {
+	reftag.IDt13
+}
+
+var NewUserSessionRefID = reftag.New[UserSessionRefID]
+
+// UserSession indexes a logged in browser session of a user, so it can
+// be listed and revoked. The session data itself lives in the session
+// store.
+type UserSession struct {
+	Created   time.Time
+	LastSeen  time.Time `db:"last_seen"`
+	Expires   time.Time
+	IPAddress string `db:"ip_address"`
+	UserAgent string `db:"user_agent"`
+	UserID    int    `db:"user_id"`
+	ID        int
+	RefID     UserSessionRefID `db:"ref_id"`
+}
+
+func NewUserSession(ctx context.Context, db PgxHandle,
+	userID int, ipAddress, userAgent string, expires time.Time,
+) (*UserSession, error) {
+	refID := util.Must(NewUserSessionRefID())
+	return CreateUserSession(ctx, db, refID, userID, ipAddress, userAgent, expires)
+}
+
+func CreateUserSession(ctx context.Context, db PgxHandle,
+	refID UserSessionRefID, userID int, ipAddress, userAgent string,
+	expires time.Time,
+) (*UserSession, error) {
+	q := `
+		INSERT INTO user_session_ (
+			ref_id, user_id, ip_address, user_agent, expires
+		)
+		VALUES (@refID, @userID, @ipAddress, @userAgent, @expires)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"refID":     refID,
+		"userID":    userID,
+		"ipAddress": ipAddress,
+		"userAgent": userAgent,
+		"expires":   expires,
+	}
+	return QueryOneTx[UserSession](ctx, db, q, args)
+}
+
+func GetUserSessionByRefID(ctx context.Context, db PgxHandle,
+	refID UserSessionRefID,
+) (*UserSession, error) {
+	q := `SELECT * FROM user_session_ WHERE ref_id = $1`
+	return QueryOne[UserSession](ctx, db, q, refID)
+}
+
+// GetUserSessionsByUser returns the unexpired sessions of the user, most
+// recently used first.
+func GetUserSessionsByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*UserSession, error) {
+	q := `
+		SELECT * FROM user_session_
+		WHERE
+			user_id = $1 AND
+			expires > timezone('utc', now())
+		ORDER BY last_seen DESC`
+	return Query[UserSession](ctx, db, q, userID)
+}
+
+func TouchUserSession(ctx context.Context, db PgxHandle,
+	ID int,
+) error {
+	q := `UPDATE user_session_ SET last_seen = timezone('utc', now()) WHERE id = $1`
+	return ExecTx[UserSession](ctx, db, q, ID)
+}
+
+func DeleteUserSession(ctx context.Context, db PgxHandle,
+	ID int,
+) error {
+	q := `DELETE FROM user_session_ WHERE id = $1`
+	return ExecTx[UserSession](ctx, db, q, ID)
+}
+
+func DeleteUserSessionsByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) error {
+	q := `DELETE FROM user_session_ WHERE user_id = $1`
+	return ExecTx[UserSession](ctx, db, q, userID)
+}
+
+// DeleteOtherUserSessionsByUser deletes all sessions of the user, except
+// the one given.
+func DeleteOtherUserSessionsByUser(ctx context.Context, db PgxHandle,
+	userID int, keepRefID UserSessionRefID,
+) error {
+	q := `DELETE FROM user_session_ WHERE user_id = $1 AND ref_id != $2`
+	return ExecTx[UserSession](ctx, db, q, userID, keepRefID)
+}
+
+func DeleteExpiredUserSessionsByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) error {
+	q := `
+		DELETE FROM user_session_
+		WHERE
+			user_id = $1 AND
+			expires <= timezone('utc', now())`
+	return ExecTx[UserSession](ctx, db, q, userID)
+}
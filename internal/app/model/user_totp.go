@@ -0,0 +1,145 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type UserTOTP struct {
+	Created      time.Time
+	LastModified time.Time `db:"last_modified"`
+	// Secret is encrypted at rest
+	Secret   []byte
+	LastStep int64 `db:"last_step"`
+	UserID   int   `db:"user_id"`
+	Enabled  bool
+}
+
+type UserRecoveryCode struct {
+	Created  time.Time
+	Used     *time.Time
+	CodeHash []byte `db:"code_hash"`
+	UserID   int    `db:"user_id"`
+	ID       int
+}
+
+func GetUserTOTP(ctx context.Context, db PgxHandle,
+	userID int,
+) (*UserTOTP, error) {
+	q := `SELECT * FROM user_totp_ WHERE user_id = $1`
+	return QueryOne[UserTOTP](ctx, db, q, userID)
+}
+
+// CreatePendingUserTOTP stores secret for an enrollment that is yet to
+// be confirmed, replacing any earlier unconfirmed enrollment. If totp
+// is already enabled for the user, pgx.ErrNoRows is returned.
+func CreatePendingUserTOTP(ctx context.Context, db PgxHandle,
+	userID int, secret []byte,
+) (*UserTOTP, error) {
+	q := `
+		INSERT INTO user_totp_ (user_id, secret)
+		VALUES (@userID, @secret)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			secret = EXCLUDED.secret,
+			last_step = 0
+		WHERE NOT user_totp_.enabled
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"userID": userID,
+		"secret": secret,
+	}
+	return QueryOneTx[UserTOTP](ctx, db, q, args)
+}
+
+func EnableUserTOTP(ctx context.Context, db PgxHandle,
+	userID int, step int64,
+) error {
+	q := `
+		UPDATE user_totp_
+		SET enabled = TRUE, last_step = @step
+		WHERE user_id = @userID`
+	args := pgx.NamedArgs{
+		"userID": userID,
+		"step":   step,
+	}
+	return ExecTx[UserTOTP](ctx, db, q, args)
+}
+
+// UpdateUserTOTPLastStep records step as used. If a later step was
+// recorded in the meantime, pgx.ErrNoRows is returned.
+func UpdateUserTOTPLastStep(ctx context.Context, db PgxHandle,
+	userID int, step int64,
+) (*UserTOTP, error) {
+	q := `
+		UPDATE user_totp_
+		SET last_step = @step
+		WHERE user_id = @userID AND last_step < @step
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"userID": userID,
+		"step":   step,
+	}
+	return QueryOneTx[UserTOTP](ctx, db, q, args)
+}
+
+func DeleteUserTOTP(ctx context.Context, db PgxHandle,
+	userID int,
+) error {
+	q := `DELETE FROM user_totp_ WHERE user_id = $1`
+	return ExecTx[UserTOTP](ctx, db, q, userID)
+}
+
+func CreateUserRecoveryCodes(ctx context.Context, db PgxHandle,
+	userID int, codeHashes [][]byte,
+) error {
+	q := `
+		INSERT INTO user_recovery_code_ (user_id, code_hash)
+		SELECT @userID, unnest(@codeHashes::bytea[])`
+	args := pgx.NamedArgs{
+		"userID":     userID,
+		"codeHashes": codeHashes,
+	}
+	return ExecTx[UserRecoveryCode](ctx, db, q, args)
+}
+
+// UseUserRecoveryCode marks the unused recovery code matching codeHash
+// as used. If there is no such code, pgx.ErrNoRows is returned.
+func UseUserRecoveryCode(ctx context.Context, db PgxHandle,
+	userID int, codeHash []byte,
+) (*UserRecoveryCode, error) {
+	q := `
+		UPDATE user_recovery_code_
+		SET used = timezone('utc', now())
+		WHERE
+			user_id = @userID AND
+			code_hash = @codeHash AND
+			used IS NULL
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"userID":   userID,
+		"codeHash": codeHash,
+	}
+	return QueryOneTx[UserRecoveryCode](ctx, db, q, args)
+}
+
+func GetUnusedUserRecoveryCodeCount(ctx context.Context, db PgxHandle,
+	userID int,
+) (int, error) {
+	q := `
+		SELECT count(*) FROM user_recovery_code_
+		WHERE user_id = $1 AND used IS NULL`
+	return Get[int](ctx, db, q, userID)
+}
+
+func DeleteUserRecoveryCodes(ctx context.Context, db PgxHandle,
+	userID int,
+) error {
+	q := `DELETE FROM user_recovery_code_ WHERE user_id = $1`
+	return ExecTx[UserRecoveryCode](ctx, db, q, userID)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	Created      time.Time
+	LastModified time.Time `db:"last_modified"`
+	NextAttempt  time.Time `db:"next_attempt"`
+	EventType    string    `db:"event_type"`
+	Status       string
+	Payload      []byte
+	EndpointID   int `db:"endpoint_id"`
+	ID           int
+	Attempts     int
+}
+
+type WebhookAttempt struct {
+	Created    time.Time
+	EventType  string `db:"event_type"`
+	Error      string
+	EndpointID int `db:"endpoint_id"`
+	DeliveryID int `db:"delivery_id"`
+	ID         int
+	StatusCode int `db:"status_code"`
+}
+
+// EnqueueWebhookDeliveries queues a delivery of payload for each of the
+// user's enabled endpoints subscribed to eventType.
+func EnqueueWebhookDeliveries(ctx context.Context, db PgxHandle,
+	userID int, eventType string, payload []byte,
+) error {
+	q := `
+		INSERT INTO webhook_delivery_ (
+			endpoint_id, event_type, payload
+		)
+		SELECT id, @eventType::text, @payload::jsonb
+		FROM webhook_endpoint_
+		WHERE
+			user_id = @userID AND
+			enabled = TRUE AND
+			(
+				cardinality(event_types) = 0 OR
+				@eventType::text = ANY(event_types)
+			)`
+	args := pgx.NamedArgs{
+		"userID":    userID,
+		"eventType": eventType,
+		"payload":   payload,
+	}
+	return ExecTx[WebhookDelivery](ctx, db, q, args)
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries that are
+// due, pushing their next attempt out by lease. This keeps concurrent
+// workers from picking up the same deliveries, while still letting
+// another worker retry them if this one goes away mid delivery.
+func ClaimWebhookDeliveries(ctx context.Context, db PgxHandle,
+	limit int, lease time.Duration,
+) ([]*WebhookDelivery, error) {
+	q := `
+		UPDATE webhook_delivery_
+		SET next_attempt = timezone('utc', now()) + make_interval(secs => @leaseSecs)
+		WHERE id IN (
+			SELECT id
+			FROM webhook_delivery_
+			WHERE
+				status = 'pending' AND
+				next_attempt <= timezone('utc', now())
+			ORDER BY next_attempt ASC
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"limit":     limit,
+		"leaseSecs": lease.Seconds(),
+	}
+	return QueryTx[WebhookDelivery](ctx, db, q, args)
+}
+
+// UpdateWebhookDelivery records the outcome of an attempt. leasedUntil is
+// the next_attempt set when the delivery was claimed. If another worker
+// has since claimed it, nothing is updated and zero rows are returned.
+func UpdateWebhookDelivery(ctx context.Context, db PgxHandle,
+	deliveryID int, leasedUntil time.Time,
+	status string, attempts int, nextAttempt time.Time,
+) (int64, error) {
+	q := `
+		UPDATE webhook_delivery_
+		SET
+			status = @status,
+			attempts = @attempts,
+			next_attempt = @nextAttempt
+		WHERE
+			id = @deliveryID AND
+			status = 'pending' AND
+			next_attempt = @leasedUntil`
+	args := pgx.NamedArgs{
+		"status":      status,
+		"attempts":    attempts,
+		"nextAttempt": nextAttempt,
+		"deliveryID":  deliveryID,
+		"leasedUntil": leasedUntil,
+	}
+	return ExecTxRows[WebhookDelivery](ctx, db, q, args)
+}
+
+// DeleteWebhookDeliveriesBefore prunes finished deliveries (and their
+// attempts) created before the given time.
+func DeleteWebhookDeliveriesBefore(ctx context.Context, db PgxHandle,
+	before time.Time,
+) error {
+	q := `
+		DELETE FROM webhook_delivery_
+		WHERE
+			status != 'pending' AND
+			created < $1`
+	return ExecTx[WebhookDelivery](ctx, db, q, before)
+}
+
+func CreateWebhookAttempt(ctx context.Context, db PgxHandle,
+	delivery *WebhookDelivery, statusCode int, errMsg string,
+) (*WebhookAttempt, error) {
+	q := `
+		INSERT INTO webhook_attempt_ (
+			endpoint_id, delivery_id, event_type, status_code, error
+		)
+		VALUES (
+			@endpointID, @deliveryID, @eventType, @statusCode, @error
+		)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"endpointID": delivery.EndpointID,
+		"deliveryID": delivery.ID,
+		"eventType":  delivery.EventType,
+		"statusCode": statusCode,
+		"error":      errMsg,
+	}
+	return QueryOneTx[WebhookAttempt](ctx, db, q, args)
+}
+
+func GetWebhookAttemptsByEndpoint(ctx context.Context, db PgxHandle,
+	endpointID int, limit int,
+) ([]*WebhookAttempt, error) {
+	q := `
+		SELECT *
+		FROM webhook_attempt_
+		WHERE endpoint_id = @endpointID
+		ORDER BY created DESC, id DESC
+		LIMIT @limit
+		`
+	args := pgx.NamedArgs{
+		"endpointID": endpointID,
+		"limit":      limit,
+	}
+	return Query[WebhookAttempt](ctx, db, q, args)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package model
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+type WebhookEndpointRefID struct {
+	reftag.IDt10
+}
+
+var NewWebhookEndpointRefID = reftag.New[WebhookEndpointRefID]
+
+type WebhookEndpoint struct {
+	Created      time.Time
+	LastModified time.Time `db:"last_modified"`
+	URL          string
+	Secret       string
+	// empty means all event types
+	EventTypes []string `db:"event_types"`
+	UserID     int      `db:"user_id"`
+	ID         int
+	RefID      WebhookEndpointRefID `db:"ref_id"`
+	Enabled    bool
+}
+
+// Wants reports whether the endpoint is subscribed to eventType
+func (we *WebhookEndpoint) Wants(eventType string) bool {
+	return len(we.EventTypes) == 0 || slices.Contains(we.EventTypes, eventType)
+}
+
+func NewWebhookEndpoint(ctx context.Context, db PgxHandle,
+	userID int, url, secret string, eventTypes []string,
+) (*WebhookEndpoint, error) {
+	refID := util.Must(NewWebhookEndpointRefID())
+	return CreateWebhookEndpoint(ctx, db, refID, userID, url, secret, eventTypes)
+}
+
+func CreateWebhookEndpoint(ctx context.Context, db PgxHandle,
+	refID WebhookEndpointRefID, userID int,
+	url, secret string, eventTypes []string,
+) (*WebhookEndpoint, error) {
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	q := `
+		INSERT INTO webhook_endpoint_ (
+			ref_id, user_id, url, secret, event_types
+		)
+		VALUES (
+			@refID, @userID, @url, @secret, @eventTypes
+		)
+		RETURNING *`
+	args := pgx.NamedArgs{
+		"refID":      refID,
+		"userID":     userID,
+		"url":        url,
+		"secret":     secret,
+		"eventTypes": eventTypes,
+	}
+	return QueryOneTx[WebhookEndpoint](ctx, db, q, args)
+}
+
+type WebhookEndpointUpdateModelValues struct {
+	URL        mo.Option[string]
+	Secret     mo.Option[string]
+	EventTypes mo.Option[[]string]
+	Enabled    mo.Option[bool]
+}
+
+func UpdateWebhookEndpoint(ctx context.Context, db PgxHandle,
+	endpointID int, vals *WebhookEndpointUpdateModelValues,
+) error {
+	q := `
+		UPDATE webhook_endpoint_
+		SET
+			url = COALESCE(@url, url),
+			secret = COALESCE(@secret, secret),
+			event_types = COALESCE(@eventTypes, event_types),
+			enabled = COALESCE(@enabled, enabled)
+		WHERE id = @endpointID`
+	args := pgx.NamedArgs{
+		"url":        vals.URL,
+		"secret":     vals.Secret,
+		"eventTypes": vals.EventTypes,
+		"enabled":    vals.Enabled,
+		"endpointID": endpointID,
+	}
+	return ExecTx[WebhookEndpoint](ctx, db, q, args)
+}
+
+func DeleteWebhookEndpoint(ctx context.Context, db PgxHandle,
+	endpointID int,
+) error {
+	q := `DELETE FROM webhook_endpoint_ WHERE id = $1`
+	return ExecTx[WebhookEndpoint](ctx, db, q, endpointID)
+}
+
+func GetWebhookEndpointByID(ctx context.Context, db PgxHandle,
+	endpointID int,
+) (*WebhookEndpoint, error) {
+	q := `SELECT * FROM webhook_endpoint_ WHERE id = $1`
+	return QueryOne[WebhookEndpoint](ctx, db, q, endpointID)
+}
+
+func GetWebhookEndpointByRefID(ctx context.Context, db PgxHandle,
+	refID WebhookEndpointRefID,
+) (*WebhookEndpoint, error) {
+	q := `SELECT * FROM webhook_endpoint_ WHERE ref_id = $1`
+	return QueryOne[WebhookEndpoint](ctx, db, q, refID)
+}
+
+func GetWebhookEndpointsByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) ([]*WebhookEndpoint, error) {
+	q := `
+		SELECT *
+		FROM webhook_endpoint_
+		WHERE user_id = $1
+		ORDER BY created ASC
+		`
+	return Query[WebhookEndpoint](ctx, db, q, userID)
+}
+
+func GetWebhookEndpointCountByUser(ctx context.Context, db PgxHandle,
+	userID int,
+) (int, error) {
+	q := `SELECT count(*) FROM webhook_endpoint_ WHERE user_id = $1`
+	return Get[int](ctx, db, q, userID)
+}
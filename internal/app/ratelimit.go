@@ -0,0 +1,44 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package app
+
+import (
+	"time"
+
+	"github.com/dropwhile/icanbringthat/internal/ratelimit"
+)
+
+// per route rate limit policies. account keyed policies slow down
+// attempts against a single account, ip keyed policies slow down
+// attempts spread across many accounts.
+var (
+	loginLimits = []ratelimit.Policy{
+		{Name: "login-ip", Key: ratelimit.ByIP, Limit: 30, Window: 15 * time.Minute},
+		{Name: "login-account", Key: ratelimit.ByFormValue("email"), Limit: 10, Window: 15 * time.Minute},
+	}
+	pwResetLimits = []ratelimit.Policy{
+		{Name: "pw-reset-ip", Key: ratelimit.ByIP, Limit: 20, Window: time.Hour},
+		{Name: "pw-reset-account", Key: ratelimit.ByFormValue("email"), Limit: 5, Window: time.Hour},
+	}
+	loginLinkLimits = []ratelimit.Policy{
+		{Name: "login-link-ip", Key: ratelimit.ByIP, Limit: 20, Window: time.Hour},
+		{Name: "login-link-account", Key: ratelimit.ByFormValue("email"), Limit: 5, Window: time.Hour},
+	}
+	oidcLimits = []ratelimit.Policy{
+		{Name: "oidc-ip", Key: ratelimit.ByIP, Limit: 30, Window: 15 * time.Minute},
+	}
+	verifyLimits = []ratelimit.Policy{
+		{Name: "verify-user", Key: ratelimit.ByUser, Limit: 5, Window: time.Hour},
+	}
+	totpLimits = []ratelimit.Policy{
+		{Name: "totp-user", Key: ratelimit.ByUser, Limit: 10, Window: 15 * time.Minute},
+	}
+	signupLimits = []ratelimit.Policy{
+		{Name: "signup-ip", Key: ratelimit.ByIP, Limit: 10, Window: time.Hour},
+	}
+	rpcLimits = []ratelimit.Policy{
+		{Name: "rpc-ip", Key: ratelimit.ByIP, Limit: 1200, Window: time.Minute},
+		{Name: "rpc-api-key", Key: ratelimit.ByApiKey, Limit: 600, Window: time.Minute},
+	}
+)
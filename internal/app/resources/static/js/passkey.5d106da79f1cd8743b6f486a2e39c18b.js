@@ -73,7 +73,7 @@ async function registerPasskey() {
 
 window.registerPasskey = registerPasskey
 
-async function authPasskey(autofill = false) {
+async function authPasskey(autofill = false, remember = false) {
     const notyf = new Notyf({
         ripple: false,
         dismissible: true,
@@ -91,7 +91,8 @@ async function authPasskey(autofill = false) {
 
     // GET authentication options from the endpoint that calls
     // @simplewebauthn/server -> generateAuthenticationOptions()
-    const loginResp = await fetch('/webauthn/login');
+    const loginResp = await fetch(
+        '/webauthn/login' + (remember ? '?remember=on' : ''));
     const loginJSON = await loginResp.json();
 
     if (!loginJSON) {
@@ -17,6 +17,7 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
 )
 
 func (s *Server) EventListEarmarks(ctx context.Context,
@@ -70,11 +71,26 @@ func (s *Server) EarmarksList(ctx context.Context,
 	var paginationResult *icbt.PaginationResult
 	var earmarks []*model.Earmark
 	if req.Msg.HasPagination() {
-		ems, pgResult, errx := s.svc.GetEarmarksPaginated(
-			ctx, user.ID,
-			int(req.Msg.GetPagination().GetLimit()),
-			int(req.Msg.GetPagination().GetOffset()),
-			showArchived)
+		cursor, err := parsePageCursor(req.Msg.GetPagination())
+		if err != nil {
+			return nil, err
+		}
+
+		var ems []*model.Earmark
+		var pgResult *service.Pagination
+		var errx errs.Error
+		if cursor != nil {
+			ems, pgResult, errx = s.svc.GetEarmarksPaginatedAfter(
+				ctx, user.ID,
+				int(req.Msg.GetPagination().GetLimit()),
+				cursor, showArchived)
+		} else {
+			ems, pgResult, errx = s.svc.GetEarmarksPaginated(
+				ctx, user.ID,
+				int(req.Msg.GetPagination().GetLimit()),
+				int(req.Msg.GetPagination().GetOffset()),
+				showArchived)
+		}
 		if errx != nil {
 			return nil, convert.ToConnectRpcError(errx)
 		}
@@ -118,20 +134,23 @@ func (s *Server) EarmarkCreate(ctx context.Context,
 		return nil, convert.ToConnectRpcError(errx)
 	}
 
-	earmark, errx := s.svc.NewEarmark(ctx, user, eventItem.ID, req.Msg.GetNote())
-	if errx != nil {
-		return nil, convert.ToConnectRpcError(errx)
-	}
+	return idempotent(ctx, s.svc, user.ID, rpcv1connect.IcbtRpcServiceEarmarkCreateProcedure, req,
+		func() (*connect.Response[icbt.EarmarkCreateResponse], error) {
+			earmark, errx := s.svc.NewEarmark(ctx, user, eventItem.ID, req.Msg.GetNote())
+			if errx != nil {
+				return nil, convert.ToConnectRpcError(errx)
+			}
 
-	pbEarmark, err := convert.ToPbEarmark(ctx, s.svc, earmark)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, errors.New("db error"))
-	}
+			pbEarmark, err := convert.ToPbEarmark(ctx, s.svc, earmark)
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, errors.New("db error"))
+			}
 
-	response := icbt.EarmarkCreateResponse_builder{
-		Earmark: pbEarmark,
-	}.Build()
-	return connect.NewResponse(response), nil
+			response := icbt.EarmarkCreateResponse_builder{
+				Earmark: pbEarmark,
+			}.Build()
+			return connect.NewResponse(response), nil
+		})
 }
 
 func (s *Server) EarmarkGetDetails(ctx context.Context,
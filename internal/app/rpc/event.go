@@ -15,8 +15,10 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/app/convert"
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
 )
 
 func (s *Server) EventsList(ctx context.Context,
@@ -38,10 +40,23 @@ func (s *Server) EventsList(ctx context.Context,
 	if req.Msg.HasPagination() {
 		limit := int(req.Msg.GetPagination().GetLimit())
 		offset := int(req.Msg.GetPagination().GetOffset())
+		cursor, err := parsePageCursor(req.Msg.GetPagination())
+		if err != nil {
+			return nil, err
+		}
 
-		evts, pagination, errx := s.svc.GetEventsPaginated(
-			ctx, user.ID, limit, offset, showArchived,
-		)
+		var evts []*model.Event
+		var pagination *service.Pagination
+		var errx errs.Error
+		if cursor != nil {
+			evts, pagination, errx = s.svc.GetEventsPaginatedAfter(
+				ctx, user.ID, limit, cursor, showArchived,
+			)
+		} else {
+			evts, pagination, errx = s.svc.GetEventsPaginated(
+				ctx, user.ID, limit, offset, showArchived,
+			)
+		}
 		if errx != nil {
 			return nil, convert.ToConnectRpcError(errx)
 		}
@@ -83,17 +98,20 @@ func (s *Server) EventCreate(ctx context.Context,
 	when := req.Msg.GetWhen().GetTs().AsTime()
 	tz := req.Msg.GetWhen().GetTz()
 
-	event, errx := s.svc.CreateEvent(
-		ctx, user, name, description, when, tz,
-	)
-	if errx != nil {
-		return nil, convert.ToConnectRpcError(errx)
-	}
-
-	response := icbt.EventCreateResponse_builder{
-		Event: convert.ToPbEvent(event),
-	}.Build()
-	return connect.NewResponse(response), nil
+	return idempotent(ctx, s.svc, user.ID, rpcv1connect.IcbtRpcServiceEventCreateProcedure, req,
+		func() (*connect.Response[icbt.EventCreateResponse], error) {
+			event, errx := s.svc.CreateEvent(
+				ctx, user, name, description, when, tz,
+			)
+			if errx != nil {
+				return nil, convert.ToConnectRpcError(errx)
+			}
+
+			response := icbt.EventCreateResponse_builder{
+				Event: convert.ToPbEvent(event),
+			}.Build()
+			return connect.NewResponse(response), nil
+		})
 }
 
 func (s *Server) EventUpdate(ctx context.Context,
@@ -127,10 +145,22 @@ func (s *Server) EventUpdate(ctx context.Context,
 			euvs.Tz = mo.Some(tz)
 		}
 	}
+	if req.Msg.HasVersion() {
+		euvs.Version = mo.Some(req.Msg.GetVersion())
+	}
 
 	errx := s.svc.UpdateEvent(ctx, user.ID, refID, euvs)
 	if errx != nil {
-		return nil, convert.ToConnectRpcError(errx)
+		rpcErr := convert.ToConnectRpcError(errx)
+		if errx.Code() == errs.Aborted {
+			// include the current state, so the client can reconcile
+			if event, errx := s.svc.GetEvent(ctx, refID); errx == nil {
+				if detail, err := connect.NewErrorDetail(convert.ToPbEvent(event)); err == nil {
+					rpcErr.AddDetail(detail)
+				}
+			}
+		}
+		return nil, rpcErr
 	}
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
@@ -160,6 +190,7 @@ func (s *Server) EventGetDetails(ctx context.Context,
 	if errx != nil {
 		return nil, convert.ToConnectRpcError(errx)
 	}
+	eventItems = service.SortEventItems(eventItems, event.ItemSortOrder)
 	pbEventItems := convert.ToPbList(convert.ToPbEventItem, eventItems)
 
 	earmarks, errx := s.svc.GetEarmarksByEventID(ctx, event.ID)
@@ -8,14 +8,18 @@ import (
 	"errors"
 
 	"connectrpc.com/connect"
+	"github.com/samber/mo"
 
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/dropwhile/icanbringthat/internal/app/convert"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
 
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
 )
 
 func (s *Server) EventListItems(ctx context.Context,
@@ -43,6 +47,53 @@ func (s *Server) EventListItems(ctx context.Context,
 	return connect.NewResponse(response), nil
 }
 
+func (s *Server) EventUpdateItemSorting(ctx context.Context,
+	req *connect.Request[icbt.EventUpdateItemSortingRequest],
+) (*connect.Response[icbt.EventUpdateItemSortingResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseEventRefID(req.Msg.GetEventRefId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad event ref-id"))
+	}
+
+	items, errx := s.svc.GetEventItemsByEvent(ctx, refID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	// sort order is stored by item id, so map the supplied ref-ids
+	itemIDs := make(map[string]int, len(items))
+	for _, item := range items {
+		itemIDs[item.RefID.String()] = item.ID
+	}
+	order := make([]int, 0, len(req.Msg.GetItemRefIds()))
+	for _, itemRefID := range req.Msg.GetItemRefIds() {
+		itemID, ok := itemIDs[itemRefID]
+		if !ok {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				errors.New("item ref-id not found in event: "+itemRefID))
+		}
+		order = append(order, itemID)
+	}
+	order = util.Uniq(order)
+
+	event, errx := s.svc.UpdateEventItemSorting(ctx, user.ID, refID, order)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	items = service.SortEventItems(items, event.ItemSortOrder)
+	response := icbt.EventUpdateItemSortingResponse_builder{
+		Items: convert.ToPbList(convert.ToPbEventItem, items),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
 func (s *Server) EventRemoveItem(ctx context.Context,
 	req *connect.Request[icbt.EventRemoveItemRequest],
 ) (*connect.Response[emptypb.Empty], error) {
@@ -79,17 +130,20 @@ func (s *Server) EventAddItem(ctx context.Context,
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad event ref-id"))
 	}
 
-	eventItem, errx := s.svc.AddEventItem(
-		ctx, user.ID, refID, req.Msg.GetDescription(),
-	)
-	if errx != nil {
-		return nil, convert.ToConnectRpcError(errx)
-	}
-
-	response := icbt.EventAddItemResponse_builder{
-		EventItem: convert.ToPbEventItem(eventItem),
-	}.Build()
-	return connect.NewResponse(response), nil
+	return idempotent(ctx, s.svc, user.ID, rpcv1connect.IcbtRpcServiceEventAddItemProcedure, req,
+		func() (*connect.Response[icbt.EventAddItemResponse], error) {
+			eventItem, errx := s.svc.AddEventItem(
+				ctx, user.ID, refID, req.Msg.GetDescription(),
+			)
+			if errx != nil {
+				return nil, convert.ToConnectRpcError(errx)
+			}
+
+			response := icbt.EventAddItemResponse_builder{
+				EventItem: convert.ToPbEventItem(eventItem),
+			}.Build()
+			return connect.NewResponse(response), nil
+		})
 }
 
 func (s *Server) EventUpdateItem(ctx context.Context,
@@ -106,11 +160,25 @@ func (s *Server) EventUpdateItem(ctx context.Context,
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad event-item ref-id"))
 	}
 
+	version := mo.None[string]()
+	if req.Msg.HasVersion() {
+		version = mo.Some(req.Msg.GetVersion())
+	}
+
 	eventItem, errx := s.svc.UpdateEventItem(
-		ctx, user.ID, refID, req.Msg.GetDescription(), nil,
+		ctx, user.ID, refID, req.Msg.GetDescription(), version, nil,
 	)
 	if errx != nil {
-		return nil, convert.ToConnectRpcError(errx)
+		rpcErr := convert.ToConnectRpcError(errx)
+		if errx.Code() == errs.Aborted {
+			// include the current state, so the client can reconcile
+			if eventItem, errx := s.svc.GetEventItem(ctx, refID); errx == nil {
+				if detail, err := connect.NewErrorDetail(convert.ToPbEventItem(eventItem)); err == nil {
+					rpcErr.AddDetail(detail)
+				}
+			}
+		}
+		return nil, rpcErr
 	}
 
 	response := icbt.EventUpdateItemResponse_builder{
@@ -9,6 +9,7 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/dropwhile/assert"
+	"github.com/samber/mo"
 	"go.uber.org/mock/gomock"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
@@ -397,7 +398,7 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 
 		mock.EXPECT().
 			UpdateEventItem(
-				ctx, user.ID, eventItemRefID, description,
+				ctx, user.ID, eventItemRefID, description, mo.None[string](),
 				gomock.AssignableToTypeOf(eventItemFailIfCheck),
 			).
 			Return(
@@ -444,7 +445,7 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 
 		mock.EXPECT().
 			UpdateEventItem(
-				ctx, user.ID, eventItemRefID, description,
+				ctx, user.ID, eventItemRefID, description, mo.None[string](),
 				gomock.AssignableToTypeOf(eventItemFailIfCheck),
 			).
 			Return(nil, errs.PermissionDenied.Error("event is archived"))
@@ -469,7 +470,7 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 
 		mock.EXPECT().
 			UpdateEventItem(
-				ctx, user.ID, eventItemRefID, description,
+				ctx, user.ID, eventItemRefID, description, mo.None[string](),
 				gomock.AssignableToTypeOf(eventItemFailIfCheck),
 			).
 			Return(nil, errs.PermissionDenied.Error("not event owner"))
@@ -494,7 +495,7 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 
 		mock.EXPECT().
 			UpdateEventItem(
-				ctx, user.ID, eventItemRefID, description,
+				ctx, user.ID, eventItemRefID, description, mo.None[string](),
 				gomock.AssignableToTypeOf(eventItemFailIfCheck),
 			).
 			Return(nil, errs.PermissionDenied.Error("earmarked by other user"))
@@ -519,7 +520,7 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 
 		mock.EXPECT().
 			UpdateEventItem(
-				ctx, user.ID, eventItemRefID, description,
+				ctx, user.ID, eventItemRefID, description, mo.None[string](),
 				gomock.AssignableToTypeOf(eventItemFailIfCheck),
 			).
 			Return(nil, errs.ArgumentError("description", "bad value"))
@@ -544,7 +545,7 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 
 		mock.EXPECT().
 			UpdateEventItem(
-				ctx, user.ID, eventItemRefID, description,
+				ctx, user.ID, eventItemRefID, description, mo.None[string](),
 				gomock.AssignableToTypeOf(eventItemFailIfCheck),
 			).
 			Return(nil, errs.NotFound.Error("event-item not found"))
@@ -558,3 +559,115 @@ func TestRpc_UpdateEventItem(t *testing.T) {
 		errs.AssertError(t, rpcErr, connect.CodeNotFound, "event-item not found")
 	})
 }
+
+func TestRpc_UpdateEventItemSorting(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	items := []*model.EventItem{
+		{
+			ID:          4,
+			RefID:       util.Must(model.NewEventItemRefID()),
+			EventID:     3,
+			Description: "first",
+		},
+		{
+			ID:          5,
+			RefID:       util.Must(model.NewEventItemRefID()),
+			EventID:     3,
+			Description: "second",
+		},
+	}
+
+	t.Run("update item sorting should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		eventRefID := util.Must(model.NewEventRefID())
+
+		mock.EXPECT().
+			GetEventItemsByEvent(ctx, eventRefID).
+			Return(items, nil)
+		mock.EXPECT().
+			UpdateEventItemSorting(ctx, user.ID, eventRefID, []int{5, 4}).
+			Return(&model.Event{
+				ID:            3,
+				RefID:         eventRefID,
+				UserID:        user.ID,
+				ItemSortOrder: []int{5, 4},
+			}, nil)
+
+		request := icbt.EventUpdateItemSortingRequest_builder{
+			EventRefId: eventRefID.String(),
+			ItemRefIds: []string{
+				items[1].RefID.String(),
+				items[0].RefID.String(),
+				items[1].RefID.String(),
+			},
+		}.Build()
+		response, err := server.EventUpdateItemSorting(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		result := response.Msg.GetItems()
+		assert.Equal(t, len(result), 2)
+		assert.Equal(t, result[0].GetRefId(), items[1].RefID.String())
+		assert.Equal(t, result[1].GetRefId(), items[0].RefID.String())
+	})
+
+	t.Run("update item sorting with unknown item should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		eventRefID := util.Must(model.NewEventRefID())
+		otherRefID := util.Must(model.NewEventItemRefID())
+
+		mock.EXPECT().
+			GetEventItemsByEvent(ctx, eventRefID).
+			Return(items, nil)
+
+		request := icbt.EventUpdateItemSortingRequest_builder{
+			EventRefId: eventRefID.String(),
+			ItemRefIds: []string{otherRefID.String()},
+		}.Build()
+		_, err := server.EventUpdateItemSorting(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeInvalidArgument,
+			"item ref-id not found in event: "+otherRefID.String())
+	})
+
+	t.Run("update item sorting for other user event should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		eventRefID := util.Must(model.NewEventRefID())
+
+		mock.EXPECT().
+			GetEventItemsByEvent(ctx, eventRefID).
+			Return(items, nil)
+		mock.EXPECT().
+			UpdateEventItemSorting(ctx, user.ID, eventRefID, []int{4}).
+			Return(nil, errs.PermissionDenied.Error("permission denied"))
+
+		request := icbt.EventUpdateItemSortingRequest_builder{
+			EventRefId: eventRefID.String(),
+			ItemRefIds: []string{items[0].RefID.String()},
+		}.Build()
+		_, err := server.EventUpdateItemSorting(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodePermissionDenied, "permission denied")
+	})
+}
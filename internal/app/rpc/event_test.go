@@ -11,6 +11,8 @@ import (
 	"connectrpc.com/connect"
 	"github.com/dropwhile/assert"
 	"github.com/samber/mo"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/dropwhile/icanbringthat/internal/app/convert"
 	"github.com/dropwhile/icanbringthat/internal/app/model"
@@ -19,6 +21,7 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 	"github.com/dropwhile/icanbringthat/internal/util"
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
 )
 
 func TestRpc_ListEvents(t *testing.T) {
@@ -80,6 +83,65 @@ func TestRpc_ListEvents(t *testing.T) {
 		assert.Equal(t, len(response.Msg.GetEvents()), 1)
 	})
 
+	t.Run("list events with cursor should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		cursor := &service.PageCursor{Time: tstTs, ID: 12}
+		nextCursor := &service.PageCursor{Time: tstTs.Add(-time.Hour), ID: 11}
+
+		mock.EXPECT().
+			GetEventsPaginatedAfter(ctx, user.ID, 10, cursor, false).
+			Return(
+				[]*model.Event{{
+					ID:            11,
+					RefID:         util.Must(model.NewEventRefID()),
+					UserID:        user.ID,
+					Name:          "some name",
+					Description:   "some desc",
+					ItemSortOrder: []int{},
+					StartTime:     nextCursor.Time,
+					StartTimeTz:   util.Must(service.ParseTimeZone("UTC")),
+				}},
+				&service.Pagination{
+					Limit:      10,
+					Count:      5,
+					NextCursor: nextCursor.String(),
+				}, nil,
+			)
+
+		request := icbt.EventsListRequest_builder{
+			Pagination: icbt.PaginationRequest_builder{
+				Limit:  10,
+				Cursor: cursor.String(),
+			}.Build(),
+		}.Build()
+		response, err := server.EventsList(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		assert.Equal(t, len(response.Msg.GetEvents()), 1)
+		assert.Equal(t, response.Msg.GetPagination().GetNextCursor(), nextCursor.String())
+	})
+
+	t.Run("list events with bad cursor should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := icbt.EventsListRequest_builder{
+			Pagination: icbt.PaginationRequest_builder{
+				Limit:  10,
+				Cursor: "hodor",
+			}.Build(),
+		}.Build()
+		_, err := server.EventsList(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeInvalidArgument, "bad pagination cursor")
+	})
+
 	t.Run("list events non-paginated should succeed", func(t *testing.T) {
 		t.Parallel()
 
@@ -292,6 +354,114 @@ func TestRpc_CreateEvent(t *testing.T) {
 		assert.Equal(t, response.Msg.GetEvent().GetName(), event.Name)
 	})
 
+	t.Run("create event with idempotency key should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		key := "some-key"
+
+		mock.EXPECT().
+			ReserveIdempotencyKey(
+				ctx, user.ID, key, rpcv1connect.IcbtRpcServiceEventCreateProcedure,
+				gomock.Any(),
+			).
+			Return(&model.IdempotencyKey{}, true, nil)
+		mock.EXPECT().
+			CreateEvent(
+				ctx, user, event.Name, event.Description, event.StartTime,
+				event.StartTimeTz.Location.String(),
+			).
+			Return(event, nil)
+		mock.EXPECT().
+			CompleteIdempotencyKey(ctx, user.ID, key, gomock.Any()).
+			Return(nil)
+
+		request := connect.NewRequest(icbt.EventCreateRequest_builder{
+			Name:        event.Name,
+			Description: event.Description,
+			When: convert.TimeToTimestampTZ(
+				event.StartTime.In(event.StartTimeTz.Location)),
+		}.Build())
+		request.Header().Set(IdempotencyKeyHeader, key)
+		response, err := server.EventCreate(ctx, request)
+		assert.Nil(t, err)
+		assert.Equal(t, response.Msg.GetEvent().GetName(), event.Name)
+		assert.Equal(t, response.Header().Get(IdempotentReplayedHeader), "")
+	})
+
+	t.Run("create event with used idempotency key should replay", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		key := "some-key"
+
+		stored, err := proto.Marshal(icbt.EventCreateResponse_builder{
+			Event: icbt.Event_builder{
+				RefId: event.RefID.String(),
+				Name:  event.Name,
+			}.Build(),
+		}.Build())
+		assert.Nil(t, err)
+
+		mock.EXPECT().
+			ReserveIdempotencyKey(
+				ctx, user.ID, key, rpcv1connect.IcbtRpcServiceEventCreateProcedure,
+				gomock.Any(),
+			).
+			Return(&model.IdempotencyKey{Response: stored}, false, nil)
+
+		request := connect.NewRequest(icbt.EventCreateRequest_builder{
+			Name:        event.Name,
+			Description: event.Description,
+			When: convert.TimeToTimestampTZ(
+				event.StartTime.In(event.StartTimeTz.Location)),
+		}.Build())
+		request.Header().Set(IdempotencyKeyHeader, key)
+		response, err := server.EventCreate(ctx, request)
+		assert.Nil(t, err)
+		assert.Equal(t, response.Msg.GetEvent().GetRefId(), event.RefID.String())
+		assert.Equal(t, response.Header().Get(IdempotentReplayedHeader), "true")
+	})
+
+	t.Run("create event failure should release idempotency key", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		key := "some-key"
+
+		mock.EXPECT().
+			ReserveIdempotencyKey(
+				ctx, user.ID, key, rpcv1connect.IcbtRpcServiceEventCreateProcedure,
+				gomock.Any(),
+			).
+			Return(&model.IdempotencyKey{}, true, nil)
+		mock.EXPECT().
+			CreateEvent(
+				ctx, user, event.Name, event.Description, event.StartTime,
+				event.StartTimeTz.Location.String(),
+			).
+			Return(nil, errs.Internal.Error("db error"))
+		mock.EXPECT().
+			ReleaseIdempotencyKey(ctx, user.ID, key).
+			Return(nil)
+
+		request := connect.NewRequest(icbt.EventCreateRequest_builder{
+			Name:        event.Name,
+			Description: event.Description,
+			When: convert.TimeToTimestampTZ(
+				event.StartTime.In(event.StartTimeTz.Location)),
+		}.Build())
+		request.Header().Set(IdempotencyKeyHeader, key)
+		_, err := server.EventCreate(ctx, request)
+		errs.AssertError(t, err, connect.CodeInternal, "db error")
+	})
+
 	t.Run("create event with empty TZ should fail", func(t *testing.T) {
 		t.Parallel()
 
@@ -443,6 +613,42 @@ func TestRpc_UpdateEvent(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("update event with stale version should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		stale := model.Version(tstTs.Add(-time.Minute))
+
+		mock.EXPECT().
+			UpdateEvent(ctx, user.ID, event.RefID, &service.EventUpdateValues{
+				Name:    mo.Some(event.Name),
+				Version: mo.Some(stale),
+			}).
+			Return(errs.Aborted.Error("event was modified").
+				WithMeta("version", event.Version()))
+		mock.EXPECT().
+			GetEvent(ctx, event.RefID).
+			Return(event, nil)
+
+		request := icbt.EventUpdateRequest_builder{
+			RefId:   event.RefID.String(),
+			Name:    &event.Name,
+			Version: &stale,
+		}.Build()
+		_, err := server.EventUpdate(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeAborted, "event was modified")
+		details := rpcErr.Details()
+		assert.Equal(t, len(details), 1)
+		detail, derr := details[0].Value()
+		assert.Nil(t, derr)
+		current, ok := detail.(*icbt.Event)
+		assert.True(t, ok)
+		assert.Equal(t, current.GetVersion(), event.Version())
+	})
+
 	t.Run("update event with empty TZ should succeed", func(t *testing.T) {
 		t.Parallel()
 
@@ -15,9 +15,11 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/app/convert"
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
 )
 
 func (s *Server) FavoriteListEvents(ctx context.Context,
@@ -39,9 +41,21 @@ func (s *Server) FavoriteListEvents(ctx context.Context,
 	if req.Msg.HasPagination() {
 		limit := int(req.Msg.GetPagination().GetLimit())
 		offset := int(req.Msg.GetPagination().GetOffset())
+		cursor, err := parsePageCursor(req.Msg.GetPagination())
+		if err != nil {
+			return nil, err
+		}
 
-		favs, pagination, errx := s.svc.GetFavoriteEventsPaginated(
-			ctx, user.ID, limit, offset, showArchived)
+		var favs []*model.Event
+		var pagination *service.Pagination
+		var errx errs.Error
+		if cursor != nil {
+			favs, pagination, errx = s.svc.GetFavoriteEventsPaginatedAfter(
+				ctx, user.ID, limit, cursor, showArchived)
+		} else {
+			favs, pagination, errx = s.svc.GetFavoriteEventsPaginated(
+				ctx, user.ID, limit, offset, showArchived)
+		}
 		if errx != nil {
 			return nil, convert.ToConnectRpcError(errx)
 		}
@@ -100,16 +114,19 @@ func (s *Server) FavoriteAdd(ctx context.Context,
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad event ref-id"))
 	}
 
-	favorite, errx := s.svc.AddFavorite(ctx, user.ID, refID)
-	if errx != nil {
-		return nil, convert.ToConnectRpcError(errx)
-	}
-
-	response := icbt.FavoriteAddResponse_builder{
-		Favorite: icbt.Favorite_builder{
-			EventRefId: req.Msg.GetEventRefId(),
-			Created:    timestamppb.New(favorite.Created),
-		}.Build(),
-	}.Build()
-	return connect.NewResponse(response), nil
+	return idempotent(ctx, s.svc, user.ID, rpcv1connect.IcbtRpcServiceFavoriteAddProcedure, req,
+		func() (*connect.Response[icbt.FavoriteAddResponse], error) {
+			favorite, errx := s.svc.AddFavorite(ctx, user.ID, refID)
+			if errx != nil {
+				return nil, convert.ToConnectRpcError(errx)
+			}
+
+			response := icbt.FavoriteAddResponse_builder{
+				Favorite: icbt.Favorite_builder{
+					EventRefId: req.Msg.GetEventRefId(),
+					Created:    timestamppb.New(favorite.Created),
+				}.Build(),
+			}.Build()
+			return connect.NewResponse(response), nil
+		})
 }
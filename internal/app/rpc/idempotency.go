@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dropwhile/icanbringthat/internal/app/convert"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients safely retry create requests.
+	// A retry with the same key and request gets the original response,
+	// instead of creating a duplicate.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on replayed responses.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotent runs create, recording its response against the request
+// idempotency key, if any. A request reusing a key gets the recorded
+// response back, and create is not run.
+func idempotent[Req, Res any](
+	ctx context.Context, svc service.Servicer, userID int, procedure string,
+	req *connect.Request[Req],
+	create func() (*connect.Response[Res], error),
+) (*connect.Response[Res], error) {
+	key := req.Header().Get(IdempotencyKeyHeader)
+	if key == "" {
+		return create()
+	}
+
+	reqMsg, ok := any(req.Msg).(proto.Message)
+	if !ok {
+		return nil, InternalError("request is not a proto message")
+	}
+	reqBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(reqMsg)
+	if err != nil {
+		return nil, InternalError("failed to marshal request")
+	}
+	reqHash := sha256.Sum256(reqBytes)
+
+	record, reserved, errx := svc.ReserveIdempotencyKey(
+		ctx, userID, key, procedure, reqHash[:])
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	if !reserved {
+		resMsg := new(Res)
+		resProto, ok := any(resMsg).(proto.Message)
+		if !ok {
+			return nil, InternalError("response is not a proto message")
+		}
+		if err := proto.Unmarshal(record.Response, resProto); err != nil {
+			return nil, InternalError("failed to unmarshal response")
+		}
+		response := connect.NewResponse(resMsg)
+		response.Header().Set(IdempotentReplayedHeader, "true")
+		return response, nil
+	}
+
+	response, err := create()
+	if err != nil {
+		// allow the request to be retried with the same key
+		if errx := svc.ReleaseIdempotencyKey(ctx, userID, key); errx != nil {
+			slog.ErrorContext(ctx, "failed to release idempotency key",
+				"procedure", procedure, "error", errx)
+		}
+		return nil, err
+	}
+
+	var resBytes []byte
+	if resProto, ok := any(response.Msg).(proto.Message); ok {
+		resBytes, err = proto.Marshal(resProto)
+	} else {
+		err = errors.New("response is not a proto message")
+	}
+	if err == nil {
+		if errx := svc.CompleteIdempotencyKey(ctx, userID, key, resBytes); errx != nil {
+			err = errx
+		}
+	}
+	if err != nil {
+		// the request itself succeeded, so respond regardless. retries
+		// see the key as in progress until it expires.
+		slog.ErrorContext(ctx, "failed to record idempotent response",
+			"procedure", procedure, "error", err)
+	}
+	return response, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/app/convert"
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
 
 	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
@@ -28,12 +29,27 @@ func (s *Server) NotificationsList(ctx context.Context,
 		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
 	}
 
+	unreadOnly := req.Msg.GetUnreadOnly()
 	var paginationResult *icbt.PaginationResult
 	var notifications []*model.Notification
 	if req.Msg.HasPagination() {
 		limit := int(req.Msg.GetPagination().GetLimit())
 		offset := int(req.Msg.GetPagination().GetOffset())
-		notifs, pagination, errx := s.svc.GetNotificationsPaginated(ctx, user.ID, limit, offset)
+		cursor, err := parsePageCursor(req.Msg.GetPagination())
+		if err != nil {
+			return nil, err
+		}
+
+		var notifs []*model.Notification
+		var pagination *service.Pagination
+		var errx errs.Error
+		if cursor != nil {
+			notifs, pagination, errx = s.svc.GetNotificationsPaginatedAfter(
+				ctx, user.ID, limit, cursor, unreadOnly)
+		} else {
+			notifs, pagination, errx = s.svc.GetNotificationsPaginated(
+				ctx, user.ID, limit, offset, unreadOnly)
+		}
 		if errx != nil {
 			return nil, convert.ToConnectRpcError(errx)
 		}
@@ -41,7 +57,7 @@ func (s *Server) NotificationsList(ctx context.Context,
 		notifications = notifs
 		paginationResult = convert.ToPbPagination(pagination)
 	} else {
-		notifs, errx := s.svc.GetNotifications(ctx, user.ID)
+		notifs, errx := s.svc.GetNotifications(ctx, user.ID, unreadOnly)
 		if errx != nil {
 			return nil, convert.ToConnectRpcError(errx)
 		}
@@ -94,3 +110,54 @@ func (s *Server) NotificationsDeleteAll(ctx context.Context,
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
 }
+
+func (s *Server) NotificationMarkRead(ctx context.Context,
+	req *connect.Request[icbt.NotificationMarkReadRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	return s.notificationUpdateRead(ctx, req.Msg.GetRefId(), true)
+}
+
+func (s *Server) NotificationMarkUnread(ctx context.Context,
+	req *connect.Request[icbt.NotificationMarkUnreadRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	return s.notificationUpdateRead(ctx, req.Msg.GetRefId(), false)
+}
+
+func (s *Server) notificationUpdateRead(ctx context.Context,
+	rawRefID string, read bool,
+) (*connect.Response[emptypb.Empty], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseNotificationRefID(rawRefID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad notification ref-id"))
+	}
+
+	errx := s.svc.UpdateNotificationRead(ctx, user.ID, refID, read)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+func (s *Server) NotificationsMarkAllRead(ctx context.Context,
+	req *connect.Request[icbt.NotificationsMarkAllReadRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	errx := s.svc.MarkAllNotificationsRead(ctx, user.ID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
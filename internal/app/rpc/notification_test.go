@@ -52,7 +52,7 @@ func TestRpc_ListNotifications(t *testing.T) {
 		offset := 0
 
 		mock.EXPECT().
-			GetNotificationsPaginated(ctx, user.ID, limit, offset).
+			GetNotificationsPaginated(ctx, user.ID, limit, offset, false).
 			Return(
 				[]*model.Notification{notification},
 				&service.Pagination{
@@ -81,7 +81,7 @@ func TestRpc_ListNotifications(t *testing.T) {
 		ctx = auth.ContextSet(ctx, "user", user)
 
 		mock.EXPECT().
-			GetNotifications(ctx, user.ID).
+			GetNotifications(ctx, user.ID, false).
 			Return([]*model.Notification{notification}, nil)
 
 		request := &icbt.NotificationsListRequest{}
@@ -90,6 +90,27 @@ func TestRpc_ListNotifications(t *testing.T) {
 
 		assert.Equal(t, len(response.Msg.GetNotifications()), 1)
 	})
+
+	t.Run("list notifications unread only should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			GetNotifications(ctx, user.ID, true).
+			Return([]*model.Notification{notification}, nil)
+
+		request := icbt.NotificationsListRequest_builder{
+			UnreadOnly: true,
+		}.Build()
+		response, err := server.NotificationsList(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		assert.Equal(t, len(response.Msg.GetNotifications()), 1)
+		assert.Equal(t, response.Msg.GetNotifications()[0].GetRead(), false)
+	})
 }
 
 func TestRpc_DeleteNotification(t *testing.T) {
@@ -217,3 +238,128 @@ func TestRpc_DeleteAllNotifications(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestRpc_NotificationMarkRead(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	notification := &model.Notification{
+		ID:           2,
+		RefID:        util.Must(model.NewNotificationRefID()),
+		UserID:       user.ID,
+		Message:      "",
+		Read:         false,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("mark read with bad refid should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := icbt.NotificationMarkReadRequest_builder{
+			RefId: "hodor",
+		}.Build()
+		_, err := server.NotificationMarkRead(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeInvalidArgument, "bad notification ref-id")
+	})
+
+	t.Run("mark read for different user should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateNotificationRead(ctx, user.ID, notification.RefID, true).
+			Return(errs.PermissionDenied.Error("permission denied"))
+
+		request := icbt.NotificationMarkReadRequest_builder{
+			RefId: notification.RefID.String(),
+		}.Build()
+		_, err := server.NotificationMarkRead(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodePermissionDenied, "permission denied")
+	})
+
+	t.Run("mark read should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateNotificationRead(ctx, user.ID, notification.RefID, true).
+			Return(nil)
+
+		request := icbt.NotificationMarkReadRequest_builder{
+			RefId: notification.RefID.String(),
+		}.Build()
+		_, err := server.NotificationMarkRead(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+
+	t.Run("mark unread should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateNotificationRead(ctx, user.ID, notification.RefID, false).
+			Return(nil)
+
+		request := icbt.NotificationMarkUnreadRequest_builder{
+			RefId: notification.RefID.String(),
+		}.Build()
+		_, err := server.NotificationMarkUnread(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+}
+
+func TestRpc_NotificationsMarkAllRead(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("mark all read should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			MarkAllNotificationsRead(ctx, user.ID).
+			Return(nil)
+
+		request := &icbt.NotificationsMarkAllReadRequest{}
+		_, err := server.NotificationsMarkAllRead(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+}
@@ -4,9 +4,11 @@
 package rpc
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"connectrpc.com/connect"
 	connectValidate "connectrpc.com/validate"
@@ -18,12 +20,15 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/app/service"
 	"github.com/dropwhile/icanbringthat/internal/crypto"
 	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	"github.com/dropwhile/icanbringthat/internal/validate"
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
 	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
 )
 
 type Server struct {
 	redis     *redis.Client
+	broker    pubsub.Broker
 	templates resources.TGetter
 	mailer    mail.MailSender
 	cMAC      crypto.HMACer
@@ -33,8 +38,9 @@ type Server struct {
 }
 
 type Options struct { // betteralign:ignore
-	Db           model.PgxHandle   `validate:"required"`
-	Redis        *redis.Client     `validate:"required"`
+	Db           model.PgxHandle `validate:"required"`
+	Redis        *redis.Client
+	Broker       pubsub.Broker     `validate:"required"`
 	Templates    resources.TGetter `validate:"required"`
 	Mailer       mail.MailSender   `validate:"required"`
 	HMACKeyBytes []byte            `validate:"required"`
@@ -56,12 +62,15 @@ func New(opts Options) (*Server, error) {
 	cMAC := crypto.NewMAC(opts.HMACKeyBytes)
 	svr := &Server{
 		redis:     opts.Redis,
+		broker:    opts.Broker,
 		templates: opts.Templates,
 		mailer:    opts.Mailer,
 		cMAC:      cMAC,
-		svc: &service.Service{
-			Db: opts.Db,
-		},
+		svc: service.New(service.Options{
+			Db:           opts.Db,
+			Broker:       opts.Broker,
+			HMACKeyBytes: opts.HMACKeyBytes,
+		}),
 		baseURL: opts.BaseURL,
 		isProd:  opts.IsProd,
 	}
@@ -81,5 +90,31 @@ func (s *Server) GenHandler() http.Handler {
 			compress.WithNew(compress.Gzip, compress.LevelBalanced),
 		),
 	))
-	return api
+	return liftStreamWriteDeadline(api)
+}
+
+// liftStreamWriteDeadline clears the server write timeout for the
+// server-streaming procedures, which are expected to be long lived.
+func liftStreamWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case rpcv1connect.IcbtRpcServiceWatchEventProcedure,
+			rpcv1connect.IcbtRpcServiceWatchNotificationsProcedure:
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parsePageCursor returns the keyset cursor of a pagination request, or
+// nil if the request uses offset pagination.
+func parsePageCursor(pg *icbt.PaginationRequest) (*service.PageCursor, error) {
+	if pg.GetCursor() == "" {
+		return nil, nil
+	}
+	cursor, err := service.ParsePageCursor(pg.GetCursor())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad pagination cursor"))
+	}
+	return cursor, nil
 }
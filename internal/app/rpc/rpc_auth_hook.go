@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -17,7 +18,8 @@ import (
 )
 
 type GetUserProvider interface {
-	GetUserByApiKey(context.Context, string) (*model.User, errs.Error)
+	AuthenticateApiKey(context.Context, string) (*model.User, *model.ApiKey, errs.Error)
+	TouchApiKey(context.Context, int, string)
 }
 
 /*
@@ -75,6 +77,18 @@ func InternalError(msg string) *connect.Error {
 	return connect.NewError(connect.CodeInternal, errors.New(msg))
 }
 
+func ScopeError(msg string) *connect.Error {
+	return connect.NewError(connect.CodePermissionDenied, errors.New(msg))
+}
+
+// remoteIP returns the client address, as set by the RealIP middleware
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
 func RequireApiKey(up GetUserProvider, opts ...connect.HandlerOption) func(http.Handler) http.Handler {
 	errW := connect.NewErrorWriter(opts...)
 
@@ -88,9 +102,12 @@ func RequireApiKey(up GetUserProvider, opts ...connect.HandlerOption) func(http.
 			_ = errW.Write(w, r, err)
 		} else {
 			// Send an error to non-RPC clients.
-			if err.Code() == connect.CodeInternal {
+			switch err.Code() {
+			case connect.CodeInternal:
 				http.Error(w, err.Message(), http.StatusInternalServerError)
-			} else {
+			case connect.CodePermissionDenied:
+				http.Error(w, err.Message(), http.StatusForbidden)
+			default:
 				http.Error(w, err.Message(), http.StatusUnauthorized)
 			}
 		}
@@ -109,19 +126,19 @@ func RequireApiKey(up GetUserProvider, opts ...connect.HandlerOption) func(http.
 			}
 
 			// lookup user
-			user, errx := up.GetUserByApiKey(ctx, apiKey)
+			user, key, errx := up.AuthenticateApiKey(ctx, apiKey)
 			switch {
+			case errx != nil && errx.Code() == errs.Unauthenticated:
+				writeErr(w, r, AuthError(errx.Msg()))
+				return
 			case errx != nil && errx.Code() != errs.NotFound:
 				writeErr(w, r, InternalError("db error"))
 				return
-			case errx != nil || user == nil:
+			case errx != nil || user == nil || key == nil:
 				writeErr(w, r, AuthError("invalid auth"))
 				return
 			}
 
-			// the above query checks to ensure user.apikey is true as well,
-			// but double check to make sure (in case sql above changes), as
-			// this is a cheap local comparison anyway
 			if !user.ApiAccess {
 				writeErr(w, r, AuthError("invalid auth"))
 				return
@@ -132,6 +149,22 @@ func RequireApiKey(up GetUserProvider, opts ...connect.HandlerOption) func(http.
 				return
 			}
 
+			// path has had the rpc prefix stripped, so it is the
+			// procedure name
+			scope, ok := procedureScopes[r.URL.Path]
+			if !ok {
+				// fail closed for procedures without a scope
+				writeErr(w, r, ScopeError("permission denied"))
+				return
+			}
+			if !key.HasScope(scope) {
+				writeErr(w, r, ScopeError("api key missing scope: "+scope))
+				return
+			}
+
+			// only record usage for authorized requests
+			up.TouchApiKey(ctx, key.ID, remoteIP(r))
+
 			ctx = auth.ContextSet(ctx, "user", user)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -0,0 +1,59 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
+)
+
+// procedureScopes maps each rpc procedure to the api key scope
+// required to call it
+var procedureScopes = map[string]string{
+	// earmarks
+	rpcv1connect.IcbtRpcServiceEarmarkCreateProcedure:     model.ApiKeyScopeEarmarksWrite,
+	rpcv1connect.IcbtRpcServiceEarmarkGetDetailsProcedure: model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceEarmarkRemoveProcedure:     model.ApiKeyScopeEarmarksWrite,
+	rpcv1connect.IcbtRpcServiceEarmarksListProcedure:      model.ApiKeyScopeRead,
+	// events
+	rpcv1connect.IcbtRpcServiceEventCreateProcedure:       model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceEventUpdateProcedure:       model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceEventDeleteProcedure:       model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceEventsListProcedure:        model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceEventGetDetailsProcedure:   model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceEventListItemsProcedure:    model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceEventListEarmarksProcedure: model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceWatchEventProcedure:        model.ApiKeyScopeRead,
+	// event-items
+	rpcv1connect.IcbtRpcServiceEventAddItemProcedure:           model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceEventUpdateItemProcedure:        model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceEventRemoveItemProcedure:        model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceEventUpdateItemSortingProcedure: model.ApiKeyScopeEventsWrite,
+	// favorites
+	rpcv1connect.IcbtRpcServiceFavoriteAddProcedure:        model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceFavoriteRemoveProcedure:     model.ApiKeyScopeEventsWrite,
+	rpcv1connect.IcbtRpcServiceFavoriteListEventsProcedure: model.ApiKeyScopeRead,
+	// notifications
+	rpcv1connect.IcbtRpcServiceNotificationDeleteProcedure:       model.ApiKeyScopeNotifications,
+	rpcv1connect.IcbtRpcServiceNotificationsDeleteAllProcedure:   model.ApiKeyScopeNotifications,
+	rpcv1connect.IcbtRpcServiceNotificationsListProcedure:        model.ApiKeyScopeNotifications,
+	rpcv1connect.IcbtRpcServiceNotificationMarkReadProcedure:     model.ApiKeyScopeNotifications,
+	rpcv1connect.IcbtRpcServiceNotificationMarkUnreadProcedure:   model.ApiKeyScopeNotifications,
+	rpcv1connect.IcbtRpcServiceNotificationsMarkAllReadProcedure: model.ApiKeyScopeNotifications,
+	rpcv1connect.IcbtRpcServiceWatchNotificationsProcedure:       model.ApiKeyScopeNotifications,
+	// user
+	rpcv1connect.IcbtRpcServiceUserGetMeProcedure:            model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceUserUpdateProcedure:           model.ApiKeyScopeAccountWrite,
+	rpcv1connect.IcbtRpcServiceUserSettingsGetProcedure:      model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceUserSettingsUpdateProcedure:   model.ApiKeyScopeAccountWrite,
+	rpcv1connect.IcbtRpcServiceUserCredentialsListProcedure:  model.ApiKeyScopeRead,
+	rpcv1connect.IcbtRpcServiceUserCredentialDeleteProcedure: model.ApiKeyScopeAccountWrite,
+	rpcv1connect.IcbtRpcServiceAccountExportProcedure:        model.ApiKeyScopeAccountWrite,
+	// webhooks
+	rpcv1connect.IcbtRpcServiceWebhookCreateProcedure:       model.ApiKeyScopeWebhooks,
+	rpcv1connect.IcbtRpcServiceWebhookUpdateProcedure:       model.ApiKeyScopeWebhooks,
+	rpcv1connect.IcbtRpcServiceWebhookDeleteProcedure:       model.ApiKeyScopeWebhooks,
+	rpcv1connect.IcbtRpcServiceWebhooksListProcedure:        model.ApiKeyScopeWebhooks,
+	rpcv1connect.IcbtRpcServiceWebhookListAttemptsProcedure: model.ApiKeyScopeWebhooks,
+}
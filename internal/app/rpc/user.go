@@ -0,0 +1,293 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/samber/mo"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dropwhile/icanbringthat/internal/app/convert"
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+func (s *Server) UserGetMe(ctx context.Context,
+	req *connect.Request[icbt.UserGetMeRequest],
+) (*connect.Response[icbt.UserGetMeResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	response := icbt.UserGetMeResponse_builder{
+		User: convert.ToPbUser(user),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) UserUpdate(ctx context.Context,
+	req *connect.Request[icbt.UserUpdateRequest],
+) (*connect.Response[icbt.UserUpdateResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	changes := false
+	euvs := &service.UserUpdateValues{}
+	if req.Msg.HasName() && req.Msg.GetName() != user.Name {
+		changes = true
+		euvs.Name = mo.Some(req.Msg.GetName())
+	}
+	if req.Msg.HasEmail() && req.Msg.GetEmail() != user.Email {
+		// a changed email is not applied until confirmed from the new
+		// address, and can be reverted from the old one.
+		change, errx := s.svc.NewUserEmailChange(ctx, user, req.Msg.GetEmail())
+		if errx != nil {
+			return nil, convert.ToConnectRpcError(errx)
+		}
+		errx = service.SendUserEmailChangeMails(ctx,
+			s.mailer, s.templates, s.cMAC, s.baseURL, change)
+		if errx != nil {
+			return nil, convert.ToConnectRpcError(errx)
+		}
+	}
+	if req.Msg.HasPassword() {
+		if !req.Msg.HasOldPassword() {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				errors.New("old_password is required to change password"))
+		}
+		changes = true
+		euvs.PwUpdate = mo.Some(&service.PasswdUpdate{
+			NewPass: []byte(req.Msg.GetPassword()),
+			OldPass: []byte(req.Msg.GetOldPassword()),
+		})
+	}
+
+	if changes {
+		if errx := s.svc.UpdateUser(ctx, user, euvs); errx != nil {
+			return nil, convert.ToConnectRpcError(errx)
+		}
+		if euvs.PwUpdate.IsPresent() {
+			// a changed password logs out every browser session
+			if errx := s.svc.DeleteUserSessions(ctx, user.ID); errx != nil {
+				return nil, convert.ToConnectRpcError(errx)
+			}
+		}
+		updated, errx := s.svc.GetUserByID(ctx, user.ID)
+		if errx != nil {
+			return nil, convert.ToConnectRpcError(errx)
+		}
+		user = updated
+	}
+
+	response := icbt.UserUpdateResponse_builder{
+		User: convert.ToPbUser(user),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) UserSettingsGet(ctx context.Context,
+	req *connect.Request[icbt.UserSettingsGetRequest],
+) (*connect.Response[icbt.UserSettingsGetResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	response := icbt.UserSettingsGetResponse_builder{
+		Settings: convert.ToPbUserSettings(&user.Settings),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) UserSettingsUpdate(ctx context.Context,
+	req *connect.Request[icbt.UserSettingsUpdateRequest],
+) (*connect.Response[icbt.UserSettingsUpdateResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	changes := false
+	settings := user.Settings
+
+	if req.Msg.HasEnableReminders() && req.Msg.GetEnableReminders() != settings.EnableReminders {
+		if req.Msg.GetEnableReminders() && !user.Verified {
+			return nil, connect.NewError(connect.CodeFailedPrecondition,
+				errors.New("account must be verified before enabling reminder emails"))
+		}
+		changes = true
+		settings.EnableReminders = req.Msg.GetEnableReminders()
+	}
+
+	if req.Msg.HasNudgeGuests() && req.Msg.GetNudgeGuests() != settings.NudgeGuests {
+		changes = true
+		settings.NudgeGuests = req.Msg.GetNudgeGuests()
+	}
+
+	if req.Msg.HasReminderThresholdHours() {
+		val, err := model.ValidateReminderThresholdHours(req.Msg.GetReminderThresholdHours())
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				errors.New("bad reminder_threshold_hours value"))
+		}
+		if val != settings.ReminderThresholdHours {
+			changes = true
+			settings.ReminderThresholdHours = val
+		}
+	}
+
+	if req.Msg.HasQuietHoursEnabled() && req.Msg.GetQuietHoursEnabled() != settings.QuietHoursEnabled {
+		changes = true
+		settings.QuietHoursEnabled = req.Msg.GetQuietHoursEnabled()
+	}
+
+	for _, qh := range []struct {
+		dst   *uint8
+		name  string
+		value uint32
+		set   bool
+	}{
+		{&settings.QuietHoursStart, "quiet_hours_start",
+			req.Msg.GetQuietHoursStart(), req.Msg.HasQuietHoursStart()},
+		{&settings.QuietHoursEnd, "quiet_hours_end",
+			req.Msg.GetQuietHoursEnd(), req.Msg.HasQuietHoursEnd()},
+	} {
+		if !qh.set {
+			continue
+		}
+		val, err := model.ValidateQuietHour(qh.value)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				errors.New("bad "+qh.name+" value"))
+		}
+		if *qh.dst != val {
+			changes = true
+			*qh.dst = val
+		}
+	}
+
+	if req.Msg.HasTimezone() {
+		loc, err := time.LoadLocation(req.Msg.GetTimezone())
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				errors.New("bad timezone value"))
+		}
+		if loc.String() != settings.Timezone {
+			changes = true
+			settings.Timezone = loc.String()
+		}
+	}
+
+	if req.Msg.HasDigestFrequency() {
+		val, err := model.ValidateDigestFrequency(req.Msg.GetDigestFrequency())
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				errors.New("bad digest_frequency value"))
+		}
+		if val != settings.DigestFrequency {
+			if val != model.DigestNone && !user.Verified {
+				return nil, connect.NewError(connect.CodeFailedPrecondition,
+					errors.New("account must be verified before enabling digest emails"))
+			}
+			changes = true
+			settings.DigestFrequency = val
+		}
+	}
+
+	if changes {
+		if errx := s.svc.UpdateUserSettings(ctx, user.ID, &settings); errx != nil {
+			return nil, convert.ToConnectRpcError(errx)
+		}
+	}
+
+	response := icbt.UserSettingsUpdateResponse_builder{
+		Settings: convert.ToPbUserSettings(&settings),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) UserCredentialsList(ctx context.Context,
+	req *connect.Request[icbt.UserCredentialsListRequest],
+) (*connect.Response[icbt.UserCredentialsListResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	credentials, errx := s.svc.GetUserCredentialsByUser(ctx, user.ID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	response := icbt.UserCredentialsListResponse_builder{
+		Credentials: convert.ToPbList(convert.ToPbUserCredential, credentials),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) UserCredentialDelete(ctx context.Context,
+	req *connect.Request[icbt.UserCredentialDeleteRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseCredentialRefID(req.Msg.GetRefId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad credential ref-id"))
+	}
+
+	errx := s.svc.DeleteUserCredential(ctx, user, refID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	// sessions started with the removed key should not outlive it.
+	// unlike the web handler there is no current browser session to
+	// keep here, so every session is removed.
+	errx = s.svc.DeleteUserSessions(ctx, user.ID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+func (s *Server) AccountExport(ctx context.Context,
+	req *connect.Request[icbt.AccountExportRequest],
+) (*connect.Response[icbt.AccountExportResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	export, errx := s.svc.RequestUserDataExport(ctx, user.ID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	response := icbt.AccountExportResponse_builder{
+		RefId:   export.RefID.String(),
+		Created: convert.TimeToTimestamp(export.Created),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
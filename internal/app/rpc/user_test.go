@@ -0,0 +1,581 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"html/template"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/dropwhile/assert"
+	"github.com/samber/mo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/crypto"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/mail/mockmail"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+func TestRpc_UserGetMe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get me should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := &model.User{
+			ID:           1,
+			RefID:        util.Must(model.NewUserRefID()),
+			Email:        "user@example.com",
+			Name:         "user",
+			PWHash:       []byte("00x00"),
+			Verified:     true,
+			ApiAccess:    true,
+			Created:      tstTs,
+			LastModified: tstTs,
+		}
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserGetMeRequest{}
+		response, err := server.UserGetMe(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		assert.Equal(t, response.Msg.GetUser().GetRefId(), user.RefID.String())
+		assert.Equal(t, response.Msg.GetUser().GetEmail(), user.Email)
+		assert.Equal(t, response.Msg.GetUser().GetApiAccess(), true)
+	})
+}
+
+func TestRpc_UserUpdate(t *testing.T) {
+	t.Parallel()
+
+	newUser := func() *model.User {
+		return &model.User{
+			ID:           1,
+			RefID:        util.Must(model.NewUserRefID()),
+			Email:        "user@example.com",
+			Name:         "user",
+			PWHash:       []byte("00x00"),
+			Verified:     true,
+			ApiAccess:    true,
+			Created:      tstTs,
+			LastModified: tstTs,
+		}
+	}
+
+	t.Run("update name should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateUser(ctx, user, &service.UserUpdateValues{
+				Name: mo.Some("new name"),
+			}).
+			Return(nil)
+		mock.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(&model.User{
+				ID:       user.ID,
+				RefID:    user.RefID,
+				Email:    user.Email,
+				Name:     "new name",
+				Verified: true,
+				Created:  tstTs,
+			}, nil)
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetName("new name")
+		// an unchanged email is ignored
+		request.SetEmail(user.Email)
+		response, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		assert.Equal(t, response.Msg.GetUser().GetName(), "new name")
+		assert.Equal(t, response.Msg.GetUser().GetEmail(), user.Email)
+		assert.Equal(t, response.Msg.GetUser().GetVerified(), true)
+	})
+
+	t.Run("update email should start a change", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		mockmailer := mockmail.NewMockMailSender(gomock.NewController(t))
+		server.mailer = mockmailer
+		server.cMAC = crypto.NewMAC([]byte("test-hmac-key"))
+		server.baseURL = "http://example.com"
+		server.templates = &resources.TemplateMap{
+			"mail_email_change_confirm.gohtml": util.Must(
+				template.New("").Parse(`confirm: {{.ConfirmUrl}}`)),
+			"mail_email_change_notice.gohtml": util.Must(
+				template.New("").Parse(`revert: {{.RevertUrl}}`)),
+		}
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		email := "new@example.com"
+		change := &model.UserEmailChange{
+			ID:       1,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: user.Email,
+			NewEmail: email,
+		}
+
+		mock.EXPECT().
+			NewUserEmailChange(ctx, user, email).
+			Return(change, nil)
+		mockmailer.EXPECT().
+			SendAsync("", []string{email},
+				gomock.AssignableToTypeOf("string"),
+				gomock.AssignableToTypeOf("string"),
+				gomock.AssignableToTypeOf("string"),
+				gomock.Any())
+		mockmailer.EXPECT().
+			SendAsync("", []string{user.Email},
+				gomock.AssignableToTypeOf("string"),
+				gomock.AssignableToTypeOf("string"),
+				gomock.AssignableToTypeOf("string"),
+				gomock.Any())
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetEmail(email)
+		response, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		// the address is not updated until confirmed
+		assert.Equal(t, response.Msg.GetUser().GetEmail(), user.Email)
+	})
+
+	t.Run("update email in use should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewUserEmailChange(ctx, user, "new@example.com").
+			Return(nil, errs.AlreadyExists.Error("email already in use"))
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetEmail("new@example.com")
+		_, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeAlreadyExists,
+			"email already in use")
+	})
+
+	t.Run("update password should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateUser(ctx, user, &service.UserUpdateValues{
+				PwUpdate: mo.Some(&service.PasswdUpdate{
+					NewPass: []byte("new-pass"),
+					OldPass: []byte("old-pass"),
+				}),
+			}).
+			Return(nil)
+		mock.EXPECT().
+			DeleteUserSessions(ctx, user.ID).
+			Return(nil)
+		mock.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetPassword("new-pass")
+		request.SetOldPassword("old-pass")
+		_, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+
+	t.Run("update password without old password should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetPassword("new-pass")
+		_, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeInvalidArgument,
+			"old_password is required to change password")
+	})
+
+	t.Run("update password with bad old password should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateUser(ctx, user, &service.UserUpdateValues{
+				PwUpdate: mo.Some(&service.PasswdUpdate{
+					NewPass: []byte("new-pass"),
+					OldPass: []byte("bad-pass"),
+				}),
+			}).
+			Return(errs.ArgumentError("OldPass", "bad value"))
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetPassword("new-pass")
+		request.SetOldPassword("bad-pass")
+		_, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		assert.Equal(t, rpcErr.Code(), connect.CodeInvalidArgument)
+	})
+
+	t.Run("update with no changes should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser()
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserUpdateRequest{}
+		request.SetName(user.Name)
+		response, err := server.UserUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		assert.Equal(t, response.Msg.GetUser().GetName(), user.Name)
+	})
+}
+
+func TestRpc_UserSettingsGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get settings should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := &model.User{
+			ID:       1,
+			RefID:    util.Must(model.NewUserRefID()),
+			Verified: true,
+			Settings: model.UserSettings{
+				ReminderThresholdHours: 24,
+				EnableReminders:        true,
+				Timezone:               "Europe/Amsterdam",
+				DigestFrequency:        model.DigestWeekly,
+			},
+		}
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserSettingsGetRequest{}
+		response, err := server.UserSettingsGet(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		settings := response.Msg.GetSettings()
+		assert.Equal(t, settings.GetReminderThresholdHours(), uint32(24))
+		assert.Equal(t, settings.GetEnableReminders(), true)
+		assert.Equal(t, settings.GetTimezone(), "Europe/Amsterdam")
+		assert.Equal(t, settings.GetDigestFrequency(), model.DigestWeekly)
+	})
+}
+
+func TestRpc_UserSettingsUpdate(t *testing.T) {
+	t.Parallel()
+
+	newUser := func(verified bool) *model.User {
+		return &model.User{
+			ID:       1,
+			RefID:    util.Must(model.NewUserRefID()),
+			Verified: verified,
+			Settings: model.UserSettings{
+				ReminderThresholdHours: 24,
+			},
+		}
+	}
+
+	t.Run("update settings should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser(true)
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			UpdateUserSettings(ctx, user.ID, &model.UserSettings{
+				ReminderThresholdHours: 48,
+				EnableReminders:        true,
+				Timezone:               "Europe/Amsterdam",
+				QuietHoursEnabled:      true,
+				QuietHoursStart:        22,
+				QuietHoursEnd:          7,
+				DigestFrequency:        model.DigestDaily,
+			}).
+			Return(nil)
+
+		request := &icbt.UserSettingsUpdateRequest{}
+		request.SetReminderThresholdHours(48)
+		request.SetEnableReminders(true)
+		request.SetTimezone("Europe/Amsterdam")
+		request.SetQuietHoursEnabled(true)
+		request.SetQuietHoursStart(22)
+		request.SetQuietHoursEnd(7)
+		request.SetDigestFrequency(model.DigestDaily)
+		response, err := server.UserSettingsUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		assert.Equal(t, response.Msg.GetSettings().GetReminderThresholdHours(), uint32(48))
+		assert.Equal(t, response.Msg.GetSettings().GetQuietHoursStart(), uint32(22))
+		// context user is left untouched
+		assert.Equal(t, user.Settings.ReminderThresholdHours, uint8(24))
+	})
+
+	t.Run("enable reminders on unverified account should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser(false)
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserSettingsUpdateRequest{}
+		request.SetEnableReminders(true)
+		_, err := server.UserSettingsUpdate(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeFailedPrecondition,
+			"account must be verified before enabling reminder emails")
+	})
+
+	t.Run("enable digest on unverified account should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser(false)
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserSettingsUpdateRequest{}
+		request.SetDigestFrequency(model.DigestDaily)
+		_, err := server.UserSettingsUpdate(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeFailedPrecondition,
+			"account must be verified before enabling digest emails")
+	})
+
+	t.Run("update with bad timezone should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser(true)
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserSettingsUpdateRequest{}
+		request.SetTimezone("Hodor/Hodor")
+		_, err := server.UserSettingsUpdate(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeInvalidArgument, "bad timezone value")
+	})
+
+	t.Run("update with bad reminder threshold should fail", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser(true)
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserSettingsUpdateRequest{}
+		request.SetReminderThresholdHours(200)
+		_, err := server.UserSettingsUpdate(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeInvalidArgument,
+			"bad reminder_threshold_hours value")
+	})
+
+	t.Run("update with no changes should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		user := newUser(true)
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := &icbt.UserSettingsUpdateRequest{}
+		request.SetReminderThresholdHours(24)
+		response, err := server.UserSettingsUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		assert.Equal(t, response.Msg.GetSettings().GetReminderThresholdHours(), uint32(24))
+	})
+}
+
+func TestRpc_UserCredentialsList(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:       1,
+		RefID:    util.Must(model.NewUserRefID()),
+		Verified: true,
+	}
+
+	t.Run("list credentials should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		credential := &model.UserCredential{
+			ID:      2,
+			RefID:   util.Must(model.NewCredentialRefID()),
+			UserID:  user.ID,
+			KeyName: "my key",
+			Created: tstTs,
+		}
+
+		mock.EXPECT().
+			GetUserCredentialsByUser(ctx, user.ID).
+			Return([]*model.UserCredential{credential}, nil)
+
+		request := &icbt.UserCredentialsListRequest{}
+		response, err := server.UserCredentialsList(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		credentials := response.Msg.GetCredentials()
+		assert.Equal(t, len(credentials), 1)
+		assert.Equal(t, credentials[0].GetRefId(), credential.RefID.String())
+		assert.Equal(t, credentials[0].GetKeyName(), credential.KeyName)
+	})
+}
+
+func TestRpc_UserCredentialDelete(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:       1,
+		RefID:    util.Must(model.NewUserRefID()),
+		Verified: true,
+	}
+
+	t.Run("delete credential should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewCredentialRefID())
+
+		mock.EXPECT().
+			DeleteUserCredential(ctx, user, refID).
+			Return(nil)
+		mock.EXPECT().
+			DeleteUserSessions(ctx, user.ID).
+			Return(nil)
+
+		request := icbt.UserCredentialDeleteRequest_builder{
+			RefId: refID.String(),
+		}.Build()
+		_, err := server.UserCredentialDelete(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete last credential should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewCredentialRefID())
+
+		mock.EXPECT().
+			DeleteUserCredential(ctx, user, refID).
+			Return(errs.FailedPrecondition.Error(
+				"refusing to remove last passkey when password auth disabled"))
+
+		request := icbt.UserCredentialDeleteRequest_builder{
+			RefId: refID.String(),
+		}.Build()
+		_, err := server.UserCredentialDelete(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeFailedPrecondition,
+			"refusing to remove last passkey when password auth disabled")
+	})
+
+	t.Run("delete with bad refid should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := icbt.UserCredentialDeleteRequest_builder{
+			RefId: "hodor",
+		}.Build()
+		_, err := server.UserCredentialDelete(ctx, connect.NewRequest(request))
+		errs.AssertError(t, err, connect.CodeInvalidArgument, "bad credential ref-id")
+	})
+}
+
+func TestRpc_AccountExport(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:       1,
+		RefID:    util.Must(model.NewUserRefID()),
+		Verified: true,
+	}
+
+	t.Run("export should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		export := &model.UserDataExport{
+			ID:      2,
+			RefID:   util.Must(model.NewUserDataExportRefID()),
+			UserID:  user.ID,
+			Created: tstTs,
+		}
+
+		mock.EXPECT().
+			RequestUserDataExport(ctx, user.ID).
+			Return(export, nil)
+
+		request := &icbt.AccountExportRequest{}
+		response, err := server.AccountExport(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		assert.Equal(t, response.Msg.GetRefId(), export.RefID.String())
+		assert.Equal(t, response.Msg.GetCreated().AsTime(), tstTs)
+	})
+
+	t.Run("export without auth should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+
+		request := &icbt.AccountExportRequest{}
+		_, err := server.AccountExport(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeUnauthenticated, "invalid credentials")
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"connectrpc.com/connect"
+
+	"github.com/dropwhile/icanbringthat/internal/app/convert"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+func (s *Server) WatchEvent(ctx context.Context,
+	req *connect.Request[icbt.WatchEventRequest],
+	stream *connect.ServerStream[icbt.WatchEventResponse],
+) error {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseEventRefID(req.Msg.GetRefId())
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("bad event ref-id"))
+	}
+
+	event, errx := s.svc.GetEvent(ctx, refID)
+	if errx != nil {
+		return convert.ToConnectRpcError(errx)
+	}
+
+	return s.watchTopic(ctx, pubsub.EventTopic(event.ID), req.Msg.GetCursor(),
+		func(msg *pubsub.Message) error {
+			if msg == nil {
+				return stream.Send(icbt.WatchEventResponse_builder{
+					Kind: icbt.EventChangeKind_EVENT_CHANGE_KIND_RESYNC,
+				}.Build())
+			}
+			return stream.Send(icbt.WatchEventResponse_builder{
+				Kind:   convert.ToPbEventChangeKind(msg.Change),
+				RefId:  msg.RefID,
+				Cursor: msg.Cursor,
+			}.Build())
+		},
+	)
+}
+
+func (s *Server) WatchNotifications(ctx context.Context,
+	req *connect.Request[icbt.WatchNotificationsRequest],
+	stream *connect.ServerStream[icbt.WatchNotificationsResponse],
+) error {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	return s.watchTopic(ctx, pubsub.UserTopic(user.ID), req.Msg.GetCursor(),
+		func(msg *pubsub.Message) error {
+			if msg == nil {
+				return stream.Send(icbt.WatchNotificationsResponse_builder{
+					Kind: icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_RESYNC,
+				}.Build())
+			}
+			return stream.Send(icbt.WatchNotificationsResponse_builder{
+				Kind:   convert.ToPbNotificationChangeKind(msg.Change),
+				RefId:  msg.RefID,
+				Cursor: msg.Cursor,
+			}.Build())
+		},
+	)
+}
+
+// watchTopic sends messages published to topic until the client goes
+// away. If cursor is set, retained messages published after it are sent
+// first. If the cursor can no longer be resumed from, send is called
+// with a nil message to signal that the client should resync.
+func (s *Server) watchTopic(ctx context.Context,
+	topic string, cursor string, send func(*pubsub.Message) error,
+) error {
+	// subscribe before replaying, so nothing published in between is
+	// missed. messages seen in both are only sent once.
+	sub, err := s.broker.Subscribe(ctx, topic)
+	if err != nil {
+		slog.ErrorContext(ctx, "error subscribing", "error", err)
+		return connect.NewError(connect.CodeUnavailable, errors.New("subscription error"))
+	}
+	defer sub.Close()
+
+	replayed := make(map[string]struct{})
+	if cursor != "" {
+		messages, err := s.broker.Replay(ctx, topic, cursor)
+		switch {
+		case errors.Is(err, pubsub.ErrCursorExpired):
+			if err := send(nil); err != nil {
+				return err
+			}
+		case err != nil:
+			slog.ErrorContext(ctx, "error replaying", "error", err)
+			return connect.NewError(connect.CodeInternal, errors.New("replay error"))
+		}
+		for _, msg := range messages {
+			replayed[msg.Cursor] = struct{}{}
+			if err := send(msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return connect.NewError(connect.CodeUnavailable, errors.New("subscription closed"))
+			}
+			if _, ok := replayed[msg.Cursor]; ok {
+				delete(replayed, msg.Cursor)
+				continue
+			}
+			if err := send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/dropwhile/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/util"
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+	"github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1/rpcv1connect"
+)
+
+// newWatchClient serves server over http, as streams can not be
+// exercised by calling the methods directly.
+func newWatchClient(
+	t *testing.T, server *Server, user *model.User,
+) rpcv1connect.IcbtRpcServiceClient {
+	t.Helper()
+	_, h := rpcv1connect.NewIcbtRpcServiceHandler(server)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ctx := auth.ContextSet(r.Context(), "user", user)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		}))
+	t.Cleanup(srv.Close)
+	return rpcv1connect.NewIcbtRpcServiceClient(srv.Client(), srv.URL)
+}
+
+func TestRpc_WatchEvent(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	event := &model.Event{
+		ID:           2,
+		RefID:        util.Must(model.NewEventRefID()),
+		UserID:       user.ID,
+		Name:         "event",
+		Description:  "description",
+		StartTime:    tstTs,
+		StartTimeTz:  util.Must(service.ParseTimeZone("Etc/UTC")),
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	itemRefID := util.Must(model.NewEventItemRefID()).String()
+
+	t.Run("watch event resumes from cursor then streams", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		server, mock := NewTestServer(t)
+		broker := pubsub.NewMemoryBroker()
+		server.broker = broker
+		client := newWatchClient(t, server, user)
+		topic := pubsub.EventTopic(event.ID)
+
+		mock.EXPECT().
+			GetEvent(gomock.Any(), event.RefID).
+			Return(event, nil)
+
+		// record some history to resume from
+		sub, err := broker.Subscribe(ctx, topic)
+		assert.Nil(t, err)
+		assert.Nil(t, broker.Publish(ctx, topic, &pubsub.Message{
+			Type: pubsub.TypeEventUpdated, Change: pubsub.ChangeItemAdded, RefID: itemRefID,
+		}))
+		assert.Nil(t, broker.Publish(ctx, topic, &pubsub.Message{
+			Type: pubsub.TypeEventUpdated, Change: pubsub.ChangeItemUpdated, RefID: itemRefID,
+		}))
+		first := <-sub.Messages()
+		assert.Nil(t, sub.Close())
+
+		request := icbt.WatchEventRequest_builder{
+			RefId:  event.RefID.String(),
+			Cursor: first.Cursor,
+		}.Build()
+		stream, err := client.WatchEvent(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		defer stream.Close()
+
+		assert.True(t, stream.Receive(), "expected replayed message")
+		msg := stream.Msg()
+		assert.Equal(t, msg.GetKind(), icbt.EventChangeKind_EVENT_CHANGE_KIND_ITEM_UPDATED)
+		assert.Equal(t, msg.GetRefId(), itemRefID)
+
+		// the server subscribes before replaying, so this is seen live
+		assert.Nil(t, broker.Publish(ctx, topic, &pubsub.Message{
+			Type: pubsub.TypeEventUpdated, Change: pubsub.ChangeItemRemoved, RefID: itemRefID,
+		}))
+		assert.True(t, stream.Receive(), "expected live message")
+		msg = stream.Msg()
+		assert.Equal(t, msg.GetKind(), icbt.EventChangeKind_EVENT_CHANGE_KIND_ITEM_REMOVED)
+		assert.Equal(t, msg.GetCursor(), "3")
+	})
+
+	t.Run("watch event with expired cursor should resync", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		server, mock := NewTestServer(t)
+		server.broker = pubsub.NewMemoryBroker()
+		client := newWatchClient(t, server, user)
+
+		mock.EXPECT().
+			GetEvent(gomock.Any(), event.RefID).
+			Return(event, nil)
+
+		request := icbt.WatchEventRequest_builder{
+			RefId:  event.RefID.String(),
+			Cursor: "42",
+		}.Build()
+		stream, err := client.WatchEvent(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		defer stream.Close()
+
+		assert.True(t, stream.Receive(), "expected resync message")
+		assert.Equal(t, stream.Msg().GetKind(), icbt.EventChangeKind_EVENT_CHANGE_KIND_RESYNC)
+	})
+
+	t.Run("watch event with missing event should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		server.broker = pubsub.NewMemoryBroker()
+		client := newWatchClient(t, server, user)
+
+		mock.EXPECT().
+			GetEvent(gomock.Any(), event.RefID).
+			Return(nil, errs.NotFound.Error("event not found"))
+
+		request := icbt.WatchEventRequest_builder{
+			RefId: event.RefID.String(),
+		}.Build()
+		stream, err := client.WatchEvent(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		defer stream.Close()
+
+		assert.Equal(t, stream.Receive(), false)
+		errc := AsConnectError(t, stream.Err())
+		assert.Equal(t, errc.Code(), connect.CodeNotFound)
+	})
+}
+
+func TestRpc_WatchNotifications(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("watch notifications should stream changes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		server, _ := NewTestServer(t)
+		broker := pubsub.NewMemoryBroker()
+		server.broker = broker
+		client := newWatchClient(t, server, user)
+
+		// an unknown cursor forces a resync message, which also tells us
+		// the server side subscription is in place.
+		request := icbt.WatchNotificationsRequest_builder{
+			Cursor: "42",
+		}.Build()
+		stream, err := client.WatchNotifications(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		defer stream.Close()
+
+		assert.True(t, stream.Receive(), "expected resync message")
+		assert.Equal(t, stream.Msg().GetKind(),
+			icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_RESYNC)
+
+		assert.Nil(t, broker.Publish(ctx, pubsub.UserTopic(user.ID), &pubsub.Message{
+			Type: pubsub.TypeNotifCount, Change: pubsub.ChangeNotificationsRead,
+		}))
+		assert.True(t, stream.Receive(), "expected live message")
+		msg := stream.Msg()
+		assert.Equal(t, msg.GetKind(),
+			icbt.NotificationChangeKind_NOTIFICATION_CHANGE_KIND_ALL_READ)
+		assert.Equal(t, msg.GetCursor(), "1")
+	})
+}
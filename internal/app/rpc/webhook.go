@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/samber/mo"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dropwhile/icanbringthat/internal/app/convert"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+// default number of delivery attempts returned by WebhookListAttempts
+const defaultWebhookAttemptsLimit = 20
+
+func (s *Server) WebhookCreate(ctx context.Context,
+	req *connect.Request[icbt.WebhookCreateRequest],
+) (*connect.Response[icbt.WebhookCreateResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	endpoint, errx := s.svc.NewWebhookEndpoint(ctx, user.ID,
+		req.Msg.GetUrl(), req.Msg.GetSecret(), req.Msg.GetEventTypes())
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	response := icbt.WebhookCreateResponse_builder{
+		Webhook: convert.ToPbWebhook(endpoint),
+		Secret:  endpoint.Secret,
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) WebhookUpdate(ctx context.Context,
+	req *connect.Request[icbt.WebhookUpdateRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseWebhookEndpointRefID(req.Msg.GetRefId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad webhook ref-id"))
+	}
+
+	wuvs := &service.WebhookEndpointUpdateValues{}
+	if req.Msg.HasUrl() {
+		wuvs.URL = mo.Some(req.Msg.GetUrl())
+	}
+	if req.Msg.HasSecret() {
+		wuvs.Secret = mo.Some(req.Msg.GetSecret())
+	}
+	if req.Msg.HasEventTypes() {
+		wuvs.EventTypes = mo.Some(req.Msg.GetEventTypes().GetEventTypes())
+	}
+	if req.Msg.HasEnabled() {
+		wuvs.Enabled = mo.Some(req.Msg.GetEnabled())
+	}
+
+	errx := s.svc.UpdateWebhookEndpoint(ctx, user.ID, refID, wuvs)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+func (s *Server) WebhookDelete(ctx context.Context,
+	req *connect.Request[icbt.WebhookDeleteRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseWebhookEndpointRefID(req.Msg.GetRefId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad webhook ref-id"))
+	}
+
+	errx := s.svc.DeleteWebhookEndpoint(ctx, user.ID, refID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+func (s *Server) WebhooksList(ctx context.Context,
+	req *connect.Request[icbt.WebhooksListRequest],
+) (*connect.Response[icbt.WebhooksListResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	endpoints, errx := s.svc.GetWebhookEndpoints(ctx, user.ID)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	response := icbt.WebhooksListResponse_builder{
+		Webhooks: convert.ToPbList(convert.ToPbWebhook, endpoints),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
+
+func (s *Server) WebhookListAttempts(ctx context.Context,
+	req *connect.Request[icbt.WebhookListAttemptsRequest],
+) (*connect.Response[icbt.WebhookListAttemptsResponse], error) {
+	// get user from auth in context
+	user, err := auth.UserFromContext(ctx)
+	if err != nil || user == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid credentials"))
+	}
+
+	refID, err := service.ParseWebhookEndpointRefID(req.Msg.GetRefId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad webhook ref-id"))
+	}
+
+	limit := int(req.Msg.GetLimit())
+	if limit == 0 {
+		limit = defaultWebhookAttemptsLimit
+	}
+
+	attempts, errx := s.svc.GetWebhookAttempts(ctx, user.ID, refID, limit)
+	if errx != nil {
+		return nil, convert.ToConnectRpcError(errx)
+	}
+
+	response := icbt.WebhookListAttemptsResponse_builder{
+		Attempts: convert.ToPbList(convert.ToPbWebhookAttempt, attempts),
+	}.Build()
+	return connect.NewResponse(response), nil
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/dropwhile/assert"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/service"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+	"github.com/dropwhile/icanbringthat/internal/util"
+	icbt "github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1"
+)
+
+func TestRpc_WebhookCreate(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	endpoint := &model.WebhookEndpoint{
+		ID:         2,
+		RefID:      util.Must(model.NewWebhookEndpointRefID()),
+		UserID:     user.ID,
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{"item-added"},
+		Enabled:    true,
+		Created:    tstTs,
+	}
+
+	t.Run("create webhook should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewWebhookEndpoint(ctx, user.ID, endpoint.URL, "", endpoint.EventTypes).
+			Return(endpoint, nil)
+
+		request := icbt.WebhookCreateRequest_builder{
+			Url:        endpoint.URL,
+			EventTypes: endpoint.EventTypes,
+		}.Build()
+		response, err := server.WebhookCreate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+
+		assert.Equal(t, response.Msg.GetSecret(), endpoint.Secret)
+		assert.Equal(t, response.Msg.GetWebhook().GetRefId(), endpoint.RefID.String())
+		assert.Equal(t, response.Msg.GetWebhook().GetEventTypes(), endpoint.EventTypes)
+	})
+
+	t.Run("create webhook over limit should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			NewWebhookEndpoint(ctx, user.ID, endpoint.URL, "", nil).
+			Return(nil, errs.ResourceExhausted.Error("too many webhooks"))
+
+		request := icbt.WebhookCreateRequest_builder{
+			Url: endpoint.URL,
+		}.Build()
+		_, err := server.WebhookCreate(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeResourceExhausted, "too many webhooks")
+	})
+}
+
+func TestRpc_WebhookUpdate(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("update webhook should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewWebhookEndpointRefID())
+
+		mock.EXPECT().
+			UpdateWebhookEndpoint(ctx, user.ID, refID,
+				&service.WebhookEndpointUpdateValues{
+					EventTypes: mo.Some([]string{}),
+					Enabled:    mo.Some(false),
+				}).
+			Return(nil)
+
+		request := icbt.WebhookUpdateRequest_builder{
+			RefId:      refID.String(),
+			EventTypes: icbt.WebhookEventTypeList_builder{EventTypes: []string{}}.Build(),
+			Enabled:    func(b bool) *bool { return &b }(false),
+		}.Build()
+		_, err := server.WebhookUpdate(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+
+	t.Run("update webhook with bad refid should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, _ := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		request := icbt.WebhookUpdateRequest_builder{
+			RefId:   "hodor",
+			Enabled: func(b bool) *bool { return &b }(false),
+		}.Build()
+		_, err := server.WebhookUpdate(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeInvalidArgument, "bad webhook ref-id")
+	})
+}
+
+func TestRpc_WebhookDelete(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("delete webhook should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewWebhookEndpointRefID())
+
+		mock.EXPECT().
+			DeleteWebhookEndpoint(ctx, user.ID, refID).
+			Return(nil)
+
+		request := icbt.WebhookDeleteRequest_builder{
+			RefId: refID.String(),
+		}.Build()
+		_, err := server.WebhookDelete(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete other user webhook should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewWebhookEndpointRefID())
+
+		mock.EXPECT().
+			DeleteWebhookEndpoint(ctx, user.ID, refID).
+			Return(errs.PermissionDenied.Error("permission denied"))
+
+		request := icbt.WebhookDeleteRequest_builder{
+			RefId: refID.String(),
+		}.Build()
+		_, err := server.WebhookDelete(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodePermissionDenied, "permission denied")
+	})
+}
+
+func TestRpc_WebhooksList(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("list webhooks should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+
+		mock.EXPECT().
+			GetWebhookEndpoints(ctx, user.ID).
+			Return([]*model.WebhookEndpoint{{
+				ID:         2,
+				RefID:      util.Must(model.NewWebhookEndpointRefID()),
+				UserID:     user.ID,
+				URL:        "https://example.com/hook",
+				Secret:     "0123456789abcdef",
+				EventTypes: []string{},
+				Enabled:    true,
+				Created:    tstTs,
+			}}, nil)
+
+		request := &icbt.WebhooksListRequest{}
+		response, err := server.WebhooksList(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		assert.Equal(t, len(response.Msg.GetWebhooks()), 1)
+	})
+}
+
+func TestRpc_WebhookListAttempts(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("list attempts should use default limit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewWebhookEndpointRefID())
+
+		mock.EXPECT().
+			GetWebhookAttempts(ctx, user.ID, refID, defaultWebhookAttemptsLimit).
+			Return([]*model.WebhookAttempt{{
+				ID:         1,
+				EndpointID: 2,
+				DeliveryID: 3,
+				EventType:  "item-added",
+				StatusCode: 500,
+				Error:      "500 Internal Server Error",
+				Created:    tstTs,
+			}}, nil)
+
+		request := icbt.WebhookListAttemptsRequest_builder{
+			RefId: refID.String(),
+		}.Build()
+		response, err := server.WebhookListAttempts(ctx, connect.NewRequest(request))
+		assert.Nil(t, err)
+		assert.Equal(t, len(response.Msg.GetAttempts()), 1)
+		assert.Equal(t, response.Msg.GetAttempts()[0].GetStatusCode(), uint32(500))
+	})
+
+	t.Run("list attempts for missing webhook should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server, mock := NewTestServer(t)
+		ctx = auth.ContextSet(ctx, "user", user)
+		refID := util.Must(model.NewWebhookEndpointRefID())
+
+		mock.EXPECT().
+			GetWebhookAttempts(ctx, user.ID, refID, 5).
+			Return(nil, errs.NotFound.Error("webhook not found"))
+
+		request := icbt.WebhookListAttemptsRequest_builder{
+			RefId: refID.String(),
+			Limit: 5,
+		}.Build()
+		_, err := server.WebhookListAttempts(ctx, connect.NewRequest(request))
+		rpcErr := AsConnectError(t, err)
+		errs.AssertError(t, rpcErr, connect.CodeNotFound, "webhook not found")
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 )
 
 var (
@@ -88,10 +89,55 @@ func (s *Service) GetEarmarksPaginated(
 		Limit:  limit,
 		Offset: offset,
 		Count:  count,
+		NextCursor: nextPageCursor(
+			earmarks, offset+len(earmarks) < count, earmarkPageCursor),
 	}
 	return earmarks, pagination, nil
 }
 
+func (s *Service) GetEarmarksPaginatedAfter(
+	ctx context.Context, userID int,
+	limit int, cursor *PageCursor, archived bool,
+) ([]*model.Earmark, *Pagination, errs.Error) {
+	bifurCount, errx := s.GetEarmarksCount(ctx, userID)
+	if errx != nil {
+		slog.Error("db error", "error", errx)
+		return nil, nil, errs.Internal.Error("db error")
+	}
+	count := bifurCount.Current
+	if archived {
+		count = bifurCount.Archived
+	}
+
+	earmarks := []*model.Earmark{}
+	if count > 0 {
+		elems, err := model.GetEarmarksByUserKeysetFiltered(
+			ctx, s.Db, userID, limit+1, cursor.Time, cursor.ID, archived)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			elems = []*model.Earmark{}
+		case err != nil:
+			slog.Error("db error", "error", err)
+			return nil, nil, errs.Internal.Error("db error")
+		}
+		earmarks = elems
+	}
+	more := len(earmarks) > limit
+	if more {
+		earmarks = earmarks[:limit]
+	}
+	pagination := &Pagination{
+		Limit:      limit,
+		Count:      count,
+		NextCursor: nextPageCursor(earmarks, more, earmarkPageCursor),
+	}
+	return earmarks, pagination, nil
+}
+
+func earmarkPageCursor(earmark *model.Earmark) *PageCursor {
+	return &PageCursor{Time: earmark.Created, ID: earmark.ID}
+}
+
 func (s *Service) GetEarmarks(
 	ctx context.Context, userID int, archived bool,
 ) ([]*model.Earmark, errs.Error) {
@@ -157,9 +203,45 @@ func (s *Service) NewEarmark(
 		}
 		return nil, errs.Internal.Errorf("error creating earmark: %w", err)
 	}
+
+	s.eventChanged(ctx, event, pubsub.ChangeEarmarkCreated, earmark.RefID)
+
+	// let the event owner know someone is bringing something
+	if event.UserID != user.ID {
+		s.notifyEarmarkClaimed(ctx, user, event, earmark)
+	}
 	return earmark, nil
 }
 
+// notifyEarmarkClaimed is best effort. Failing to notify the event owner
+// is logged, but does not fail the earmark itself.
+func (s *Service) notifyEarmarkClaimed(ctx context.Context,
+	user *model.User, event *model.Event, earmark *model.Earmark,
+) {
+	eventItem, err := model.GetEventItemByID(ctx, s.Db, earmark.EventItemID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error fetching event item for notification",
+			"error", err)
+		return
+	}
+
+	_, errx := s.NewTypedNotification(ctx, event.UserID,
+		model.NotificationKindEarmarkClaimed,
+		model.NotificationPayload{
+			EventRefID:           event.RefID.String(),
+			EventName:            event.Name,
+			EventItemRefID:       eventItem.RefID.String(),
+			EventItemDescription: eventItem.Description,
+			EarmarkRefID:         earmark.RefID.String(),
+			UserName:             user.Name,
+		},
+	)
+	if errx != nil {
+		slog.ErrorContext(ctx, "error adding earmark notification",
+			"error", errx)
+	}
+}
+
 func (s *Service) GetEarmark(
 	ctx context.Context, refID model.EarmarkRefID,
 ) (*model.Earmark, errs.Error) {
@@ -196,6 +278,7 @@ func (s *Service) DeleteEarmark(
 	if err != nil {
 		return errs.Internal.Error("db error")
 	}
+	s.eventChanged(ctx, event, pubsub.ChangeEarmarkDeleted, earmark.RefID)
 	return nil
 }
 
@@ -452,6 +452,96 @@ func TestService_NewEarmark(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("create earmark by guest should notify owner", func(t *testing.T) {
+		t.Parallel()
+
+		guest := &model.User{
+			ID:       44,
+			RefID:    util.Must(model.NewUserRefID()),
+			Email:    "guest@example.com",
+			Name:     "guest",
+			Verified: true,
+		}
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM earmark_").
+			WithArgs(eventItem.ID).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM event_ (.+)").
+			WithArgs(eventItem.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"start_time", "start_time_tz", "created", "last_modified",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name, event.Description,
+					event.StartTime, event.StartTimeTz, ts, ts,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO earmark_").
+			WithArgs(pgx.NamedArgs{
+				"refID":       EarmarkRefIDMatcher,
+				"eventItemID": eventItem.ID,
+				"userID":      guest.ID,
+				"note":        "some note",
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_item_id", "user_id", "note", "created", "last_modified",
+				}).
+				AddRow(
+					earmark.ID, earmark.RefID, eventItem.ID, guest.ID,
+					"some note", ts, ts,
+				),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectQuery("^SELECT (.+) FROM event_item_").
+			WithArgs(eventItem.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description", "created", "last_modified",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID, eventItem.EventID,
+					eventItem.Description, ts, ts,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO notification_").
+			WithArgs(pgx.NamedArgs{
+				"refID":   NotificationRefIDMatcher,
+				"userID":  event.UserID,
+				"kind":    model.NotificationKindEarmarkClaimed,
+				"message": "guest earmarked 'eventitem' in event 'event'.",
+				"payload": model.NotificationPayload{
+					EventRefID:           event.RefID.String(),
+					EventName:            event.Name,
+					EventItemRefID:       eventItem.RefID.String(),
+					EventItemDescription: eventItem.Description,
+					EarmarkRefID:         earmark.RefID.String(),
+					UserName:             guest.Name,
+				},
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, util.Must(model.NewNotificationRefID()), event.UserID),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		_, err := svc.NewEarmark(ctx, guest, eventItem.ID, "some note")
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("create earmark missing event", func(t *testing.T) {
 		t.Parallel()
 
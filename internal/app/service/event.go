@@ -17,6 +17,7 @@ import (
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	"github.com/dropwhile/icanbringthat/internal/validate"
 )
 
@@ -96,6 +97,8 @@ type EventUpdateValues struct {
 	Description   mo.Option[string]    `validate:"omitnil,notblank"`
 	Tz            mo.Option[string]    `validate:"omitnil,timezone"`
 	ItemSortOrder mo.Option[[]int]     `validate:"omitnil,gt=0"`
+	// Version, if set, must match the current event version
+	Version mo.Option[string]
 }
 
 func (s *Service) UpdateEvent(
@@ -160,21 +163,92 @@ func (s *Service) UpdateEvent(
 		return errs.PermissionDenied.Error("event is archived")
 	}
 
+	// the update itself is conditional on last_modified too, so a write
+	// landing between the read above and the update is still caught
+	var lastModified mo.Option[time.Time]
+	if val, ok := euvs.Version.Get(); ok {
+		if val != event.Version() {
+			return errs.Aborted.Error("event was modified").
+				WithMeta("version", event.Version())
+		}
+		lastModified = mo.Some(event.LastModified)
+	}
+
+	startChanged := false
+	if val, ok := euvs.StartTime.Get(); ok && !val.Equal(event.StartTime) {
+		startChanged = true
+	}
+	if loc != nil && (event.StartTimeTz == nil || !loc.Equal(*event.StartTimeTz)) {
+		startChanged = true
+	}
+
 	// do update
-	err = model.UpdateEvent(ctx, s.Db, event.ID, &model.EventUpdateModelValues{
+	rows, err := model.UpdateEvent(ctx, s.Db, event.ID, &model.EventUpdateModelValues{
 		Name:          euvs.Name,
 		Description:   euvs.Description,
 		ItemSortOrder: euvs.ItemSortOrder,
 		StartTime:     euvs.StartTime,
 		Tz:            maybeLoc,
+		LastModified:  lastModified,
 	})
 	if err != nil {
 		slog.With("error", err).Error("db error")
 		return errs.Internal.Error("db error")
 	}
+	if rows == 0 {
+		current, err := model.GetEventByID(ctx, s.Db, event.ID)
+		if err != nil {
+			return errs.Internal.Error("db error")
+		}
+		return errs.Aborted.Error("event was modified").
+			WithMeta("version", current.Version())
+	}
+
+	s.eventChanged(ctx, event, pubsub.ChangeEventUpdated, event.RefID)
+
+	// guests bringing items need to know if the event moved
+	if startChanged {
+		if name, ok := euvs.Name.Get(); ok {
+			event.Name = name
+		}
+		s.notifyEventChanged(ctx, event)
+	}
 	return nil
 }
 
+// notifyEventChanged is best effort. Failing to notify guests is logged,
+// but does not fail the event update itself.
+func (s *Service) notifyEventChanged(ctx context.Context, event *model.Event) {
+	earmarks, err := model.GetEarmarksByEvent(ctx, s.Db, event.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		slog.ErrorContext(ctx, "error fetching earmarks for notification",
+			"error", err)
+		return
+	}
+
+	notified := make(map[int]struct{}, len(earmarks))
+	for _, em := range earmarks {
+		if em.UserID == event.UserID {
+			continue
+		}
+		if _, ok := notified[em.UserID]; ok {
+			continue
+		}
+		notified[em.UserID] = struct{}{}
+		_, errx := s.NewTypedNotification(ctx, em.UserID,
+			model.NotificationKindEventChanged,
+			model.NotificationPayload{
+				EventRefID: event.RefID.String(),
+				EventName:  event.Name,
+			},
+		)
+		if errx != nil {
+			slog.ErrorContext(ctx, "error adding event notification",
+				"error", errx)
+		}
+	}
+}
+
 func (s *Service) UpdateEventItemSorting(
 	ctx context.Context, userID int,
 	refID model.EventRefID, itemSortOrder []int,
@@ -201,13 +275,14 @@ func (s *Service) UpdateEventItemSorting(
 
 	event.ItemSortOrder = itemSortOrder
 
-	if err := model.UpdateEvent(
+	if _, err := model.UpdateEvent(
 		ctx, s.Db, event.ID, &model.EventUpdateModelValues{
 			ItemSortOrder: mo.Some(event.ItemSortOrder),
 		},
 	); err != nil {
 		return nil, errs.Internal.Error("db error")
 	}
+	s.eventChanged(ctx, event, pubsub.ChangeItemsSorted, event.RefID)
 	return event, nil
 }
 
@@ -299,10 +374,56 @@ func (s *Service) GetEventsPaginated(
 		Limit:  limit,
 		Offset: offset,
 		Count:  count,
+		NextCursor: nextPageCursor(
+			events, offset+len(events) < count, eventPageCursor),
+	}
+	return events, pagination, nil
+}
+
+// GetEventsPaginatedAfter returns up to limit events following cursor,
+// using the same ordering as GetEventsPaginated.
+func (s *Service) GetEventsPaginatedAfter(
+	ctx context.Context, userID int,
+	limit int, cursor *PageCursor, archived bool,
+) ([]*model.Event, *Pagination, errs.Error) {
+	eventCount, errx := s.GetEventsCount(ctx, userID)
+	if errx != nil {
+		return nil, nil, errs.Internal.Error("db error")
+	}
+	count := eventCount.Current
+	if archived {
+		count = eventCount.Archived
+	}
+
+	events := []*model.Event{}
+	if count > 0 {
+		// fetch one extra row to tell if there is a further page
+		evts, err := model.GetEventsByUserKeysetFiltered(
+			ctx, s.Db, userID, limit+1, cursor.Time, cursor.ID, archived)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			evts = []*model.Event{}
+		case err != nil:
+			return nil, nil, errs.Internal.Error("db error")
+		}
+		events = evts
+	}
+	more := len(events) > limit
+	if more {
+		events = events[:limit]
+	}
+	pagination := &Pagination{
+		Limit:      limit,
+		Count:      count,
+		NextCursor: nextPageCursor(events, more, eventPageCursor),
 	}
 	return events, pagination, nil
 }
 
+func eventPageCursor(event *model.Event) *PageCursor {
+	return &PageCursor{Time: event.StartTime, ID: event.ID}
+}
+
 func (s *Service) GetEventsComingSoonPaginated(
 	ctx context.Context, userID int,
 	limit, offset int,
@@ -7,13 +7,18 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
 
 	"github.com/dropwhile/refid/v2/reftag"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/util"
 	"github.com/dropwhile/icanbringthat/internal/validate"
 )
 
@@ -50,7 +55,37 @@ func (s *Service) GetEventItemsByEvent(
 		return nil, errs.Internal.Error("db error")
 	}
 
-	return s.GetEventItemsByEventID(ctx, event.ID)
+	items, errx := s.GetEventItemsByEventID(ctx, event.ID)
+	if errx != nil {
+		return nil, errx
+	}
+	return SortEventItems(items, event.ItemSortOrder), nil
+}
+
+// SortEventItems orders items according to an event's item sort order.
+// Items missing from the sort order (likely new) are placed at the front.
+func SortEventItems(
+	items []*model.EventItem, sortOrder []int,
+) []*model.EventItem {
+	if len(sortOrder) == 0 {
+		return items
+	}
+
+	sortSet := util.ToSetIndexed(sortOrder)
+	sortedList := make([]*model.EventItem, len(sortOrder))
+	unsortedList := make([]*model.EventItem, 0)
+	for _, item := range items {
+		if idx, ok := sortSet[item.ID]; ok {
+			sortedList[idx] = item
+		} else {
+			unsortedList = append(unsortedList, item)
+		}
+	}
+	// the sort order may still reference removed items
+	sortedList = slices.DeleteFunc(sortedList, func(item *model.EventItem) bool {
+		return item == nil
+	})
+	return append(unsortedList, sortedList...)
 }
 
 func (s *Service) GetEventItemsByEventID(
@@ -149,6 +184,7 @@ func (s *Service) RemoveEventItem(
 	if err != nil {
 		return errs.Internal.Error("db error")
 	}
+	s.eventChanged(ctx, event, pubsub.ChangeItemRemoved, eventItem.RefID)
 	return nil
 }
 
@@ -186,12 +222,14 @@ func (s *Service) AddEventItem(
 		return nil, errs.Internal.Error("db error")
 	}
 
+	s.eventChanged(ctx, event, pubsub.ChangeItemAdded, eventItem.RefID)
 	return eventItem, nil
 }
 
 func (s *Service) UpdateEventItem(
 	ctx context.Context, userID int,
 	refID model.EventItemRefID, description string,
+	version mo.Option[string],
 	failIfChecks FailIfCheckFunc[*model.EventItem],
 ) (*model.EventItem, errs.Error) {
 	err := validate.Validate.VarCtx(ctx, description, "required,notblank")
@@ -245,8 +283,35 @@ func (s *Service) UpdateEventItem(
 		return nil, errs.PermissionDenied.Error("earmarked by other user")
 	}
 
+	// the update itself is conditional on last_modified too, so a write
+	// landing between the read above and the update is still caught
+	var lastModified mo.Option[time.Time]
+	if val, ok := version.Get(); ok {
+		if val != eventItem.Version() {
+			return nil, errs.Aborted.Error("event-item was modified").
+				WithMeta("version", eventItem.Version())
+		}
+		lastModified = mo.Some(eventItem.LastModified)
+	}
+
 	eventItem.Description = description
-	err = model.UpdateEventItem(ctx, s.Db, eventItem.ID, eventItem.Description)
+	rows, err := model.UpdateEventItem(ctx, s.Db, eventItem.ID,
+		eventItem.Description, lastModified)
+	if err != nil {
+		return nil, errs.Internal.Error("db error")
+	}
+	if rows == 0 {
+		current, err := model.GetEventItemByID(ctx, s.Db, eventItem.ID)
+		if err != nil {
+			return nil, errs.Internal.Error("db error")
+		}
+		return nil, errs.Aborted.Error("event-item was modified").
+			WithMeta("version", current.Version())
+	}
+	s.eventChanged(ctx, event, pubsub.ChangeItemUpdated, eventItem.RefID)
+
+	// refetch, so the returned item carries its new version
+	eventItem, err = model.GetEventItemByID(ctx, s.Db, eventItem.ID)
 	if err != nil {
 		return nil, errs.Internal.Error("db error")
 	}
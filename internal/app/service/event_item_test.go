@@ -6,13 +6,16 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/dropwhile/assert"
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
+	"github.com/samber/mo"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	"github.com/dropwhile/icanbringthat/internal/util"
 )
 
@@ -203,6 +206,43 @@ func TestService_GetEventItemsByEvent(t *testing.T) {
 	})
 }
 
+func TestSortEventItems(t *testing.T) {
+	t.Parallel()
+
+	items := []*model.EventItem{
+		{ID: 1, Description: "one"},
+		{ID: 2, Description: "two"},
+		{ID: 3, Description: "three"},
+	}
+	ids := func(items []*model.EventItem) []int {
+		out := make([]int, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	t.Run("empty sort order keeps input order", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, ids(SortEventItems(items, nil)), []int{1, 2, 3})
+	})
+
+	t.Run("sort order should be applied", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, ids(SortEventItems(items, []int{3, 1, 2})), []int{3, 1, 2})
+	})
+
+	t.Run("unsorted items should be placed first", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, ids(SortEventItems(items, []int{3, 1})), []int{2, 3, 1})
+	})
+
+	t.Run("removed items in sort order should be skipped", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, ids(SortEventItems(items, []int{3, 9, 2, 1})), []int{3, 2, 1})
+	})
+}
+
 func TestService_GetEventItemsByEventID(t *testing.T) {
 	t.Parallel()
 
@@ -818,6 +858,61 @@ func TestService_AddEventItem(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("add item should publish change", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		broker := pubsub.NewMemoryBroker()
+		svc := New(Options{Db: mock, Broker: broker})
+		sub, err := broker.Subscribe(ctx, pubsub.EventTopic(event.ID))
+		assert.Nil(t, err)
+		defer sub.Close()
+
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO event_item_").
+			WithArgs(pgx.NamedArgs{
+				"refID":       EventItemRefIDMatcher,
+				"eventID":     eventItem.EventID,
+				"description": eventItem.Description,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID,
+					eventItem.EventID, eventItem.Description,
+				),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		_, errx := svc.AddEventItem(
+			ctx, user.ID, event.RefID, eventItem.Description,
+		)
+		assert.Nil(t, errx)
+		msg := <-sub.Messages()
+		assert.Equal(t, msg.Type, pubsub.TypeEventUpdated)
+		assert.Equal(t, msg.Change, pubsub.ChangeItemAdded)
+		assert.Equal(t, msg.RefID, eventItem.RefID.String())
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("add item with archived event should fail", func(t *testing.T) {
 		t.Parallel()
 
@@ -995,16 +1090,29 @@ func TestService_UpdateEventItem(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec("UPDATE event_item_ ").
 			WithArgs(pgx.NamedArgs{
-				"description": description,
-				"eventItemID": eventItem.ID,
+				"description":  description,
+				"lastModified": mo.None[time.Time](),
+				"eventItemID":  eventItem.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 		mock.ExpectCommit()
 		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT (.+) FROM event_item_ ").
+			WithArgs(eventItem.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID,
+					eventItem.EventID, description,
+				),
+			)
 
-		result, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		result, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		assert.Nil(t, err)
 		assert.Equal(t, result.RefID, eventItem.RefID)
+		assert.Equal(t, result.Description, description)
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
@@ -1056,16 +1164,29 @@ func TestService_UpdateEventItem(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec("UPDATE event_item_ ").
 			WithArgs(pgx.NamedArgs{
-				"description": description,
-				"eventItemID": eventItem.ID,
+				"description":  description,
+				"lastModified": mo.None[time.Time](),
+				"eventItemID":  eventItem.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 		mock.ExpectCommit()
 		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT (.+) FROM event_item_ ").
+			WithArgs(eventItem.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID,
+					eventItem.EventID, description,
+				),
+			)
 
-		result, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		result, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		assert.Nil(t, err)
 		assert.Equal(t, result.RefID, eventItem.RefID)
+		assert.Equal(t, result.Description, description)
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
@@ -1115,13 +1236,132 @@ func TestService_UpdateEventItem(t *testing.T) {
 				),
 			)
 
-		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		errs.AssertError(t, err, errs.PermissionDenied, "earmarked by other user")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("update with stale version should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		description := "hodor"
+
+		mock.ExpectQuery("SELECT (.+) FROM event_item_ ").
+			WithArgs(eventItem.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description", "last_modified",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID,
+					eventItem.EventID, eventItem.Description,
+					eventItem.LastModified,
+				),
+			)
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM earmark_").
+			WithArgs(eventItem.ID).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := svc.UpdateEventItem(
+			ctx, user.ID, eventItem.RefID, description,
+			mo.Some(model.Version(ts.Add(-time.Minute))), nil,
+		)
+		errs.AssertError(t, err, errs.Aborted, "event-item was modified")
+		assert.Equal(t, err.Meta("version"), eventItem.Version())
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("update racing another write should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		description := "hodor"
+		modified := ts.Add(time.Second)
+
+		mock.ExpectQuery("SELECT (.+) FROM event_item_ ").
+			WithArgs(eventItem.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description", "last_modified",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID,
+					eventItem.EventID, eventItem.Description,
+					eventItem.LastModified,
+				),
+			)
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM earmark_").
+			WithArgs(eventItem.ID).
+			WillReturnError(pgx.ErrNoRows)
+		// another writer updated the row after it was read above
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE event_item_ ").
+			WithArgs(pgx.NamedArgs{
+				"description":  description,
+				"lastModified": mo.Some(eventItem.LastModified),
+				"eventItemID":  eventItem.ID,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT (.+) FROM event_item_ ").
+			WithArgs(eventItem.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description", "last_modified",
+				}).
+				AddRow(
+					eventItem.ID, eventItem.RefID,
+					eventItem.EventID, "other-description", modified,
+				),
+			)
+
+		_, err := svc.UpdateEventItem(
+			ctx, user.ID, eventItem.RefID, description,
+			mo.Some(eventItem.Version()), nil,
+		)
+		errs.AssertError(t, err, errs.Aborted, "event-item was modified")
+		assert.Equal(t, err.Meta("version"), model.Version(modified))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("update archived event should fail", func(t *testing.T) {
 		t.Parallel()
 
@@ -1155,7 +1395,7 @@ func TestService_UpdateEventItem(t *testing.T) {
 				),
 			)
 
-		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		errs.AssertError(t, err, errs.PermissionDenied, "event is archived")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
@@ -1195,7 +1435,7 @@ func TestService_UpdateEventItem(t *testing.T) {
 				),
 			)
 
-		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		errs.AssertError(t, err, errs.PermissionDenied, "not event owner")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
@@ -1226,7 +1466,7 @@ func TestService_UpdateEventItem(t *testing.T) {
 			WithArgs(event.ID).
 			WillReturnError(pgx.ErrNoRows)
 
-		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		errs.AssertError(t, err, errs.NotFound, "event not found")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
@@ -1255,7 +1495,7 @@ func TestService_UpdateEventItem(t *testing.T) {
 			)
 
 		_, err := svc.UpdateEventItem(
-			ctx, user.ID, eventItem.RefID, description,
+			ctx, user.ID, eventItem.RefID, description, mo.None[string](),
 			func(ei *model.EventItem) bool { return true },
 		)
 		errs.AssertError(t, err, errs.FailedPrecondition, "extra checks failed")
@@ -1277,7 +1517,7 @@ func TestService_UpdateEventItem(t *testing.T) {
 			WithArgs(eventItem.RefID).
 			WillReturnError(pgx.ErrNoRows)
 
-		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, nil)
+		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, description, mo.None[string](), nil)
 		errs.AssertError(t, err, errs.NotFound, "event-item not found")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
@@ -1291,7 +1531,7 @@ func TestService_UpdateEventItem(t *testing.T) {
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, "", nil)
+		_, err := svc.UpdateEventItem(ctx, user.ID, eventItem.RefID, "", mo.None[string](), nil)
 		errs.AssertError(t, err, errs.InvalidArgument, "description bad value")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
@@ -404,6 +404,7 @@ func TestService_UpdateEvent(t *testing.T) {
 				"startTime":     euvs.StartTime,
 				"startTimeTz":   startTimeTz,
 				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.None[time.Time](),
 				"eventID":       event.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
@@ -459,6 +460,7 @@ func TestService_UpdateEvent(t *testing.T) {
 				"startTime":     euvs.StartTime,
 				"startTimeTz":   startTimeTz,
 				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.None[time.Time](),
 				"eventID":       event.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
@@ -515,11 +517,39 @@ func TestService_UpdateEvent(t *testing.T) {
 				"startTime":     euvs.StartTime,
 				"startTimeTz":   startTimeTz,
 				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.None[time.Time](),
 				"eventID":       event.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 		mock.ExpectCommit()
 		mock.ExpectRollback()
+		// guests with earmarks are notified of the change
+		mock.ExpectQuery("SELECT earmark_(.+) FROM earmark_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "event_item_id", "user_id"}).
+				AddRow(5, util.Must(model.NewEarmarkRefID()), 6, 7).
+				AddRow(8, util.Must(model.NewEarmarkRefID()), 9, 7).
+				AddRow(10, util.Must(model.NewEarmarkRefID()), 11, user.ID),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO notification_").
+			WithArgs(pgx.NamedArgs{
+				"refID":   NotificationRefIDMatcher,
+				"userID":  7,
+				"kind":    model.NotificationKindEventChanged,
+				"message": "The start time of event 'event' has changed.",
+				"payload": model.NotificationPayload{
+					EventRefID: event.RefID.String(),
+					EventName:  event.Name,
+				},
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(12, util.Must(model.NewNotificationRefID()), 7),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
 
 		err := svc.UpdateEvent(ctx, user.ID, event.RefID, euvs)
 		assert.Nil(t, err)
@@ -571,6 +601,7 @@ func TestService_UpdateEvent(t *testing.T) {
 				"startTime":     euvs.StartTime,
 				"startTimeTz":   startTimeTz,
 				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.None[time.Time](),
 				"eventID":       event.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
@@ -627,6 +658,7 @@ func TestService_UpdateEvent(t *testing.T) {
 				"startTime":     euvs.StartTime,
 				"startTimeTz":   startTimeTz,
 				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.None[time.Time](),
 				"eventID":       event.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
@@ -697,6 +729,146 @@ func TestService_UpdateEvent(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("update with current version should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		euvs := &EventUpdateValues{
+			Name:    mo.Some("new-name"),
+			Version: mo.Some(event.Version()),
+		}
+
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived", "last_modified",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived, event.LastModified,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE event_ ").
+			WithArgs(pgx.NamedArgs{
+				"name":          euvs.Name,
+				"description":   euvs.Description,
+				"startTime":     euvs.StartTime,
+				"startTimeTz":   mo.None[*model.TimeZone](),
+				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.Some(event.LastModified),
+				"eventID":       event.ID,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.UpdateEvent(ctx, user.ID, event.RefID, euvs)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("update with stale version should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		euvs := &EventUpdateValues{
+			Name:    mo.Some("new-name"),
+			Version: mo.Some(model.Version(ts.Add(-time.Minute))),
+		}
+
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived", "last_modified",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived, event.LastModified,
+				),
+			)
+
+		err := svc.UpdateEvent(ctx, user.ID, event.RefID, euvs)
+		errs.AssertError(t, err, errs.Aborted, "event was modified")
+		assert.Equal(t, err.Meta("version"), event.Version())
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("update racing another write should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		euvs := &EventUpdateValues{
+			Name:    mo.Some("new-name"),
+			Version: mo.Some(event.Version()),
+		}
+		modified := ts.Add(time.Second)
+
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived", "last_modified",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived, event.LastModified,
+				),
+			)
+		// another writer updated the row after it was read above
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE event_ ").
+			WithArgs(pgx.NamedArgs{
+				"name":          euvs.Name,
+				"description":   euvs.Description,
+				"startTime":     euvs.StartTime,
+				"startTimeTz":   mo.None[*model.TimeZone](),
+				"itemSortOrder": euvs.ItemSortOrder,
+				"lastModified":  mo.Some(event.LastModified),
+				"eventID":       event.ID,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT (.+) FROM event_ ").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"archived", "last_modified",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, "other-name",
+					event.Description, event.Archived, modified,
+				),
+			)
+
+		err := svc.UpdateEvent(ctx, user.ID, event.RefID, euvs)
+		errs.AssertError(t, err, errs.Aborted, "event was modified")
+		assert.Equal(t, err.Meta("version"), model.Version(modified))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("update not owner should fail", func(t *testing.T) {
 		t.Parallel()
 
@@ -947,6 +1119,7 @@ func TestService_UpdateEventItemSorting(t *testing.T) {
 				"startTime":     mo.None[time.Time](),
 				"startTimeTz":   mo.None[*model.TimeZone](),
 				"itemSortOrder": mo.Some(itemSortOrder),
+				"lastModified":  mo.None[time.Time](),
 				"eventID":       event.ID,
 			}).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
@@ -1370,6 +1543,101 @@ func TestService_GetEventsPaginated(t *testing.T) {
 	})
 }
 
+func TestService_GetEventsPaginatedAfter(t *testing.T) {
+	t.Parallel()
+
+	ts := tstTs
+	user := &model.User{
+		ID: 1,
+	}
+	eventRows := func(count int) *pgxmock.Rows {
+		rows := pgxmock.NewRows(
+			[]string{
+				"id", "ref_id", "user_id", "name", "start_time", "archived",
+			})
+		for i := range count {
+			rows.AddRow(
+				10-i, util.Must(model.NewEventRefID()), user.ID, "event",
+				ts.Add(-time.Duration(i)*time.Hour), false,
+			)
+		}
+		return rows
+	}
+
+	t.Run("get with more results should return next cursor", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		limit := 2
+		cursor := &PageCursor{Time: ts.Add(time.Hour), ID: 11}
+
+		mock.ExpectQuery("^SELECT count(.+) FROM event_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"current", "archived"}).
+				AddRow(5, 0),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(pgx.NamedArgs{
+				"userID":    user.ID,
+				"limit":     limit + 1,
+				"afterTime": cursor.Time,
+				"afterID":   cursor.ID,
+				"archived":  false,
+			}).
+			WillReturnRows(eventRows(3))
+
+		results, pagination, err := svc.GetEventsPaginatedAfter(ctx, user.ID, limit, cursor, false)
+		assert.Nil(t, err)
+		assert.Equal(t, len(results), limit)
+		assert.Equal(t, pagination.Limit, limit)
+		assert.Equal(t, pagination.Count, 5)
+		next, perr := ParsePageCursor(pagination.NextCursor)
+		assert.Nil(t, perr)
+		assert.Equal(t, next.ID, results[1].ID)
+		assert.True(t, next.Time.Equal(results[1].StartTime))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("get last page should return no next cursor", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		limit := 2
+		cursor := &PageCursor{Time: ts.Add(time.Hour), ID: 11}
+
+		mock.ExpectQuery("^SELECT count(.+) FROM event_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"current", "archived"}).
+				AddRow(5, 0),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(pgx.NamedArgs{
+				"userID":    user.ID,
+				"limit":     limit + 1,
+				"afterTime": cursor.Time,
+				"afterID":   cursor.ID,
+				"archived":  false,
+			}).
+			WillReturnRows(eventRows(1))
+
+		results, pagination, err := svc.GetEventsPaginatedAfter(ctx, user.ID, limit, cursor, false)
+		assert.Nil(t, err)
+		assert.Equal(t, len(results), 1)
+		assert.Equal(t, pagination.NextCursor, "")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
 func TestService_GetEventsComingSoonPaginated(t *testing.T) {
 	t.Parallel()
 
@@ -104,6 +104,46 @@ func (s *Service) GetFavoriteEventsPaginated(
 		Limit:  limit,
 		Offset: offset,
 		Count:  count,
+		NextCursor: nextPageCursor(
+			events, offset+len(events) < count, eventPageCursor),
+	}
+	return events, pagination, nil
+}
+
+func (s *Service) GetFavoriteEventsPaginatedAfter(
+	ctx context.Context, userID int,
+	limit int, cursor *PageCursor, archived bool,
+) ([]*model.Event, *Pagination, errs.Error) {
+	favCount, errx := s.GetFavoriteEventsCount(ctx, userID)
+	if errx != nil {
+		return nil, nil, errs.Internal.Error("db error")
+	}
+	count := favCount.Current
+	if archived {
+		count = favCount.Archived
+	}
+
+	events := []*model.Event{}
+	if count > 0 {
+		favs, err := model.GetFavoriteEventsByUserKeysetFiltered(
+			ctx, s.Db, userID, limit+1, cursor.Time, cursor.ID, archived)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			favs = []*model.Event{}
+		case err != nil:
+			slog.Error("db error", "error", err)
+			return nil, nil, errs.Internal.Error("db error")
+		}
+		events = favs
+	}
+	more := len(events) > limit
+	if more {
+		events = events[:limit]
+	}
+	pagination := &Pagination{
+		Limit:      limit,
+		Count:      count,
+		NextCursor: nextPageCursor(events, more, eventPageCursor),
 	}
 	return events, pagination, nil
 }
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/validate"
+)
+
+// IdempotencyKeyTTL is how long the response to a request made with an
+// idempotency key is kept, and replayed to retries of that request.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// ReserveIdempotencyKey marks key as in flight for userID. If the key was
+// already used for the same request, the stored record is returned with
+// reserved set to false, so its response can be replayed.
+func (s *Service) ReserveIdempotencyKey(
+	ctx context.Context, userID int, key, procedure string, requestHash []byte,
+) (*model.IdempotencyKey, bool, errs.Error) {
+	err := validate.Validate.VarCtx(ctx, key, "required,max=255,printascii")
+	if err != nil {
+		slog.
+			With("field", "idempotency_key").
+			With("error", err).
+			Info("bad field value")
+		return nil, false, errs.ArgumentError("idempotency_key", "bad value")
+	}
+
+	record, err := model.ReserveIdempotencyKey(
+		ctx, s.Db, userID, key, procedure, requestHash, IdempotencyKeyTTL)
+	switch {
+	case err == nil:
+		return record, true, nil
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, false, errs.Internal.Error("db error")
+	}
+
+	record, err = model.GetIdempotencyKey(ctx, s.Db, userID, key)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// released or purged since the reserve attempt
+		return nil, false, errs.Aborted.Error("idempotency key conflict")
+	case err != nil:
+		return nil, false, errs.Internal.Error("db error")
+	}
+
+	if record.Procedure != procedure || !bytes.Equal(record.RequestHash, requestHash) {
+		return nil, false, errs.InvalidArgument.Error(
+			"idempotency key already used for a different request")
+	}
+	if record.Response == nil {
+		return nil, false, errs.Aborted.Error(
+			"request with idempotency key is in progress")
+	}
+	return record, false, nil
+}
+
+// CompleteIdempotencyKey stores the response for a reserved key.
+func (s *Service) CompleteIdempotencyKey(
+	ctx context.Context, userID int, key string, response []byte,
+) errs.Error {
+	err := model.CompleteIdempotencyKey(ctx, s.Db, userID, key, response)
+	if err != nil {
+		return errs.Internal.Error("db error")
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey removes a reserved key, so that a failed request
+// can be retried with it.
+func (s *Service) ReleaseIdempotencyKey(
+	ctx context.Context, userID int, key string,
+) errs.Error {
+	err := model.DeleteIdempotencyKey(ctx, s.Db, userID, key)
+	if err != nil {
+		return errs.Internal.Error("db error")
+	}
+	return nil
+}
+
+func (s *Service) DeleteExpiredIdempotencyKeys(ctx context.Context) error {
+	return model.DeleteExpiredIdempotencyKeys(ctx, s.Db, IdempotencyKeyTTL)
+}
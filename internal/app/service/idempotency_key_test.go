@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/errs"
+)
+
+func TestService_ReserveIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+	key := "4b3f1d6e-key"
+	procedure := "/icbt.rpc.v1.IcbtRpcService/EventCreate"
+	requestHash := []byte("hash")
+	columns := []string{
+		"user_id", "key", "procedure", "request_hash", "response", "created",
+	}
+	reserveArgs := pgx.NamedArgs{
+		"userID":      userID,
+		"key":         key,
+		"procedure":   procedure,
+		"requestHash": requestHash,
+		"ttlSeconds":  int(IdempotencyKeyTTL.Seconds()),
+	}
+
+	t.Run("reserve new key should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO idempotency_key_").
+			WithArgs(reserveArgs).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, key, procedure, requestHash, nil, tstTs))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		record, reserved, err := svc.ReserveIdempotencyKey(
+			ctx, userID, key, procedure, requestHash)
+		assert.Nil(t, err)
+		assert.True(t, reserved)
+		assert.Equal(t, record.Key, key)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("reserve completed key should replay", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO idempotency_key_").
+			WithArgs(reserveArgs).
+			WillReturnRows(pgxmock.NewRows(columns))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+		mock.ExpectQuery("^SELECT (.+) FROM idempotency_key_").
+			WithArgs(userID, key).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, key, procedure, requestHash, []byte("response"), tstTs))
+
+		record, reserved, err := svc.ReserveIdempotencyKey(
+			ctx, userID, key, procedure, requestHash)
+		assert.Nil(t, err)
+		assert.True(t, !reserved)
+		assert.Equal(t, record.Response, []byte("response"))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("reserve key used for other request should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO idempotency_key_").
+			WithArgs(reserveArgs).
+			WillReturnRows(pgxmock.NewRows(columns))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+		mock.ExpectQuery("^SELECT (.+) FROM idempotency_key_").
+			WithArgs(userID, key).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, key, procedure, []byte("other"), []byte("response"), tstTs))
+
+		_, _, err := svc.ReserveIdempotencyKey(
+			ctx, userID, key, procedure, requestHash)
+		errs.AssertError(t, err, errs.InvalidArgument,
+			"idempotency key already used for a different request")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("reserve key in progress should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO idempotency_key_").
+			WithArgs(reserveArgs).
+			WillReturnRows(pgxmock.NewRows(columns))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+		mock.ExpectQuery("^SELECT (.+) FROM idempotency_key_").
+			WithArgs(userID, key).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, key, procedure, requestHash, nil, tstTs))
+
+		_, _, err := svc.ReserveIdempotencyKey(
+			ctx, userID, key, procedure, requestHash)
+		errs.AssertError(t, err, errs.Aborted,
+			"request with idempotency key is in progress")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("reserve bad key should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, _, err := svc.ReserveIdempotencyKey(
+			ctx, userID, strings.Repeat("x", 256), procedure, requestHash)
+		errs.AssertError(t, err, errs.InvalidArgument, "idempotency_key bad value")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+)
+
+const (
+	// consecutive failed logins allowed before delays kick in
+	loginFailuresBeforeDelay = 5
+	// consecutive failed logins that lock the account
+	loginFailuresBeforeLockout = 10
+	loginBaseDelay             = 30 * time.Second
+	LoginLockoutDuration       = 30 * time.Minute
+	// how long login history is kept
+	LoginAttemptRetention = 90 * 24 * time.Hour
+)
+
+// LoginDelay returns how long after the last of failures consecutive
+// failed logins, a new login attempt is refused. The delay doubles with
+// each failure, until the account is locked out.
+func LoginDelay(failures int) time.Duration {
+	switch {
+	case failures < loginFailuresBeforeDelay:
+		return 0
+	case failures >= loginFailuresBeforeLockout:
+		return LoginLockoutDuration
+	}
+	return loginBaseDelay << (failures - loginFailuresBeforeDelay)
+}
+
+// CheckLoginAllowed returns a ResourceExhausted error, if recent failed
+// logins mean userID may not attempt another login yet. The error meta
+// "retry_after" holds the wait in seconds.
+func (s *Service) CheckLoginAllowed(ctx context.Context, userID int) errs.Error {
+	failures, err := model.GetLoginFailures(ctx, s.Db, userID)
+	if err != nil {
+		return errs.Internal.Error("db error")
+	}
+	if failures.Last == nil {
+		return nil
+	}
+	delay := LoginDelay(failures.Count)
+	retryAfter := failures.Last.Add(delay).Sub(time.Now().UTC())
+	if retryAfter <= 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "login refused after failed attempts",
+		"userID", userID, "failures", failures.Count)
+	return errs.ResourceExhausted.Error("too many failed login attempts").
+		WithMeta("retry_after", strconv.Itoa(int(retryAfter.Seconds())+1))
+}
+
+func (s *Service) RecordLoginFailure(
+	ctx context.Context, userID int, method model.LoginMethod,
+	ipAddress, userAgent string,
+) errs.Error {
+	_, err := model.CreateLoginAttempt(
+		ctx, s.Db, userID, method, false, ipAddress, userAgent)
+	if err != nil {
+		return errs.Internal.Error("db error")
+	}
+	return nil
+}
+
+// RecordLoginSuccess records a successful login. If the user has logged
+// in before, but never from this ip address and user agent, they are
+// sent a notification and newSource is true.
+func (s *Service) RecordLoginSuccess(
+	ctx context.Context, userID int, method model.LoginMethod,
+	ipAddress, userAgent string,
+) (newSource bool, errx errs.Error) {
+	source, err := model.GetLoginSource(ctx, s.Db, userID, ipAddress, userAgent)
+	if err != nil {
+		return false, errs.Internal.Error("db error")
+	}
+	_, err = model.CreateLoginAttempt(
+		ctx, s.Db, userID, method, true, ipAddress, userAgent)
+	if err != nil {
+		return false, errs.Internal.Error("db error")
+	}
+
+	// the first recorded login is not news
+	if !source.AnySuccess || source.Known {
+		return false, nil
+	}
+
+	_, errx = s.NewTypedNotification(ctx, userID,
+		model.NotificationKindNewSignIn,
+		model.NotificationPayload{
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+		},
+	)
+	if errx != nil {
+		slog.ErrorContext(ctx, "error creating new sign-in notification",
+			"userID", userID, "error", errx)
+	}
+	return true, nil
+}
+
+func (s *Service) DeleteOldLoginAttempts(ctx context.Context) error {
+	return model.DeleteLoginAttemptsBefore(ctx, s.Db,
+		time.Now().UTC().Add(-LoginAttemptRetention))
+}
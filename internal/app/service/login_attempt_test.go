@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestLoginDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		failures int
+		delay    time.Duration
+	}{
+		{0, 0},
+		{4, 0},
+		{5, 30 * time.Second},
+		{6, time.Minute},
+		{9, 8 * time.Minute},
+		{10, LoginLockoutDuration},
+		{50, LoginLockoutDuration},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, LoginDelay(tt.failures), tt.delay)
+	}
+}
+
+func TestService_CheckLoginAllowed(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+
+	t.Run("no failures should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM login_attempt_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "last"}).
+				AddRow(0, (*time.Time)(nil)))
+
+		err := svc.CheckLoginAllowed(ctx, userID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delay elapsed should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		last := time.Now().UTC().Add(-time.Minute)
+		mock.ExpectQuery("^SELECT (.+) FROM login_attempt_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "last"}).
+				AddRow(5, &last))
+
+		err := svc.CheckLoginAllowed(ctx, userID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("locked out should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		last := time.Now().UTC().Add(-time.Minute)
+		mock.ExpectQuery("^SELECT (.+) FROM login_attempt_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "last"}).
+				AddRow(10, &last))
+
+		err := svc.CheckLoginAllowed(ctx, userID)
+		errs.AssertError(t, err, errs.ResourceExhausted, "too many failed login attempts")
+		assert.True(t, err.Meta("retry_after") != "")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_RecordLoginSuccess(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+	ipAddress := "192.0.2.1"
+	userAgent := "test-agent"
+	insertArgs := pgx.NamedArgs{
+		"userID":    userID,
+		"method":    model.LoginMethodPassword,
+		"success":   true,
+		"ipAddress": ipAddress,
+		"userAgent": userAgent,
+	}
+	sourceArgs := pgx.NamedArgs{
+		"userID":    userID,
+		"ipAddress": ipAddress,
+		"userAgent": userAgent,
+	}
+
+	t.Run("known source should not notify", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) login_attempt_").
+			WithArgs(sourceArgs).
+			WillReturnRows(pgxmock.NewRows([]string{"any_success", "known"}).
+				AddRow(true, true))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO login_attempt_").
+			WithArgs(insertArgs).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id"}).
+				AddRow(1, userID))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		newSource, err := svc.RecordLoginSuccess(ctx, userID,
+			model.LoginMethodPassword, ipAddress, userAgent)
+		assert.Nil(t, err)
+		assert.True(t, !newSource)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("first login should not notify", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) login_attempt_").
+			WithArgs(sourceArgs).
+			WillReturnRows(pgxmock.NewRows([]string{"any_success", "known"}).
+				AddRow(false, false))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO login_attempt_").
+			WithArgs(insertArgs).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id"}).
+				AddRow(1, userID))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		newSource, err := svc.RecordLoginSuccess(ctx, userID,
+			model.LoginMethodPassword, ipAddress, userAgent)
+		assert.Nil(t, err)
+		assert.True(t, !newSource)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("new source should notify", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		payload := model.NotificationPayload{
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+		}
+		mock.ExpectQuery("^SELECT (.+) login_attempt_").
+			WithArgs(sourceArgs).
+			WillReturnRows(pgxmock.NewRows([]string{"any_success", "known"}).
+				AddRow(true, false))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO login_attempt_").
+			WithArgs(insertArgs).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id"}).
+				AddRow(1, userID))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO notification_").
+			WithArgs(pgx.NamedArgs{
+				"refID":   NotificationRefIDMatcher,
+				"userID":  userID,
+				"kind":    model.NotificationKindNewSignIn,
+				"message": "New sign-in to your account from 192.0.2.1 (test-agent).",
+				"payload": payload,
+			}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "ref_id", "user_id"}).
+				AddRow(1, util.Must(model.NewNotificationRefID()), userID))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		newSource, err := svc.RecordLoginSuccess(ctx, userID,
+			model.LoginMethodPassword, ipAddress, userAgent)
+		assert.Nil(t, err)
+		assert.True(t, newSource)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
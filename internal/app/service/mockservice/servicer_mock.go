@@ -11,6 +11,7 @@ package mockservice
 
 import (
 	context "context"
+	http "net/http"
 	reflect "reflect"
 	time "time"
 
@@ -19,6 +20,7 @@ import (
 	service "github.com/dropwhile/icanbringthat/internal/app/service"
 	errs "github.com/dropwhile/icanbringthat/internal/errs"
 	mail "github.com/dropwhile/icanbringthat/internal/mail"
+	mo "github.com/samber/mo"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -90,6 +92,122 @@ func (mr *MockServicerMockRecorder) ArchiveOldEvents(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveOldEvents", reflect.TypeOf((*MockServicer)(nil).ArchiveOldEvents), ctx)
 }
 
+// AuthenticateApiKey mocks base method.
+func (m *MockServicer) AuthenticateApiKey(ctx context.Context, token string) (*model.User, *model.ApiKey, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateApiKey", ctx, token)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*model.ApiKey)
+	ret2, _ := ret[2].(errs.Error)
+	return ret0, ret1, ret2
+}
+
+// AuthenticateApiKey indicates an expected call of AuthenticateApiKey.
+func (mr *MockServicerMockRecorder) AuthenticateApiKey(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateApiKey", reflect.TypeOf((*MockServicer)(nil).AuthenticateApiKey), ctx, token)
+}
+
+// CancelUserDeletion mocks base method.
+func (m *MockServicer) CancelUserDeletion(ctx context.Context, deletion *model.UserDeletion) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelUserDeletion", ctx, deletion)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// CancelUserDeletion indicates an expected call of CancelUserDeletion.
+func (mr *MockServicerMockRecorder) CancelUserDeletion(ctx, deletion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUserDeletion", reflect.TypeOf((*MockServicer)(nil).CancelUserDeletion), ctx, deletion)
+}
+
+// CheckLoginAllowed mocks base method.
+func (m *MockServicer) CheckLoginAllowed(ctx context.Context, userID int) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLoginAllowed", ctx, userID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// CheckLoginAllowed indicates an expected call of CheckLoginAllowed.
+func (mr *MockServicerMockRecorder) CheckLoginAllowed(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLoginAllowed", reflect.TypeOf((*MockServicer)(nil).CheckLoginAllowed), ctx, userID)
+}
+
+// CheckUserSession mocks base method.
+func (m *MockServicer) CheckUserSession(ctx context.Context, userID int, refID model.UserSessionRefID) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUserSession", ctx, userID, refID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// CheckUserSession indicates an expected call of CheckUserSession.
+func (mr *MockServicerMockRecorder) CheckUserSession(ctx, userID, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserSession", reflect.TypeOf((*MockServicer)(nil).CheckUserSession), ctx, userID, refID)
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockServicer) CompleteIdempotencyKey(ctx context.Context, userID int, key string, response []byte) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, userID, key, response)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockServicerMockRecorder) CompleteIdempotencyKey(ctx, userID, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockServicer)(nil).CompleteIdempotencyKey), ctx, userID, key, response)
+}
+
+// ConfirmTOTPEnrollment mocks base method.
+func (m *MockServicer) ConfirmTOTPEnrollment(ctx context.Context, userID int, code string) ([]string, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTPEnrollment", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// ConfirmTOTPEnrollment indicates an expected call of ConfirmTOTPEnrollment.
+func (mr *MockServicerMockRecorder) ConfirmTOTPEnrollment(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTPEnrollment", reflect.TypeOf((*MockServicer)(nil).ConfirmTOTPEnrollment), ctx, userID, code)
+}
+
+// ConfirmUserEmailChange mocks base method.
+func (m *MockServicer) ConfirmUserEmailChange(ctx context.Context, user *model.User, change *model.UserEmailChange) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmUserEmailChange", ctx, user, change)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// ConfirmUserEmailChange indicates an expected call of ConfirmUserEmailChange.
+func (mr *MockServicerMockRecorder) ConfirmUserEmailChange(ctx, user, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmUserEmailChange", reflect.TypeOf((*MockServicer)(nil).ConfirmUserEmailChange), ctx, user, change)
+}
+
+// ConsumeUserLoginLink mocks base method.
+func (m *MockServicer) ConsumeUserLoginLink(ctx context.Context, refID model.UserLoginLinkRefID) (*model.UserLoginLink, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeUserLoginLink", ctx, refID)
+	ret0, _ := ret[0].(*model.UserLoginLink)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// ConsumeUserLoginLink indicates an expected call of ConsumeUserLoginLink.
+func (mr *MockServicerMockRecorder) ConsumeUserLoginLink(ctx, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeUserLoginLink", reflect.TypeOf((*MockServicer)(nil).ConsumeUserLoginLink), ctx, refID)
+}
+
 // CreateEvent mocks base method.
 func (m *MockServicer) CreateEvent(ctx context.Context, user *model.User, name, description string, when time.Time, tz string) (*model.Event, errs.Error) {
 	m.ctrl.T.Helper()
@@ -119,6 +237,20 @@ func (mr *MockServicerMockRecorder) DeleteAllNotifications(ctx, userID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllNotifications", reflect.TypeOf((*MockServicer)(nil).DeleteAllNotifications), ctx, userID)
 }
 
+// DeleteApiKey mocks base method.
+func (m *MockServicer) DeleteApiKey(ctx context.Context, userID int, prefix string) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApiKey", ctx, userID, prefix)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DeleteApiKey indicates an expected call of DeleteApiKey.
+func (mr *MockServicerMockRecorder) DeleteApiKey(ctx, userID, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApiKey", reflect.TypeOf((*MockServicer)(nil).DeleteApiKey), ctx, userID, prefix)
+}
+
 // DeleteEarmark mocks base method.
 func (m *MockServicer) DeleteEarmark(ctx context.Context, userID int, earmark *model.Earmark) errs.Error {
 	m.ctrl.T.Helper()
@@ -161,6 +293,34 @@ func (mr *MockServicerMockRecorder) DeleteEvent(ctx, userID, refID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockServicer)(nil).DeleteEvent), ctx, userID, refID)
 }
 
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockServicer) DeleteExpiredIdempotencyKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockServicerMockRecorder) DeleteExpiredIdempotencyKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockServicer)(nil).DeleteExpiredIdempotencyKeys), ctx)
+}
+
+// DeleteExpiredUserDataExports mocks base method.
+func (m *MockServicer) DeleteExpiredUserDataExports(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredUserDataExports", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredUserDataExports indicates an expected call of DeleteExpiredUserDataExports.
+func (mr *MockServicerMockRecorder) DeleteExpiredUserDataExports(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredUserDataExports", reflect.TypeOf((*MockServicer)(nil).DeleteExpiredUserDataExports), ctx)
+}
+
 // DeleteNotification mocks base method.
 func (m *MockServicer) DeleteNotification(ctx context.Context, userID int, refID model.NotificationRefID) errs.Error {
 	m.ctrl.T.Helper()
@@ -175,6 +335,48 @@ func (mr *MockServicerMockRecorder) DeleteNotification(ctx, userID, refID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotification", reflect.TypeOf((*MockServicer)(nil).DeleteNotification), ctx, userID, refID)
 }
 
+// DeleteOldLoginAttempts mocks base method.
+func (m *MockServicer) DeleteOldLoginAttempts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldLoginAttempts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldLoginAttempts indicates an expected call of DeleteOldLoginAttempts.
+func (mr *MockServicerMockRecorder) DeleteOldLoginAttempts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldLoginAttempts", reflect.TypeOf((*MockServicer)(nil).DeleteOldLoginAttempts), ctx)
+}
+
+// DeleteOtherUserSessions mocks base method.
+func (m *MockServicer) DeleteOtherUserSessions(ctx context.Context, userID int, keepRefID model.UserSessionRefID) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOtherUserSessions", ctx, userID, keepRefID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DeleteOtherUserSessions indicates an expected call of DeleteOtherUserSessions.
+func (mr *MockServicerMockRecorder) DeleteOtherUserSessions(ctx, userID, keepRefID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOtherUserSessions", reflect.TypeOf((*MockServicer)(nil).DeleteOtherUserSessions), ctx, userID, keepRefID)
+}
+
+// DeleteScheduledUsers mocks base method.
+func (m *MockServicer) DeleteScheduledUsers(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledUsers", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledUsers indicates an expected call of DeleteScheduledUsers.
+func (mr *MockServicerMockRecorder) DeleteScheduledUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledUsers", reflect.TypeOf((*MockServicer)(nil).DeleteScheduledUsers), ctx)
+}
+
 // DeleteUser mocks base method.
 func (m *MockServicer) DeleteUser(ctx context.Context, userID int) errs.Error {
 	m.ctrl.T.Helper()
@@ -203,6 +405,76 @@ func (mr *MockServicerMockRecorder) DeleteUserCredential(ctx, user, refID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserCredential", reflect.TypeOf((*MockServicer)(nil).DeleteUserCredential), ctx, user, refID)
 }
 
+// DeleteUserIdentity mocks base method.
+func (m *MockServicer) DeleteUserIdentity(ctx context.Context, user *model.User, refID model.UserIdentityRefID) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserIdentity", ctx, user, refID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DeleteUserIdentity indicates an expected call of DeleteUserIdentity.
+func (mr *MockServicerMockRecorder) DeleteUserIdentity(ctx, user, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserIdentity", reflect.TypeOf((*MockServicer)(nil).DeleteUserIdentity), ctx, user, refID)
+}
+
+// DeleteUserSession mocks base method.
+func (m *MockServicer) DeleteUserSession(ctx context.Context, userID int, refID model.UserSessionRefID) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSession", ctx, userID, refID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DeleteUserSession indicates an expected call of DeleteUserSession.
+func (mr *MockServicerMockRecorder) DeleteUserSession(ctx, userID, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSession", reflect.TypeOf((*MockServicer)(nil).DeleteUserSession), ctx, userID, refID)
+}
+
+// DeleteUserSessions mocks base method.
+func (m *MockServicer) DeleteUserSessions(ctx context.Context, userID int) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", ctx, userID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockServicerMockRecorder) DeleteUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockServicer)(nil).DeleteUserSessions), ctx, userID)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockServicer) DeleteWebhookEndpoint(ctx context.Context, userID int, refID model.WebhookEndpointRefID) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", ctx, userID, refID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockServicerMockRecorder) DeleteWebhookEndpoint(ctx, userID, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockServicer)(nil).DeleteWebhookEndpoint), ctx, userID, refID)
+}
+
+// DeliverWebhooks mocks base method.
+func (m *MockServicer) DeliverWebhooks(ctx context.Context, client *http.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverWebhooks", ctx, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverWebhooks indicates an expected call of DeliverWebhooks.
+func (mr *MockServicerMockRecorder) DeliverWebhooks(ctx, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverWebhooks", reflect.TypeOf((*MockServicer)(nil).DeliverWebhooks), ctx, client)
+}
+
 // DisableRemindersWithNotification mocks base method.
 func (m *MockServicer) DisableRemindersWithNotification(ctx context.Context, email, suppressionReason string) errs.Error {
 	m.ctrl.T.Helper()
@@ -217,19 +489,33 @@ func (mr *MockServicerMockRecorder) DisableRemindersWithNotification(ctx, email,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableRemindersWithNotification", reflect.TypeOf((*MockServicer)(nil).DisableRemindersWithNotification), ctx, email, suppressionReason)
 }
 
-// GetApiKeyByUser mocks base method.
-func (m *MockServicer) GetApiKeyByUser(ctx context.Context, userID int) (*model.ApiKey, errs.Error) {
+// DisableTOTP mocks base method.
+func (m *MockServicer) DisableTOTP(ctx context.Context, userID int) errs.Error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApiKeyByUser", ctx, userID)
-	ret0, _ := ret[0].(*model.ApiKey)
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, userID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockServicerMockRecorder) DisableTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockServicer)(nil).DisableTOTP), ctx, userID)
+}
+
+// GetApiKeysByUser mocks base method.
+func (m *MockServicer) GetApiKeysByUser(ctx context.Context, userID int) ([]*model.ApiKey, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeysByUser", ctx, userID)
+	ret0, _ := ret[0].([]*model.ApiKey)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// GetApiKeyByUser indicates an expected call of GetApiKeyByUser.
-func (mr *MockServicerMockRecorder) GetApiKeyByUser(ctx, userID any) *gomock.Call {
+// GetApiKeysByUser indicates an expected call of GetApiKeysByUser.
+func (mr *MockServicerMockRecorder) GetApiKeysByUser(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeyByUser", reflect.TypeOf((*MockServicer)(nil).GetApiKeyByUser), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeysByUser", reflect.TypeOf((*MockServicer)(nil).GetApiKeysByUser), ctx, userID)
 }
 
 // GetEarmark mocks base method.
@@ -323,6 +609,22 @@ func (mr *MockServicerMockRecorder) GetEarmarksPaginated(ctx, userID, limit, off
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEarmarksPaginated", reflect.TypeOf((*MockServicer)(nil).GetEarmarksPaginated), ctx, userID, limit, offset, archived)
 }
 
+// GetEarmarksPaginatedAfter mocks base method.
+func (m *MockServicer) GetEarmarksPaginatedAfter(ctx context.Context, userID, limit int, cursor *service.PageCursor, archived bool) ([]*model.Earmark, *service.Pagination, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEarmarksPaginatedAfter", ctx, userID, limit, cursor, archived)
+	ret0, _ := ret[0].([]*model.Earmark)
+	ret1, _ := ret[1].(*service.Pagination)
+	ret2, _ := ret[2].(errs.Error)
+	return ret0, ret1, ret2
+}
+
+// GetEarmarksPaginatedAfter indicates an expected call of GetEarmarksPaginatedAfter.
+func (mr *MockServicerMockRecorder) GetEarmarksPaginatedAfter(ctx, userID, limit, cursor, archived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEarmarksPaginatedAfter", reflect.TypeOf((*MockServicer)(nil).GetEarmarksPaginatedAfter), ctx, userID, limit, cursor, archived)
+}
+
 // GetEvent mocks base method.
 func (m *MockServicer) GetEvent(ctx context.Context, refID model.EventRefID) (*model.Event, errs.Error) {
 	m.ctrl.T.Helper()
@@ -443,6 +745,21 @@ func (mr *MockServicerMockRecorder) GetEventItemsCount(ctx, eventIDs any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventItemsCount", reflect.TypeOf((*MockServicer)(nil).GetEventItemsCount), ctx, eventIDs)
 }
 
+// GetEventTransferOptions mocks base method.
+func (m *MockServicer) GetEventTransferOptions(ctx context.Context, userID int) ([]*service.EventTransferOption, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventTransferOptions", ctx, userID)
+	ret0, _ := ret[0].([]*service.EventTransferOption)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetEventTransferOptions indicates an expected call of GetEventTransferOptions.
+func (mr *MockServicerMockRecorder) GetEventTransferOptions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventTransferOptions", reflect.TypeOf((*MockServicer)(nil).GetEventTransferOptions), ctx, userID)
+}
+
 // GetEvents mocks base method.
 func (m *MockServicer) GetEvents(ctx context.Context, userID int, archived bool) ([]*model.Event, errs.Error) {
 	m.ctrl.T.Helper()
@@ -520,6 +837,22 @@ func (mr *MockServicerMockRecorder) GetEventsPaginated(ctx, userID, limit, offse
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsPaginated", reflect.TypeOf((*MockServicer)(nil).GetEventsPaginated), ctx, userID, limit, offset, archived)
 }
 
+// GetEventsPaginatedAfter mocks base method.
+func (m *MockServicer) GetEventsPaginatedAfter(ctx context.Context, userID, limit int, cursor *service.PageCursor, archived bool) ([]*model.Event, *service.Pagination, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsPaginatedAfter", ctx, userID, limit, cursor, archived)
+	ret0, _ := ret[0].([]*model.Event)
+	ret1, _ := ret[1].(*service.Pagination)
+	ret2, _ := ret[2].(errs.Error)
+	return ret0, ret1, ret2
+}
+
+// GetEventsPaginatedAfter indicates an expected call of GetEventsPaginatedAfter.
+func (mr *MockServicerMockRecorder) GetEventsPaginatedAfter(ctx, userID, limit, cursor, archived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsPaginatedAfter", reflect.TypeOf((*MockServicer)(nil).GetEventsPaginatedAfter), ctx, userID, limit, cursor, archived)
+}
+
 // GetFavoriteByUserEvent mocks base method.
 func (m *MockServicer) GetFavoriteByUserEvent(ctx context.Context, userID, eventID int) (*model.Favorite, errs.Error) {
 	m.ctrl.T.Helper()
@@ -581,19 +914,35 @@ func (mr *MockServicerMockRecorder) GetFavoriteEventsPaginated(ctx, userID, limi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteEventsPaginated", reflect.TypeOf((*MockServicer)(nil).GetFavoriteEventsPaginated), ctx, userID, limit, offset, archived)
 }
 
+// GetFavoriteEventsPaginatedAfter mocks base method.
+func (m *MockServicer) GetFavoriteEventsPaginatedAfter(ctx context.Context, userID, limit int, cursor *service.PageCursor, archived bool) ([]*model.Event, *service.Pagination, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFavoriteEventsPaginatedAfter", ctx, userID, limit, cursor, archived)
+	ret0, _ := ret[0].([]*model.Event)
+	ret1, _ := ret[1].(*service.Pagination)
+	ret2, _ := ret[2].(errs.Error)
+	return ret0, ret1, ret2
+}
+
+// GetFavoriteEventsPaginatedAfter indicates an expected call of GetFavoriteEventsPaginatedAfter.
+func (mr *MockServicerMockRecorder) GetFavoriteEventsPaginatedAfter(ctx, userID, limit, cursor, archived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteEventsPaginatedAfter", reflect.TypeOf((*MockServicer)(nil).GetFavoriteEventsPaginatedAfter), ctx, userID, limit, cursor, archived)
+}
+
 // GetNotifications mocks base method.
-func (m *MockServicer) GetNotifications(ctx context.Context, userID int) ([]*model.Notification, errs.Error) {
+func (m *MockServicer) GetNotifications(ctx context.Context, userID int, unreadOnly bool) ([]*model.Notification, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNotifications", ctx, userID)
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, userID, unreadOnly)
 	ret0, _ := ret[0].([]*model.Notification)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
 // GetNotifications indicates an expected call of GetNotifications.
-func (mr *MockServicerMockRecorder) GetNotifications(ctx, userID any) *gomock.Call {
+func (mr *MockServicerMockRecorder) GetNotifications(ctx, userID, unreadOnly any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockServicer)(nil).GetNotifications), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockServicer)(nil).GetNotifications), ctx, userID, unreadOnly)
 }
 
 // GetNotificationsCount mocks base method.
@@ -612,9 +961,9 @@ func (mr *MockServicerMockRecorder) GetNotificationsCount(ctx, userID any) *gomo
 }
 
 // GetNotificationsPaginated mocks base method.
-func (m *MockServicer) GetNotificationsPaginated(ctx context.Context, userID, limit, offset int) ([]*model.Notification, *service.Pagination, errs.Error) {
+func (m *MockServicer) GetNotificationsPaginated(ctx context.Context, userID, limit, offset int, unreadOnly bool) ([]*model.Notification, *service.Pagination, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNotificationsPaginated", ctx, userID, limit, offset)
+	ret := m.ctrl.Call(m, "GetNotificationsPaginated", ctx, userID, limit, offset, unreadOnly)
 	ret0, _ := ret[0].([]*model.Notification)
 	ret1, _ := ret[1].(*service.Pagination)
 	ret2, _ := ret[2].(errs.Error)
@@ -622,39 +971,85 @@ func (m *MockServicer) GetNotificationsPaginated(ctx context.Context, userID, li
 }
 
 // GetNotificationsPaginated indicates an expected call of GetNotificationsPaginated.
-func (mr *MockServicerMockRecorder) GetNotificationsPaginated(ctx, userID, limit, offset any) *gomock.Call {
+func (mr *MockServicerMockRecorder) GetNotificationsPaginated(ctx, userID, limit, offset, unreadOnly any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsPaginated", reflect.TypeOf((*MockServicer)(nil).GetNotificationsPaginated), ctx, userID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsPaginated", reflect.TypeOf((*MockServicer)(nil).GetNotificationsPaginated), ctx, userID, limit, offset, unreadOnly)
 }
 
-// GetUser mocks base method.
-func (m *MockServicer) GetUser(ctx context.Context, refID model.UserRefID) (*model.User, errs.Error) {
+// GetNotificationsPaginatedAfter mocks base method.
+func (m *MockServicer) GetNotificationsPaginatedAfter(ctx context.Context, userID, limit int, cursor *service.PageCursor, unreadOnly bool) ([]*model.Notification, *service.Pagination, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, refID)
-	ret0, _ := ret[0].(*model.User)
+	ret := m.ctrl.Call(m, "GetNotificationsPaginatedAfter", ctx, userID, limit, cursor, unreadOnly)
+	ret0, _ := ret[0].([]*model.Notification)
+	ret1, _ := ret[1].(*service.Pagination)
+	ret2, _ := ret[2].(errs.Error)
+	return ret0, ret1, ret2
+}
+
+// GetNotificationsPaginatedAfter indicates an expected call of GetNotificationsPaginatedAfter.
+func (mr *MockServicerMockRecorder) GetNotificationsPaginatedAfter(ctx, userID, limit, cursor, unreadOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsPaginatedAfter", reflect.TypeOf((*MockServicer)(nil).GetNotificationsPaginatedAfter), ctx, userID, limit, cursor, unreadOnly)
+}
+
+// GetNotificationsUnreadCount mocks base method.
+func (m *MockServicer) GetNotificationsUnreadCount(ctx context.Context, userID int) (int, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsUnreadCount", ctx, userID)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockServicerMockRecorder) GetUser(ctx, refID any) *gomock.Call {
+// GetNotificationsUnreadCount indicates an expected call of GetNotificationsUnreadCount.
+func (mr *MockServicerMockRecorder) GetNotificationsUnreadCount(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockServicer)(nil).GetUser), ctx, refID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsUnreadCount", reflect.TypeOf((*MockServicer)(nil).GetNotificationsUnreadCount), ctx, userID)
+}
+
+// GetPendingUserEmailChange mocks base method.
+func (m *MockServicer) GetPendingUserEmailChange(ctx context.Context, userID int) (*model.UserEmailChange, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingUserEmailChange", ctx, userID)
+	ret0, _ := ret[0].(*model.UserEmailChange)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetPendingUserEmailChange indicates an expected call of GetPendingUserEmailChange.
+func (mr *MockServicerMockRecorder) GetPendingUserEmailChange(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingUserEmailChange", reflect.TypeOf((*MockServicer)(nil).GetPendingUserEmailChange), ctx, userID)
+}
+
+// GetRecoveryCodesRemaining mocks base method.
+func (m *MockServicer) GetRecoveryCodesRemaining(ctx context.Context, userID int) (int, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecoveryCodesRemaining", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
 }
 
-// GetUserByApiKey mocks base method.
-func (m *MockServicer) GetUserByApiKey(ctx context.Context, token string) (*model.User, errs.Error) {
+// GetRecoveryCodesRemaining indicates an expected call of GetRecoveryCodesRemaining.
+func (mr *MockServicerMockRecorder) GetRecoveryCodesRemaining(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecoveryCodesRemaining", reflect.TypeOf((*MockServicer)(nil).GetRecoveryCodesRemaining), ctx, userID)
+}
+
+// GetUser mocks base method.
+func (m *MockServicer) GetUser(ctx context.Context, refID model.UserRefID) (*model.User, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByApiKey", ctx, token)
+	ret := m.ctrl.Call(m, "GetUser", ctx, refID)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// GetUserByApiKey indicates an expected call of GetUserByApiKey.
-func (mr *MockServicerMockRecorder) GetUserByApiKey(ctx, token any) *gomock.Call {
+// GetUser indicates an expected call of GetUser.
+func (mr *MockServicerMockRecorder) GetUser(ctx, refID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByApiKey", reflect.TypeOf((*MockServicer)(nil).GetUserByApiKey), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockServicer)(nil).GetUser), ctx, refID)
 }
 
 // GetUserByEmail mocks base method.
@@ -732,37 +1127,142 @@ func (mr *MockServicerMockRecorder) GetUserCredentialsByUser(ctx, userID any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCredentialsByUser", reflect.TypeOf((*MockServicer)(nil).GetUserCredentialsByUser), ctx, userID)
 }
 
-// GetUserPWResetByRefID mocks base method.
-func (m *MockServicer) GetUserPWResetByRefID(ctx context.Context, refID model.UserPWResetRefID) (*model.UserPWReset, errs.Error) {
+// GetUserDataExport mocks base method.
+func (m *MockServicer) GetUserDataExport(ctx context.Context, userID int, refID model.UserDataExportRefID) (*model.UserDataExport, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserPWResetByRefID", ctx, refID)
-	ret0, _ := ret[0].(*model.UserPWReset)
+	ret := m.ctrl.Call(m, "GetUserDataExport", ctx, userID, refID)
+	ret0, _ := ret[0].(*model.UserDataExport)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// GetUserPWResetByRefID indicates an expected call of GetUserPWResetByRefID.
-func (mr *MockServicerMockRecorder) GetUserPWResetByRefID(ctx, refID any) *gomock.Call {
+// GetUserDataExport indicates an expected call of GetUserDataExport.
+func (mr *MockServicerMockRecorder) GetUserDataExport(ctx, userID, refID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPWResetByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserPWResetByRefID), ctx, refID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDataExport", reflect.TypeOf((*MockServicer)(nil).GetUserDataExport), ctx, userID, refID)
 }
 
-// GetUserVerifyByRefID mocks base method.
-func (m *MockServicer) GetUserVerifyByRefID(ctx context.Context, refID model.UserVerifyRefID) (*model.UserVerify, errs.Error) {
+// GetUserDeletion mocks base method.
+func (m *MockServicer) GetUserDeletion(ctx context.Context, userID int) (*model.UserDeletion, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserVerifyByRefID", ctx, refID)
-	ret0, _ := ret[0].(*model.UserVerify)
+	ret := m.ctrl.Call(m, "GetUserDeletion", ctx, userID)
+	ret0, _ := ret[0].(*model.UserDeletion)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// GetUserVerifyByRefID indicates an expected call of GetUserVerifyByRefID.
-func (mr *MockServicerMockRecorder) GetUserVerifyByRefID(ctx, refID any) *gomock.Call {
+// GetUserDeletion indicates an expected call of GetUserDeletion.
+func (mr *MockServicerMockRecorder) GetUserDeletion(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVerifyByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserVerifyByRefID), ctx, refID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDeletion", reflect.TypeOf((*MockServicer)(nil).GetUserDeletion), ctx, userID)
 }
 
-// GetUsersByIDs mocks base method.
+// GetUserDeletionByRefID mocks base method.
+func (m *MockServicer) GetUserDeletionByRefID(ctx context.Context, refID model.UserDeletionRefID) (*model.UserDeletion, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDeletionByRefID", ctx, refID)
+	ret0, _ := ret[0].(*model.UserDeletion)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserDeletionByRefID indicates an expected call of GetUserDeletionByRefID.
+func (mr *MockServicerMockRecorder) GetUserDeletionByRefID(ctx, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDeletionByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserDeletionByRefID), ctx, refID)
+}
+
+// GetUserEmailChangeByRefID mocks base method.
+func (m *MockServicer) GetUserEmailChangeByRefID(ctx context.Context, refID model.UserEmailChangeRefID) (*model.UserEmailChange, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserEmailChangeByRefID", ctx, refID)
+	ret0, _ := ret[0].(*model.UserEmailChange)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserEmailChangeByRefID indicates an expected call of GetUserEmailChangeByRefID.
+func (mr *MockServicerMockRecorder) GetUserEmailChangeByRefID(ctx, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEmailChangeByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserEmailChangeByRefID), ctx, refID)
+}
+
+// GetUserIdentities mocks base method.
+func (m *MockServicer) GetUserIdentities(ctx context.Context, userID int) ([]*model.UserIdentity, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentities", ctx, userID)
+	ret0, _ := ret[0].([]*model.UserIdentity)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserIdentities indicates an expected call of GetUserIdentities.
+func (mr *MockServicerMockRecorder) GetUserIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentities", reflect.TypeOf((*MockServicer)(nil).GetUserIdentities), ctx, userID)
+}
+
+// GetUserLoginLinkByRefID mocks base method.
+func (m *MockServicer) GetUserLoginLinkByRefID(ctx context.Context, refID model.UserLoginLinkRefID) (*model.UserLoginLink, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLoginLinkByRefID", ctx, refID)
+	ret0, _ := ret[0].(*model.UserLoginLink)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserLoginLinkByRefID indicates an expected call of GetUserLoginLinkByRefID.
+func (mr *MockServicerMockRecorder) GetUserLoginLinkByRefID(ctx, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginLinkByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserLoginLinkByRefID), ctx, refID)
+}
+
+// GetUserPWResetByRefID mocks base method.
+func (m *MockServicer) GetUserPWResetByRefID(ctx context.Context, refID model.UserPWResetRefID) (*model.UserPWReset, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPWResetByRefID", ctx, refID)
+	ret0, _ := ret[0].(*model.UserPWReset)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserPWResetByRefID indicates an expected call of GetUserPWResetByRefID.
+func (mr *MockServicerMockRecorder) GetUserPWResetByRefID(ctx, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPWResetByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserPWResetByRefID), ctx, refID)
+}
+
+// GetUserSessions mocks base method.
+func (m *MockServicer) GetUserSessions(ctx context.Context, userID int) ([]*model.UserSession, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSessions", ctx, userID)
+	ret0, _ := ret[0].([]*model.UserSession)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserSessions indicates an expected call of GetUserSessions.
+func (mr *MockServicerMockRecorder) GetUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSessions", reflect.TypeOf((*MockServicer)(nil).GetUserSessions), ctx, userID)
+}
+
+// GetUserVerifyByRefID mocks base method.
+func (m *MockServicer) GetUserVerifyByRefID(ctx context.Context, refID model.UserVerifyRefID) (*model.UserVerify, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserVerifyByRefID", ctx, refID)
+	ret0, _ := ret[0].(*model.UserVerify)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetUserVerifyByRefID indicates an expected call of GetUserVerifyByRefID.
+func (mr *MockServicerMockRecorder) GetUserVerifyByRefID(ctx, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVerifyByRefID", reflect.TypeOf((*MockServicer)(nil).GetUserVerifyByRefID), ctx, refID)
+}
+
+// GetUsersByIDs mocks base method.
 func (m *MockServicer) GetUsersByIDs(ctx context.Context, userIDs []int) ([]*model.User, errs.Error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUsersByIDs", ctx, userIDs)
@@ -777,34 +1277,108 @@ func (mr *MockServicerMockRecorder) GetUsersByIDs(ctx, userIDs any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockServicer)(nil).GetUsersByIDs), ctx, userIDs)
 }
 
-// NewApiKey mocks base method.
-func (m *MockServicer) NewApiKey(ctx context.Context, userID int) (*model.ApiKey, errs.Error) {
+// GetWebhookAttempts mocks base method.
+func (m *MockServicer) GetWebhookAttempts(ctx context.Context, userID int, refID model.WebhookEndpointRefID, limit int) ([]*model.WebhookAttempt, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewApiKey", ctx, userID)
-	ret0, _ := ret[0].(*model.ApiKey)
+	ret := m.ctrl.Call(m, "GetWebhookAttempts", ctx, userID, refID, limit)
+	ret0, _ := ret[0].([]*model.WebhookAttempt)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// NewApiKey indicates an expected call of NewApiKey.
-func (mr *MockServicerMockRecorder) NewApiKey(ctx, userID any) *gomock.Call {
+// GetWebhookAttempts indicates an expected call of GetWebhookAttempts.
+func (mr *MockServicerMockRecorder) GetWebhookAttempts(ctx, userID, refID, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewApiKey", reflect.TypeOf((*MockServicer)(nil).NewApiKey), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookAttempts", reflect.TypeOf((*MockServicer)(nil).GetWebhookAttempts), ctx, userID, refID, limit)
 }
 
-// NewApiKeyIfNotExists mocks base method.
-func (m *MockServicer) NewApiKeyIfNotExists(ctx context.Context, userID int) (*model.ApiKey, errs.Error) {
+// GetWebhookEndpoint mocks base method.
+func (m *MockServicer) GetWebhookEndpoint(ctx context.Context, userID int, refID model.WebhookEndpointRefID) (*model.WebhookEndpoint, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewApiKeyIfNotExists", ctx, userID)
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", ctx, userID, refID)
+	ret0, _ := ret[0].(*model.WebhookEndpoint)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockServicerMockRecorder) GetWebhookEndpoint(ctx, userID, refID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockServicer)(nil).GetWebhookEndpoint), ctx, userID, refID)
+}
+
+// GetWebhookEndpoints mocks base method.
+func (m *MockServicer) GetWebhookEndpoints(ctx context.Context, userID int) ([]*model.WebhookEndpoint, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoints", ctx, userID)
+	ret0, _ := ret[0].([]*model.WebhookEndpoint)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoints indicates an expected call of GetWebhookEndpoints.
+func (mr *MockServicerMockRecorder) GetWebhookEndpoints(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoints", reflect.TypeOf((*MockServicer)(nil).GetWebhookEndpoints), ctx, userID)
+}
+
+// IsTOTPEnabled mocks base method.
+func (m *MockServicer) IsTOTPEnabled(ctx context.Context, userID int) (bool, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTOTPEnabled", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// IsTOTPEnabled indicates an expected call of IsTOTPEnabled.
+func (mr *MockServicerMockRecorder) IsTOTPEnabled(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTOTPEnabled", reflect.TypeOf((*MockServicer)(nil).IsTOTPEnabled), ctx, userID)
+}
+
+// LinkExternalIdentity mocks base method.
+func (m *MockServicer) LinkExternalIdentity(ctx context.Context, userID int, ident *service.ExternalIdentity) (*model.UserIdentity, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkExternalIdentity", ctx, userID, ident)
+	ret0, _ := ret[0].(*model.UserIdentity)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// LinkExternalIdentity indicates an expected call of LinkExternalIdentity.
+func (mr *MockServicerMockRecorder) LinkExternalIdentity(ctx, userID, ident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkExternalIdentity", reflect.TypeOf((*MockServicer)(nil).LinkExternalIdentity), ctx, userID, ident)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockServicer) MarkAllNotificationsRead(ctx context.Context, userID int) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockServicerMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockServicer)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// NewApiKey mocks base method.
+func (m *MockServicer) NewApiKey(ctx context.Context, userID int, name string, scopes []string, expires *time.Time) (*model.ApiKey, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewApiKey", ctx, userID, name, scopes, expires)
 	ret0, _ := ret[0].(*model.ApiKey)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
-// NewApiKeyIfNotExists indicates an expected call of NewApiKeyIfNotExists.
-func (mr *MockServicerMockRecorder) NewApiKeyIfNotExists(ctx, userID any) *gomock.Call {
+// NewApiKey indicates an expected call of NewApiKey.
+func (mr *MockServicerMockRecorder) NewApiKey(ctx, userID, name, scopes, expires any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewApiKeyIfNotExists", reflect.TypeOf((*MockServicer)(nil).NewApiKeyIfNotExists), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewApiKey", reflect.TypeOf((*MockServicer)(nil).NewApiKey), ctx, userID, name, scopes, expires)
 }
 
 // NewEarmark mocks base method.
@@ -837,6 +1411,21 @@ func (mr *MockServicerMockRecorder) NewNotification(ctx, userID, message any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewNotification", reflect.TypeOf((*MockServicer)(nil).NewNotification), ctx, userID, message)
 }
 
+// NewTypedNotification mocks base method.
+func (m *MockServicer) NewTypedNotification(ctx context.Context, userID int, kind model.NotificationKind, payload model.NotificationPayload) (*model.Notification, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTypedNotification", ctx, userID, kind, payload)
+	ret0, _ := ret[0].(*model.Notification)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// NewTypedNotification indicates an expected call of NewTypedNotification.
+func (mr *MockServicerMockRecorder) NewTypedNotification(ctx, userID, kind, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTypedNotification", reflect.TypeOf((*MockServicer)(nil).NewTypedNotification), ctx, userID, kind, payload)
+}
+
 // NewUser mocks base method.
 func (m *MockServicer) NewUser(ctx context.Context, email, name string, rawPass []byte) (*model.User, errs.Error) {
 	m.ctrl.T.Helper()
@@ -867,6 +1456,36 @@ func (mr *MockServicerMockRecorder) NewUserCredential(ctx, userID, keyName, cred
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUserCredential", reflect.TypeOf((*MockServicer)(nil).NewUserCredential), ctx, userID, keyName, credential)
 }
 
+// NewUserEmailChange mocks base method.
+func (m *MockServicer) NewUserEmailChange(ctx context.Context, user *model.User, newEmail string) (*model.UserEmailChange, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewUserEmailChange", ctx, user, newEmail)
+	ret0, _ := ret[0].(*model.UserEmailChange)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// NewUserEmailChange indicates an expected call of NewUserEmailChange.
+func (mr *MockServicerMockRecorder) NewUserEmailChange(ctx, user, newEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUserEmailChange", reflect.TypeOf((*MockServicer)(nil).NewUserEmailChange), ctx, user, newEmail)
+}
+
+// NewUserLoginLink mocks base method.
+func (m *MockServicer) NewUserLoginLink(ctx context.Context, userID int) (*model.UserLoginLink, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewUserLoginLink", ctx, userID)
+	ret0, _ := ret[0].(*model.UserLoginLink)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// NewUserLoginLink indicates an expected call of NewUserLoginLink.
+func (mr *MockServicerMockRecorder) NewUserLoginLink(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUserLoginLink", reflect.TypeOf((*MockServicer)(nil).NewUserLoginLink), ctx, userID)
+}
+
 // NewUserPWReset mocks base method.
 func (m *MockServicer) NewUserPWReset(ctx context.Context, userID int) (*model.UserPWReset, errs.Error) {
 	m.ctrl.T.Helper()
@@ -882,6 +1501,21 @@ func (mr *MockServicerMockRecorder) NewUserPWReset(ctx, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUserPWReset", reflect.TypeOf((*MockServicer)(nil).NewUserPWReset), ctx, userID)
 }
 
+// NewUserSession mocks base method.
+func (m *MockServicer) NewUserSession(ctx context.Context, userID int, ipAddress, userAgent string, expires time.Time) (*model.UserSession, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewUserSession", ctx, userID, ipAddress, userAgent, expires)
+	ret0, _ := ret[0].(*model.UserSession)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// NewUserSession indicates an expected call of NewUserSession.
+func (mr *MockServicerMockRecorder) NewUserSession(ctx, userID, ipAddress, userAgent, expires any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUserSession", reflect.TypeOf((*MockServicer)(nil).NewUserSession), ctx, userID, ipAddress, userAgent, expires)
+}
+
 // NewUserVerify mocks base method.
 func (m *MockServicer) NewUserVerify(ctx context.Context, userID int) (*model.UserVerify, errs.Error) {
 	m.ctrl.T.Helper()
@@ -897,6 +1531,21 @@ func (mr *MockServicerMockRecorder) NewUserVerify(ctx, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUserVerify", reflect.TypeOf((*MockServicer)(nil).NewUserVerify), ctx, userID)
 }
 
+// NewWebhookEndpoint mocks base method.
+func (m *MockServicer) NewWebhookEndpoint(ctx context.Context, userID int, endpointURL, secret string, eventTypes []string) (*model.WebhookEndpoint, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewWebhookEndpoint", ctx, userID, endpointURL, secret, eventTypes)
+	ret0, _ := ret[0].(*model.WebhookEndpoint)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// NewWebhookEndpoint indicates an expected call of NewWebhookEndpoint.
+func (mr *MockServicerMockRecorder) NewWebhookEndpoint(ctx, userID, endpointURL, secret, eventTypes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWebhookEndpoint", reflect.TypeOf((*MockServicer)(nil).NewWebhookEndpoint), ctx, userID, endpointURL, secret, eventTypes)
+}
+
 // NotifyUsersPendingEvents mocks base method.
 func (m *MockServicer) NotifyUsersPendingEvents(ctx context.Context, mailer mail.MailSender, tplContainer resources.TGetter, siteBaseUrl string) error {
 	m.ctrl.T.Helper()
@@ -911,6 +1560,93 @@ func (mr *MockServicerMockRecorder) NotifyUsersPendingEvents(ctx, mailer, tplCon
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyUsersPendingEvents", reflect.TypeOf((*MockServicer)(nil).NotifyUsersPendingEvents), ctx, mailer, tplContainer, siteBaseUrl)
 }
 
+// ProcessUserDataExports mocks base method.
+func (m *MockServicer) ProcessUserDataExports(ctx context.Context, mailer mail.MailSender, tplContainer resources.TGetter, siteBaseUrl string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessUserDataExports", ctx, mailer, tplContainer, siteBaseUrl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessUserDataExports indicates an expected call of ProcessUserDataExports.
+func (mr *MockServicerMockRecorder) ProcessUserDataExports(ctx, mailer, tplContainer, siteBaseUrl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessUserDataExports", reflect.TypeOf((*MockServicer)(nil).ProcessUserDataExports), ctx, mailer, tplContainer, siteBaseUrl)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockServicer) RecordLoginFailure(ctx context.Context, userID int, method model.LoginMethod, ipAddress, userAgent string) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", ctx, userID, method, ipAddress, userAgent)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockServicerMockRecorder) RecordLoginFailure(ctx, userID, method, ipAddress, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockServicer)(nil).RecordLoginFailure), ctx, userID, method, ipAddress, userAgent)
+}
+
+// RecordLoginSuccess mocks base method.
+func (m *MockServicer) RecordLoginSuccess(ctx context.Context, userID int, method model.LoginMethod, ipAddress, userAgent string) (bool, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginSuccess", ctx, userID, method, ipAddress, userAgent)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// RecordLoginSuccess indicates an expected call of RecordLoginSuccess.
+func (mr *MockServicerMockRecorder) RecordLoginSuccess(ctx, userID, method, ipAddress, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginSuccess", reflect.TypeOf((*MockServicer)(nil).RecordLoginSuccess), ctx, userID, method, ipAddress, userAgent)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockServicer) RegenerateRecoveryCodes(ctx context.Context, userID int) ([]string, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockServicerMockRecorder) RegenerateRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockServicer)(nil).RegenerateRecoveryCodes), ctx, userID)
+}
+
+// RehashApiKeys mocks base method.
+func (m *MockServicer) RehashApiKeys(ctx context.Context) (int, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RehashApiKeys", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// RehashApiKeys indicates an expected call of RehashApiKeys.
+func (mr *MockServicerMockRecorder) RehashApiKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RehashApiKeys", reflect.TypeOf((*MockServicer)(nil).RehashApiKeys), ctx)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockServicer) ReleaseIdempotencyKey(ctx context.Context, userID int, key string) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, userID, key)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockServicerMockRecorder) ReleaseIdempotencyKey(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockServicer)(nil).ReleaseIdempotencyKey), ctx, userID, key)
+}
+
 // RemoveEventItem mocks base method.
 func (m *MockServicer) RemoveEventItem(ctx context.Context, userID int, eventItemRefID model.EventItemRefID, failIfChecks service.FailIfCheckFunc[*model.EventItem]) errs.Error {
 	m.ctrl.T.Helper()
@@ -939,6 +1675,95 @@ func (mr *MockServicerMockRecorder) RemoveFavorite(ctx, userID, refID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFavorite", reflect.TypeOf((*MockServicer)(nil).RemoveFavorite), ctx, userID, refID)
 }
 
+// RequestUserDataExport mocks base method.
+func (m *MockServicer) RequestUserDataExport(ctx context.Context, userID int) (*model.UserDataExport, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestUserDataExport", ctx, userID)
+	ret0, _ := ret[0].(*model.UserDataExport)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// RequestUserDataExport indicates an expected call of RequestUserDataExport.
+func (mr *MockServicerMockRecorder) RequestUserDataExport(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestUserDataExport", reflect.TypeOf((*MockServicer)(nil).RequestUserDataExport), ctx, userID)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockServicer) ReserveIdempotencyKey(ctx context.Context, userID int, key, procedure string, requestHash []byte) (*model.IdempotencyKey, bool, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, userID, key, procedure, requestHash)
+	ret0, _ := ret[0].(*model.IdempotencyKey)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(errs.Error)
+	return ret0, ret1, ret2
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockServicerMockRecorder) ReserveIdempotencyKey(ctx, userID, key, procedure, requestHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockServicer)(nil).ReserveIdempotencyKey), ctx, userID, key, procedure, requestHash)
+}
+
+// ResolveExternalIdentity mocks base method.
+func (m *MockServicer) ResolveExternalIdentity(ctx context.Context, ident *service.ExternalIdentity) (*model.User, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveExternalIdentity", ctx, ident)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// ResolveExternalIdentity indicates an expected call of ResolveExternalIdentity.
+func (mr *MockServicerMockRecorder) ResolveExternalIdentity(ctx, ident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveExternalIdentity", reflect.TypeOf((*MockServicer)(nil).ResolveExternalIdentity), ctx, ident)
+}
+
+// RevertUserEmailChange mocks base method.
+func (m *MockServicer) RevertUserEmailChange(ctx context.Context, change *model.UserEmailChange) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertUserEmailChange", ctx, change)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// RevertUserEmailChange indicates an expected call of RevertUserEmailChange.
+func (mr *MockServicerMockRecorder) RevertUserEmailChange(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertUserEmailChange", reflect.TypeOf((*MockServicer)(nil).RevertUserEmailChange), ctx, change)
+}
+
+// ScheduleUserDeletion mocks base method.
+func (m *MockServicer) ScheduleUserDeletion(ctx context.Context, userID int, transfers []*service.EventTransfer) (*model.UserDeletion, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleUserDeletion", ctx, userID, transfers)
+	ret0, _ := ret[0].(*model.UserDeletion)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// ScheduleUserDeletion indicates an expected call of ScheduleUserDeletion.
+func (mr *MockServicerMockRecorder) ScheduleUserDeletion(ctx, userID, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleUserDeletion", reflect.TypeOf((*MockServicer)(nil).ScheduleUserDeletion), ctx, userID, transfers)
+}
+
+// SendUserDigests mocks base method.
+func (m *MockServicer) SendUserDigests(ctx context.Context, mailer mail.MailSender, tplContainer resources.TGetter, siteBaseUrl string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendUserDigests", ctx, mailer, tplContainer, siteBaseUrl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendUserDigests indicates an expected call of SendUserDigests.
+func (mr *MockServicerMockRecorder) SendUserDigests(ctx, mailer, tplContainer, siteBaseUrl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendUserDigests", reflect.TypeOf((*MockServicer)(nil).SendUserDigests), ctx, mailer, tplContainer, siteBaseUrl)
+}
+
 // SetUserVerified mocks base method.
 func (m *MockServicer) SetUserVerified(ctx context.Context, user *model.User, verifier *model.UserVerify) errs.Error {
 	m.ctrl.T.Helper()
@@ -953,6 +1778,33 @@ func (mr *MockServicerMockRecorder) SetUserVerified(ctx, user, verifier any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserVerified", reflect.TypeOf((*MockServicer)(nil).SetUserVerified), ctx, user, verifier)
 }
 
+// StartTOTPEnrollment mocks base method.
+func (m *MockServicer) StartTOTPEnrollment(ctx context.Context, user *model.User) (*service.TOTPEnrollment, errs.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTOTPEnrollment", ctx, user)
+	ret0, _ := ret[0].(*service.TOTPEnrollment)
+	ret1, _ := ret[1].(errs.Error)
+	return ret0, ret1
+}
+
+// StartTOTPEnrollment indicates an expected call of StartTOTPEnrollment.
+func (mr *MockServicerMockRecorder) StartTOTPEnrollment(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTOTPEnrollment", reflect.TypeOf((*MockServicer)(nil).StartTOTPEnrollment), ctx, user)
+}
+
+// TouchApiKey mocks base method.
+func (m *MockServicer) TouchApiKey(ctx context.Context, apiKeyID int, remoteIP string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TouchApiKey", ctx, apiKeyID, remoteIP)
+}
+
+// TouchApiKey indicates an expected call of TouchApiKey.
+func (mr *MockServicerMockRecorder) TouchApiKey(ctx, apiKeyID, remoteIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchApiKey", reflect.TypeOf((*MockServicer)(nil).TouchApiKey), ctx, apiKeyID, remoteIP)
+}
+
 // UpdateEvent mocks base method.
 func (m *MockServicer) UpdateEvent(ctx context.Context, userID int, refID model.EventRefID, euvs *service.EventUpdateValues) errs.Error {
 	m.ctrl.T.Helper()
@@ -968,18 +1820,18 @@ func (mr *MockServicerMockRecorder) UpdateEvent(ctx, userID, refID, euvs any) *g
 }
 
 // UpdateEventItem mocks base method.
-func (m *MockServicer) UpdateEventItem(ctx context.Context, userID int, refID model.EventItemRefID, description string, failIfChecks service.FailIfCheckFunc[*model.EventItem]) (*model.EventItem, errs.Error) {
+func (m *MockServicer) UpdateEventItem(ctx context.Context, userID int, refID model.EventItemRefID, description string, version mo.Option[string], failIfChecks service.FailIfCheckFunc[*model.EventItem]) (*model.EventItem, errs.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEventItem", ctx, userID, refID, description, failIfChecks)
+	ret := m.ctrl.Call(m, "UpdateEventItem", ctx, userID, refID, description, version, failIfChecks)
 	ret0, _ := ret[0].(*model.EventItem)
 	ret1, _ := ret[1].(errs.Error)
 	return ret0, ret1
 }
 
 // UpdateEventItem indicates an expected call of UpdateEventItem.
-func (mr *MockServicerMockRecorder) UpdateEventItem(ctx, userID, refID, description, failIfChecks any) *gomock.Call {
+func (mr *MockServicerMockRecorder) UpdateEventItem(ctx, userID, refID, description, version, failIfChecks any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventItem", reflect.TypeOf((*MockServicer)(nil).UpdateEventItem), ctx, userID, refID, description, failIfChecks)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventItem", reflect.TypeOf((*MockServicer)(nil).UpdateEventItem), ctx, userID, refID, description, version, failIfChecks)
 }
 
 // UpdateEventItemSorting mocks base method.
@@ -997,6 +1849,20 @@ func (mr *MockServicerMockRecorder) UpdateEventItemSorting(ctx, userID, refID, i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventItemSorting", reflect.TypeOf((*MockServicer)(nil).UpdateEventItemSorting), ctx, userID, refID, itemSortOrder)
 }
 
+// UpdateNotificationRead mocks base method.
+func (m *MockServicer) UpdateNotificationRead(ctx context.Context, userID int, refID model.NotificationRefID, read bool) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationRead", ctx, userID, refID, read)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// UpdateNotificationRead indicates an expected call of UpdateNotificationRead.
+func (mr *MockServicerMockRecorder) UpdateNotificationRead(ctx, userID, refID, read any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationRead", reflect.TypeOf((*MockServicer)(nil).UpdateNotificationRead), ctx, userID, refID, read)
+}
+
 // UpdateUser mocks base method.
 func (m *MockServicer) UpdateUser(ctx context.Context, user *model.User, euvs *service.UserUpdateValues) errs.Error {
 	m.ctrl.T.Helper()
@@ -1039,6 +1905,34 @@ func (mr *MockServicerMockRecorder) UpdateUserSettings(ctx, userID, pm any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSettings", reflect.TypeOf((*MockServicer)(nil).UpdateUserSettings), ctx, userID, pm)
 }
 
+// UpdateWebhookEndpoint mocks base method.
+func (m *MockServicer) UpdateWebhookEndpoint(ctx context.Context, userID int, refID model.WebhookEndpointRefID, wuvs *service.WebhookEndpointUpdateValues) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookEndpoint", ctx, userID, refID, wuvs)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// UpdateWebhookEndpoint indicates an expected call of UpdateWebhookEndpoint.
+func (mr *MockServicerMockRecorder) UpdateWebhookEndpoint(ctx, userID, refID, wuvs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookEndpoint", reflect.TypeOf((*MockServicer)(nil).UpdateWebhookEndpoint), ctx, userID, refID, wuvs)
+}
+
+// VerifyTOTP mocks base method.
+func (m *MockServicer) VerifyTOTP(ctx context.Context, userID int, code string) errs.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTOTP", ctx, userID, code)
+	ret0, _ := ret[0].(errs.Error)
+	return ret0
+}
+
+// VerifyTOTP indicates an expected call of VerifyTOTP.
+func (mr *MockServicerMockRecorder) VerifyTOTP(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTOTP", reflect.TypeOf((*MockServicer)(nil).VerifyTOTP), ctx, userID, code)
+}
+
 // WebAuthnUserFrom mocks base method.
 func (m *MockServicer) WebAuthnUserFrom(user *model.User) *service.WebAuthnUser {
 	m.ctrl.T.Helper()
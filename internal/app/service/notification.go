@@ -6,6 +6,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/dropwhile/refid/v2/reftag"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	"github.com/dropwhile/icanbringthat/internal/validate"
 )
 
@@ -32,10 +34,26 @@ func (s *Service) GetNotificationsCount(
 	return notifCount, nil
 }
 
+func (s *Service) GetNotificationsUnreadCount(
+	ctx context.Context, userID int,
+) (int, errs.Error) {
+	notifCount, err := model.GetNotificationUnreadCountByUser(ctx, s.Db, userID)
+	if err != nil {
+		return 0, errs.Internal.Error("db error")
+	}
+	return notifCount, nil
+}
+
 func (s *Service) GetNotificationsPaginated(
-	ctx context.Context, userID int, limit, offset int,
+	ctx context.Context, userID int, limit, offset int, unreadOnly bool,
 ) ([]*model.Notification, *Pagination, errs.Error) {
-	notifCount, errx := s.GetNotificationsCount(ctx, userID)
+	var notifCount int
+	var errx errs.Error
+	if unreadOnly {
+		notifCount, errx = s.GetNotificationsUnreadCount(ctx, userID)
+	} else {
+		notifCount, errx = s.GetNotificationsCount(ctx, userID)
+	}
 	if errx != nil {
 		slog.
 			With("error", errx).
@@ -46,7 +64,7 @@ func (s *Service) GetNotificationsPaginated(
 	notifications := []*model.Notification{}
 	if notifCount > 0 {
 		notifs, err := model.GetNotificationsByUserPaginated(
-			ctx, s.Db, userID, limit, offset)
+			ctx, s.Db, userID, limit, offset, unreadOnly)
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			notifs = []*model.Notification{}
@@ -62,14 +80,65 @@ func (s *Service) GetNotificationsPaginated(
 		Limit:  limit,
 		Offset: offset,
 		Count:  notifCount,
+		NextCursor: nextPageCursor(
+			notifications, offset+len(notifications) < notifCount,
+			notificationPageCursor),
+	}
+	return notifications, pagination, nil
+}
+
+func (s *Service) GetNotificationsPaginatedAfter(
+	ctx context.Context, userID int, limit int, cursor *PageCursor, unreadOnly bool,
+) ([]*model.Notification, *Pagination, errs.Error) {
+	var notifCount int
+	var errx errs.Error
+	if unreadOnly {
+		notifCount, errx = s.GetNotificationsUnreadCount(ctx, userID)
+	} else {
+		notifCount, errx = s.GetNotificationsCount(ctx, userID)
+	}
+	if errx != nil {
+		slog.
+			With("error", errx).
+			Info("db error")
+		return nil, nil, errs.Internal.Error("db error")
+	}
+
+	notifications := []*model.Notification{}
+	if notifCount > 0 {
+		notifs, err := model.GetNotificationsByUserKeyset(
+			ctx, s.Db, userID, limit+1, cursor.Time, cursor.ID, unreadOnly)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			notifs = []*model.Notification{}
+		case err != nil:
+			slog.
+				With("error", err).
+				Info("db error")
+			return nil, nil, errs.Internal.Error("db error")
+		}
+		notifications = notifs
+	}
+	more := len(notifications) > limit
+	if more {
+		notifications = notifications[:limit]
+	}
+	pagination := &Pagination{
+		Limit:      limit,
+		Count:      notifCount,
+		NextCursor: nextPageCursor(notifications, more, notificationPageCursor),
 	}
 	return notifications, pagination, nil
 }
 
+func notificationPageCursor(notification *model.Notification) *PageCursor {
+	return &PageCursor{Time: notification.Created, ID: notification.ID}
+}
+
 func (s *Service) GetNotifications(
-	ctx context.Context, userID int,
+	ctx context.Context, userID int, unreadOnly bool,
 ) ([]*model.Notification, errs.Error) {
-	notifications, err := model.GetNotificationsByUser(ctx, s.Db, userID)
+	notifications, err := model.GetNotificationsByUser(ctx, s.Db, userID, unreadOnly)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
 		return []*model.Notification{}, nil
@@ -102,6 +171,62 @@ func (s *Service) DeleteNotification(
 	if err != nil {
 		return errs.Internal.Errorf("db error: %w", err)
 	}
+	s.publishNotificationChange(ctx, userID,
+		pubsub.ChangeNotificationDeleted, notification.RefID.String())
+	return nil
+}
+
+func (s *Service) UpdateNotificationRead(
+	ctx context.Context, userID int, refID model.NotificationRefID, read bool,
+) errs.Error {
+	if userID == 0 {
+		return errs.Unauthenticated.Error("invalid credentials")
+	}
+
+	notification, err := model.GetNotificationByRefID(ctx, s.Db, refID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return errs.NotFound.Error("notification not found")
+	case err != nil:
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+
+	if userID != notification.UserID {
+		return errs.PermissionDenied.Error("permission denied")
+	}
+
+	// nothing to do
+	if notification.Read == read {
+		return nil
+	}
+
+	err = model.UpdateNotification(ctx, s.Db, notification.ID, read)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	change := pubsub.ChangeNotificationUnread
+	if read {
+		change = pubsub.ChangeNotificationRead
+	}
+	s.publishNotificationChange(ctx, userID,
+		change, notification.RefID.String())
+	return nil
+}
+
+func (s *Service) MarkAllNotificationsRead(
+	ctx context.Context, userID int,
+) errs.Error {
+	if userID == 0 {
+		return errs.Unauthenticated.Error("invalid credentials")
+	}
+
+	err := model.UpdateNotificationsReadByUser(ctx, s.Db, userID)
+	if err != nil {
+		return errs.Internal.Error("db error")
+	}
+
+	s.publishNotificationChange(ctx, userID,
+		pubsub.ChangeNotificationsRead, "")
 	return nil
 }
 
@@ -117,18 +242,59 @@ func (s *Service) DeleteAllNotifications(
 		return errs.Internal.Error("db error")
 	}
 
+	s.publishNotificationChange(ctx, userID,
+		pubsub.ChangeNotificationsDeleted, "")
 	return nil
 }
 
 func (s *Service) NewNotification(
 	ctx context.Context, userID int, message string,
 ) (*model.Notification, errs.Error) {
-	return s.newNotification(ctx, s.Db, userID, message)
+	notification, errx := s.newNotification(ctx, s.Db, userID,
+		model.NotificationKindMessage, message, model.NotificationPayload{})
+	if errx != nil {
+		return nil, errx
+	}
+	s.publishNotificationCreated(ctx, notification)
+	return notification, nil
+}
+
+func (s *Service) NewTypedNotification(
+	ctx context.Context, userID int,
+	kind model.NotificationKind, payload model.NotificationPayload,
+) (*model.Notification, errs.Error) {
+	notification, errx := s.newTypedNotification(ctx, s.Db, userID, kind, payload)
+	if errx != nil {
+		return nil, errx
+	}
+	s.publishNotificationCreated(ctx, notification)
+	return notification, nil
+}
+
+func (s *Service) newTypedNotification(
+	ctx context.Context, db model.PgxHandle, userID int,
+	kind model.NotificationKind, payload model.NotificationPayload,
+) (*model.Notification, errs.Error) {
+	return s.newNotification(ctx, db, userID,
+		kind, notificationMessage(kind, payload), payload)
 }
 
+// newNotification creates the notification, but does not publish it. db
+// may be a transaction, so callers publish with publishNotificationCreated
+// once the notification is committed.
 func (s *Service) newNotification(
-	ctx context.Context, db model.PgxHandle, userID int, message string,
+	ctx context.Context, db model.PgxHandle, userID int,
+	kind model.NotificationKind, message string,
+	payload model.NotificationPayload,
 ) (*model.Notification, errs.Error) {
+	if !kind.Valid() {
+		slog.
+			With("field", "kind").
+			With("kind", kind).
+			Info("bad field value")
+		return nil, errs.ArgumentError("kind", "bad value")
+	}
+
 	err := validate.Validate.VarCtx(ctx, message, "required,notblank")
 	if err != nil {
 		slog.
@@ -138,7 +304,8 @@ func (s *Service) newNotification(
 		return nil, errs.ArgumentError("message", "bad value")
 	}
 
-	notification, err := model.NewNotification(ctx, db, userID, message)
+	notification, err := model.NewNotification(
+		ctx, db, userID, kind, message, payload)
 	if err != nil {
 		slog.
 			With("error", err).
@@ -147,3 +314,30 @@ func (s *Service) newNotification(
 	}
 	return notification, nil
 }
+
+// notificationMessage builds the plain text form of a typed notification.
+// It is stored alongside the payload, and used wherever a structured
+// rendering is not available (emails, api clients, etc).
+func notificationMessage(
+	kind model.NotificationKind, payload model.NotificationPayload,
+) string {
+	switch kind {
+	case model.NotificationKindAccountUnverified:
+		return "Account is not currently verified. Please verify account in Account Settings."
+	case model.NotificationKindRemindersDisabled:
+		return fmt.Sprintf("Email notifications disabled due to '%s'.", payload.Reason)
+	case model.NotificationKindEarmarkClaimed:
+		return fmt.Sprintf("%s earmarked '%s' in event '%s'.",
+			payload.UserName, payload.EventItemDescription, payload.EventName)
+	case model.NotificationKindEventChanged:
+		return fmt.Sprintf("The start time of event '%s' has changed.",
+			payload.EventName)
+	case model.NotificationKindNewSignIn:
+		return fmt.Sprintf("New sign-in to your account from %s (%s).",
+			payload.IPAddress, payload.UserAgent)
+	case model.NotificationKindEventTransferred:
+		return fmt.Sprintf("%s deleted their account, and handed event '%s' over to you.",
+			payload.UserName, payload.EventName)
+	}
+	return ""
+}
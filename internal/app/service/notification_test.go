@@ -77,9 +77,10 @@ func TestService_GetNotificationsPaginated(t *testing.T) {
 			)
 		mock.ExpectQuery(`SELECT [*] FROM notification_`).
 			WithArgs(pgx.NamedArgs{
-				"userID": userID,
-				"limit":  limit,
-				"offset": offset,
+				"userID":     userID,
+				"limit":      limit,
+				"offset":     offset,
+				"unreadOnly": false,
 			}).
 			WillReturnRows(pgxmock.NewRows(
 				[]string{
@@ -95,7 +96,7 @@ func TestService_GetNotificationsPaginated(t *testing.T) {
 				),
 			)
 
-		notifications, pagination, err := svc.GetNotificationsPaginated(ctx, userID, limit, offset)
+		notifications, pagination, err := svc.GetNotificationsPaginated(ctx, userID, limit, offset, false)
 		assert.Nil(t, err)
 		assert.Equal(t, len(notifications), count)
 		assert.Equal(t, pagination.Limit, limit)
@@ -123,7 +124,7 @@ func TestService_GetNotificationsPaginated(t *testing.T) {
 				AddRow(0),
 			)
 
-		notifications, pagination, err := svc.GetNotificationsPaginated(ctx, userID, limit, offset)
+		notifications, pagination, err := svc.GetNotificationsPaginated(ctx, userID, limit, offset, false)
 		assert.Nil(t, err)
 		assert.Equal(t, len(notifications), 0)
 		assert.Equal(t, pagination.Limit, limit)
@@ -135,6 +136,59 @@ func TestService_GetNotificationsPaginated(t *testing.T) {
 	})
 }
 
+func TestService_GetNotificationsPaginatedAfter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get unread with results should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		userID := 4
+		limit := 5
+		cursor := &PageCursor{Time: tstTs, ID: 3}
+
+		mock.ExpectQuery("SELECT count(.+) FROM notification_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"count"}).
+				AddRow(4),
+			)
+		mock.ExpectQuery(`SELECT [*] FROM notification_`).
+			WithArgs(pgx.NamedArgs{
+				"userID":     userID,
+				"limit":      limit + 1,
+				"afterTime":  cursor.Time,
+				"afterID":    cursor.ID,
+				"unreadOnly": true,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "read", "message",
+				}).
+				AddRow(
+					2, util.Must(model.NewNotificationRefID()), userID,
+					false, "some message 2",
+				).
+				AddRow(
+					1, util.Must(model.NewNotificationRefID()), userID,
+					false, "some message 1",
+				),
+			)
+
+		notifications, pagination, err := svc.GetNotificationsPaginatedAfter(ctx, userID, limit, cursor, true)
+		assert.Nil(t, err)
+		assert.Equal(t, len(notifications), 2)
+		assert.Equal(t, pagination.Limit, limit)
+		assert.Equal(t, pagination.Count, 4)
+		assert.Equal(t, pagination.NextCursor, "")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
 func TestService_GetNotifications(t *testing.T) {
 	t.Parallel()
 
@@ -159,7 +213,8 @@ func TestService_GetNotifications(t *testing.T) {
 
 		mock.ExpectQuery(`SELECT [*] FROM notification_`).
 			WithArgs(pgx.NamedArgs{
-				"userID": user.ID,
+				"userID":     user.ID,
+				"unreadOnly": false,
 			}).
 			WillReturnRows(pgxmock.NewRows(
 				[]string{
@@ -175,7 +230,7 @@ func TestService_GetNotifications(t *testing.T) {
 				),
 			)
 
-		notifications, err := svc.GetNotifications(ctx, user.ID)
+		notifications, err := svc.GetNotifications(ctx, user.ID, false)
 		assert.Nil(t, err)
 		assert.Equal(t, len(notifications), count)
 		// we make sure that all expectations were met
@@ -191,11 +246,12 @@ func TestService_GetNotifications(t *testing.T) {
 
 		mock.ExpectQuery(`SELECT [*] FROM notification_`).
 			WithArgs(pgx.NamedArgs{
-				"userID": user.ID,
+				"userID":     user.ID,
+				"unreadOnly": false,
 			}).
 			WillReturnError(pgx.ErrNoRows)
 
-		notifications, err := svc.GetNotifications(ctx, user.ID)
+		notifications, err := svc.GetNotifications(ctx, user.ID, false)
 		assert.Nil(t, err)
 		assert.Equal(t, len(notifications), 0)
 		// we make sure that all expectations were met
@@ -376,7 +432,9 @@ func TestService_NewNotification(t *testing.T) {
 			WithArgs(pgx.NamedArgs{
 				"refID":   NotificationRefIDMatcher,
 				"userID":  user.ID,
+				"kind":    model.NotificationKindMessage,
 				"message": notification.Message,
+				"payload": model.NotificationPayload{},
 			}).
 			WillReturnRows(pgxmock.NewRows(
 				[]string{
@@ -415,3 +473,160 @@ func TestService_NewNotification(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 }
+
+func TestService_UpdateNotificationRead(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	notification := &model.Notification{
+		ID:      2,
+		RefID:   util.Must(model.NewNotificationRefID()),
+		UserID:  user.ID,
+		Message: "message",
+		Read:    false,
+	}
+
+	t.Run("mark read should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery(`SELECT [*] FROM notification_`).
+			WithArgs(notification.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "read", "message",
+				}).
+				AddRow(
+					notification.ID, notification.RefID,
+					notification.UserID, notification.Read,
+					notification.Message,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE notification_").
+			WithArgs(pgx.NamedArgs{
+				"read":           true,
+				"notificationID": notification.ID,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.UpdateNotificationRead(ctx, user.ID, notification.RefID, true)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("mark unread when already unread should be a no-op", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery(`SELECT [*] FROM notification_`).
+			WithArgs(notification.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "read", "message",
+				}).
+				AddRow(
+					notification.ID, notification.RefID,
+					notification.UserID, notification.Read,
+					notification.Message,
+				),
+			)
+
+		err := svc.UpdateNotificationRead(ctx, user.ID, notification.RefID, false)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("mark read with different user owner should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery(`SELECT [*] FROM notification_`).
+			WithArgs(notification.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "read", "message",
+				}).
+				AddRow(
+					notification.ID, notification.RefID,
+					33, notification.Read,
+					notification.Message,
+				),
+			)
+
+		err := svc.UpdateNotificationRead(ctx, user.ID, notification.RefID, true)
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("mark read with missing notification should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery(`SELECT [*] FROM notification_`).
+			WithArgs(notification.RefID).
+			WillReturnError(pgx.ErrNoRows)
+
+		err := svc.UpdateNotificationRead(ctx, user.ID, notification.RefID, true)
+		errs.AssertError(t, err, errs.NotFound, "notification not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_MarkAllNotificationsRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mark all read should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		userID := 1
+
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE notification_").
+			WithArgs(pgx.NamedArgs{"userID": userID}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.MarkAllNotificationsRead(ctx, userID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
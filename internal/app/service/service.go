@@ -5,20 +5,74 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/crypto"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 )
 
 type FailIfCheckFunc[T any] func(T) bool
 
 type Pagination struct {
-	Limit  int
-	Offset int
-	Count  int
+	Limit      int
+	Offset     int
+	Count      int
+	NextCursor string
+}
+
+// PageCursor is a keyset position in a paginated listing: the sort time
+// and id of the last row of the previous page.
+type PageCursor struct {
+	Time time.Time
+	ID   int
+}
+
+// String encodes the cursor as an opaque url-safe token.
+func (c *PageCursor) String() string {
+	v := strconv.FormatInt(c.Time.UnixMicro(), 36) + "." + strconv.FormatInt(int64(c.ID), 36)
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
+
+func ParsePageCursor(s string) (*PageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("bad cursor encoding: %w", err)
+	}
+	ts, id, ok := strings.Cut(string(b), ".")
+	if !ok {
+		return nil, errors.New("bad cursor format")
+	}
+	micros, err := strconv.ParseInt(ts, 36, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bad cursor time: %w", err)
+	}
+	cursorID, err := strconv.ParseInt(id, 36, 0)
+	if err != nil || cursorID < 0 {
+		return nil, errors.New("bad cursor id")
+	}
+	return &PageCursor{
+		Time: time.UnixMicro(micros).UTC(),
+		ID:   int(cursorID),
+	}, nil
+}
+
+// nextPageCursor returns the cursor following the last of items, or an
+// empty string if there are no more results.
+func nextPageCursor[T any](items []T, more bool, key func(T) *PageCursor) string {
+	if !more || len(items) == 0 {
+		return ""
+	}
+	return key(items[len(items)-1]).String()
 }
 
 func TxnFunc(ctx context.Context, db model.PgxHandle,
@@ -51,12 +105,66 @@ func ParseTimeZone(tz string) (*model.TimeZone, error) {
 //go:generate tool mockgen -source servicer_iface.go -destination mockservice/servicer_mock.go -package mockservice
 type Service struct {
 	Db model.PgxHandle
+	// Broker is optional. When set, changes are published to it
+	// for real-time delivery to connected clients.
+	Broker pubsub.Broker
+	// mac is used to hash api key tokens
+	mac crypto.HMACer
+	// secrets encrypts secrets stored at rest, such as totp seeds
+	secrets crypto.SecretBoxer
 }
 
 type Options struct {
-	Db model.PgxHandle
+	Db           model.PgxHandle
+	Broker       pubsub.Broker
+	HMACKeyBytes []byte
 }
 
 func New(opts Options) *Service {
-	return &Service{Db: opts.Db}
+	return &Service{
+		Db:      opts.Db,
+		Broker:  opts.Broker,
+		mac:     crypto.NewMAC(opts.HMACKeyBytes),
+		secrets: crypto.NewSecretBox(opts.HMACKeyBytes),
+	}
+}
+
+func (s *Service) publish(ctx context.Context, topic string, msg *pubsub.Message) {
+	if s.Broker == nil {
+		return
+	}
+	if err := s.Broker.Publish(ctx, topic, msg); err != nil {
+		slog.ErrorContext(ctx, "error publishing message",
+			"topic", topic, "error", err)
+	}
+}
+
+// eventChanged fans an event change out to live subscribers, and queues
+// it for delivery to the event owner's webhooks.
+func (s *Service) eventChanged(ctx context.Context,
+	event *model.Event, change string, refID fmt.Stringer,
+) {
+	s.publish(ctx, pubsub.EventTopic(event.ID), &pubsub.Message{
+		Type:   pubsub.TypeEventUpdated,
+		Change: change,
+		RefID:  refID.String(),
+	})
+	s.enqueueWebhooks(ctx, event, change, refID.String())
+}
+
+func (s *Service) publishNotificationCreated(ctx context.Context,
+	notification *model.Notification,
+) {
+	s.publishNotificationChange(ctx, notification.UserID,
+		pubsub.ChangeNotificationCreated, notification.RefID.String())
+}
+
+func (s *Service) publishNotificationChange(ctx context.Context,
+	userID int, change string, refID string,
+) {
+	s.publish(ctx, pubsub.UserTopic(userID), &pubsub.Message{
+		Type:   pubsub.TypeNotifCount,
+		Change: change,
+		RefID:  refID,
+	})
 }
@@ -72,3 +72,24 @@ func TestMain(m *testing.M) {
 	logger.SetupLogging(logger.NewTestLogger, nil)
 	m.Run()
 }
+
+func TestPageCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cursor should round trip", func(t *testing.T) {
+		t.Parallel()
+		cursor := &PageCursor{Time: tstTs.Add(123 * time.Microsecond), ID: 42}
+		parsed, err := ParsePageCursor(cursor.String())
+		assert.Nil(t, err)
+		assert.True(t, parsed.Time.Equal(cursor.Time))
+		assert.Equal(t, parsed.ID, cursor.ID)
+	})
+
+	t.Run("bad cursor should fail", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{"", "!!!", "aG9kb3I", "Xy4x", "MS4tMQ"} {
+			_, err := ParsePageCursor(s)
+			assert.True(t, err != nil, "expected error for "+s)
+		}
+	})
+}
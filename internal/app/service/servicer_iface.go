@@ -4,8 +4,11 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"time"
 
+	"github.com/samber/mo"
+
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/app/resources"
 	"github.com/dropwhile/icanbringthat/internal/errs"
@@ -18,6 +21,7 @@ type Servicer interface {
 	GetEarmarkByEventItemID(ctx context.Context, eventItemID int) (*model.Earmark, errs.Error)
 	GetEarmarksCount(ctx context.Context, userID int) (*model.BifurcatedRowCounts, errs.Error)
 	GetEarmarksPaginated(ctx context.Context, userID int, limit, offset int, archived bool) ([]*model.Earmark, *Pagination, errs.Error)
+	GetEarmarksPaginatedAfter(ctx context.Context, userID int, limit int, cursor *PageCursor, archived bool) ([]*model.Earmark, *Pagination, errs.Error)
 	GetEarmarks(ctx context.Context, userID int, archived bool) ([]*model.Earmark, errs.Error)
 	NewEarmark(ctx context.Context, user *model.User, eventItemID int, note string) (*model.Earmark, errs.Error)
 	GetEarmark(ctx context.Context, refID model.EarmarkRefID) (*model.Earmark, errs.Error)
@@ -31,6 +35,7 @@ type Servicer interface {
 	UpdateEventItemSorting(ctx context.Context, userID int, refID model.EventRefID, itemSortOrder []int) (*model.Event, errs.Error)
 	CreateEvent(ctx context.Context, user *model.User, name string, description string, when time.Time, tz string) (*model.Event, errs.Error)
 	GetEventsPaginated(ctx context.Context, userID int, limit, offset int, archived bool) ([]*model.Event, *Pagination, errs.Error)
+	GetEventsPaginatedAfter(ctx context.Context, userID int, limit int, cursor *PageCursor, archived bool) ([]*model.Event, *Pagination, errs.Error)
 	GetEventsComingSoonPaginated(ctx context.Context, userID int, limit, offset int) ([]*model.Event, *Pagination, errs.Error)
 	GetEventsCount(ctx context.Context, userID int) (*model.BifurcatedRowCounts, errs.Error)
 	GetEvents(ctx context.Context, userID int, archived bool) ([]*model.Event, errs.Error)
@@ -43,19 +48,33 @@ type Servicer interface {
 	GetEventItemByID(ctx context.Context, eventItemID int) (*model.EventItem, errs.Error)
 	RemoveEventItem(ctx context.Context, userID int, eventItemRefID model.EventItemRefID, failIfChecks FailIfCheckFunc[*model.EventItem]) errs.Error
 	AddEventItem(ctx context.Context, userID int, refID model.EventRefID, description string) (*model.EventItem, errs.Error)
-	UpdateEventItem(ctx context.Context, userID int, refID model.EventItemRefID, description string, failIfChecks FailIfCheckFunc[*model.EventItem]) (*model.EventItem, errs.Error)
+	UpdateEventItem(ctx context.Context, userID int, refID model.EventItemRefID, description string, version mo.Option[string], failIfChecks FailIfCheckFunc[*model.EventItem]) (*model.EventItem, errs.Error)
 	AddFavorite(ctx context.Context, userID int, refID model.EventRefID) (*model.Event, errs.Error)
 	RemoveFavorite(ctx context.Context, userID int, refID model.EventRefID) errs.Error
 	GetFavoriteEventsPaginated(ctx context.Context, userID int, limit, offset int, archived bool) ([]*model.Event, *Pagination, errs.Error)
+	GetFavoriteEventsPaginatedAfter(ctx context.Context, userID int, limit int, cursor *PageCursor, archived bool) ([]*model.Event, *Pagination, errs.Error)
 	GetFavoriteEventsCount(ctx context.Context, userID int) (*model.BifurcatedRowCounts, errs.Error)
 	GetFavoriteEvents(ctx context.Context, userID int, archived bool) ([]*model.Event, errs.Error)
 	GetFavoriteByUserEvent(ctx context.Context, userID int, eventID int) (*model.Favorite, errs.Error)
+	ReserveIdempotencyKey(ctx context.Context, userID int, key, procedure string, requestHash []byte) (*model.IdempotencyKey, bool, errs.Error)
+	CompleteIdempotencyKey(ctx context.Context, userID int, key string, response []byte) errs.Error
+	ReleaseIdempotencyKey(ctx context.Context, userID int, key string) errs.Error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) error
+	CheckLoginAllowed(ctx context.Context, userID int) errs.Error
+	RecordLoginFailure(ctx context.Context, userID int, method model.LoginMethod, ipAddress, userAgent string) errs.Error
+	RecordLoginSuccess(ctx context.Context, userID int, method model.LoginMethod, ipAddress, userAgent string) (newSource bool, errx errs.Error)
+	DeleteOldLoginAttempts(ctx context.Context) error
 	GetNotificationsCount(ctx context.Context, userID int) (int, errs.Error)
-	GetNotificationsPaginated(ctx context.Context, userID int, limit, offset int) ([]*model.Notification, *Pagination, errs.Error)
-	GetNotifications(ctx context.Context, userID int) ([]*model.Notification, errs.Error)
+	GetNotificationsUnreadCount(ctx context.Context, userID int) (int, errs.Error)
+	GetNotificationsPaginated(ctx context.Context, userID int, limit, offset int, unreadOnly bool) ([]*model.Notification, *Pagination, errs.Error)
+	GetNotificationsPaginatedAfter(ctx context.Context, userID int, limit int, cursor *PageCursor, unreadOnly bool) ([]*model.Notification, *Pagination, errs.Error)
+	GetNotifications(ctx context.Context, userID int, unreadOnly bool) ([]*model.Notification, errs.Error)
 	DeleteNotification(ctx context.Context, userID int, refID model.NotificationRefID) errs.Error
+	UpdateNotificationRead(ctx context.Context, userID int, refID model.NotificationRefID, read bool) errs.Error
+	MarkAllNotificationsRead(ctx context.Context, userID int) errs.Error
 	DeleteAllNotifications(ctx context.Context, userID int) errs.Error
 	NewNotification(ctx context.Context, userID int, message string) (*model.Notification, errs.Error)
+	NewTypedNotification(ctx context.Context, userID int, kind model.NotificationKind, payload model.NotificationPayload) (*model.Notification, errs.Error)
 	GetUser(ctx context.Context, refID model.UserRefID) (*model.User, errs.Error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, errs.Error)
 	GetUserByID(ctx context.Context, ID int) (*model.User, errs.Error)
@@ -64,14 +83,52 @@ type Servicer interface {
 	UpdateUser(ctx context.Context, user *model.User, euvs *UserUpdateValues) errs.Error
 	UpdateUserSettings(ctx context.Context, userID int, pm *model.UserSettings) errs.Error
 	DeleteUser(ctx context.Context, userID int) errs.Error
-	GetApiKeyByUser(ctx context.Context, userID int) (*model.ApiKey, errs.Error)
-	GetUserByApiKey(ctx context.Context, token string) (*model.User, errs.Error)
-	NewApiKey(ctx context.Context, userID int) (*model.ApiKey, errs.Error)
-	NewApiKeyIfNotExists(ctx context.Context, userID int) (*model.ApiKey, errs.Error)
+	GetApiKeysByUser(ctx context.Context, userID int) ([]*model.ApiKey, errs.Error)
+	AuthenticateApiKey(ctx context.Context, token string) (*model.User, *model.ApiKey, errs.Error)
+	TouchApiKey(ctx context.Context, apiKeyID int, remoteIP string)
+	RehashApiKeys(ctx context.Context) (int, errs.Error)
+	NewApiKey(ctx context.Context, userID int, name string, scopes []string, expires *time.Time) (*model.ApiKey, errs.Error)
+	DeleteApiKey(ctx context.Context, userID int, prefix string) errs.Error
+	RequestUserDataExport(ctx context.Context, userID int) (*model.UserDataExport, errs.Error)
+	GetUserDataExport(ctx context.Context, userID int, refID model.UserDataExportRefID) (*model.UserDataExport, errs.Error)
+	ProcessUserDataExports(ctx context.Context, mailer mail.MailSender, tplContainer resources.TGetter, siteBaseUrl string) error
+	DeleteExpiredUserDataExports(ctx context.Context) error
+	GetEventTransferOptions(ctx context.Context, userID int) ([]*EventTransferOption, errs.Error)
+	ScheduleUserDeletion(ctx context.Context, userID int, transfers []*EventTransfer) (*model.UserDeletion, errs.Error)
+	GetUserDeletion(ctx context.Context, userID int) (*model.UserDeletion, errs.Error)
+	GetUserDeletionByRefID(ctx context.Context, refID model.UserDeletionRefID) (*model.UserDeletion, errs.Error)
+	CancelUserDeletion(ctx context.Context, deletion *model.UserDeletion) errs.Error
+	DeleteScheduledUsers(ctx context.Context) error
+	SendUserDigests(ctx context.Context, mailer mail.MailSender, tplContainer resources.TGetter, siteBaseUrl string) error
+	NewUserEmailChange(ctx context.Context, user *model.User, newEmail string) (*model.UserEmailChange, errs.Error)
+	GetUserEmailChangeByRefID(ctx context.Context, refID model.UserEmailChangeRefID) (*model.UserEmailChange, errs.Error)
+	GetPendingUserEmailChange(ctx context.Context, userID int) (*model.UserEmailChange, errs.Error)
+	ConfirmUserEmailChange(ctx context.Context, user *model.User, change *model.UserEmailChange) errs.Error
+	RevertUserEmailChange(ctx context.Context, change *model.UserEmailChange) errs.Error
 	NotifyUsersPendingEvents(ctx context.Context, mailer mail.MailSender, tplContainer resources.TGetter, siteBaseUrl string) error
+	GetUserIdentities(ctx context.Context, userID int) ([]*model.UserIdentity, errs.Error)
+	ResolveExternalIdentity(ctx context.Context, ident *ExternalIdentity) (*model.User, errs.Error)
+	LinkExternalIdentity(ctx context.Context, userID int, ident *ExternalIdentity) (*model.UserIdentity, errs.Error)
+	DeleteUserIdentity(ctx context.Context, user *model.User, refID model.UserIdentityRefID) errs.Error
+	GetUserLoginLinkByRefID(ctx context.Context, refID model.UserLoginLinkRefID) (*model.UserLoginLink, errs.Error)
+	NewUserLoginLink(ctx context.Context, userID int) (*model.UserLoginLink, errs.Error)
+	ConsumeUserLoginLink(ctx context.Context, refID model.UserLoginLinkRefID) (*model.UserLoginLink, errs.Error)
 	GetUserPWResetByRefID(ctx context.Context, refID model.UserPWResetRefID) (*model.UserPWReset, errs.Error)
 	NewUserPWReset(ctx context.Context, userID int) (*model.UserPWReset, errs.Error)
 	UpdateUserPWReset(ctx context.Context, user *model.User, upw *model.UserPWReset) errs.Error
+	NewUserSession(ctx context.Context, userID int, ipAddress, userAgent string, expires time.Time) (*model.UserSession, errs.Error)
+	GetUserSessions(ctx context.Context, userID int) ([]*model.UserSession, errs.Error)
+	CheckUserSession(ctx context.Context, userID int, refID model.UserSessionRefID) errs.Error
+	DeleteUserSession(ctx context.Context, userID int, refID model.UserSessionRefID) errs.Error
+	DeleteUserSessions(ctx context.Context, userID int) errs.Error
+	DeleteOtherUserSessions(ctx context.Context, userID int, keepRefID model.UserSessionRefID) errs.Error
+	StartTOTPEnrollment(ctx context.Context, user *model.User) (*TOTPEnrollment, errs.Error)
+	ConfirmTOTPEnrollment(ctx context.Context, userID int, code string) ([]string, errs.Error)
+	IsTOTPEnabled(ctx context.Context, userID int) (bool, errs.Error)
+	GetRecoveryCodesRemaining(ctx context.Context, userID int) (int, errs.Error)
+	VerifyTOTP(ctx context.Context, userID int, code string) errs.Error
+	DisableTOTP(ctx context.Context, userID int) errs.Error
+	RegenerateRecoveryCodes(ctx context.Context, userID int) ([]string, errs.Error)
 	GetUserVerifyByRefID(ctx context.Context, refID model.UserVerifyRefID) (*model.UserVerify, errs.Error)
 	NewUserVerify(ctx context.Context, userID int) (*model.UserVerify, errs.Error)
 	SetUserVerified(ctx context.Context, user *model.User, verifier *model.UserVerify) errs.Error
@@ -82,4 +139,11 @@ type Servicer interface {
 	NewUserCredential(ctx context.Context, userID int, keyName string, credential []byte) (*model.UserCredential, errs.Error)
 	WebAuthnUserFrom(user *model.User) *WebAuthnUser
 	DisableRemindersWithNotification(ctx context.Context, email string, suppressionReason string) errs.Error
+	DeliverWebhooks(ctx context.Context, client *http.Client) error
+	GetWebhookEndpoints(ctx context.Context, userID int) ([]*model.WebhookEndpoint, errs.Error)
+	GetWebhookEndpoint(ctx context.Context, userID int, refID model.WebhookEndpointRefID) (*model.WebhookEndpoint, errs.Error)
+	NewWebhookEndpoint(ctx context.Context, userID int, endpointURL, secret string, eventTypes []string) (*model.WebhookEndpoint, errs.Error)
+	UpdateWebhookEndpoint(ctx context.Context, userID int, refID model.WebhookEndpointRefID, wuvs *WebhookEndpointUpdateValues) errs.Error
+	DeleteWebhookEndpoint(ctx context.Context, userID int, refID model.WebhookEndpointRefID) errs.Error
+	GetWebhookAttempts(ctx context.Context, userID int, refID model.WebhookEndpointRefID, limit int) ([]*model.WebhookAttempt, errs.Error)
 }
@@ -5,8 +5,11 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -14,6 +17,7 @@ import (
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/validate"
 )
 
 var (
@@ -22,40 +26,173 @@ var (
 	ParseApiKeyRefID     = reftag.Parse[model.ApiKeyRefID]
 )
 
-func (s *Service) GetApiKeyByUser(
+const maxApiKeys = 20
+
+func (s *Service) GetApiKeysByUser(
 	ctx context.Context, userID int,
-) (*model.ApiKey, errs.Error) {
-	apiKey, err := model.GetApiKeyByUser(ctx, s.Db, userID)
+) ([]*model.ApiKey, errs.Error) {
+	apiKeys, err := model.GetApiKeysByUser(ctx, s.Db, userID)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
-		return nil, errs.NotFound.Error("user-api-key not found")
+		apiKeys = []*model.ApiKey{}
 	case err != nil:
 		slog.ErrorContext(ctx,
-			"error getting api key by user", "error", err)
+			"error getting api keys by user", "error", err)
 		return nil, errs.Internal.Error("db error")
 	}
-	return apiKey, nil
+	return apiKeys, nil
 }
 
-func (s *Service) GetUserByApiKey(
+// AuthenticateApiKey returns the user and api key for an api token.
+// Usage is not recorded here, as the caller may still reject the request;
+// see TouchApiKey.
+func (s *Service) AuthenticateApiKey(
 	ctx context.Context, token string,
-) (*model.User, errs.Error) {
-	user, err := model.GetUserByApiKey(ctx, s.Db, token)
+) (*model.User, *model.ApiKey, errs.Error) {
+	prefix, ok := model.ApiKeyTokenPrefix(token)
+	if !ok {
+		return nil, nil, errs.NotFound.Error("api key not found")
+	}
+
+	apiKey, err := model.GetApiKeyByPrefix(ctx, s.Db, prefix)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
-		return nil, errs.NotFound.Error("user not found")
+		return nil, nil, errs.NotFound.Error("api key not found")
 	case err != nil:
 		slog.ErrorContext(ctx,
-			"error getting user by key", "error", err)
-		return nil, errs.Internal.Error("db error")
+			"error getting api key by prefix", "error", err)
+		return nil, nil, errs.Internal.Error("db error")
+	}
+
+	if !s.checkApiKeyToken(ctx, apiKey, token) {
+		return nil, nil, errs.NotFound.Error("api key not found")
+	}
+
+	if apiKey.IsExpired() {
+		return nil, nil, errs.Unauthenticated.Error("api key expired")
+	}
+
+	user, errx := s.GetUserByID(ctx, apiKey.UserID)
+	if errx != nil {
+		return nil, nil, errx
+	}
+
+	// api access is suspended while the account is scheduled for deletion
+	_, err = model.GetUserDeletionByUser(ctx, s.Db, user.ID)
+	switch {
+	case err == nil:
+		return nil, nil, errs.Unauthenticated.Error("account scheduled for deletion")
+	case !errors.Is(err, pgx.ErrNoRows):
+		slog.ErrorContext(ctx,
+			"error getting user deletion", "error", err)
+		return nil, nil, errs.Internal.Error("db error")
+	}
+	return user, apiKey, nil
+}
+
+// TouchApiKey records the api key as used from remoteIP. It should only
+// be called once the request has been authorized.
+func (s *Service) TouchApiKey(
+	ctx context.Context, apiKeyID int, remoteIP string,
+) {
+	// best effort. failing to record usage shouldn't fail the request
+	if err := model.TouchApiKey(ctx, s.Db, apiKeyID, remoteIP); err != nil {
+		slog.ErrorContext(ctx,
+			"error recording api key usage", "error", err)
+	}
+}
+
+// checkApiKeyToken compares token against the stored token in constant
+// time. A key still storing a legacy plaintext token is rehashed on a
+// successful match.
+func (s *Service) checkApiKeyToken(
+	ctx context.Context, apiKey *model.ApiKey, token string,
+) bool {
+	switch {
+	case apiKey.TokenHash != nil:
+		return s.mac.Validate([]byte(token), apiKey.TokenHash)
+	case apiKey.LegacyToken != nil:
+		if subtle.ConstantTimeCompare([]byte(*apiKey.LegacyToken), []byte(token)) != 1 {
+			return false
+		}
+		// best effort. RehashApiKeys will pick up any failures
+		err := model.UpdateApiKeyTokenHash(ctx, s.Db, apiKey.ID,
+			model.HashApiKeyToken(s.mac, token))
+		if err != nil {
+			slog.ErrorContext(ctx,
+				"error rehashing api key token", "error", err)
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// RehashApiKeys replaces any legacy plaintext api key tokens with their
+// keyed hash, returning the number of keys rehashed.
+func (s *Service) RehashApiKeys(ctx context.Context) (int, errs.Error) {
+	apiKeys, err := model.GetApiKeysWithLegacyToken(ctx, s.Db)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return 0, nil
+	case err != nil:
+		return 0, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	count := 0
+	for _, apiKey := range apiKeys {
+		if apiKey.LegacyToken == nil {
+			continue
+		}
+		err := model.UpdateApiKeyTokenHash(ctx, s.Db, apiKey.ID,
+			model.HashApiKeyToken(s.mac, *apiKey.LegacyToken))
+		if err != nil {
+			return count, errs.Internal.Errorf("db error: %w", err)
+		}
+		count++
 	}
-	return user, nil
+	return count, nil
 }
 
 func (s *Service) NewApiKey(
 	ctx context.Context, userID int,
+	name string, scopes []string, expires *time.Time,
 ) (*model.ApiKey, errs.Error) {
-	apikey, err := model.NewApiKey(ctx, s.Db, userID)
+	err := validate.Validate.VarCtx(ctx, name, "notblank,max=255")
+	if err != nil {
+		slog.
+			With("field", "name").
+			With("error", err).
+			Info("bad field value")
+		return nil, errs.ArgumentError("name", "bad value")
+	}
+
+	cleaned := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(model.ApiKeyScopes, scope) {
+			return nil, errs.ArgumentError("scopes", "bad value")
+		}
+		if !slices.Contains(cleaned, scope) {
+			cleaned = append(cleaned, scope)
+		}
+	}
+	if len(cleaned) == 0 {
+		return nil, errs.ArgumentError("scopes", "at least one scope is required")
+	}
+
+	if expires != nil && !expires.After(time.Now()) {
+		return nil, errs.ArgumentError("expires", "must be in the future")
+	}
+
+	count, err := model.GetApiKeyCountByUser(ctx, s.Db, userID)
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	if count >= maxApiKeys {
+		return nil, errs.ResourceExhausted.Error("too many api keys")
+	}
+
+	apikey, err := model.NewApiKey(ctx, s.Db, s.mac, userID, name, cleaned, expires)
 	if err != nil {
 		slog.ErrorContext(ctx,
 			"error generating new api key", "error", err)
@@ -64,19 +201,23 @@ func (s *Service) NewApiKey(
 	return apikey, nil
 }
 
-func (s *Service) NewApiKeyIfNotExists(
-	ctx context.Context, userID int,
-) (*model.ApiKey, errs.Error) {
-	apikey, errx := s.GetApiKeyByUser(ctx, userID)
-	if errx == nil {
-		return apikey, nil
-	} else if errx.Code() != errs.NotFound {
-		return nil, errx
+func (s *Service) DeleteApiKey(
+	ctx context.Context, userID int, prefix string,
+) errs.Error {
+	apiKey, err := model.GetApiKeyByPrefix(ctx, s.Db, prefix)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return errs.NotFound.Error("api key not found")
+	case err != nil:
+		return errs.Internal.Errorf("db error: %w", err)
 	}
 
-	apikey, err := model.NewApiKey(ctx, s.Db, userID)
-	if err != nil {
-		return nil, errs.Internal.Errorf("db error: %w", err)
+	if apiKey.UserID != userID {
+		return errs.PermissionDenied.Error("permission denied")
 	}
-	return apikey, nil
+
+	if err := model.DeleteApiKey(ctx, s.Db, apiKey.ID); err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
 }
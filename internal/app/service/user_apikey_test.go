@@ -6,17 +6,23 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/dropwhile/assert"
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/crypto"
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/util"
 )
 
-func TestService_GetApiKeyByUser(t *testing.T) {
+func tokenHash(token string) []byte {
+	return model.HashApiKeyToken(crypto.NewMAC(nil), token)
+}
+
+func TestService_GetApiKeysByUser(t *testing.T) {
 	t.Parallel()
 
 	user := &model.User{
@@ -30,104 +36,252 @@ func TestService_GetApiKeyByUser(t *testing.T) {
 		LastModified: tstTs,
 	}
 
-	t.Run("get user apikey should succeed", func(t *testing.T) {
+	t.Run("get user apikeys should succeed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		token := "some-token"
-
 		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
 			WithArgs(user.ID).
 			WillReturnRows(pgxmock.NewRows(
-				[]string{"user_id", "token"}).
-				AddRow(user.ID, token),
+				[]string{"id", "user_id", "name", "prefix", "token_hash", "scopes"}).
+				AddRow(1, user.ID, "one", "prefix-1", []byte("hash-1"), []string{"read"}).
+				AddRow(2, user.ID, "two", "prefix-2", []byte("hash-2"), []string{"read"}),
 			)
 
-		result, err := svc.GetApiKeyByUser(ctx, user.ID)
+		result, err := svc.GetApiKeysByUser(ctx, user.ID)
 		assert.Nil(t, err)
-		assert.Equal(t, result.UserID, user.ID)
-		assert.Equal(t, result.Token, token)
+		assert.Equal(t, len(result), 2)
+		assert.Equal(t, result[1].Name, "two")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
 	})
+}
+
+func TestService_AuthenticateApiKey(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		ApiAccess:    true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
 
-	t.Run("get user apikey not found should fail", func(t *testing.T) {
+	t.Run("authenticate apikey should succeed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
+		token := "prefix:some-token"
+		expires := time.Now().Add(time.Hour)
+
 		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token_hash", "scopes", "expires"}).
+				AddRow(3, user.ID, "key", "prefix", tokenHash(token), []string{"read"}, &expires),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
 			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "verified", "api_access"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, user.ApiAccess),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(user.ID).
+			WillReturnError(pgx.ErrNoRows)
+		resultUser, resultKey, err := svc.AuthenticateApiKey(ctx, token)
+		assert.Nil(t, err)
+		assert.Equal(t, resultUser.RefID, user.RefID)
+		assert.Equal(t, resultKey.ID, 3)
+		assert.True(t, resultKey.HasScope(model.ApiKeyScopeRead))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("authenticate apikey not found should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		token := "prefix:some-token"
+
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
 			WillReturnError(pgx.ErrNoRows)
 
-		_, err := svc.GetApiKeyByUser(ctx, user.ID)
-		errs.AssertError(t, err, errs.NotFound, "user-api-key not found")
+		_, _, err := svc.AuthenticateApiKey(ctx, token)
+		errs.AssertError(t, err, errs.NotFound, "api key not found")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
 	})
-}
 
-func TestService_GetUserByApiKey(t *testing.T) {
-	t.Parallel()
+	t.Run("authenticate expired apikey should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
 
-	user := &model.User{
-		ID:           1,
-		RefID:        util.Must(model.NewUserRefID()),
-		Email:        "user@example.com",
-		Name:         "user",
-		PWHash:       []byte("00x00"),
-		Verified:     true,
-		Created:      tstTs,
-		LastModified: tstTs,
-	}
+		token := "prefix:some-token"
+		expires := time.Now().Add(-time.Hour)
 
-	t.Run("get user by apikey should succeed", func(t *testing.T) {
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token_hash", "scopes", "expires"}).
+				AddRow(3, user.ID, "key", "prefix", tokenHash(token), []string{"read"}, &expires),
+			)
+
+		_, _, err := svc.AuthenticateApiKey(ctx, token)
+		errs.AssertError(t, err, errs.Unauthenticated, "api key expired")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("authenticate apikey with wrong token should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token_hash", "scopes"}).
+				AddRow(3, user.ID, "key", "prefix", tokenHash("prefix:some-token"), []string{"read"}),
+			)
+
+		_, _, err := svc.AuthenticateApiKey(ctx, "prefix:other-token")
+		errs.AssertError(t, err, errs.NotFound, "api key not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("authenticate malformed apikey should fail", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		token := "some-token"
+		_, _, err := svc.AuthenticateApiKey(ctx, "some-token")
+		errs.AssertError(t, err, errs.NotFound, "api key not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
 
+	t.Run("authenticate apikey with deletion scheduled should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		token := "prefix:some-token"
+
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token_hash", "scopes"}).
+				AddRow(3, user.ID, "key", "prefix", tokenHash(token), []string{"read"}),
+			)
 		mock.ExpectQuery("^SELECT (.+) FROM user_").
-			WithArgs(token).
+			WithArgs(user.ID).
 			WillReturnRows(pgxmock.NewRows(
-				[]string{
-					"id", "ref_id", "email", "name", "verified",
-				}).
-				AddRow(
-					user.ID, user.RefID, user.Email, user.Name,
-					user.Verified,
-				),
+				[]string{"id", "ref_id", "email", "name", "verified", "api_access"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, user.ApiAccess),
 			)
-
-		result, err := svc.GetUserByApiKey(ctx, token)
-		assert.Nil(t, err)
-		assert.Equal(t, result.RefID, user.RefID)
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "scheduled"}).
+				AddRow(5, util.Must(model.NewUserDeletionRefID()), user.ID,
+					tstTs.Add(model.UserDeletionGracePeriod)),
+			)
+		_, _, err := svc.AuthenticateApiKey(ctx, token)
+		errs.AssertError(t, err, errs.Unauthenticated, "account scheduled for deletion")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
 	})
 
-	t.Run("get user by apikey not found should fail", func(t *testing.T) {
+	t.Run("authenticate legacy apikey should rehash and succeed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		token := "some-token"
+		token := "prefix:some-token"
 
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token", "scopes"}).
+				AddRow(3, user.ID, "key", "prefix", &token, []string{"read"}),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE api_key_").
+			WithArgs(pgx.NamedArgs{
+				"tokenHash": tokenHash(token),
+				"apiKeyID":  3,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
 		mock.ExpectQuery("^SELECT (.+) FROM user_").
-			WithArgs(token).
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "verified", "api_access"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, user.ApiAccess),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(user.ID).
 			WillReturnError(pgx.ErrNoRows)
+		resultUser, _, err := svc.AuthenticateApiKey(ctx, token)
+		assert.Nil(t, err)
+		assert.Equal(t, resultUser.RefID, user.RefID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
 
-		_, err := svc.GetUserByApiKey(ctx, token)
-		errs.AssertError(t, err, errs.NotFound, "user not found")
+func TestService_TouchApiKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("touch apikey should record usage", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE api_key_").
+			WithArgs(pgx.NamedArgs{
+				"remoteIP": "127.0.0.1",
+				"apiKeyID": 3,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		svc.TouchApiKey(ctx, 3, "127.0.0.1")
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
@@ -154,97 +308,212 @@ func TestService_NewApiKey(t *testing.T) {
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		token := "some-token"
+		scopes := []string{model.ApiKeyScopeRead, model.ApiKeyScopeEventsWrite}
 
+		mock.ExpectQuery("^SELECT count(.+) FROM api_key_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
 		mock.ExpectBegin()
 		mock.ExpectQuery("^INSERT INTO api_key_").
 			WithArgs(pgx.NamedArgs{
-				"userID": user.ID,
-				"token":  pgxmock.AnyArg(),
+				"userID":    user.ID,
+				"name":      "my key",
+				"prefix":    pgxmock.AnyArg(),
+				"tokenHash": pgxmock.AnyArg(),
+				"scopes":    scopes,
+				"expires":   (*time.Time)(nil),
 			}).
 			WillReturnRows(pgxmock.NewRows(
-				[]string{"user_id", "token"}).
-				AddRow(user.ID, token),
+				[]string{"id", "user_id", "name", "prefix", "token_hash", "scopes"}).
+				AddRow(1, user.ID, "my key", "prefix", []byte("hash"), scopes),
 			)
 		mock.ExpectCommit()
 		mock.ExpectRollback()
 
-		result, err := svc.NewApiKey(ctx, user.ID)
+		result, err := svc.NewApiKey(ctx, user.ID, "my key",
+			[]string{model.ApiKeyScopeRead, model.ApiKeyScopeEventsWrite, model.ApiKeyScopeRead},
+			nil,
+		)
 		assert.Nil(t, err)
-		assert.Equal(t, result.Token, token)
+		// plaintext token is only available on the newly created key
+		prefix, ok := model.ApiKeyTokenPrefix(result.Token)
+		assert.True(t, ok)
+		assert.True(t, len(prefix) > 0)
 		assert.Equal(t, result.UserID, user.ID)
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
 	})
+
+	t.Run("add user apikey with bad scope should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, err := svc.NewApiKey(ctx, user.ID, "my key", []string{"hodor"}, nil)
+		errs.AssertError(t, err, errs.InvalidArgument, "scopes bad value")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("add user apikey with no scopes should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, err := svc.NewApiKey(ctx, user.ID, "my key", nil, nil)
+		errs.AssertError(t, err, errs.InvalidArgument, "scopes at least one scope is required")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("add user apikey with blank name should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, err := svc.NewApiKey(ctx, user.ID, " ", []string{model.ApiKeyScopeRead}, nil)
+		errs.AssertError(t, err, errs.InvalidArgument, "name bad value")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("add user apikey with past expiry should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		expires := time.Now().Add(-time.Minute)
+		_, err := svc.NewApiKey(ctx, user.ID, "my key", []string{model.ApiKeyScopeRead}, &expires)
+		errs.AssertError(t, err, errs.InvalidArgument, "expires must be in the future")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("add user apikey over limit should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT count(.+) FROM api_key_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(maxApiKeys))
+
+		_, err := svc.NewApiKey(ctx, user.ID, "my key", []string{model.ApiKeyScopeRead}, nil)
+		errs.AssertError(t, err, errs.ResourceExhausted, "too many api keys")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
 }
 
-func TestService_NewApiKeyIfNotExists(t *testing.T) {
+func TestService_DeleteApiKey(t *testing.T) {
 	t.Parallel()
 
-	user := &model.User{
-		ID:           1,
-		RefID:        util.Must(model.NewUserRefID()),
-		Email:        "user@example.com",
-		Name:         "user",
-		PWHash:       []byte("00x00"),
-		Verified:     true,
-		Created:      tstTs,
-		LastModified: tstTs,
-	}
+	t.Run("delete user apikey should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
 
-	t.Run("add user apikey should succeed", func(t *testing.T) {
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix"}).
+				AddRow(3, 1, "key", "prefix"),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM api_key_").
+			WithArgs(3).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.DeleteApiKey(ctx, 1, "prefix")
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete other user apikey should fail", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		token := "some-token"
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs("prefix").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix"}).
+				AddRow(3, 2, "key", "prefix"),
+			)
+
+		err := svc.DeleteApiKey(ctx, 1, "prefix")
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_RehashApiKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rehash legacy apikeys should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		token := "prefix:some-token"
 
 		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
-			WithArgs(user.ID).
-			WillReturnError(pgx.ErrNoRows)
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token"}).
+				AddRow(3, 1, "key", "prefix", &token),
+			)
 		mock.ExpectBegin()
-		mock.ExpectQuery("^INSERT INTO api_key_").
+		mock.ExpectExec("^UPDATE api_key_").
 			WithArgs(pgx.NamedArgs{
-				"userID": user.ID,
-				"token":  pgxmock.AnyArg(),
+				"tokenHash": tokenHash(token),
+				"apiKeyID":  3,
 			}).
-			WillReturnRows(pgxmock.NewRows(
-				[]string{"user_id", "token"}).
-				AddRow(user.ID, token),
-			)
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 		mock.ExpectCommit()
 		mock.ExpectRollback()
 
-		result, err := svc.NewApiKeyIfNotExists(ctx, user.ID)
+		count, err := svc.RehashApiKeys(ctx)
 		assert.Nil(t, err)
-		assert.Equal(t, result.Token, token)
-		assert.Equal(t, result.UserID, user.ID)
+		assert.Equal(t, count, 1)
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
 	})
 
-	t.Run("add user apikey already exists should succeed", func(t *testing.T) {
+	t.Run("rehash with no legacy apikeys should succeed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		mock := SetupDBMock(t, ctx)
 		svc := New(Options{Db: mock})
 
-		token := "some-token"
-
 		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
-			WithArgs(user.ID).
 			WillReturnRows(pgxmock.NewRows(
-				[]string{"user_id", "token"}).
-				AddRow(user.ID, token),
+				[]string{"id", "user_id", "name", "prefix", "token"}),
 			)
 
-		result, err := svc.NewApiKeyIfNotExists(ctx, user.ID)
+		count, err := svc.RehashApiKeys(ctx)
 		assert.Nil(t, err)
-		assert.Equal(t, result.Token, token)
-		assert.Equal(t, result.UserID, user.ID)
+		assert.Equal(t, count, 0)
 		// we make sure that all expectations were met
 		assert.Nil(t, mock.ExpectationsWereMet(),
 			"there were unfulfilled expectations")
@@ -0,0 +1,435 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+	"github.com/k3a/html2text"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+)
+
+var (
+	UserDataExportRefIDMatcher = reftag.NewMatcher[model.UserDataExportRefID]()
+	ParseUserDataExportRefID   = reftag.Parse[model.UserDataExportRefID]
+)
+
+// download links are signed over a prefixed refid, so they can not be
+// confused with any other signed link
+const DataExportLinkPrefix = "export-"
+
+type exportProfile struct {
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"last_modified"`
+	RefID        string    `json:"ref_id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	Verified     bool      `json:"verified"`
+	PWAuth       bool      `json:"password_auth"`
+	ApiAccess    bool      `json:"api_access"`
+	WebAuthn     bool      `json:"webauthn"`
+}
+
+type exportEvent struct {
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"last_modified"`
+	StartTime    time.Time `json:"start_time"`
+	StartTimeTz  string    `json:"start_time_tz"`
+	RefID        string    `json:"ref_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Archived     bool      `json:"archived"`
+}
+
+type exportEventItem struct {
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"last_modified"`
+	RefID        string    `json:"ref_id"`
+	EventRefID   string    `json:"event_ref_id"`
+	Description  string    `json:"description"`
+}
+
+type exportEarmark struct {
+	Created        time.Time `json:"created"`
+	LastModified   time.Time `json:"last_modified"`
+	RefID          string    `json:"ref_id"`
+	EventItemRefID string    `json:"event_item_ref_id"`
+	Note           string    `json:"note"`
+}
+
+type exportFavorite struct {
+	EventRefID string `json:"event_ref_id"`
+	EventName  string `json:"event_name"`
+}
+
+type exportNotification struct {
+	Created time.Time                 `json:"created"`
+	Payload model.NotificationPayload `json:"payload"`
+	RefID   string                    `json:"ref_id"`
+	Kind    string                    `json:"kind"`
+	Message string                    `json:"message"`
+	Read    bool                      `json:"read"`
+}
+
+type exportApiKey struct {
+	Created    time.Time  `json:"created"`
+	Expires    *time.Time `json:"expires"`
+	LastUsed   *time.Time `json:"last_used"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	LastUsedIP string     `json:"last_used_ip"`
+	Scopes     []string   `json:"scopes"`
+}
+
+type exportCredential struct {
+	Created time.Time `json:"created"`
+	RefID   string    `json:"ref_id"`
+	KeyName string    `json:"key_name"`
+}
+
+// RequestUserDataExport queues an export of the user's data for the
+// worker. If an export is already queued, that one is returned instead.
+func (s *Service) RequestUserDataExport(
+	ctx context.Context, userID int,
+) (*model.UserDataExport, errs.Error) {
+	export, err := model.GetPendingUserDataExportByUser(ctx, s.Db, userID)
+	switch {
+	case err == nil:
+		return export, nil
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	export, err = model.NewUserDataExport(ctx, s.Db, userID)
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	return export, nil
+}
+
+// GetUserDataExport returns a completed, unexpired export owned by the
+// user.
+func (s *Service) GetUserDataExport(
+	ctx context.Context, userID int, refID model.UserDataExportRefID,
+) (*model.UserDataExport, errs.Error) {
+	export, err := model.GetUserDataExportByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("export not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	if export.UserID != userID {
+		return nil, errs.PermissionDenied.Error("permission denied")
+	}
+	if export.Completed == nil {
+		return nil, errs.FailedPrecondition.Error("export not ready")
+	}
+	if export.Completed.Add(model.UserDataExportExpiry).Before(time.Now()) {
+		return nil, errs.NotFound.Error("export expired")
+	}
+	return export, nil
+}
+
+// buildUserDataExport collects everything stored about the user into a
+// zip archive of json files. Secrets, such as password and api key
+// hashes, are left out.
+func (s *Service) buildUserDataExport(
+	ctx context.Context, user *model.User,
+) ([]byte, error) {
+	events, err := model.GetEventsByUser(ctx, s.Db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	exEvents := make([]*exportEvent, 0, len(events))
+	exItems := make([]*exportEventItem, 0)
+	for _, event := range events {
+		tz := ""
+		if event.StartTimeTz != nil {
+			tz = event.StartTimeTz.String()
+		}
+		exEvents = append(exEvents, &exportEvent{
+			Created:      event.Created,
+			LastModified: event.LastModified,
+			StartTime:    event.StartTime,
+			StartTimeTz:  tz,
+			RefID:        event.RefID.String(),
+			Name:         event.Name,
+			Description:  event.Description,
+			Archived:     event.Archived,
+		})
+		items, err := model.GetEventItemsByEvent(ctx, s.Db, event.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			exItems = append(exItems, &exportEventItem{
+				Created:      item.Created,
+				LastModified: item.LastModified,
+				RefID:        item.RefID.String(),
+				EventRefID:   event.RefID.String(),
+				Description:  item.Description,
+			})
+		}
+	}
+
+	earmarks, err := model.GetEarmarksByUser(ctx, s.Db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	exEarmarks := make([]*exportEarmark, 0, len(earmarks))
+	if len(earmarks) > 0 {
+		itemIDs := make([]int, 0, len(earmarks))
+		for _, em := range earmarks {
+			itemIDs = append(itemIDs, em.EventItemID)
+		}
+		items, err := model.GetEventItemsByIDs(ctx, s.Db, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+		itemRefIDs := make(map[int]string, len(items))
+		for _, item := range items {
+			itemRefIDs[item.ID] = item.RefID.String()
+		}
+		for _, em := range earmarks {
+			exEarmarks = append(exEarmarks, &exportEarmark{
+				Created:        em.Created,
+				LastModified:   em.LastModified,
+				RefID:          em.RefID.String(),
+				EventItemRefID: itemRefIDs[em.EventItemID],
+				Note:           em.Note,
+			})
+		}
+	}
+
+	favorites, err := model.GetFavoriteEventsByUser(ctx, s.Db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	exFavorites := make([]*exportFavorite, 0, len(favorites))
+	for _, event := range favorites {
+		exFavorites = append(exFavorites, &exportFavorite{
+			EventRefID: event.RefID.String(),
+			EventName:  event.Name,
+		})
+	}
+
+	notifications, err := model.GetNotificationsByUser(ctx, s.Db, user.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	exNotifications := make([]*exportNotification, 0, len(notifications))
+	for _, n := range notifications {
+		exNotifications = append(exNotifications, &exportNotification{
+			Created: n.Created,
+			Payload: n.Payload,
+			RefID:   n.RefID.String(),
+			Kind:    string(n.Kind),
+			Message: n.Message,
+			Read:    n.Read,
+		})
+	}
+
+	apiKeys, err := model.GetApiKeysByUser(ctx, s.Db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	exApiKeys := make([]*exportApiKey, 0, len(apiKeys))
+	for _, key := range apiKeys {
+		exApiKeys = append(exApiKeys, &exportApiKey{
+			Created:    key.Created,
+			Expires:    key.Expires,
+			LastUsed:   key.LastUsed,
+			Name:       key.Name,
+			Prefix:     key.Prefix,
+			LastUsedIP: key.LastUsedIP,
+			Scopes:     key.Scopes,
+		})
+	}
+
+	credentials, err := model.GetUserCredentialsByUser(ctx, s.Db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	exCredentials := make([]*exportCredential, 0, len(credentials))
+	for _, cred := range credentials {
+		exCredentials = append(exCredentials, &exportCredential{
+			Created: cred.Created,
+			RefID:   cred.RefID.String(),
+			KeyName: cred.KeyName,
+		})
+	}
+
+	files := []struct {
+		contents any
+		name     string
+	}{
+		{name: "profile.json", contents: &exportProfile{
+			Created:      user.Created,
+			LastModified: user.LastModified,
+			RefID:        user.RefID.String(),
+			Email:        user.Email,
+			Name:         user.Name,
+			Verified:     user.Verified,
+			PWAuth:       user.PWAuth,
+			ApiAccess:    user.ApiAccess,
+			WebAuthn:     user.WebAuthn,
+		}},
+		{name: "settings.json", contents: &user.Settings},
+		{name: "events.json", contents: exEvents},
+		{name: "event_items.json", contents: exItems},
+		{name: "earmarks.json", contents: exEarmarks},
+		{name: "favorites.json", contents: exFavorites},
+		{name: "notifications.json", contents: exNotifications},
+		{name: "api_keys.json", contents: exApiKeys},
+		{name: "webauthn_credentials.json", contents: exCredentials},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("zip error: %w", err)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.contents); err != nil {
+			return nil, fmt.Errorf("json encode error: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("zip error: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ProcessUserDataExports builds all pending exports, and emails each
+// user a signed link to download theirs. An export that fails is left
+// pending, to be retried on the next run.
+func (s *Service) ProcessUserDataExports(ctx context.Context,
+	mailer mail.MailSender, tplContainer resources.TGetter,
+	siteBaseUrl string,
+) error {
+	exports, err := model.GetPendingUserDataExports(ctx, s.Db)
+	if err != nil {
+		return err
+	}
+	if len(exports) == 0 {
+		return nil
+	}
+
+	tplHtml, err := tplContainer.Get("mail_data_export.gohtml")
+	if err != nil {
+		return fmt.Errorf("template get error: %w", err)
+	}
+
+	for _, export := range exports {
+		err := s.processUserDataExport(ctx, mailer, tplHtml, siteBaseUrl, export)
+		if err != nil {
+			slog.ErrorContext(ctx, "error processing data export",
+				slog.Int("exportID", export.ID),
+				logger.Err(err))
+			continue
+		}
+	}
+	return nil
+}
+
+// processUserDataExport builds a single export and emails the user the
+// link to it. The archive is stored before the email is sent, so the
+// link always works. The export is only marked emailed once the email
+// is sent, so a failed send is retried on its own, without rebuilding
+// the archive.
+func (s *Service) processUserDataExport(ctx context.Context,
+	mailer mail.MailSender, tplHtml resources.TExecuter,
+	siteBaseUrl string, export *model.UserDataExport,
+) error {
+	user, err := model.GetUserByID(ctx, s.Db, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	if export.Completed == nil {
+		data, err := s.buildUserDataExport(ctx, user)
+		if err != nil {
+			return fmt.Errorf("error building export: %w", err)
+		}
+		err = model.CompleteUserDataExport(ctx, s.Db, export.ID, data)
+		if err != nil {
+			return fmt.Errorf("error updating database: %w", err)
+		}
+	}
+
+	refIDStr := export.RefID.String()
+	macStr := encoder.Base32EncodeToString(
+		s.mac.Generate([]byte(DataExportLinkPrefix + refIDStr)))
+	downloadURL, err := url.JoinPath(
+		siteBaseUrl,
+		fmt.Sprintf("/settings/export/%s-%s", refIDStr, macStr),
+	)
+	if err != nil {
+		return fmt.Errorf("url path join error: %w", err)
+	}
+
+	subject := "Your data export is ready"
+	vars := map[string]any{
+		"Subject":     subject,
+		"DownloadUrl": downloadURL,
+		"ExpiryHours": int(model.UserDataExportExpiry.Hours()),
+	}
+
+	var bufHtml bytes.Buffer
+	err = tplHtml.Execute(&bufHtml, vars)
+	if err != nil {
+		return fmt.Errorf("html template exec error: %w", err)
+	}
+
+	messageHtml := bufHtml.String()
+	messagePlain := html2text.HTML2Text(messageHtml)
+
+	slog.DebugContext(ctx, "email content",
+		slog.String("plain", messagePlain),
+		slog.String("html", messageHtml),
+	)
+
+	err = mailer.Send("", []string{user.Email},
+		subject, messagePlain, messageHtml,
+		mail.MailHeader{
+			"X-PM-Message-Stream": "outbound",
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	err = model.MarkUserDataExportEmailed(ctx, s.Db, export.ID)
+	if err != nil {
+		return fmt.Errorf("error updating database: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) DeleteExpiredUserDataExports(ctx context.Context) error {
+	return model.DeleteExpiredUserDataExports(ctx, s.Db,
+		time.Now().UTC().Add(-model.UserDataExportExpiry))
+}
@@ -0,0 +1,540 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+// captureArg matches any value, and keeps it for later inspection
+type captureArg struct {
+	value any
+}
+
+func (c *captureArg) Match(v any) bool {
+	c.value = v
+	return true
+}
+
+func TestService_RequestUserDataExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("request should create export", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDataExportRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs(1).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_data_export_").
+			WithArgs(pgx.NamedArgs{
+				"refID":  pgxmock.AnyArg(),
+				"userID": 1,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "created"}).
+				AddRow(5, refID, 1, tstTs),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		export, err := svc.RequestUserDataExport(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, export.RefID, refID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("request with pending export should reuse it", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDataExportRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "created"}).
+				AddRow(5, refID, 1, tstTs),
+			)
+
+		export, err := svc.RequestUserDataExport(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, export.RefID, refID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_GetUserDataExport(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"id", "ref_id", "user_id", "data", "completed"}
+
+	t.Run("get completed export should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDataExportRefID())
+		completed := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 1, []byte("zip"), &completed),
+			)
+
+		export, err := svc.GetUserDataExport(ctx, 1, refID)
+		assert.Nil(t, err)
+		assert.Equal(t, export.Data, []byte("zip"))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("get export of other user should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDataExportRefID())
+		completed := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 2, []byte("zip"), &completed),
+			)
+
+		_, err := svc.GetUserDataExport(ctx, 1, refID)
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("get pending export should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDataExportRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 1, nil, nil),
+			)
+
+		_, err := svc.GetUserDataExport(ctx, 1, refID)
+		errs.AssertError(t, err, errs.FailedPrecondition, "export not ready")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("get expired export should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDataExportRefID())
+		completed := time.Now().UTC().Add(-2 * model.UserDataExportExpiry)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 1, []byte("zip"), &completed),
+			)
+
+		_, err := svc.GetUserDataExport(ctx, 1, refID)
+		errs.AssertError(t, err, errs.NotFound, "export expired")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_ProcessUserDataExports(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	event := &model.Event{
+		ID:          2,
+		RefID:       util.Must(model.NewEventRefID()),
+		UserID:      user.ID,
+		Name:        "event",
+		Description: "description",
+		StartTime:   tstTs,
+		StartTimeTz: util.Must(ParseTimeZone("Etc/UTC")),
+	}
+	eventItem := &model.EventItem{
+		ID:          3,
+		RefID:       util.Must(model.NewEventItemRefID()),
+		EventID:     event.ID,
+		Description: "item",
+	}
+	earmark := &model.Earmark{
+		ID:          4,
+		RefID:       util.Must(model.NewEarmarkRefID()),
+		EventItemID: eventItem.ID,
+		UserID:      user.ID,
+		Note:        "note",
+	}
+	templates := &resources.TemplateMap{
+		"mail_data_export.gohtml": util.Must(
+			template.New("mail_data_export.gohtml").
+				ParseFiles("../resources/templates/html/view/mail_data_export.gohtml"),
+		),
+	}
+
+	// the queries made to build the export for user
+	expectBuild := func(mock pgxmock.PgxConnIface) {
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "pwhash", "verified"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.PWHash, user.Verified),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"start_time", "start_time_tz",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.StartTime, event.StartTimeTz,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_item_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "event_id", "description"}).
+				AddRow(eventItem.ID, eventItem.RefID, eventItem.EventID,
+					eventItem.Description),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM earmark_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "event_item_id", "user_id", "note"}).
+				AddRow(earmark.ID, earmark.RefID, earmark.EventItemID,
+					earmark.UserID, earmark.Note),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_item_").
+			WithArgs([]int{eventItem.ID}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "event_id", "description"}).
+				AddRow(eventItem.ID, eventItem.RefID, eventItem.EventID,
+					eventItem.Description),
+			)
+		mock.ExpectQuery("^SELECT event_.(.+) FROM event_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name"}))
+		mock.ExpectQuery("^SELECT (.+) FROM notification_").
+			WithArgs(pgx.NamedArgs{"userID": user.ID, "unreadOnly": false}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "message"}))
+		mock.ExpectQuery("^SELECT (.+) FROM api_key_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "user_id", "name", "prefix", "token_hash"}).
+				AddRow(6, user.ID, "key", "abc", []byte("secret-hash")),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_webauthn_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "key_name"}))
+	}
+
+	// the statements made to mark an export emailed
+	expectEmailed := func(mock pgxmock.PgxConnIface, exportID int) {
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_data_export_ SET emailed").
+			WithArgs(exportID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+	}
+
+	t.Run("process should build export and send link", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		refID := util.Must(model.NewUserDataExportRefID())
+		data := &captureArg{}
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, user.ID),
+			)
+		expectBuild(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_data_export_").
+			WithArgs(pgx.NamedArgs{
+				"exportID": 5,
+				"data":     data,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		expectEmailed(mock, 5)
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Your data export is ready",
+				gomock.Cond(func(x string) bool {
+					return strings.Contains(x,
+						"http://example.org/settings/export/"+refID.String()+"-")
+				}),
+				gomock.AssignableToTypeOf("string"),
+				mail.MailHeader{
+					"X-PM-Message-Stream": "outbound",
+				},
+			).
+			Return(nil)
+
+		err := svc.ProcessUserDataExports(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+
+		// check the archive contents
+		zipBytes := data.value.([]byte)
+		zr, zerr := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+		assert.Nil(t, zerr)
+		files := map[string][]byte{}
+		for _, f := range zr.File {
+			rc := util.Must(f.Open())
+			files[f.Name] = util.Must(io.ReadAll(rc))
+			rc.Close()
+		}
+		assert.Equal(t, len(files), 9)
+
+		var profile map[string]any
+		assert.Nil(t, json.Unmarshal(files["profile.json"], &profile))
+		assert.Equal(t, profile["email"], any(user.Email))
+		assert.Equal(t, bytes.Contains(files["profile.json"], user.PWHash), false)
+
+		var earmarks []map[string]any
+		assert.Nil(t, json.Unmarshal(files["earmarks.json"], &earmarks))
+		assert.Equal(t, len(earmarks), 1)
+		assert.Equal(t, earmarks[0]["event_item_ref_id"], any(eventItem.RefID.String()))
+
+		assert.True(t, bytes.Contains(files["event_items.json"], []byte(event.RefID.String())))
+		assert.Equal(t, bytes.Contains(files["api_keys.json"], []byte("secret-hash")), false)
+	})
+
+	t.Run("process with failed email should store export and leave it pending", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		refID := util.Must(model.NewUserDataExportRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, user.ID),
+			)
+		expectBuild(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_data_export_").
+			WithArgs(pgx.NamedArgs{
+				"exportID": 5,
+				"data":     pgxmock.AnyArg(),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Your data export is ready",
+				gomock.Any(), gomock.Any(), gomock.Any(),
+			).
+			Return(errors.New("mail error"))
+
+		// export is not marked emailed
+		err := svc.ProcessUserDataExports(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("process of a stored export should only send link", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		refID := util.Must(model.NewUserDataExportRefID())
+		completed := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "completed"}).
+				AddRow(5, refID, user.ID, &completed),
+			)
+		// the archive is not rebuilt
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "pwhash", "verified"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.PWHash, user.Verified),
+			)
+		expectEmailed(mock, 5)
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Your data export is ready",
+				gomock.Cond(func(x string) bool {
+					return strings.Contains(x,
+						"http://example.org/settings/export/"+refID.String()+"-")
+				}),
+				gomock.AssignableToTypeOf("string"),
+				mail.MailHeader{
+					"X-PM-Message-Stream": "outbound",
+				},
+			).
+			Return(nil)
+
+		err := svc.ProcessUserDataExports(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("process should continue past a failed export", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, util.Must(model.NewUserDataExportRefID()), 7).
+				AddRow(6, util.Must(model.NewUserDataExportRefID()), user.ID),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(7).
+			WillReturnError(pgx.ErrNoRows)
+		expectBuild(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_data_export_").
+			WithArgs(pgx.NamedArgs{
+				"exportID": 6,
+				"data":     pgxmock.AnyArg(),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		expectEmailed(mock, 6)
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Your data export is ready",
+				gomock.Any(), gomock.Any(), gomock.Any(),
+			).
+			Return(nil)
+
+		err := svc.ProcessUserDataExports(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("process with nothing pending should do nothing", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_data_export_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}))
+
+		err := svc.ProcessUserDataExports(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
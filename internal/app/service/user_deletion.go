@@ -0,0 +1,270 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+)
+
+var (
+	UserDeletionRefIDMatcher = reftag.NewMatcher[model.UserDeletionRefID]()
+	ParseUserDeletionRefID   = reftag.Parse[model.UserDeletionRefID]
+)
+
+// EventTransfer requests that an event be handed over to another user,
+// when the account of its owner is deleted.
+type EventTransfer struct {
+	EventRefID model.EventRefID
+	UserRefID  model.UserRefID
+}
+
+// EventTransferOption is an upcoming event, along with the guests it can
+// be handed over to.
+type EventTransferOption struct {
+	Event  *model.Event
+	Guests []*model.User
+}
+
+// GetEventTransferOptions returns the upcoming events of the user that
+// have guests, who could take the event over if the user deletes their
+// account.
+func (s *Service) GetEventTransferOptions(
+	ctx context.Context, userID int,
+) ([]*EventTransferOption, errs.Error) {
+	events, err := model.GetEventsUpcomingByUser(ctx, s.Db, userID)
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	options := make([]*EventTransferOption, 0, len(events))
+	for _, event := range events {
+		guests, err := model.GetUsersEarmarkingEvent(ctx, s.Db, event.ID)
+		if err != nil {
+			return nil, errs.Internal.Errorf("db error: %w", err)
+		}
+		if len(guests) == 0 {
+			continue
+		}
+		options = append(options, &EventTransferOption{
+			Event:  event,
+			Guests: guests,
+		})
+	}
+	return options, nil
+}
+
+// ScheduleUserDeletion schedules the account of the user for deletion
+// once the grace period is over. The requested events are handed over
+// to their new owners at that time, instead of being deleted along with
+// the account. Every session of the user is logged out.
+func (s *Service) ScheduleUserDeletion(
+	ctx context.Context, userID int, transfers []*EventTransfer,
+) (*model.UserDeletion, errs.Error) {
+	_, err := model.GetUserDeletionByUser(ctx, s.Db, userID)
+	switch {
+	case err == nil:
+		return nil, errs.AlreadyExists.Error("deletion already scheduled")
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	// resolve transfers up front, so a bad request schedules nothing
+	type resolved struct {
+		eventID int
+		userID  int
+	}
+	resolvedTransfers := make([]resolved, 0, len(transfers))
+	for _, transfer := range transfers {
+		event, err := model.GetEventByRefID(ctx, s.Db, transfer.EventRefID)
+		if err != nil {
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				return nil, errs.NotFound.Error("event not found")
+			default:
+				return nil, errs.Internal.Errorf("db error: %w", err)
+			}
+		}
+		if event.UserID != userID {
+			return nil, errs.PermissionDenied.Error("permission denied")
+		}
+		if event.Archived {
+			return nil, errs.ArgumentError("transfer", "event is archived")
+		}
+		// only upcoming events are offered for transfer
+		if !event.StartTime.After(time.Now()) {
+			return nil, errs.ArgumentError("transfer", "event has already started")
+		}
+		guests, err := model.GetUsersEarmarkingEvent(ctx, s.Db, event.ID)
+		if err != nil {
+			return nil, errs.Internal.Errorf("db error: %w", err)
+		}
+		newOwnerID := 0
+		for _, guest := range guests {
+			if guest.RefID == transfer.UserRefID {
+				newOwnerID = guest.ID
+				break
+			}
+		}
+		if newOwnerID == 0 {
+			return nil, errs.ArgumentError("transfer", "user is not a guest of the event")
+		}
+		resolvedTransfers = append(resolvedTransfers, resolved{
+			eventID: event.ID,
+			userID:  newOwnerID,
+		})
+	}
+
+	var deletion *model.UserDeletion
+	errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		var innerErr error
+		deletion, innerErr = model.NewUserDeletion(ctx, tx, userID,
+			time.Now().UTC().Add(model.UserDeletionGracePeriod))
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error scheduling deletion",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		for _, transfer := range resolvedTransfers {
+			_, innerErr = model.NewUserDeletionTransfer(ctx, tx,
+				deletion.ID, transfer.eventID, transfer.userID)
+			if innerErr != nil {
+				slog.DebugContext(ctx, "inner db error saving event transfer",
+					logger.Err(innerErr))
+				return innerErr
+			}
+		}
+		innerErr = model.DeleteUserSessionsByUser(ctx, tx, userID)
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error deleting sessions",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		return nil
+	})
+	if errx != nil {
+		return nil, errx
+	}
+	return deletion, nil
+}
+
+func (s *Service) GetUserDeletion(
+	ctx context.Context, userID int,
+) (*model.UserDeletion, errs.Error) {
+	deletion, err := model.GetUserDeletionByUser(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("deletion not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	return deletion, nil
+}
+
+func (s *Service) GetUserDeletionByRefID(
+	ctx context.Context, refID model.UserDeletionRefID,
+) (*model.UserDeletion, errs.Error) {
+	deletion, err := model.GetUserDeletionByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("deletion not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	return deletion, nil
+}
+
+// CancelUserDeletion cancels a scheduled deletion, along with any
+// requested event transfers.
+func (s *Service) CancelUserDeletion(
+	ctx context.Context, deletion *model.UserDeletion,
+) errs.Error {
+	err := model.DeleteUserDeletion(ctx, s.Db, deletion.ID)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
+
+// DeleteScheduledUsers carries out the deletions whose grace period is
+// over. Requested event transfers are done first, so those events (and
+// the earmarks of their guests) are not removed with the account.
+func (s *Service) DeleteScheduledUsers(ctx context.Context) error {
+	deletions, err := model.GetUserDeletionsDue(ctx, s.Db)
+	if err != nil {
+		return err
+	}
+
+	for _, deletion := range deletions {
+		user, err := model.GetUserByID(ctx, s.Db, deletion.UserID)
+		if err != nil {
+			return err
+		}
+		transfers, err := model.GetUserDeletionTransfers(ctx, s.Db, deletion.ID)
+		if err != nil {
+			return err
+		}
+
+		// published once the deletion is committed
+		var notifications []*model.Notification
+		errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+			for _, transfer := range transfers {
+				event, innerErr := model.GetEventByID(ctx, tx, transfer.EventID)
+				if innerErr != nil {
+					return innerErr
+				}
+				// skip events archived during the grace period
+				if event.UserID != user.ID || event.Archived {
+					continue
+				}
+				innerErr = model.TransferEvent(ctx, tx, event.ID, transfer.UserID)
+				if innerErr != nil {
+					slog.DebugContext(ctx, "inner db error transferring event",
+						logger.Err(innerErr))
+					return innerErr
+				}
+				notification, errx := s.newTypedNotification(ctx, tx, transfer.UserID,
+					model.NotificationKindEventTransferred,
+					model.NotificationPayload{
+						EventRefID: event.RefID.String(),
+						EventName:  event.Name,
+						UserName:   user.Name,
+					},
+				)
+				if errx != nil {
+					return errx
+				}
+				notifications = append(notifications, notification)
+			}
+			innerErr := model.DeleteUser(ctx, tx, user.ID)
+			if innerErr != nil {
+				slog.DebugContext(ctx, "inner db error deleting user",
+					logger.Err(innerErr))
+				return innerErr
+			}
+			return nil
+		})
+		if errx != nil {
+			return errx
+		}
+		for _, notification := range notifications {
+			s.publishNotificationCreated(ctx, notification)
+		}
+		slog.InfoContext(ctx, "deleted scheduled user account",
+			"userID", user.ID)
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_ScheduleUserDeletion(t *testing.T) {
+	t.Parallel()
+
+	event := &model.Event{
+		ID:        3,
+		RefID:     util.Must(model.NewEventRefID()),
+		UserID:    1,
+		Name:      "event",
+		StartTime: time.Now().Add(24 * time.Hour),
+	}
+	guest := &model.User{
+		ID:    7,
+		RefID: util.Must(model.NewUserRefID()),
+		Email: "guest@example.com",
+		Name:  "guest",
+	}
+
+	t.Run("schedule with transfer should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserDeletionRefID())
+		scheduled := tstTs.Add(model.UserDeletionGracePeriod)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(1).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name", "archived", "start_time"}).
+				AddRow(event.ID, event.RefID, event.UserID, event.Name, false,
+					event.StartTime),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(guest.ID, guest.RefID, guest.Email, guest.Name),
+			)
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_deletion_ ").
+			WithArgs(pgx.NamedArgs{
+				"refID":     UserDeletionRefIDMatcher,
+				"userID":    1,
+				"scheduled": pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "scheduled"}).
+				AddRow(5, refID, 1, scheduled),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_deletion_transfer_").
+			WithArgs(pgx.NamedArgs{
+				"deletionID": 5,
+				"eventID":    event.ID,
+				"userID":     guest.ID,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "deletion_id", "event_id", "user_id"}).
+				AddRow(9, 5, event.ID, guest.ID),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		// every session of the user is logged out
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_session_").
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		deletion, err := svc.ScheduleUserDeletion(ctx, 1, []*EventTransfer{
+			{EventRefID: event.RefID, UserRefID: guest.RefID},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, deletion.RefID, refID)
+		assert.Equal(t, deletion.Scheduled, scheduled)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("schedule when already scheduled should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "scheduled"}).
+				AddRow(5, util.Must(model.NewUserDeletionRefID()), 1, tstTs),
+			)
+
+		_, err := svc.ScheduleUserDeletion(ctx, 1, []*EventTransfer{})
+		errs.AssertError(t, err, errs.AlreadyExists, "deletion already scheduled")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("schedule transfer to non-guest should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(1).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name", "archived", "start_time"}).
+				AddRow(event.ID, event.RefID, event.UserID, event.Name, false,
+					event.StartTime),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(guest.ID, guest.RefID, guest.Email, guest.Name),
+			)
+
+		_, err := svc.ScheduleUserDeletion(ctx, 1, []*EventTransfer{
+			{EventRefID: event.RefID, UserRefID: util.Must(model.NewUserRefID())},
+		})
+		errs.AssertError(t, err, errs.InvalidArgument,
+			"transfer user is not a guest of the event")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("schedule transfer of other users event should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(2).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name", "archived", "start_time"}).
+				AddRow(event.ID, event.RefID, event.UserID, event.Name, false,
+					event.StartTime),
+			)
+
+		_, err := svc.ScheduleUserDeletion(ctx, 2, []*EventTransfer{
+			{EventRefID: event.RefID, UserRefID: guest.RefID},
+		})
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("schedule transfer of started event should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs(1).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name", "archived", "start_time"}).
+				AddRow(event.ID, event.RefID, event.UserID, event.Name, false,
+					time.Now().Add(-time.Hour)),
+			)
+
+		_, err := svc.ScheduleUserDeletion(ctx, 1, []*EventTransfer{
+			{EventRefID: event.RefID, UserRefID: guest.RefID},
+		})
+		errs.AssertError(t, err, errs.InvalidArgument,
+			"transfer event has already started")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_DeleteScheduledUsers(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:    1,
+		RefID: util.Must(model.NewUserRefID()),
+		Email: "user@example.com",
+		Name:  "user",
+	}
+	event := &model.Event{
+		ID:     3,
+		RefID:  util.Must(model.NewEventRefID()),
+		UserID: user.ID,
+		Name:   "event",
+	}
+	deletion := &model.UserDeletion{
+		ID:        5,
+		RefID:     util.Must(model.NewUserDeletionRefID()),
+		UserID:    user.ID,
+		Scheduled: tstTs,
+	}
+
+	t.Run("delete should transfer events and remove user", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "scheduled"}).
+				AddRow(deletion.ID, deletion.RefID, deletion.UserID, deletion.Scheduled),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_ ").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_transfer_").
+			WithArgs(deletion.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "deletion_id", "event_id", "user_id"}).
+				AddRow(9, deletion.ID, event.ID, 7),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name", "archived"}).
+				AddRow(event.ID, event.RefID, event.UserID, event.Name, false),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE event_").
+			WithArgs(pgx.NamedArgs{
+				"eventID": event.ID,
+				"userID":  7,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO notification_").
+			WithArgs(pgx.NamedArgs{
+				"refID":   NotificationRefIDMatcher,
+				"userID":  7,
+				"kind":    model.NotificationKindEventTransferred,
+				"message": "user deleted their account, and handed event 'event' over to you.",
+				"payload": model.NotificationPayload{
+					EventRefID: event.RefID.String(),
+					EventName:  event.Name,
+					UserName:   user.Name,
+				},
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(12, util.Must(model.NewNotificationRefID()), 7),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_").
+			WithArgs(user.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.DeleteScheduledUsers(ctx)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete should skip archived events", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "scheduled"}).
+				AddRow(deletion.ID, deletion.RefID, deletion.UserID, deletion.Scheduled),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_ ").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_deletion_transfer_").
+			WithArgs(deletion.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "deletion_id", "event_id", "user_id"}).
+				AddRow(9, deletion.ID, event.ID, 7),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "name", "archived"}).
+				AddRow(event.ID, event.RefID, event.UserID, event.Name, true),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_").
+			WithArgs(user.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.DeleteScheduledUsers(ctx)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k3a/html2text"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+)
+
+type digestEvent struct {
+	Name string
+	When string
+	URL  string
+}
+
+type digestActivity struct {
+	Name        string
+	URL         string
+	NewItems    int
+	NewEarmarks int
+}
+
+func digestPeriod(frequency string) time.Duration {
+	if frequency == model.DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+func (s *Service) SendUserDigests(ctx context.Context,
+	mailer mail.MailSender, tplContainer resources.TGetter,
+	siteBaseUrl string,
+) error {
+	digestsNeeded, err := model.GetUserDigestNeeded(ctx, s.Db)
+	if err != nil {
+		return err
+	}
+
+	tplHtml, err := tplContainer.Get("mail_digest.gohtml")
+	if err != nil {
+		return fmt.Errorf("template get error: %w", err)
+	}
+
+	for _, elem := range digestsNeeded {
+		user, err := model.GetUserByID(ctx, s.Db, elem.UserID)
+		if err != nil {
+			return err
+		}
+
+		// double check if user still wants a digest
+		if user.Settings.DigestFrequency != elem.Frequency ||
+			elem.Frequency == model.DigestNone {
+			continue
+		}
+
+		// digests are never urgent, so wait out any quiet hours
+		now := time.Now().UTC()
+		if user.Settings.InQuietHours(now) {
+			continue
+		}
+
+		period := digestPeriod(elem.Frequency)
+		since := now.Add(-period)
+		if elem.LastSent != nil {
+			since = *elem.LastSent
+		}
+
+		notifications, err := model.GetNotificationsByUserUndigested(
+			ctx, s.Db, user.ID)
+		if err != nil {
+			return err
+		}
+
+		upcoming, err := model.GetEventsUpcomingByUserInvolved(
+			ctx, s.Db, user.ID, now.Add(2*period))
+		if err != nil {
+			return err
+		}
+
+		activity, err := model.GetFavoriteActivityByUserSince(
+			ctx, s.Db, user.ID, since)
+		if err != nil {
+			return err
+		}
+
+		// nothing to report. just record the attempt, so we do not check
+		// again until the next period.
+		if len(notifications) == 0 && len(upcoming) == 0 && len(activity) == 0 {
+			if _, err := model.UpsertUserDigest(ctx, s.Db, user.ID, now); err != nil {
+				return fmt.Errorf("error updating database: %w", err)
+			}
+			continue
+		}
+
+		upcomingEvents := make([]*digestEvent, 0, len(upcoming))
+		for _, event := range upcoming {
+			eventURL, err := url.JoinPath(
+				siteBaseUrl,
+				fmt.Sprintf("/events/%s", event.RefID.String()),
+			)
+			if err != nil {
+				return fmt.Errorf("url path join error: %w", err)
+			}
+			upcomingEvents = append(upcomingEvents, &digestEvent{
+				Name: event.Name,
+				When: event.When().Format("2006-01-02 03:04PM"),
+				URL:  eventURL,
+			})
+		}
+
+		favoriteActivity := make([]*digestActivity, 0, len(activity))
+		for _, act := range activity {
+			eventURL, err := url.JoinPath(
+				siteBaseUrl,
+				fmt.Sprintf("/events/%s", act.EventRefID.String()),
+			)
+			if err != nil {
+				return fmt.Errorf("url path join error: %w", err)
+			}
+			favoriteActivity = append(favoriteActivity, &digestActivity{
+				Name:        act.EventName,
+				URL:         eventURL,
+				NewItems:    act.NewItems,
+				NewEarmarks: act.NewEarmarks,
+			})
+		}
+
+		notificationsURL, err := url.JoinPath(siteBaseUrl, "/notifications")
+		if err != nil {
+			return fmt.Errorf("url path join error: %w", err)
+		}
+
+		subject := "Your Daily Digest"
+		if elem.Frequency == model.DigestWeekly {
+			subject = "Your Weekly Digest"
+		}
+
+		vars := map[string]any{
+			"Subject":          subject,
+			"notifications":    notifications,
+			"notificationsURL": notificationsURL,
+			"upcomingEvents":   upcomingEvents,
+			"favoriteActivity": favoriteActivity,
+		}
+
+		var bufHtml bytes.Buffer
+		err = tplHtml.Execute(&bufHtml, vars)
+		if err != nil {
+			return fmt.Errorf("html template exec error: %w", err)
+		}
+
+		messageHtml := bufHtml.String()
+		messagePlain := html2text.HTML2Text(messageHtml)
+
+		slog.DebugContext(ctx, "email content",
+			slog.String("plain", messagePlain),
+			slog.String("html", messageHtml),
+		)
+
+		err = mailer.Send("", []string{user.Email},
+			subject, messagePlain, messageHtml,
+			mail.MailHeader{
+				"X-PM-Message-Stream": "broadcast",
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("error sending email: %w", err)
+		}
+
+		notifIDs := make([]int, 0, len(notifications))
+		for _, n := range notifications {
+			notifIDs = append(notifIDs, n.ID)
+		}
+		errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+			if len(notifIDs) > 0 {
+				if err := model.UpdateNotificationsDigested(ctx, tx, notifIDs); err != nil {
+					return err
+				}
+			}
+			_, err := model.UpsertUserDigest(ctx, tx, user.ID, now)
+			return err
+		})
+		if errx != nil {
+			return fmt.Errorf("error updating database: %w", errx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"go.uber.org/mock/gomock"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_SendUserDigests(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+		Settings: model.UserSettings{
+			DigestFrequency: model.DigestDaily,
+		},
+	}
+	event := &model.Event{
+		ID:          2,
+		RefID:       util.Must(model.NewEventRefID()),
+		UserID:      user.ID,
+		Name:        "event",
+		Description: "description",
+		StartTime:   tstTs,
+		StartTimeTz: util.Must(ParseTimeZone("Etc/UTC")),
+	}
+	notification := &model.Notification{
+		ID:      3,
+		RefID:   util.Must(model.NewNotificationRefID()),
+		UserID:  user.ID,
+		Message: "some notification",
+	}
+	templates := &resources.TemplateMap{
+		"mail_digest.gohtml": util.Must(
+			template.New("mail_digest.gohtml").
+				ParseFiles("../resources/templates/html/view/mail_digest.gohtml"),
+		),
+	}
+
+	t.Run("send digests should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		mock.ExpectQuery("SELECT (.+) FROM user_ u").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"user_id", "frequency", "last_sent"}).
+				AddRow(user.ID, model.DigestDaily, nil),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "email", "name", "verified", "settings",
+				}).
+				AddRow(
+					user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, user.Settings,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM notification_").
+			WithArgs(pgx.NamedArgs{"userID": user.ID}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "message"}).
+				AddRow(
+					notification.ID, notification.RefID,
+					notification.UserID, notification.Message,
+				),
+			)
+		mock.ExpectQuery("^SELECT DISTINCT ev.(.+) FROM event_ ev").
+			WithArgs(pgx.NamedArgs{
+				"userID": user.ID,
+				"until":  pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"start_time", "start_time_tz",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.StartTime, event.StartTimeTz,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM favorite_ fav").
+			WithArgs(pgx.NamedArgs{
+				"userID": user.ID,
+				"since":  pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"event_id", "event_ref_id", "event_name",
+					"new_items", "new_earmarks",
+				}))
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE notification_ SET digested").
+			WithArgs([]int{notification.ID}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user_digest_").
+			WithArgs(pgx.NamedArgs{
+				"userID":   user.ID,
+				"lastSent": pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"user_id", "last_sent"}).
+				AddRow(user.ID, time.Now()),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Your Daily Digest",
+				gomock.Cond(func(x string) bool {
+					return strings.Contains(x, notification.Message) &&
+						strings.Contains(x, event.Name)
+				}),
+				gomock.AssignableToTypeOf("string"),
+				mail.MailHeader{
+					"X-PM-Message-Stream": "broadcast",
+				},
+			).
+			Return(nil)
+
+		err := svc.SendUserDigests(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("send digests with nothing to report should not send", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		mock.ExpectQuery("SELECT (.+) FROM user_ u").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"user_id", "frequency", "last_sent"}).
+				AddRow(user.ID, model.DigestDaily, nil),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "email", "name", "verified", "settings",
+				}).
+				AddRow(
+					user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, user.Settings,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM notification_").
+			WithArgs(pgx.NamedArgs{"userID": user.ID}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "message"}))
+		mock.ExpectQuery("^SELECT DISTINCT ev.(.+) FROM event_ ev").
+			WithArgs(pgx.NamedArgs{
+				"userID": user.ID,
+				"until":  pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description",
+					"start_time", "start_time_tz",
+				}))
+		mock.ExpectQuery("^SELECT (.+) FROM favorite_ fav").
+			WithArgs(pgx.NamedArgs{
+				"userID": user.ID,
+				"since":  pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"event_id", "event_ref_id", "event_name",
+					"new_items", "new_earmarks",
+				}))
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user_digest_").
+			WithArgs(pgx.NamedArgs{
+				"userID":   user.ID,
+				"lastSent": pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"user_id", "last_sent"}).
+				AddRow(user.ID, time.Now()),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.SendUserDigests(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("send digests with user opted out should skip", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+
+		mock.ExpectQuery("SELECT (.+) FROM user_ u").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"user_id", "frequency", "last_sent"}).
+				AddRow(user.ID, model.DigestDaily, nil),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "email", "name", "verified", "settings",
+				}).
+				AddRow(
+					user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, model.UserSettings{},
+				),
+			)
+
+		err := svc.SendUserDigests(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/k3a/html2text"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/app/resources"
+	"github.com/dropwhile/icanbringthat/internal/crypto"
+	"github.com/dropwhile/icanbringthat/internal/encoder"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/mail"
+	"github.com/dropwhile/icanbringthat/internal/validate"
+)
+
+var (
+	UserEmailChangeRefIDMatcher = reftag.NewMatcher[model.UserEmailChangeRefID]()
+	ParseUserEmailChangeRefID   = reftag.Parse[model.UserEmailChangeRefID]
+)
+
+// revert links are signed over a prefixed refid, so a confirmation link
+// can not be used as a revert link
+const EmailChangeRevertPrefix = "revert-"
+
+// SendUserEmailChangeMails sends a confirmation link for a pending email
+// change to the new address, and a revert link to the old one. It is
+// shared by the web and rpc servers, which both start email changes.
+func SendUserEmailChangeMails(ctx context.Context,
+	mailer mail.MailSender, tplContainer resources.TGetter,
+	cMAC crypto.HMACer, siteBaseUrl string,
+	change *model.UserEmailChange,
+) errs.Error {
+	refIDStr := change.RefID.String()
+
+	confirmMac := encoder.Base32EncodeToString(
+		cMAC.Generate([]byte(refIDStr)))
+	confirmUrl, err := url.JoinPath(siteBaseUrl,
+		fmt.Sprintf("/settings/email/%s-%s", refIDStr, confirmMac))
+	if err != nil {
+		return errs.Internal.Error("processing error")
+	}
+
+	revertMac := encoder.Base32EncodeToString(
+		cMAC.Generate([]byte(EmailChangeRevertPrefix + refIDStr)))
+	revertUrl, err := url.JoinPath(siteBaseUrl,
+		fmt.Sprintf("/email-change/%s-%s", refIDStr, revertMac))
+	if err != nil {
+		return errs.Internal.Error("processing error")
+	}
+
+	mails := []struct {
+		vars     map[string]any
+		subject  string
+		template string
+		to       string
+	}{
+		{
+			subject:  "Confirm your new email address",
+			template: "mail_email_change_confirm.gohtml",
+			to:       change.NewEmail,
+			vars: map[string]any{
+				"ConfirmUrl": confirmUrl,
+			},
+		},
+		{
+			subject:  "Your email address is being changed",
+			template: "mail_email_change_notice.gohtml",
+			to:       change.OldEmail,
+			vars: map[string]any{
+				"NewEmail":  change.NewEmail,
+				"RevertUrl": revertUrl,
+			},
+		},
+	}
+	for _, m := range mails {
+		tpl, err := tplContainer.Get(m.template)
+		if err != nil {
+			return errs.Internal.Errorf("template get error: %w", err)
+		}
+		m.vars["Subject"] = m.subject
+		var buf bytes.Buffer
+		err = tpl.Execute(&buf, m.vars)
+		if err != nil {
+			return errs.Internal.Errorf("template error: %w", err)
+		}
+		messageHtml := buf.String()
+		messagePlain := html2text.HTML2Text(messageHtml)
+
+		slog.DebugContext(ctx, "email content",
+			slog.String("plain", messagePlain),
+			slog.String("html", messageHtml),
+		)
+
+		mailer.SendAsync("", []string{m.to},
+			m.subject, messagePlain, messageHtml,
+			mail.MailHeader{
+				"X-PM-Message-Stream": "outbound",
+			},
+		)
+	}
+	return nil
+}
+
+func isEmailConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.ConstraintName == "user_email_idx"
+}
+
+// NewUserEmailChange starts changing the email address of the user. The
+// address itself is not changed until the change is confirmed from the
+// new address. Any earlier pending change is replaced.
+func (s *Service) NewUserEmailChange(
+	ctx context.Context, user *model.User, newEmail string,
+) (*model.UserEmailChange, errs.Error) {
+	err := validate.Validate.VarCtx(ctx, newEmail, "required,notblank,email")
+	if err != nil {
+		slog.
+			With("field", "email").
+			With("error", err).
+			Info("bad field value")
+		return nil, errs.ArgumentError("email", "bad value")
+	}
+	if newEmail == user.Email {
+		return nil, errs.ArgumentError("email", "email unchanged")
+	}
+
+	_, err = model.GetUserByEmail(ctx, s.Db, newEmail)
+	switch {
+	case err == nil:
+		return nil, errs.AlreadyExists.Error("email already in use")
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	var change *model.UserEmailChange
+	errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		innerErr := model.DeletePendingUserEmailChangesByUser(ctx, tx, user.ID)
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error removing pending email changes",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		change, innerErr = model.NewUserEmailChange(ctx, tx,
+			user.ID, user.Email, newEmail)
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error creating email change",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		return nil
+	})
+	if errx != nil {
+		return nil, errx
+	}
+	return change, nil
+}
+
+func (s *Service) GetUserEmailChangeByRefID(
+	ctx context.Context, refID model.UserEmailChangeRefID,
+) (*model.UserEmailChange, errs.Error) {
+	change, err := model.GetUserEmailChangeByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("email change not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	return change, nil
+}
+
+// GetPendingUserEmailChange returns the unconfirmed and unexpired email
+// change of the user, if there is one.
+func (s *Service) GetPendingUserEmailChange(
+	ctx context.Context, userID int,
+) (*model.UserEmailChange, errs.Error) {
+	change, err := model.GetPendingUserEmailChangeByUser(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("email change not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	if IsTimerExpired(change.RefID, model.UserEmailChangeExpiry) {
+		return nil, errs.NotFound.Error("email change expired")
+	}
+	return change, nil
+}
+
+// ConfirmUserEmailChange switches the user to the new email address.
+// Confirming proves ownership of the new address, so it is marked as
+// verified.
+func (s *Service) ConfirmUserEmailChange(
+	ctx context.Context, user *model.User, change *model.UserEmailChange,
+) errs.Error {
+	if user.ID != change.UserID {
+		return errs.PermissionDenied.Error("permission denied")
+	}
+	if change.Confirmed != nil ||
+		IsTimerExpired(change.RefID, model.UserEmailChangeExpiry) {
+		return errs.NotFound.Error("email change expired")
+	}
+	if user.Email != change.OldEmail {
+		return errs.FailedPrecondition.Error("email changed since request")
+	}
+
+	errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		innerErr := model.UpdateUser(ctx, tx, user.ID,
+			&model.UserUpdateModelValues{
+				Email:    mo.Some(change.NewEmail),
+				Verified: mo.Some(true),
+			},
+		)
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error saving user",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		innerErr = model.ConfirmUserEmailChange(ctx, tx, change.ID)
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error confirming email change",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		return nil
+	})
+	if errx != nil {
+		if isEmailConflict(errx) {
+			return errs.AlreadyExists.Error("email already in use")
+		}
+		return errx
+	}
+	return nil
+}
+
+// RevertUserEmailChange undoes an email change from the old address. A
+// pending change is cancelled. A confirmed change restores the old
+// address and logs the user out everywhere, as the change may not have
+// been made by the owner.
+func (s *Service) RevertUserEmailChange(
+	ctx context.Context, change *model.UserEmailChange,
+) errs.Error {
+	if IsTimerExpired(change.RefID, model.UserEmailChangeRevertExpiry) {
+		return errs.NotFound.Error("email change expired")
+	}
+
+	errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		if change.Confirmed != nil {
+			// receiving the revert link proves ownership of the old address
+			innerErr := model.UpdateUser(ctx, tx, change.UserID,
+				&model.UserUpdateModelValues{
+					Email:    mo.Some(change.OldEmail),
+					Verified: mo.Some(true),
+				},
+			)
+			if innerErr != nil {
+				slog.DebugContext(ctx, "inner db error saving user",
+					logger.Err(innerErr))
+				return innerErr
+			}
+			innerErr = model.DeleteUserSessionsByUser(ctx, tx, change.UserID)
+			if innerErr != nil {
+				slog.DebugContext(ctx, "inner db error removing sessions",
+					logger.Err(innerErr))
+				return innerErr
+			}
+		}
+		innerErr := model.DeleteUserEmailChange(ctx, tx, change.ID)
+		if innerErr != nil {
+			slog.DebugContext(ctx, "inner db error removing email change",
+				logger.Err(innerErr))
+			return innerErr
+		}
+		return nil
+	})
+	if errx != nil {
+		if isEmailConflict(errx) {
+			return errs.AlreadyExists.Error("email already in use")
+		}
+		return errx
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_NewUserEmailChange(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("new email change should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserEmailChangeRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs("new@example.com").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_email_change_").
+			WithArgs(user.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_email_change_").
+			WithArgs(pgx.NamedArgs{
+				"refID":    pgxmock.AnyArg(),
+				"userID":   user.ID,
+				"oldEmail": user.Email,
+				"newEmail": "new@example.com",
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "old_email", "new_email"}).
+				AddRow(1, refID, user.ID, user.Email, "new@example.com"),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		change, err := svc.NewUserEmailChange(ctx, user, "new@example.com")
+		assert.Nil(t, err)
+		assert.Equal(t, change.RefID, refID)
+		assert.Equal(t, change.NewEmail, "new@example.com")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("email in use should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs("new@example.com").
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(2, util.Must(model.NewUserRefID()), "new@example.com", "other"),
+			)
+
+		_, err := svc.NewUserEmailChange(ctx, user, "new@example.com")
+		errs.AssertError(t, err, errs.AlreadyExists, "email already in use")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("bad email should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, err := svc.NewUserEmailChange(ctx, user, "not-an-email")
+		errs.AssertError(t, err, errs.InvalidArgument, "email bad value")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_ConfirmUserEmailChange(t *testing.T) {
+	t.Parallel()
+
+	newUser := func() *model.User {
+		return &model.User{
+			ID:           1,
+			RefID:        util.Must(model.NewUserRefID()),
+			Email:        "user@example.com",
+			Name:         "user",
+			PWHash:       []byte("00x00"),
+			Verified:     false,
+			Created:      tstTs,
+			LastModified: tstTs,
+		}
+	}
+
+	t.Run("confirm should change and verify email", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		user := newUser()
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: user.Email,
+			NewEmail: "new@example.com",
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_").
+			WithArgs(pgx.NamedArgs{
+				"userID":    user.ID,
+				"email":     mo.Some("new@example.com"),
+				"name":      mo.None[string](),
+				"pwHash":    mo.None[[]byte](),
+				"verified":  mo.Some(true),
+				"pwAuth":    mo.None[bool](),
+				"apiAccess": mo.None[bool](),
+				"webAuthn":  mo.None[bool](),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_email_change_").
+			WithArgs(change.ID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.ConfirmUserEmailChange(ctx, user, change)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("confirm by other user should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		user := newUser()
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   2,
+			OldEmail: "other@example.com",
+			NewEmail: "new@example.com",
+		}
+
+		err := svc.ConfirmUserEmailChange(ctx, user, change)
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("confirm expired should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		user := newUser()
+		refID := util.Must(model.NewUserEmailChangeRefID())
+		refID.SetTime(time.Now().Add(-2 * model.UserEmailChangeExpiry))
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    refID,
+			UserID:   user.ID,
+			OldEmail: user.Email,
+			NewEmail: "new@example.com",
+		}
+
+		err := svc.ConfirmUserEmailChange(ctx, user, change)
+		errs.AssertError(t, err, errs.NotFound, "email change expired")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("confirm twice should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		user := newUser()
+		confirmed := time.Now()
+		change := &model.UserEmailChange{
+			ID:        5,
+			RefID:     util.Must(model.NewUserEmailChangeRefID()),
+			UserID:    user.ID,
+			OldEmail:  user.Email,
+			NewEmail:  "new@example.com",
+			Confirmed: &confirmed,
+		}
+
+		err := svc.ConfirmUserEmailChange(ctx, user, change)
+		errs.AssertError(t, err, errs.NotFound, "email change expired")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("confirm after another change should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		user := newUser()
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   user.ID,
+			OldEmail: "previous@example.com",
+			NewEmail: "new@example.com",
+		}
+
+		err := svc.ConfirmUserEmailChange(ctx, user, change)
+		errs.AssertError(t, err, errs.FailedPrecondition, "email changed since request")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_RevertUserEmailChange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("revert pending should cancel", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    util.Must(model.NewUserEmailChangeRefID()),
+			UserID:   1,
+			OldEmail: "user@example.com",
+			NewEmail: "new@example.com",
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_email_change_").
+			WithArgs(change.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.RevertUserEmailChange(ctx, change)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("revert confirmed should restore email and log out", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		confirmed := time.Now()
+		change := &model.UserEmailChange{
+			ID:        5,
+			RefID:     util.Must(model.NewUserEmailChangeRefID()),
+			UserID:    1,
+			OldEmail:  "user@example.com",
+			NewEmail:  "new@example.com",
+			Confirmed: &confirmed,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_").
+			WithArgs(pgx.NamedArgs{
+				"userID":    change.UserID,
+				"email":     mo.Some("user@example.com"),
+				"name":      mo.None[string](),
+				"pwHash":    mo.None[[]byte](),
+				"verified":  mo.Some(true),
+				"pwAuth":    mo.None[bool](),
+				"apiAccess": mo.None[bool](),
+				"webAuthn":  mo.None[bool](),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_session_").
+			WithArgs(change.UserID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_email_change_").
+			WithArgs(change.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.RevertUserEmailChange(ctx, change)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("revert expired should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserEmailChangeRefID())
+		refID.SetTime(time.Now().Add(-2 * model.UserEmailChangeRevertExpiry))
+		change := &model.UserEmailChange{
+			ID:       5,
+			RefID:    refID,
+			UserID:   1,
+			OldEmail: "user@example.com",
+			NewEmail: "new@example.com",
+		}
+
+		err := svc.RevertUserEmailChange(ctx, change)
+		errs.AssertError(t, err, errs.NotFound, "email change expired")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
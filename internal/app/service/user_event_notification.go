@@ -18,6 +18,11 @@ import (
 	"github.com/dropwhile/icanbringthat/internal/mail"
 )
 
+// quietHoursDeliveryMargin is how much time must remain between the end of
+// a user's quiet hours and the start of an event for a reminder to be
+// deferred. It leaves room for a few notifier runs.
+const quietHoursDeliveryMargin = 1 * time.Hour
+
 func (s *Service) NotifyUsersPendingEvents(ctx context.Context,
 	mailer mail.MailSender, tplContainer resources.TGetter,
 	siteBaseUrl string,
@@ -49,11 +54,21 @@ func (s *Service) NotifyUsersPendingEvents(ctx context.Context,
 		if remT == 0 {
 			remT = 24
 		}
-		notifyWhen := time.Now().Add(time.Duration(remT) * time.Hour)
+		now := time.Now()
+		notifyWhen := now.Add(time.Duration(remT) * time.Hour)
 		if notifyWhen.Before(elem.When) {
 			continue
 		}
 
+		// defer delivery during the user's quiet hours, unless waiting
+		// would risk the reminder arriving after the event has started
+		if user.Settings.InQuietHours(now) {
+			resumeAt := user.Settings.QuietHoursEndAfter(now)
+			if resumeAt.Add(quietHoursDeliveryMargin).Before(elem.When) {
+				continue
+			}
+		}
+
 		// get event
 		event, err := model.GetEventByID(ctx, s.Db, elem.EventID)
 		if err != nil {
@@ -77,6 +92,17 @@ func (s *Service) NotifyUsersPendingEvents(ctx context.Context,
 			}
 		}
 
+		// get any items nobody has earmarked yet. the query only
+		// provides these for owners, or for guests when the owner has
+		// opted in to nudging guests.
+		unclaimedItems := make([]*model.EventItem, 0)
+		if len(elem.UnclaimedItemIDs) > 0 {
+			unclaimedItems, err = model.GetEventItemsByIDs(ctx, s.Db, elem.UnclaimedItemIDs)
+			if err != nil {
+				return err
+			}
+		}
+
 		owner := false
 		if user.ID == event.UserID {
 			owner = true
@@ -85,7 +111,8 @@ func (s *Service) NotifyUsersPendingEvents(ctx context.Context,
 		// 2. determine if owner of event or not
 		//    a. if owner, send info on all items and their status (as well as
 		// 		 any self earmarked items)?
-		//    b. if not owner, send info on items earmarked to bring.
+		//    b. if not owner, send info on items earmarked to bring, and
+		//       any unclaimed items if the owner asked to nudge guests.
 		// 3. send appropriate notification
 		eventURL, err := url.JoinPath(
 			siteBaseUrl,
@@ -108,6 +135,7 @@ func (s *Service) NotifyUsersPendingEvents(ctx context.Context,
 			"eventURL":         eventURL,
 			"items":            eventItems,
 			"earmarks":         earmarks,
+			"unclaimedItems":   unclaimedItems,
 		}
 
 		var bufHtml bytes.Buffer
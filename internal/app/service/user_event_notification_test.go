@@ -6,6 +6,7 @@ package service
 import (
 	"context"
 	"html/template"
+	"strings"
 	"testing"
 	"time"
 
@@ -164,6 +165,110 @@ func TestService_NotifyUsersPendingEvents(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("notify pending with unclaimed items should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+		templates := &resources.TemplateMap{
+			"mail_reminder.gohtml": util.Must(
+				template.New("mail_reminder.gohtml").
+					ParseFiles("../resources/templates/html/view/mail_reminder.gohtml"),
+			),
+		}
+
+		now := time.Now()
+		unclaimedItem := &model.EventItem{
+			ID:          5,
+			RefID:       util.Must(model.NewEventItemRefID()),
+			EventID:     event.ID,
+			Description: "unclaimed eventitem",
+		}
+
+		mock.ExpectQuery("WITH subt").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"user_id", "event_id", "when", "owner", "items",
+					"unclaimed_items",
+				}).
+				AddRow(
+					user.ID, event.ID, now, true, []int{},
+					[]int{unclaimedItem.ID},
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "email", "name", "verified", "settings",
+				}).
+				AddRow(
+					user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, user.Settings,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description", "archived",
+					"item_sort_order", "start_time", "start_time_tz",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived, event.ItemSortOrder,
+					event.StartTime, event.StartTimeTz,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_item_ (.+)").
+			WithArgs([]int{unclaimedItem.ID}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "event_id", "description",
+				}).
+				AddRow(
+					unclaimedItem.ID, unclaimedItem.RefID, unclaimedItem.EventID,
+					unclaimedItem.Description,
+				))
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user_event_notification_").
+			WithArgs(pgx.NamedArgs{
+				"userID":  user.ID,
+				"eventID": event.ID,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"user_id", "event_id",
+				}).
+				AddRow(user.ID, event.ID),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Upcoming Event Reminder",
+				gomock.Cond(func(x string) bool {
+					return strings.Contains(x, unclaimedItem.Description)
+				}),
+				gomock.AssignableToTypeOf("string"),
+				mail.MailHeader{
+					"X-PM-Message-Stream": "broadcast",
+				},
+			).
+			Return(nil)
+
+		err := svc.NotifyUsersPendingEvents(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("notify pending with user reminders disabled should succeed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -255,6 +360,150 @@ func TestService_NotifyUsersPendingEvents(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("notify pending during quiet hours should defer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+		templates := &resources.TemplateMap{
+			"mail_reminder.gohtml": util.Must(
+				template.New("mail_reminder.gohtml").
+					ParseFiles("../resources/templates/html/view/mail_reminder.gohtml"),
+			),
+		}
+
+		now := time.Now().UTC()
+		when := now.Add(20 * time.Hour)
+		settings := model.UserSettings{
+			ReminderThresholdHours: 24,
+			EnableReminders:        true,
+			Timezone:               "Etc/UTC",
+			QuietHoursEnabled:      true,
+			QuietHoursStart:        uint8(now.Hour()),
+			QuietHoursEnd:          uint8((now.Hour() + 2) % 24),
+		}
+
+		mock.ExpectQuery("WITH subt").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"user_id", "event_id", "when", "owner", "items",
+				}).
+				AddRow(user.ID, event.ID, when, true, []int{eventItem.ID}),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "email", "name", "verified", "settings",
+				}).
+				AddRow(
+					user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, settings,
+				),
+			)
+
+		err := svc.NotifyUsersPendingEvents(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("notify pending during quiet hours with event starting soon should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+		mailer := SetupMailerMock(t)
+		templates := &resources.TemplateMap{
+			"mail_reminder.gohtml": util.Must(
+				template.New("mail_reminder.gohtml").
+					ParseFiles("../resources/templates/html/view/mail_reminder.gohtml"),
+			),
+		}
+
+		now := time.Now().UTC()
+		when := now.Add(1 * time.Hour)
+		settings := model.UserSettings{
+			ReminderThresholdHours: 24,
+			EnableReminders:        true,
+			Timezone:               "Etc/UTC",
+			QuietHoursEnabled:      true,
+			QuietHoursStart:        uint8(now.Hour()),
+			QuietHoursEnd:          uint8((now.Hour() + 2) % 24),
+		}
+
+		mock.ExpectQuery("WITH subt").
+			WithArgs().
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"user_id", "event_id", "when", "owner", "items",
+				}).
+				AddRow(user.ID, event.ID, when, true, []int{}),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "email", "name", "verified", "settings",
+				}).
+				AddRow(
+					user.ID, user.RefID, user.Email, user.Name,
+					user.Verified, settings,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM event_").
+			WithArgs(event.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "ref_id", "user_id", "name", "description", "archived",
+					"item_sort_order", "start_time", "start_time_tz",
+				}).
+				AddRow(
+					event.ID, event.RefID, event.UserID, event.Name,
+					event.Description, event.Archived, event.ItemSortOrder,
+					event.StartTime, event.StartTimeTz,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user_event_notification_").
+			WithArgs(pgx.NamedArgs{
+				"userID":  user.ID,
+				"eventID": event.ID,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"user_id", "event_id",
+				}).
+				AddRow(user.ID, event.ID),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		mailer.EXPECT().
+			Send("", []string{user.Email},
+				"Upcoming Event Reminder",
+				gomock.AssignableToTypeOf("string"),
+				gomock.AssignableToTypeOf("string"),
+				mail.MailHeader{
+					"X-PM-Message-Stream": "broadcast",
+				},
+			).
+			Return(nil)
+
+		err := svc.NotifyUsersPendingEvents(
+			ctx, mailer, templates, "http://example.org",
+		)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("notify pending with empty results should succeed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
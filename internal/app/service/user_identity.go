@@ -0,0 +1,204 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/validate"
+)
+
+var (
+	UserIdentityRefIDMatcher = reftag.NewMatcher[model.UserIdentityRefID]()
+	ParseUserIdentityRefID   = reftag.Parse[model.UserIdentityRefID]
+)
+
+// ExternalIdentity is a user as asserted by an external OpenID Connect
+// provider.
+type ExternalIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	Name          string
+	EmailVerified bool
+}
+
+func (s *Service) GetUserIdentities(
+	ctx context.Context, userID int,
+) ([]*model.UserIdentity, errs.Error) {
+	identities, err := model.GetUserIdentitiesByUser(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return []*model.UserIdentity{}, nil
+		default:
+			return nil, errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	return identities, nil
+}
+
+// ResolveExternalIdentity returns the user linked to the external
+// identity. If there is none, and no user exists with the same email, a
+// new verified user is provisioned and linked.
+//
+// An existing account with the same email is never linked automatically:
+// the provider may not be authoritative for that address. The user has to
+// sign in and link the identity from their settings instead.
+func (s *Service) ResolveExternalIdentity(
+	ctx context.Context, ident *ExternalIdentity,
+) (*model.User, errs.Error) {
+	if ident.Issuer == "" || ident.Subject == "" {
+		return nil, errs.ArgumentError("identity", "bad value")
+	}
+
+	identity, err := model.GetUserIdentityBySubject(
+		ctx, s.Db, ident.Issuer, ident.Subject)
+	switch {
+	case err == nil:
+		return s.GetUserByID(ctx, identity.UserID)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	if !ident.EmailVerified {
+		return nil, errs.PermissionDenied.Error("email not verified by provider")
+	}
+	if err := validate.Validate.VarCtx(ctx, ident.Email, "required,notblank,email"); err != nil {
+		return nil, errs.ArgumentError("email", "bad value")
+	}
+
+	_, err = model.GetUserByEmail(ctx, s.Db, ident.Email)
+	switch {
+	case err == nil:
+		return nil, errs.FailedPrecondition.Error("account exists with email")
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	name := strings.TrimSpace(ident.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(ident.Email, "@")
+	}
+
+	var user *model.User
+	errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		// the password is never used, as password auth is disabled. it
+		// is random rather than empty so a later re-enable of password
+		// auth without a reset can't be guessed.
+		u, err := model.NewUser(ctx, tx, ident.Email, name, []byte(rand.Text()))
+		if err != nil {
+			return err
+		}
+		err = model.UpdateUser(ctx, tx, u.ID, &model.UserUpdateModelValues{
+			Verified: mo.Some(true),
+			PWAuth:   mo.Some(false),
+		})
+		if err != nil {
+			return err
+		}
+		u.Verified = true
+		u.PWAuth = false
+		_, err = model.NewUserIdentity(ctx, tx,
+			u.ID, ident.Issuer, ident.Subject, ident.Email)
+		if err != nil {
+			return err
+		}
+		user = u
+		return nil
+	})
+	if errx != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(errx, &pgErr) {
+			switch pgErr.ConstraintName {
+			case "user_email_idx", "user_identity_subject_idx":
+				return nil, errs.AlreadyExists.Error("user already exists")
+			}
+		}
+		return nil, errx
+	}
+	slog.InfoContext(ctx, "provisioned user from external identity",
+		slog.String("issuer", ident.Issuer),
+		slog.Int("user_id", user.ID),
+	)
+	return user, nil
+}
+
+// LinkExternalIdentity links the external identity to an existing user.
+func (s *Service) LinkExternalIdentity(
+	ctx context.Context, userID int, ident *ExternalIdentity,
+) (*model.UserIdentity, errs.Error) {
+	if ident.Issuer == "" || ident.Subject == "" {
+		return nil, errs.ArgumentError("identity", "bad value")
+	}
+
+	identity, err := model.GetUserIdentityBySubject(
+		ctx, s.Db, ident.Issuer, ident.Subject)
+	switch {
+	case err == nil && identity.UserID == userID:
+		return identity, nil
+	case err == nil:
+		return nil, errs.AlreadyExists.Error("identity linked to another account")
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	identity, err = model.NewUserIdentity(ctx, s.Db,
+		userID, ident.Issuer, ident.Subject, ident.Email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "user_identity_subject_idx" {
+			return nil, errs.AlreadyExists.Error("identity linked to another account")
+		}
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	return identity, nil
+}
+
+func (s *Service) DeleteUserIdentity(
+	ctx context.Context, user *model.User, refID model.UserIdentityRefID,
+) errs.Error {
+	identity, err := model.GetUserIdentityByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return errs.NotFound.Error("identity not found")
+		default:
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+
+	if identity.UserID != user.ID {
+		return errs.PermissionDenied.Error("permission denied")
+	}
+
+	if !user.PWAuth && !user.WebAuthn {
+		count, err := model.GetUserIdentityCountByUser(ctx, s.Db, user.ID)
+		if err != nil {
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+		if count <= 1 {
+			return errs.FailedPrecondition.Error(
+				"refusing to remove last identity when no other auth enabled",
+			)
+		}
+	}
+
+	err = model.DeleteUserIdentity(ctx, s.Db, identity.ID)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_ResolveExternalIdentity(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	ident := &ExternalIdentity{
+		Issuer:        "https://issuer.example.com",
+		Subject:       "subject-1",
+		Email:         user.Email,
+		Name:          user.Name,
+		EmailVerified: true,
+	}
+
+	t.Run("linked identity should return user", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(ident.Issuer, ident.Subject).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "issuer", "subject"}).
+				AddRow(
+					1, util.Must(model.NewUserIdentityRefID()), user.ID,
+					ident.Issuer, ident.Subject,
+				),
+			)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name),
+			)
+
+		result, err := svc.ResolveExternalIdentity(ctx, ident)
+		assert.Nil(t, err)
+		assert.Equal(t, result.ID, user.ID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("unlinked identity should provision user", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(ident.Issuer, ident.Subject).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(ident.Email).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		// create user
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_").
+			WithArgs(pgx.NamedArgs{
+				"refID":    pgxmock.AnyArg(),
+				"email":    ident.Email,
+				"name":     ident.Name,
+				"pwHash":   pgxmock.AnyArg(),
+				"pwAuth":   true,
+				"settings": model.NewUserPropertyMap(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "pwauth"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name, true),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		// mark verified, disable password auth
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE user_").
+			WithArgs(pgx.NamedArgs{
+				"userID":    user.ID,
+				"email":     mo.None[string](),
+				"name":      mo.None[string](),
+				"pwHash":    mo.None[[]byte](),
+				"verified":  mo.Some(true),
+				"pwAuth":    mo.Some(false),
+				"apiAccess": mo.None[bool](),
+				"webAuthn":  mo.None[bool](),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		// link identity
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_identity_").
+			WithArgs(pgx.NamedArgs{
+				"refID":   pgxmock.AnyArg(),
+				"userID":  user.ID,
+				"issuer":  ident.Issuer,
+				"subject": ident.Subject,
+				"email":   ident.Email,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "issuer", "subject"}).
+				AddRow(
+					1, util.Must(model.NewUserIdentityRefID()), user.ID,
+					ident.Issuer, ident.Subject,
+				),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		result, err := svc.ResolveExternalIdentity(ctx, ident)
+		assert.Nil(t, err)
+		assert.Equal(t, result.ID, user.ID)
+		assert.True(t, result.Verified)
+		assert.Equal(t, result.PWAuth, false)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("unverified email should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		unverified := *ident
+		unverified.EmailVerified = false
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(ident.Issuer, ident.Subject).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := svc.ResolveExternalIdentity(ctx, &unverified)
+		errs.AssertError(t, err, errs.PermissionDenied, "email not verified by provider")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("existing account with email should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(ident.Issuer, ident.Subject).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(ident.Email).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name),
+			)
+
+		_, err := svc.ResolveExternalIdentity(ctx, ident)
+		errs.AssertError(t, err, errs.FailedPrecondition, "account exists with email")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_LinkExternalIdentity(t *testing.T) {
+	t.Parallel()
+
+	ident := &ExternalIdentity{
+		Issuer:        "https://issuer.example.com",
+		Subject:       "subject-1",
+		Email:         "user@example.com",
+		EmailVerified: true,
+	}
+
+	t.Run("link should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserIdentityRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(ident.Issuer, ident.Subject).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_identity_").
+			WithArgs(pgx.NamedArgs{
+				"refID":   pgxmock.AnyArg(),
+				"userID":  1,
+				"issuer":  ident.Issuer,
+				"subject": ident.Subject,
+				"email":   ident.Email,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "issuer", "subject"}).
+				AddRow(1, refID, 1, ident.Issuer, ident.Subject),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		result, err := svc.LinkExternalIdentity(ctx, 1, ident)
+		assert.Nil(t, err)
+		assert.Equal(t, result.RefID, refID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("link to other user should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(ident.Issuer, ident.Subject).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "issuer", "subject"}).
+				AddRow(
+					1, util.Must(model.NewUserIdentityRefID()), 2,
+					ident.Issuer, ident.Subject,
+				),
+			)
+
+		_, err := svc.LinkExternalIdentity(ctx, 1, ident)
+		errs.AssertError(t, err, errs.AlreadyExists, "identity linked to another account")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_DeleteUserIdentity(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		PWAuth:       true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+
+	t.Run("delete should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserIdentityRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, user.ID),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_identity_").
+			WithArgs(5).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.DeleteUserIdentity(ctx, user, refID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete other user identity should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserIdentityRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, 2),
+			)
+
+		err := svc.DeleteUserIdentity(ctx, user, refID)
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete last identity without other auth should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		ssoUser := *user
+		ssoUser.PWAuth = false
+		refID := util.Must(model.NewUserIdentityRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_identity_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, user.ID),
+			)
+		mock.ExpectQuery("^SELECT count(.+) FROM user_identity_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+
+		err := svc.DeleteUserIdentity(ctx, &ssoUser, refID)
+		errs.AssertError(t, err, errs.FailedPrecondition,
+			"refusing to remove last identity when no other auth enabled")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+)
+
+var (
+	UserLoginLinkRefIDMatcher = reftag.NewMatcher[model.UserLoginLinkRefID]()
+	ParseUserLoginLinkRefID   = reftag.Parse[model.UserLoginLinkRefID]
+)
+
+func (s *Service) GetUserLoginLinkByRefID(
+	ctx context.Context, refID model.UserLoginLinkRefID,
+) (*model.UserLoginLink, errs.Error) {
+	link, err := model.GetUserLoginLinkByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("login link not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	return link, nil
+}
+
+func (s *Service) NewUserLoginLink(
+	ctx context.Context, userID int,
+) (*model.UserLoginLink, errs.Error) {
+	link, err := model.NewUserLoginLink(ctx, s.Db, userID)
+	if err != nil {
+		return nil, errs.Internal.Error("db error")
+	}
+	return link, nil
+}
+
+// ConsumeUserLoginLink uses up a login link, so it can only be used
+// once. Expired links are consumed too, but return a NotFound error.
+func (s *Service) ConsumeUserLoginLink(
+	ctx context.Context, refID model.UserLoginLinkRefID,
+) (*model.UserLoginLink, errs.Error) {
+	link, err := model.ConsumeUserLoginLink(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.NotFound.Error("login link not found")
+		default:
+			return nil, errs.Internal.Error("db error")
+		}
+	}
+	if IsTimerExpired(link.RefID, model.UserLoginLinkExpiry) {
+		return nil, errs.NotFound.Error("login link expired")
+	}
+	return link, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_ConsumeUserLoginLink(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+
+	t.Run("consume should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserLoginLinkRefID())
+		mock.ExpectBegin()
+		mock.ExpectQuery("^DELETE FROM user_login_link_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"ref_id", "user_id", "created"}).
+				AddRow(refID, userID, tstTs))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		link, err := svc.ConsumeUserLoginLink(ctx, refID)
+		assert.Nil(t, err)
+		assert.Equal(t, link.UserID, userID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("consume used link should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserLoginLinkRefID())
+		mock.ExpectBegin()
+		mock.ExpectQuery("^DELETE FROM user_login_link_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"ref_id", "user_id", "created"}))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		_, err := svc.ConsumeUserLoginLink(ctx, refID)
+		errs.AssertError(t, err, errs.NotFound, "login link not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("consume expired link should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserLoginLinkRefID())
+		refID.SetTime(time.Now().Add(-model.UserLoginLinkExpiry - time.Minute))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^DELETE FROM user_login_link_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"ref_id", "user_id", "created"}).
+				AddRow(refID, userID, tstTs))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		_, err := svc.ConsumeUserLoginLink(ctx, refID)
+		errs.AssertError(t, err, errs.NotFound, "login link expired")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+)
+
+var (
+	UserSessionRefIDMatcher = reftag.NewMatcher[model.UserSessionRefID]()
+	ParseUserSessionRefID   = reftag.Parse[model.UserSessionRefID]
+)
+
+// how stale a session last seen time can get, before it is updated.
+// avoids a write on every request.
+const userSessionTouchInterval = 5 * time.Minute
+
+func (s *Service) NewUserSession(
+	ctx context.Context, userID int, ipAddress, userAgent string,
+	expires time.Time,
+) (*model.UserSession, errs.Error) {
+	// sessions that expired in the session store leave their index
+	// entry behind. tidy them up as the user logs in again.
+	err := model.DeleteExpiredUserSessionsByUser(ctx, s.Db, userID)
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	sess, err := model.NewUserSession(ctx, s.Db,
+		userID, ipAddress, userAgent, expires.UTC())
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	return sess, nil
+}
+
+func (s *Service) GetUserSessions(
+	ctx context.Context, userID int,
+) ([]*model.UserSession, errs.Error) {
+	sessions, err := model.GetUserSessionsByUser(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return []*model.UserSession{}, nil
+		default:
+			return nil, errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	return sessions, nil
+}
+
+// CheckUserSession returns NotFound if the session was revoked or has
+// expired, and marks it as seen otherwise.
+func (s *Service) CheckUserSession(
+	ctx context.Context, userID int, refID model.UserSessionRefID,
+) errs.Error {
+	sess, err := model.GetUserSessionByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return errs.NotFound.Error("session not found")
+		default:
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+
+	if sess.UserID != userID || !sess.Expires.After(time.Now().UTC()) {
+		return errs.NotFound.Error("session not found")
+	}
+
+	if time.Since(sess.LastSeen) > userSessionTouchInterval {
+		if err := model.TouchUserSession(ctx, s.Db, sess.ID); err != nil {
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) DeleteUserSession(
+	ctx context.Context, userID int, refID model.UserSessionRefID,
+) errs.Error {
+	sess, err := model.GetUserSessionByRefID(ctx, s.Db, refID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return errs.NotFound.Error("session not found")
+		default:
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+
+	if sess.UserID != userID {
+		return errs.PermissionDenied.Error("permission denied")
+	}
+
+	err = model.DeleteUserSession(ctx, s.Db, sess.ID)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserSessions revokes all sessions of the user.
+func (s *Service) DeleteUserSessions(
+	ctx context.Context, userID int,
+) errs.Error {
+	err := model.DeleteUserSessionsByUser(ctx, s.Db, userID)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
+
+// DeleteOtherUserSessions revokes all sessions of the user, except the
+// one given.
+func (s *Service) DeleteOtherUserSessions(
+	ctx context.Context, userID int, keepRefID model.UserSessionRefID,
+) errs.Error {
+	err := model.DeleteOtherUserSessionsByUser(ctx, s.Db, userID, keepRefID)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_NewUserSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new session should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		expires := tstTs.Add(24 * time.Hour)
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_session_").
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO user_session_").
+			WithArgs(pgx.NamedArgs{
+				"refID":     pgxmock.AnyArg(),
+				"userID":    1,
+				"ipAddress": "192.0.2.1",
+				"userAgent": "test-agent",
+				"expires":   expires,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "ip_address", "user_agent", "expires"}).
+				AddRow(1, refID, 1, "192.0.2.1", "test-agent", expires),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		sess, err := svc.NewUserSession(ctx, 1, "192.0.2.1", "test-agent", expires)
+		assert.Nil(t, err)
+		assert.Equal(t, sess.RefID, refID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_CheckUserSession(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"id", "ref_id", "user_id", "last_seen", "expires"}
+
+	t.Run("recently seen session should not be touched", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+		now := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 1, now, now.Add(time.Hour)),
+			)
+
+		err := svc.CheckUserSession(ctx, 1, refID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("stale session should be touched", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+		now := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 1, now.Add(-time.Hour), now.Add(time.Hour)),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_session_").
+			WithArgs(5).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.CheckUserSession(ctx, 1, refID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("revoked session should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnError(pgx.ErrNoRows)
+
+		err := svc.CheckUserSession(ctx, 1, refID)
+		errs.AssertError(t, err, errs.NotFound, "session not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("session of other user should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+		now := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 2, now, now.Add(time.Hour)),
+			)
+
+		err := svc.CheckUserSession(ctx, 1, refID)
+		errs.AssertError(t, err, errs.NotFound, "session not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("expired session should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+		now := time.Now().UTC()
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(5, refID, 1, now.Add(-2*time.Hour), now.Add(-time.Hour)),
+			)
+
+		err := svc.CheckUserSession(ctx, 1, refID)
+		errs.AssertError(t, err, errs.NotFound, "session not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_DeleteUserSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delete should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, 1),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_session_").
+			WithArgs(5).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.DeleteUserSession(ctx, 1, refID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete session of other user should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id"}).
+				AddRow(5, refID, 2),
+			)
+
+		err := svc.DeleteUserSession(ctx, 1, refID)
+		errs.AssertError(t, err, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete missing session should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_session_").
+			WithArgs(refID).
+			WillReturnError(pgx.ErrNoRows)
+
+		err := svc.DeleteUserSession(ctx, 1, refID)
+		errs.AssertError(t, err, errs.NotFound, "session not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_DeleteOtherUserSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delete others should keep current", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		refID := util.Must(model.NewUserSessionRefID())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_session_ WHERE user_id = (.+) AND ref_id != (.+)").
+			WithArgs(1, refID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.DeleteOtherUserSessions(ctx, 1, refID)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
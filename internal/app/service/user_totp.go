@@ -0,0 +1,269 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/crypto"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+)
+
+const (
+	// TOTPIssuer is shown alongside the account name in authenticator apps
+	TOTPIssuer = "ICanBringThat"
+	// number of recovery codes generated at a time
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10
+)
+
+type TOTPEnrollment struct {
+	// Secret is the base32 encoded secret, for manual entry
+	Secret string
+	// KeyURI is the otpauth:// uri, for rendering as a QR code
+	KeyURI string
+}
+
+// generateRecoveryCode returns a random recovery code, formatted for
+// display as two dash separated groups.
+func generateRecoveryCode() string {
+	code := strings.ToLower(rand.Text()[:recoveryCodeSize])
+	return code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:]
+}
+
+// normalizeRecoveryCode strips the formatting a user may have kept or
+// added when typing in a recovery code.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
+
+func (s *Service) hashRecoveryCode(code string) []byte {
+	return s.mac.Generate([]byte(normalizeRecoveryCode(code)))
+}
+
+// StartTOTPEnrollment begins (or resumes) totp enrollment for user. The
+// enrollment is pending until confirmed with ConfirmTOTPEnrollment.
+func (s *Service) StartTOTPEnrollment(ctx context.Context,
+	user *model.User,
+) (*TOTPEnrollment, errs.Error) {
+	var secret []byte
+	userTOTP, err := model.GetUserTOTP(ctx, s.Db, user.ID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// no enrollment yet
+	case err != nil:
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	case userTOTP.Enabled:
+		return nil, errs.FailedPrecondition.Error("two-factor authentication already enabled")
+	default:
+		// resume the pending enrollment, so a secret that was already
+		// scanned stays valid
+		secret, err = s.secrets.Open(userTOTP.Secret)
+		if err != nil {
+			slog.ErrorContext(ctx, "error opening totp secret",
+				"userID", user.ID, "error", err)
+			secret = nil
+		}
+	}
+
+	if secret == nil {
+		secret = crypto.GenerateTOTPSecret()
+		_, err = model.CreatePendingUserTOTP(ctx, s.Db, user.ID, s.secrets.Seal(secret))
+		if err != nil {
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				return nil, errs.FailedPrecondition.Error("two-factor authentication already enabled")
+			default:
+				return nil, errs.Internal.Errorf("db error: %w", err)
+			}
+		}
+	}
+
+	return &TOTPEnrollment{
+		Secret: crypto.EncodeTOTPSecret(secret),
+		KeyURI: crypto.TOTPKeyURI(TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables totp for userID if code is valid for the
+// pending enrollment, and returns a fresh set of recovery codes. The
+// recovery codes are only stored hashed, so can not be shown again.
+func (s *Service) ConfirmTOTPEnrollment(ctx context.Context,
+	userID int, code string,
+) ([]string, errs.Error) {
+	userTOTP, err := model.GetUserTOTP(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errs.FailedPrecondition.Error("two-factor enrollment not started")
+		default:
+			return nil, errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	if userTOTP.Enabled {
+		return nil, errs.FailedPrecondition.Error("two-factor authentication already enabled")
+	}
+
+	secret, err := s.secrets.Open(userTOTP.Secret)
+	if err != nil {
+		return nil, errs.Internal.Errorf("error opening totp secret: %w", err)
+	}
+	step, ok := crypto.ValidateTOTP(secret, strings.TrimSpace(code),
+		time.Now(), userTOTP.LastStep)
+	if !ok {
+		return nil, errs.InvalidArgument.Error("invalid two-factor code")
+	}
+
+	codes, hashes := s.newRecoveryCodes()
+	errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		if err := model.EnableUserTOTP(ctx, tx, userID, step); err != nil {
+			return err
+		}
+		if err := model.DeleteUserRecoveryCodes(ctx, tx, userID); err != nil {
+			return err
+		}
+		return model.CreateUserRecoveryCodes(ctx, tx, userID, hashes)
+	})
+	if errx != nil {
+		return nil, errx
+	}
+	return codes, nil
+}
+
+func (s *Service) newRecoveryCodes() ([]string, [][]byte) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([][]byte, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code := generateRecoveryCode()
+		codes = append(codes, code)
+		hashes = append(hashes, s.hashRecoveryCode(code))
+	}
+	return codes, hashes
+}
+
+func (s *Service) IsTOTPEnabled(ctx context.Context, userID int) (bool, errs.Error) {
+	userTOTP, err := model.GetUserTOTP(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return false, nil
+		default:
+			return false, errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	return userTOTP.Enabled, nil
+}
+
+func (s *Service) GetRecoveryCodesRemaining(ctx context.Context, userID int) (int, errs.Error) {
+	count, err := model.GetUnusedUserRecoveryCodeCount(ctx, s.Db, userID)
+	if err != nil {
+		return 0, errs.Internal.Errorf("db error: %w", err)
+	}
+	return count, nil
+}
+
+// VerifyTOTP checks a second factor code for userID. A code of
+// crypto.TOTPDigits digits is checked as a totp code, which may only be
+// used once. Anything else is checked as a recovery code, which is
+// consumed on success.
+func (s *Service) VerifyTOTP(ctx context.Context, userID int, code string) errs.Error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return errs.InvalidArgument.Error("missing two-factor code")
+	}
+
+	userTOTP, err := model.GetUserTOTP(ctx, s.Db, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return errs.FailedPrecondition.Error("two-factor authentication not enabled")
+		default:
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	if !userTOTP.Enabled {
+		return errs.FailedPrecondition.Error("two-factor authentication not enabled")
+	}
+
+	if len(code) != crypto.TOTPDigits {
+		_, err = model.UseUserRecoveryCode(ctx, s.Db, userID, s.hashRecoveryCode(code))
+		if err != nil {
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				return errs.PermissionDenied.Error("invalid two-factor code")
+			default:
+				return errs.Internal.Errorf("db error: %w", err)
+			}
+		}
+		slog.InfoContext(ctx, "recovery code used", "userID", userID)
+		return nil
+	}
+
+	secret, err := s.secrets.Open(userTOTP.Secret)
+	if err != nil {
+		return errs.Internal.Errorf("error opening totp secret: %w", err)
+	}
+	step, ok := crypto.ValidateTOTP(secret, code, time.Now(), userTOTP.LastStep)
+	if !ok {
+		return errs.PermissionDenied.Error("invalid two-factor code")
+	}
+	// record the step, so the same code can't be replayed. a concurrent
+	// use of the same (or a later) code shows up as no rows updated.
+	_, err = model.UpdateUserTOTPLastStep(ctx, s.Db, userID, step)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return errs.PermissionDenied.Error("invalid two-factor code")
+		default:
+			return errs.Internal.Errorf("db error: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) DisableTOTP(ctx context.Context, userID int) errs.Error {
+	return TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		if err := model.DeleteUserRecoveryCodes(ctx, tx, userID); err != nil {
+			return err
+		}
+		return model.DeleteUserTOTP(ctx, tx, userID)
+	})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes for userID,
+// returning the new ones.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int) ([]string, errs.Error) {
+	enabled, errx := s.IsTOTPEnabled(ctx, userID)
+	if errx != nil {
+		return nil, errx
+	}
+	if !enabled {
+		return nil, errs.FailedPrecondition.Error("two-factor authentication not enabled")
+	}
+
+	codes, hashes := s.newRecoveryCodes()
+	errx = TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+		if err := model.DeleteUserRecoveryCodes(ctx, tx, userID); err != nil {
+			return err
+		}
+		return model.CreateUserRecoveryCodes(ctx, tx, userID, hashes)
+	})
+	if errx != nil {
+		return nil, errx
+	}
+	return codes, nil
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/crypto"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+)
+
+func TestService_ConfirmTOTPEnrollment(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+	secret := []byte("12345678901234567890")
+	columns := []string{"user_id", "secret", "enabled", "last_step"}
+
+	t.Run("valid code should enable", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		step := crypto.TOTPStep(time.Now())
+		code := crypto.TOTPCode(secret, step)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), false, int64(0)))
+		mock.ExpectBegin()
+		// inner tx start
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE user_totp_").
+			WithArgs(pgx.NamedArgs{
+				"userID": userID,
+				"step":   pgxmock.AnyArg(),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		// end inner tx
+		// inner tx start
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM user_recovery_code_").
+			WithArgs(userID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		// end inner tx
+		// inner tx start
+		mock.ExpectBegin()
+		mock.ExpectExec("^INSERT INTO user_recovery_code_").
+			WithArgs(pgx.NamedArgs{
+				"userID":     userID,
+				"codeHashes": pgxmock.AnyArg(),
+			}).
+			WillReturnResult(pgxmock.NewResult("INSERT", recoveryCodeCount))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		// end inner tx
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		codes, err := svc.ConfirmTOTPEnrollment(ctx, userID, code)
+		assert.Nil(t, err)
+		assert.Equal(t, len(codes), recoveryCodeCount)
+		assert.Equal(t, len(codes[0]), recoveryCodeSize+1)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("invalid code should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), false, int64(0)))
+
+		_, err := svc.ConfirmTOTPEnrollment(ctx, userID, "abcdef")
+		errs.AssertError(t, err, errs.InvalidArgument, "invalid two-factor code")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("already enabled should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), true, int64(0)))
+
+		_, err := svc.ConfirmTOTPEnrollment(ctx, userID, "123456")
+		errs.AssertError(t, err, errs.FailedPrecondition,
+			"two-factor authentication already enabled")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_VerifyTOTP(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+	secret := []byte("12345678901234567890")
+	columns := []string{"user_id", "secret", "enabled", "last_step"}
+
+	t.Run("valid code should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		step := crypto.TOTPStep(time.Now())
+		code := crypto.TOTPCode(secret, step)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), true, step-2))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^UPDATE user_totp_").
+			WithArgs(pgx.NamedArgs{
+				"userID": userID,
+				"step":   pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, []byte{}, true, step))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := svc.VerifyTOTP(ctx, userID, code)
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("already used code should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		step := crypto.TOTPStep(time.Now())
+		code := crypto.TOTPCode(secret, step)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), true, step+1))
+
+		err := svc.VerifyTOTP(ctx, userID, code)
+		errs.AssertError(t, err, errs.PermissionDenied, "invalid two-factor code")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("concurrent use of code should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		step := crypto.TOTPStep(time.Now())
+		code := crypto.TOTPCode(secret, step)
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), true, step-2))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^UPDATE user_totp_").
+			WithArgs(pgx.NamedArgs{
+				"userID": userID,
+				"step":   pgxmock.AnyArg(),
+			}).
+			WillReturnRows(pgxmock.NewRows(columns))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		err := svc.VerifyTOTP(ctx, userID, code)
+		errs.AssertError(t, err, errs.PermissionDenied, "invalid two-factor code")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("recovery code should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), true, int64(0)))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^UPDATE user_recovery_code_").
+			WithArgs(pgx.NamedArgs{
+				"userID":   userID,
+				"codeHash": svc.mac.Generate([]byte("abcdefghij")),
+			}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id"}).
+				AddRow(1, userID))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		// formatting and case are ignored
+		err := svc.VerifyTOTP(ctx, userID, "ABCDE-fghij")
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("used recovery code should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), true, int64(0)))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^UPDATE user_recovery_code_").
+			WithArgs(pgx.NamedArgs{
+				"userID":   userID,
+				"codeHash": svc.mac.Generate([]byte("abcdefghij")),
+			}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id"}))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		err := svc.VerifyTOTP(ctx, userID, "abcde-fghij")
+		errs.AssertError(t, err, errs.PermissionDenied, "invalid two-factor code")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("not enabled should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock, HMACKeyBytes: []byte("test-key")})
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_totp_").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(userID, svc.secrets.Seal(secret), false, int64(0)))
+
+		err := svc.VerifyTOTP(ctx, userID, "123456")
+		errs.AssertError(t, err, errs.FailedPrecondition,
+			"two-factor authentication not enabled")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
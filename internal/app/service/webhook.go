@@ -5,11 +5,11 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/validate"
 )
@@ -49,18 +49,25 @@ func (s *Service) DisableRemindersWithNotification(
 	// bounced email, marked spam, unsubscribed...etc
 	// so... disable reminders
 	user.Settings.EnableReminders = false
+	var notification *model.Notification
 	errx = TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
 		innerErr := s.updateUserSettings(ctx, tx, user.ID, &user.Settings)
 		if innerErr != nil {
 			return innerErr
 		}
-		_, innerErr = s.newNotification(ctx, tx, user.ID,
-			fmt.Sprintf("email notifications disabled due to '%s'", suppressionReason),
+		notification, innerErr = s.newTypedNotification(ctx, tx, user.ID,
+			model.NotificationKindRemindersDisabled,
+			model.NotificationPayload{Reason: suppressionReason},
 		)
 		if innerErr != nil {
 			return innerErr
 		}
 		return nil
 	})
-	return errx
+	if errx != nil {
+		return errx
+	}
+	// only published once committed, so subscribers can fetch it
+	s.publishNotificationCreated(ctx, notification)
+	return nil
 }
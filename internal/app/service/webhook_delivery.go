@@ -0,0 +1,213 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+const (
+	webhookBatchSize   = 10
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	webhookTimeout     = 10 * time.Second
+	// how long a claimed batch is reserved for the claiming worker. the
+	// batch is sent one delivery at a time, so the lease has to outlast
+	// every request in it timing out.
+	webhookLease     = webhookBatchSize*webhookTimeout + time.Minute
+	webhookRetention = 30 * 24 * time.Hour
+)
+
+// errWebhookLeaseLost is returned when a delivery was re-claimed by
+// another worker before its attempt could be recorded.
+var errWebhookLeaseLost = errors.New("webhook delivery lease lost")
+
+// webhook request headers
+const (
+	WebhookHeaderEvent     = "X-Icbt-Event"
+	WebhookHeaderDelivery  = "X-Icbt-Delivery"
+	WebhookHeaderTimestamp = "X-Icbt-Timestamp"
+	WebhookHeaderSignature = "X-Icbt-Signature"
+)
+
+// SignWebhookPayload returns the signature sent in the
+// X-Icbt-Signature header: a hex encoded HMAC-SHA256 over
+// "<timestamp>.<body>", keyed with the endpoint secret.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10))) // #nosec G104 -- doesn't actually return errors
+	mac.Write([]byte("."))                              // #nosec G104
+	mac.Write(body)                                     // #nosec G104
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns the delay before the next attempt, doubling
+// with each failed attempt.
+func webhookBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := webhookBaseBackoff << min(attempts-1, 20)
+	return min(backoff, webhookMaxBackoff)
+}
+
+// NewWebhookClient returns an http client for webhook deliveries. Unless
+// allowPrivate is set, connections to loopback, private, and link-local
+// addresses are refused, so endpoints can't be used to probe the
+// internal network.
+func NewWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+				ip.IsUnspecified() {
+				return fmt.Errorf("refusing to connect to %s", host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return &http.Client{
+		Transport: transport,
+		Timeout:   webhookTimeout,
+		// a redirect could point anywhere. don't follow them.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// DeliverWebhooks sends due webhook deliveries, recording each attempt.
+// Failed deliveries are retried with exponential backoff, until
+// webhookMaxAttempts is reached.
+func (s *Service) DeliverWebhooks(ctx context.Context, client *http.Client) error {
+	deliveries, err := model.ClaimWebhookDeliveries(
+		ctx, s.Db, webhookBatchSize, webhookLease)
+	if err != nil {
+		return fmt.Errorf("error claiming deliveries: %w", err)
+	}
+
+	endpoints := make(map[int]*model.WebhookEndpoint)
+	for _, delivery := range deliveries {
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			endpoint, err = model.GetWebhookEndpointByID(ctx, s.Db, delivery.EndpointID)
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				// endpoint removed. its deliveries go with it.
+				continue
+			case err != nil:
+				return fmt.Errorf("db error: %w", err)
+			}
+			endpoints[endpoint.ID] = endpoint
+		}
+
+		statusCode, errMsg := 0, "endpoint disabled"
+		if endpoint.Enabled {
+			statusCode, errMsg = sendWebhook(ctx, client, endpoint, delivery)
+		}
+
+		attempts := delivery.Attempts + 1
+		status := model.WebhookDeliveryPending
+		nextAttempt := time.Now().UTC().Add(webhookBackoff(attempts))
+		switch {
+		case statusCode >= 200 && statusCode < 300:
+			status = model.WebhookDeliveryDelivered
+		case !endpoint.Enabled || attempts >= webhookMaxAttempts:
+			status = model.WebhookDeliveryFailed
+		}
+
+		errx := TxnFunc(ctx, s.Db, func(tx pgx.Tx) error {
+			// only record the attempt if the lease is still ours
+			rows, err := model.UpdateWebhookDelivery(ctx, tx, delivery.ID,
+				delivery.NextAttempt, status, attempts, nextAttempt)
+			if err != nil {
+				return err
+			}
+			if rows == 0 {
+				return errWebhookLeaseLost
+			}
+			_, err = model.CreateWebhookAttempt(ctx, tx, delivery, statusCode, errMsg)
+			return err
+		})
+		switch {
+		case errors.Is(errx, errWebhookLeaseLost):
+			slog.InfoContext(ctx, "webhook delivery lease lost",
+				"delivery", delivery.ID)
+		case errx != nil:
+			return fmt.Errorf("error updating database: %w", errx)
+		}
+	}
+
+	err = model.DeleteWebhookDeliveriesBefore(
+		ctx, s.Db, time.Now().UTC().Add(-webhookRetention))
+	if err != nil {
+		return fmt.Errorf("error pruning deliveries: %w", err)
+	}
+	return nil
+}
+
+// sendWebhook posts a single delivery, returning the response status
+// code (zero if none was received) and any error message.
+func sendWebhook(ctx context.Context, client *http.Client,
+	endpoint *model.WebhookEndpoint, delivery *model.WebhookDelivery,
+) (int, string) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	vinfo, _ := util.GetVersion()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("icanbringthat-webhooks %s", vinfo.Version))
+	req.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	req.Header.Set(WebhookHeaderDelivery, strconv.Itoa(delivery.ID))
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature,
+		SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		slog.InfoContext(ctx, "webhook delivery failed",
+			"delivery", delivery.ID, "error", err)
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	// drain a little of the body, so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, resp.Status
+	}
+	return resp.StatusCode, ""
+}
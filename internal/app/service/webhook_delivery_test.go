@@ -0,0 +1,266 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	sig := SignWebhookPayload("0123456789abcdef", 1700000000, []byte(`{"a":1}`))
+	assert.Equal(t, sig,
+		"sha256=9eb18f493f8ec135d9eb2dad817c369bb4e9cbfa818657897a7437c1cd8c3a23")
+	// signature depends on timestamp, body, and secret
+	assert.True(t, sig != SignWebhookPayload("0123456789abcdef", 1700000001, []byte(`{"a":1}`)))
+	assert.True(t, sig != SignWebhookPayload("0123456789abcdef", 1700000000, []byte(`{"a":2}`)))
+	assert.True(t, sig != SignWebhookPayload("fedcba9876543210", 1700000000, []byte(`{"a":1}`)))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, webhookBackoff(0), webhookBaseBackoff)
+	assert.Equal(t, webhookBackoff(1), webhookBaseBackoff)
+	assert.Equal(t, webhookBackoff(2), 2*webhookBaseBackoff)
+	assert.Equal(t, webhookBackoff(3), 4*webhookBaseBackoff)
+	assert.Equal(t, webhookBackoff(100), webhookMaxBackoff)
+}
+
+func TestService_DeliverWebhooks(t *testing.T) {
+	t.Parallel()
+
+	endpoint := &model.WebhookEndpoint{
+		ID:         2,
+		RefID:      util.Must(model.NewWebhookEndpointRefID()),
+		UserID:     1,
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{},
+		Enabled:    true,
+	}
+	payload := []byte(`{"type":"item-added"}`)
+	leasedUntil := tstTs.Add(webhookLease)
+
+	expectDelivery := func(mock pgxmock.PgxConnIface, url string, attempts int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("^UPDATE webhook_delivery_").
+			WithArgs(pgx.NamedArgs{
+				"limit":     webhookBatchSize,
+				"leaseSecs": webhookLease.Seconds(),
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{
+					"id", "endpoint_id", "event_type", "payload", "status",
+					"attempts", "next_attempt",
+				}).
+				AddRow(
+					5, endpoint.ID, pubsub.ChangeItemAdded, payload,
+					model.WebhookDeliveryPending, attempts, leasedUntil,
+				),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectQuery("^SELECT (.+) FROM webhook_endpoint_").
+			WithArgs(endpoint.ID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "url", "secret", "event_types", "enabled"}).
+				AddRow(
+					endpoint.ID, endpoint.RefID, endpoint.UserID, url,
+					endpoint.Secret, endpoint.EventTypes, endpoint.Enabled,
+				),
+			)
+	}
+
+	expectUpdate := func(mock pgxmock.PgxConnIface, status string, attempts int, rows int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE webhook_delivery_").
+			WithArgs(pgx.NamedArgs{
+				"status":      status,
+				"attempts":    attempts,
+				"nextAttempt": pgxmock.AnyArg(),
+				"deliveryID":  5,
+				"leasedUntil": leasedUntil,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", rows))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+	}
+
+	expectPrune := func(mock pgxmock.PgxConnIface) {
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM webhook_delivery_").
+			WithArgs(pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+	}
+
+	expectRecord := func(mock pgxmock.PgxConnIface, statusCode int, errMsg, status string, attempts int) {
+		mock.ExpectBegin()
+		expectUpdate(mock, status, attempts, 1)
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO webhook_attempt_").
+			WithArgs(pgx.NamedArgs{
+				"endpointID": endpoint.ID,
+				"deliveryID": 5,
+				"eventType":  pubsub.ChangeItemAdded,
+				"statusCode": statusCode,
+				"error":      errMsg,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "endpoint_id", "delivery_id", "event_type", "status_code", "error"}).
+				AddRow(1, endpoint.ID, 5, pubsub.ChangeItemAdded, statusCode, errMsg),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		expectPrune(mock)
+	}
+
+	t.Run("deliver should send signed payload", func(t *testing.T) {
+		t.Parallel()
+
+		var gotBody []byte
+		var gotHeader http.Header
+		ts := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header.Clone()
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+		t.Cleanup(ts.Close)
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		expectDelivery(mock, ts.URL, 0)
+		expectRecord(mock, http.StatusNoContent, "", model.WebhookDeliveryDelivered, 1)
+
+		err := svc.DeliverWebhooks(ctx, NewWebhookClient(true))
+		assert.Nil(t, err)
+		assert.Equal(t, gotBody, payload)
+		assert.Equal(t, gotHeader.Get(WebhookHeaderEvent), pubsub.ChangeItemAdded)
+		assert.Equal(t, gotHeader.Get(WebhookHeaderDelivery), "5")
+		timestamp, err := strconv.ParseInt(gotHeader.Get(WebhookHeaderTimestamp), 10, 64)
+		assert.Nil(t, err)
+		assert.Equal(t, gotHeader.Get(WebhookHeaderSignature),
+			SignWebhookPayload(endpoint.Secret, timestamp, payload))
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("deliver with lease lost should not record attempt", func(t *testing.T) {
+		t.Parallel()
+
+		ts := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+		t.Cleanup(ts.Close)
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		expectDelivery(mock, ts.URL, 0)
+		// another worker re-claimed the delivery in the meantime
+		mock.ExpectBegin()
+		expectUpdate(mock, model.WebhookDeliveryDelivered, 1, 0)
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+		expectPrune(mock)
+
+		err := svc.DeliverWebhooks(ctx, NewWebhookClient(true))
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("lease should outlast a batch of timeouts", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, webhookLease > webhookBatchSize*webhookTimeout)
+	})
+
+	t.Run("deliver failure should be retried", func(t *testing.T) {
+		t.Parallel()
+
+		ts := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+		t.Cleanup(ts.Close)
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		expectDelivery(mock, ts.URL, 1)
+		expectRecord(mock, http.StatusInternalServerError,
+			"500 Internal Server Error", model.WebhookDeliveryPending, 2)
+
+		err := svc.DeliverWebhooks(ctx, NewWebhookClient(true))
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("deliver failure at max attempts should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ts := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+		t.Cleanup(ts.Close)
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		expectDelivery(mock, ts.URL, webhookMaxAttempts-1)
+		expectRecord(mock, http.StatusBadGateway,
+			"502 Bad Gateway", model.WebhookDeliveryFailed, webhookMaxAttempts)
+
+		err := svc.DeliverWebhooks(ctx, NewWebhookClient(true))
+		assert.Nil(t, err)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("private addresses should be refused", func(t *testing.T) {
+		t.Parallel()
+
+		ts := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				t.Error("request should not have been received")
+			}))
+		t.Cleanup(ts.Close)
+
+		req, err := http.NewRequest("POST", ts.URL, nil)
+		assert.Nil(t, err)
+		_, err = NewWebhookClient(false).Do(req)
+		assert.True(t, err != nil)
+	})
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/dropwhile/refid/v2/reftag"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/validate"
+)
+
+var (
+	WebhookEndpointRefIDMatcher   = reftag.NewMatcher[model.WebhookEndpointRefID]()
+	WebhookEndpointRefIDFromBytes = reftag.FromBytes[model.WebhookEndpointRefID]
+	ParseWebhookEndpointRefID     = reftag.Parse[model.WebhookEndpointRefID]
+)
+
+// WebhookEventTypes are the event types an endpoint can subscribe to.
+// They mirror the event changes published for live updates.
+var WebhookEventTypes = []string{
+	pubsub.ChangeEventUpdated,
+	pubsub.ChangeItemAdded,
+	pubsub.ChangeItemUpdated,
+	pubsub.ChangeItemRemoved,
+	pubsub.ChangeItemsSorted,
+	pubsub.ChangeEarmarkCreated,
+	pubsub.ChangeEarmarkDeleted,
+}
+
+const maxWebhookEndpoints = 10
+
+// WebhookPayload is the json body posted to webhook endpoints
+type WebhookPayload struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Type       string    `json:"type"`
+	EventRefID string    `json:"event_ref_id"`
+	RefID      string    `json:"ref_id"`
+}
+
+func (s *Service) GetWebhookEndpoints(
+	ctx context.Context, userID int,
+) ([]*model.WebhookEndpoint, errs.Error) {
+	endpoints, err := model.GetWebhookEndpointsByUser(ctx, s.Db, userID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		endpoints = []*model.WebhookEndpoint{}
+	case err != nil:
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	return endpoints, nil
+}
+
+func (s *Service) GetWebhookEndpoint(
+	ctx context.Context, userID int, refID model.WebhookEndpointRefID,
+) (*model.WebhookEndpoint, errs.Error) {
+	endpoint, err := model.GetWebhookEndpointByRefID(ctx, s.Db, refID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, errs.NotFound.Error("webhook not found")
+	case err != nil:
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+
+	if userID != endpoint.UserID {
+		return nil, errs.PermissionDenied.Error("permission denied")
+	}
+	return endpoint, nil
+}
+
+func (s *Service) NewWebhookEndpoint(
+	ctx context.Context, userID int,
+	endpointURL, secret string, eventTypes []string,
+) (*model.WebhookEndpoint, errs.Error) {
+	if errx := validateWebhookURL(endpointURL); errx != nil {
+		return nil, errx
+	}
+
+	err := validate.Validate.VarCtx(ctx, secret, "omitempty,notblank,min=16,max=128")
+	if err != nil {
+		slog.
+			With("field", "secret").
+			With("error", err).
+			Info("bad field value")
+		return nil, errs.ArgumentError("secret", "bad value")
+	}
+	if secret == "" {
+		secret = generateWebhookSecret()
+	}
+
+	eventTypes, errx := cleanWebhookEventTypes(eventTypes)
+	if errx != nil {
+		return nil, errx
+	}
+
+	count, err := model.GetWebhookEndpointCountByUser(ctx, s.Db, userID)
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	if count >= maxWebhookEndpoints {
+		return nil, errs.ResourceExhausted.Error("too many webhooks")
+	}
+
+	endpoint, err := model.NewWebhookEndpoint(
+		ctx, s.Db, userID, endpointURL, secret, eventTypes)
+	if err != nil {
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	return endpoint, nil
+}
+
+type WebhookEndpointUpdateValues struct {
+	URL        mo.Option[string]
+	Secret     mo.Option[string] `validate:"omitnil,notblank,min=16,max=128"`
+	EventTypes mo.Option[[]string]
+	Enabled    mo.Option[bool]
+}
+
+func (s *Service) UpdateWebhookEndpoint(
+	ctx context.Context, userID int,
+	refID model.WebhookEndpointRefID, wuvs *WebhookEndpointUpdateValues,
+) errs.Error {
+	// if no values, error
+	if wuvs.URL.IsAbsent() &&
+		wuvs.Secret.IsAbsent() &&
+		wuvs.EventTypes.IsAbsent() &&
+		wuvs.Enabled.IsAbsent() {
+		return errs.InvalidArgument.Error("missing fields")
+	}
+
+	err := validate.Validate.StructCtx(ctx, wuvs)
+	if err != nil {
+		badField := validate.GetErrorField(err)
+		slog.
+			With("field", badField).
+			With("error", err).
+			Info("bad field value")
+		return errs.ArgumentError(badField, "bad value")
+	}
+
+	if val, ok := wuvs.URL.Get(); ok {
+		if errx := validateWebhookURL(val); errx != nil {
+			return errx
+		}
+	}
+
+	eventTypes := mo.None[[]string]()
+	if val, ok := wuvs.EventTypes.Get(); ok {
+		cleaned, errx := cleanWebhookEventTypes(val)
+		if errx != nil {
+			return errx
+		}
+		eventTypes = mo.Some(cleaned)
+	}
+
+	endpoint, errx := s.GetWebhookEndpoint(ctx, userID, refID)
+	if errx != nil {
+		return errx
+	}
+
+	err = model.UpdateWebhookEndpoint(ctx, s.Db, endpoint.ID,
+		&model.WebhookEndpointUpdateModelValues{
+			URL:        wuvs.URL,
+			Secret:     wuvs.Secret,
+			EventTypes: eventTypes,
+			Enabled:    wuvs.Enabled,
+		},
+	)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) DeleteWebhookEndpoint(
+	ctx context.Context, userID int, refID model.WebhookEndpointRefID,
+) errs.Error {
+	endpoint, errx := s.GetWebhookEndpoint(ctx, userID, refID)
+	if errx != nil {
+		return errx
+	}
+
+	err := model.DeleteWebhookEndpoint(ctx, s.Db, endpoint.ID)
+	if err != nil {
+		return errs.Internal.Errorf("db error: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) GetWebhookAttempts(
+	ctx context.Context, userID int,
+	refID model.WebhookEndpointRefID, limit int,
+) ([]*model.WebhookAttempt, errs.Error) {
+	endpoint, errx := s.GetWebhookEndpoint(ctx, userID, refID)
+	if errx != nil {
+		return nil, errx
+	}
+
+	attempts, err := model.GetWebhookAttemptsByEndpoint(
+		ctx, s.Db, endpoint.ID, limit)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		attempts = []*model.WebhookAttempt{}
+	case err != nil:
+		return nil, errs.Internal.Errorf("db error: %w", err)
+	}
+	return attempts, nil
+}
+
+// enqueueWebhooks is best effort. Failing to queue deliveries is logged,
+// but does not fail the change that triggered it.
+func (s *Service) enqueueWebhooks(ctx context.Context,
+	event *model.Event, change string, refID string,
+) {
+	payload, err := json.Marshal(&WebhookPayload{
+		Timestamp:  time.Now().UTC(),
+		Type:       change,
+		EventRefID: event.RefID.String(),
+		RefID:      refID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "error encoding webhook payload",
+			"error", err)
+		return
+	}
+	err = model.EnqueueWebhookDeliveries(ctx, s.Db, event.UserID, change, payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "error queueing webhook deliveries",
+			"error", err)
+	}
+}
+
+func validateWebhookURL(endpointURL string) errs.Error {
+	u, err := url.Parse(endpointURL)
+	if err != nil || u.Host == "" ||
+		(u.Scheme != "https" && u.Scheme != "http") ||
+		len(endpointURL) > 2048 {
+		return errs.ArgumentError("url", "bad value")
+	}
+	return nil
+}
+
+// cleanWebhookEventTypes validates and de-duplicates event types
+func cleanWebhookEventTypes(eventTypes []string) ([]string, errs.Error) {
+	cleaned := make([]string, 0, len(eventTypes))
+	for _, et := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, et) {
+			return nil, errs.ArgumentError("event_types", "bad value")
+		}
+		if !slices.Contains(cleaned, et) {
+			cleaned = append(cleaned, et)
+		}
+	}
+	return cleaned, nil
+}
+
+func generateWebhookSecret() string {
+	b := make([]byte, 32)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
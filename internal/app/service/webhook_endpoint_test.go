@@ -0,0 +1,351 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dropwhile/assert"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/samber/mo"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
+	"github.com/dropwhile/icanbringthat/internal/util"
+)
+
+func TestService_NewWebhookEndpoint(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	endpoint := &model.WebhookEndpoint{
+		ID:         2,
+		RefID:      util.Must(model.NewWebhookEndpointRefID()),
+		UserID:     user.ID,
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{pubsub.ChangeItemAdded},
+		Enabled:    true,
+	}
+
+	t.Run("new webhook should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT count(.+) FROM webhook_endpoint_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO webhook_endpoint_").
+			WithArgs(pgx.NamedArgs{
+				"refID":      WebhookEndpointRefIDMatcher,
+				"userID":     user.ID,
+				"url":        endpoint.URL,
+				"secret":     endpoint.Secret,
+				"eventTypes": endpoint.EventTypes,
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "url", "secret", "event_types", "enabled"}).
+				AddRow(
+					endpoint.ID, endpoint.RefID, endpoint.UserID, endpoint.URL,
+					endpoint.Secret, endpoint.EventTypes, endpoint.Enabled,
+				),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		result, errx := svc.NewWebhookEndpoint(ctx, user.ID,
+			endpoint.URL, endpoint.Secret,
+			[]string{pubsub.ChangeItemAdded, pubsub.ChangeItemAdded},
+		)
+		assert.Nil(t, errx)
+		assert.Equal(t, result.RefID, endpoint.RefID)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("new webhook with blank secret should generate one", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT count(.+) FROM webhook_endpoint_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("^INSERT INTO webhook_endpoint_").
+			WithArgs(pgx.NamedArgs{
+				"refID":      WebhookEndpointRefIDMatcher,
+				"userID":     user.ID,
+				"url":        endpoint.URL,
+				"secret":     pgxmock.AnyArg(),
+				"eventTypes": []string{},
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "url", "event_types", "enabled"}).
+				AddRow(
+					endpoint.ID, endpoint.RefID, endpoint.UserID, endpoint.URL,
+					[]string{}, endpoint.Enabled,
+				),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		_, errx := svc.NewWebhookEndpoint(ctx, user.ID, endpoint.URL, "", nil)
+		assert.Nil(t, errx)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("new webhook with bad url should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		for _, badURL := range []string{
+			"", "example.com/hook", "ftp://example.com/hook", "https://",
+		} {
+			_, errx := svc.NewWebhookEndpoint(ctx, user.ID, badURL, "", nil)
+			errs.AssertError(t, errx, errs.InvalidArgument, "url bad value")
+		}
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("new webhook with bad event type should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, errx := svc.NewWebhookEndpoint(ctx, user.ID,
+			endpoint.URL, "", []string{"hodor"})
+		errs.AssertError(t, errx, errs.InvalidArgument, "event_types bad value")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("new webhook with short secret should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		_, errx := svc.NewWebhookEndpoint(ctx, user.ID,
+			endpoint.URL, "short", nil)
+		errs.AssertError(t, errx, errs.InvalidArgument, "secret bad value")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("new webhook over limit should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT count(.+) FROM webhook_endpoint_").
+			WithArgs(user.ID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).
+				AddRow(maxWebhookEndpoints))
+
+		_, errx := svc.NewWebhookEndpoint(ctx, user.ID, endpoint.URL, "", nil)
+		errs.AssertError(t, errx, errs.ResourceExhausted, "too many webhooks")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_UpdateWebhookEndpoint(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{
+		ID:           1,
+		RefID:        util.Must(model.NewUserRefID()),
+		Email:        "user@example.com",
+		Name:         "user",
+		PWHash:       []byte("00x00"),
+		Verified:     true,
+		Created:      tstTs,
+		LastModified: tstTs,
+	}
+	endpoint := &model.WebhookEndpoint{
+		ID:         2,
+		RefID:      util.Must(model.NewWebhookEndpointRefID()),
+		UserID:     user.ID,
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{},
+		Enabled:    true,
+	}
+
+	t.Run("update webhook should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM webhook_endpoint_").
+			WithArgs(endpoint.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "url", "enabled"}).
+				AddRow(
+					endpoint.ID, endpoint.RefID, endpoint.UserID,
+					endpoint.URL, endpoint.Enabled,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^UPDATE webhook_endpoint_").
+			WithArgs(pgx.NamedArgs{
+				"url":        mo.None[string](),
+				"secret":     mo.None[string](),
+				"eventTypes": mo.Some([]string{pubsub.ChangeEarmarkCreated}),
+				"enabled":    mo.Some(false),
+				"endpointID": endpoint.ID,
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		errx := svc.UpdateWebhookEndpoint(ctx, user.ID, endpoint.RefID,
+			&WebhookEndpointUpdateValues{
+				EventTypes: mo.Some([]string{pubsub.ChangeEarmarkCreated}),
+				Enabled:    mo.Some(false),
+			},
+		)
+		assert.Nil(t, errx)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("update webhook with no values should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		errx := svc.UpdateWebhookEndpoint(ctx, user.ID, endpoint.RefID,
+			&WebhookEndpointUpdateValues{})
+		errs.AssertError(t, errx, errs.InvalidArgument, "missing fields")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("update webhook of other user should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM webhook_endpoint_").
+			WithArgs(endpoint.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "url", "enabled"}).
+				AddRow(
+					endpoint.ID, endpoint.RefID, endpoint.UserID+1,
+					endpoint.URL, endpoint.Enabled,
+				),
+			)
+
+		errx := svc.UpdateWebhookEndpoint(ctx, user.ID, endpoint.RefID,
+			&WebhookEndpointUpdateValues{Enabled: mo.Some(false)})
+		errs.AssertError(t, errx, errs.PermissionDenied, "permission denied")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
+
+func TestService_DeleteWebhookEndpoint(t *testing.T) {
+	t.Parallel()
+
+	endpoint := &model.WebhookEndpoint{
+		ID:      2,
+		RefID:   util.Must(model.NewWebhookEndpointRefID()),
+		UserID:  1,
+		URL:     "https://example.com/hook",
+		Enabled: true,
+	}
+
+	t.Run("delete webhook should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM webhook_endpoint_").
+			WithArgs(endpoint.RefID).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "url", "enabled"}).
+				AddRow(
+					endpoint.ID, endpoint.RefID, endpoint.UserID,
+					endpoint.URL, endpoint.Enabled,
+				),
+			)
+		mock.ExpectBegin()
+		mock.ExpectExec("^DELETE FROM webhook_endpoint_").
+			WithArgs(endpoint.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		errx := svc.DeleteWebhookEndpoint(ctx, endpoint.UserID, endpoint.RefID)
+		assert.Nil(t, errx)
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("delete missing webhook should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		svc := New(Options{Db: mock})
+
+		mock.ExpectQuery("^SELECT (.+) FROM webhook_endpoint_").
+			WithArgs(endpoint.RefID).
+			WillReturnError(pgx.ErrNoRows)
+
+		errx := svc.DeleteWebhookEndpoint(ctx, endpoint.UserID, endpoint.RefID)
+		errs.AssertError(t, errx, errs.NotFound, "webhook not found")
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+}
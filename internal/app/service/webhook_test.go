@@ -5,6 +5,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/dropwhile/assert"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
+	"github.com/dropwhile/icanbringthat/internal/pubsub"
 	"github.com/dropwhile/icanbringthat/internal/util"
 )
 
@@ -42,7 +44,7 @@ func TestService_DisableRemindersWithNotification(t *testing.T) {
 
 		email := "user@example.com"
 		reason := "just-because"
-		msg := "email notifications disabled due to 'just-because'"
+		msg := "Email notifications disabled due to 'just-because'."
 
 		mock.ExpectQuery("^SELECT (.+) FROM user_").
 			WithArgs(user.Email).
@@ -78,7 +80,9 @@ func TestService_DisableRemindersWithNotification(t *testing.T) {
 			WithArgs(pgx.NamedArgs{
 				"refID":   pgxmock.AnyArg(),
 				"userID":  user.ID,
+				"kind":    model.NotificationKindRemindersDisabled,
 				"message": msg,
+				"payload": model.NotificationPayload{Reason: reason},
 			}).
 			WillReturnRows(pgxmock.NewRows(
 				[]string{
@@ -101,6 +105,127 @@ func TestService_DisableRemindersWithNotification(t *testing.T) {
 			"there were unfulfilled expectations")
 	})
 
+	t.Run("disable reminders should publish once committed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		broker := pubsub.NewMemoryBroker()
+		svc := New(Options{Db: mock, Broker: broker})
+		sub, err := broker.Subscribe(ctx, pubsub.UserTopic(user.ID))
+		assert.Nil(t, err)
+		defer sub.Close()
+
+		email := "user@example.com"
+		reason := "just-because"
+		msg := "Email notifications disabled due to 'just-because'."
+		notifRefID := util.Must(model.NewNotificationRefID())
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.Email).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "settings"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.Settings),
+			)
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE user_ ").
+			WithArgs(pgx.NamedArgs{
+				"userID":   user.ID,
+				"settings": pgxmock.AnyArg(),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO notification_ ").
+			WithArgs(pgx.NamedArgs{
+				"refID":   pgxmock.AnyArg(),
+				"userID":  user.ID,
+				"kind":    model.NotificationKindRemindersDisabled,
+				"message": msg,
+				"payload": model.NotificationPayload{Reason: reason},
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "message"}).
+				AddRow(1, notifRefID, user.ID, msg),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		errx := svc.DisableRemindersWithNotification(ctx, email, reason)
+		assert.Nil(t, errx)
+		published := <-sub.Messages()
+		assert.Equal(t, published.Change, pubsub.ChangeNotificationCreated)
+		assert.Equal(t, published.RefID, notifRefID.String())
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
+	t.Run("disable reminders rolled back should not publish", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mock := SetupDBMock(t, ctx)
+		broker := pubsub.NewMemoryBroker()
+		svc := New(Options{Db: mock, Broker: broker})
+		sub, err := broker.Subscribe(ctx, pubsub.UserTopic(user.ID))
+		assert.Nil(t, err)
+		defer sub.Close()
+
+		email := "user@example.com"
+		reason := "just-because"
+		msg := "Email notifications disabled due to 'just-because'."
+
+		mock.ExpectQuery("^SELECT (.+) FROM user_").
+			WithArgs(user.Email).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "email", "name", "settings"}).
+				AddRow(user.ID, user.RefID, user.Email, user.Name,
+					user.Settings),
+			)
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE user_ ").
+			WithArgs(pgx.NamedArgs{
+				"userID":   user.ID,
+				"settings": pgxmock.AnyArg(),
+			}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO notification_ ").
+			WithArgs(pgx.NamedArgs{
+				"refID":   pgxmock.AnyArg(),
+				"userID":  user.ID,
+				"kind":    model.NotificationKindRemindersDisabled,
+				"message": msg,
+				"payload": model.NotificationPayload{Reason: reason},
+			}).
+			WillReturnRows(pgxmock.NewRows(
+				[]string{"id", "ref_id", "user_id", "message"}).
+				AddRow(1, util.Must(model.NewNotificationRefID()), user.ID, msg),
+			)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+		mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+		mock.ExpectRollback()
+
+		errx := svc.DisableRemindersWithNotification(ctx, email, reason)
+		assert.True(t, errx != nil)
+		select {
+		case published := <-sub.Messages():
+			t.Fatalf("unexpected message published: %v", published)
+		default:
+		}
+		// we make sure that all expectations were met
+		assert.Nil(t, mock.ExpectationsWereMet(),
+			"there were unfulfilled expectations")
+	})
+
 	t.Run("disable reminders with user not found should fail", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
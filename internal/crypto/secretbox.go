@@ -0,0 +1,57 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"github.com/zeebo/blake3"
+)
+
+var ErrSecretBoxOpen = errors.New("secret box: message authentication failed")
+
+type SecretBoxer interface {
+	Seal(plaintext []byte) []byte
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+// SecretBox encrypts small secrets for storage at rest,
+// using AES-256-GCM with a random nonce prepended to the ciphertext.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+func (sb *SecretBox) Seal(plaintext []byte) []byte {
+	nonce := make([]byte, sb.aead.NonceSize(), sb.aead.NonceSize()+len(plaintext)+sb.aead.Overhead())
+	rand.Read(nonce) // #nosec G104 -- never returns an error
+	return sb.aead.Seal(nonce, nonce, plaintext, nil)
+}
+
+func (sb *SecretBox) Open(ciphertext []byte) ([]byte, error) {
+	nonceSize := sb.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, ErrSecretBoxOpen
+	}
+	plaintext, err := sb.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrSecretBoxOpen
+	}
+	return plaintext, nil
+}
+
+func NewSecretBox(key []byte) *SecretBox {
+	derivedKey := make([]byte, 32)
+	blake3.DeriveKey(
+		"icanbringthat 2026-10-19T16:00:00.000Z secret box", // context
+		key,        // material
+		derivedKey, // output
+	)
+	// only fails on invalid keysize, which can't happen
+	block, _ := aes.NewCipher(derivedKey)
+	aead, _ := cipher.NewGCM(block)
+	return &SecretBox{aead}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dropwhile/assert"
+)
+
+func TestSecretBox(t *testing.T) {
+	sb := NewSecretBox([]byte("some key"))
+	plaintext := []byte("some secret")
+
+	t.Run("round trip", func(t *testing.T) {
+		sealed := sb.Seal(plaintext)
+		assert.True(t, !bytes.Contains(sealed, plaintext))
+		opened, err := sb.Open(sealed)
+		assert.Nil(t, err)
+		assert.Equal(t, opened, plaintext)
+	})
+
+	t.Run("random nonce", func(t *testing.T) {
+		assert.True(t, !bytes.Equal(sb.Seal(plaintext), sb.Seal(plaintext)))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		sealed := sb.Seal(plaintext)
+		sealed[len(sealed)-1] ^= 0x01
+		_, err := sb.Open(sealed)
+		assert.Equal(t, err, ErrSecretBoxOpen)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		sealed := sb.Seal(plaintext)
+		_, err := NewSecretBox([]byte("other key")).Open(sealed)
+		assert.Equal(t, err, ErrSecretBoxOpen)
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		_, err := sb.Open([]byte("short"))
+		assert.Equal(t, err, ErrSecretBoxOpen)
+	})
+}
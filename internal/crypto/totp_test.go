@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package crypto
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+)
+
+// rfc 6238 appendix B test secret for HMAC-SHA1
+var rfcSecret = []byte("12345678901234567890")
+
+func TestTOTPCode(t *testing.T) {
+	// rfc 6238 appendix B vectors, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		step := TOTPStep(time.Unix(tt.unix, 0))
+		assert.Equal(t, TOTPCode(rfcSecret, step), tt.code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := TOTPStep(now)
+
+	t.Run("current code", func(t *testing.T) {
+		step, ok := ValidateTOTP(rfcSecret, "005924", now, 0)
+		assert.True(t, ok)
+		assert.Equal(t, step, current)
+	})
+
+	t.Run("previous period within skew", func(t *testing.T) {
+		code := TOTPCode(rfcSecret, current-1)
+		step, ok := ValidateTOTP(rfcSecret, code, now, 0)
+		assert.True(t, ok)
+		assert.Equal(t, step, current-1)
+	})
+
+	t.Run("outside skew", func(t *testing.T) {
+		code := TOTPCode(rfcSecret, current-2)
+		_, ok := ValidateTOTP(rfcSecret, code, now, 0)
+		assert.True(t, !ok)
+	})
+
+	t.Run("replayed code", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfcSecret, "005924", now, current)
+		assert.True(t, !ok)
+	})
+
+	t.Run("malformed code", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfcSecret, "5924", now, 0)
+		assert.True(t, !ok)
+	})
+}
+
+func TestTOTPKeyURI(t *testing.T) {
+	uri := TOTPKeyURI("Issuer", "user@example.com", rfcSecret)
+	u, err := url.Parse(uri)
+	assert.Nil(t, err)
+	assert.Equal(t, u.Scheme, "otpauth")
+	assert.Equal(t, u.Host, "totp")
+	assert.Equal(t, u.Path, "/Issuer:user@example.com")
+	assert.Equal(t, u.Query().Get("secret"), "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.Equal(t, u.Query().Get("issuer"), "Issuer")
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"golang.org/x/crypto/argon2"
@@ -27,6 +28,7 @@ type EnvConfig struct { // betteralign:ignore
 	// general
 	Production bool   `env:"PRODUCTION" envDefault:"true"`
 	RpcApi     bool   `env:"ENABLE_RPC" envDefault:"false"`
+	RateLimit  bool   `env:"RATE_LIMIT" envDefault:"true"`
 	BaseURL    string `env:"BASE_URL,required"`
 	// logging
 	LogFormat string     `env:"LOG_FORMAT" envDefault:"json"`
@@ -41,7 +43,13 @@ type EnvConfig struct { // betteralign:ignore
 	StaticDir   string `env:"STATIC_DIR" envDefault:"embed"`
 	// database connectivity
 	DatabaseDSN string `env:"DB_DSN,required,unset"`
-	RedisDSN    string `env:"REDIS_DSN,required,unset"`
+	// redis is optional, but required by the redis session backend
+	RedisDSN string `env:"REDIS_DSN,unset"`
+	// session settings
+	SessionBackend     string        `env:"SESSION_BACKEND" envDefault:"redis"`
+	SessionLifetime    time.Duration `env:"SESSION_LIFETIME" envDefault:"24h"`
+	SessionIdleTimeout time.Duration `env:"SESSION_IDLE_TIMEOUT" envDefault:"0s"`
+	SessionRememberMe  bool          `env:"SESSION_REMEMBER_ME" envDefault:"false"`
 	// email settings
 	SMTPHostname string `env:"SMTP_HOSTNAME,required"`
 	SMTPHost     string `env:"SMTP_HOST,expand" envDefault:"$SMTP_HOSTNAME"`
@@ -51,6 +59,11 @@ type EnvConfig struct { // betteralign:ignore
 	MailFrom     string `env:"MAIL_FROM,required"`
 	// webhook settings
 	WebhookCreds map[string]string `env:"WEBHOOK_CREDS,unset"`
+	// openid connect login settings. login is disabled if issuer is unset
+	OIDCIssuer       string `env:"OIDC_ISSUER"`
+	OIDCClientID     string `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `env:"OIDC_CLIENT_SECRET,unset"`
+	OIDCName         string `env:"OIDC_NAME" envDefault:"SSO"`
 	// values derived from other env vars (deriveConfig)
 	Listen       string
 	HMACKeyBytes []byte
@@ -127,6 +140,33 @@ func Parse() (*EnvConfig, error) {
 		}
 	}
 
+	switch config.SessionBackend {
+	case "redis":
+		if config.RedisDSN == "" {
+			return nil, fmt.Errorf("redis dsn required for redis session backend")
+		}
+	case "postgres", "memory":
+	default:
+		return nil, fmt.Errorf("session backend must be one of: redis, postgres, memory")
+	}
+
+	if config.SessionLifetime <= 0 {
+		return nil, fmt.Errorf("session lifetime must be positive")
+	}
+	if config.SessionIdleTimeout < 0 ||
+		config.SessionIdleTimeout > config.SessionLifetime {
+		return nil, fmt.Errorf("session idle timeout must be between 0 and the session lifetime")
+	}
+
+	if config.OIDCIssuer != "" {
+		if config.OIDCClientID == "" {
+			return nil, fmt.Errorf("oidc client id required when oidc issuer is set")
+		}
+		if config.Production && !strings.HasPrefix(config.OIDCIssuer, "https://") {
+			return nil, fmt.Errorf("oidc issuer must be an https url in production mode")
+		}
+	}
+
 	if config.Production && config.LogTrace {
 		// trace logging not allowed in prod mode,
 		// as it may expose private data in sql
@@ -11,6 +11,8 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/dropwhile/refid/v2/reftag"
+
 	"github.com/dropwhile/icanbringthat/internal/app/model"
 	"github.com/dropwhile/icanbringthat/internal/errs"
 	"github.com/dropwhile/icanbringthat/internal/logger"
@@ -43,17 +45,46 @@ func IsLoggedIn(ctx context.Context) bool {
 
 type UserGetter interface {
 	GetUserByID(context.Context, int) (*model.User, errs.Error)
+	CheckUserSession(context.Context, int, model.UserSessionRefID) errs.Error
 }
 
-type GetInter interface {
+type SessionGetter interface {
 	GetInt(ctx context.Context, key string) int
+	GetString(ctx context.Context, key string) string
+	Destroy(ctx context.Context) error
 }
 
-func Load(userGetter UserGetter, intGetter GetInter) func(http.Handler) http.Handler {
+func Load(userGetter UserGetter, sessGetter SessionGetter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
-			userID := intGetter.GetInt(ctx, "user-id")
+			userID := sessGetter.GetInt(ctx, "user-id")
+			if userID != 0 {
+				// sessions are indexed so they can be revoked. one missing
+				// from the index was revoked, expired, or predates the
+				// index, and is logged out.
+				refID, err := reftag.Parse[model.UserSessionRefID](
+					sessGetter.GetString(ctx, "session-id"))
+				if err == nil {
+					if errx := userGetter.CheckUserSession(ctx, userID, refID); errx != nil {
+						if errx.Code() != errs.NotFound {
+							slog.ErrorContext(ctx, "session check failure", logger.Err(errx))
+							http.Error(w, "authorization failure", http.StatusInternalServerError)
+							return
+						}
+						err = errx
+					}
+				}
+				if err != nil {
+					slog.InfoContext(ctx, "session no longer valid", logger.Err(err))
+					if err := sessGetter.Destroy(ctx); err != nil {
+						slog.ErrorContext(ctx, "session destroy failure", logger.Err(err))
+						http.Error(w, "authorization failure", http.StatusInternalServerError)
+						return
+					}
+					userID = 0
+				}
+			}
 			if userID != 0 {
 				user, err := userGetter.GetUserByID(ctx, userID)
 				if err != nil {
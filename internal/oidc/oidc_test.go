@@ -0,0 +1,226 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+
+	"github.com/dropwhile/icanbringthat/internal/oidc"
+	"github.com/dropwhile/icanbringthat/internal/oidc/oidctest"
+)
+
+func setupProvider(t *testing.T) (*oidc.Provider, *oidctest.Issuer) {
+	t.Helper()
+	issuer := oidctest.NewIssuer()
+	t.Cleanup(issuer.Close)
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "Test",
+		Issuer:       issuer.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://example.com/login/oidc/callback",
+	}, issuer.Client())
+	return provider, issuer
+}
+
+func TestS256Challenge(t *testing.T) {
+	t.Parallel()
+	// rfc 7636 appendix B
+	assert.Equal(t,
+		oidc.S256Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"),
+		"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	provider, issuer := setupProvider(t)
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	assert.Nil(t, err)
+	u, err := url.Parse(authURL)
+	assert.Nil(t, err)
+	assert.Equal(t, u.Scheme+"://"+u.Host+u.Path, issuer.URL+"/authorize")
+	q := u.Query()
+	assert.Equal(t, q.Get("response_type"), "code")
+	assert.Equal(t, q.Get("client_id"), "client-id")
+	assert.Equal(t, q.Get("redirect_uri"), "http://example.com/login/oidc/callback")
+	assert.Equal(t, q.Get("state"), "state")
+	assert.Equal(t, q.Get("nonce"), "nonce")
+	assert.Equal(t, q.Get("code_challenge"), oidc.S256Challenge("verifier"))
+	assert.Equal(t, q.Get("code_challenge_method"), "S256")
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	t.Parallel()
+
+	user := oidctest.User{
+		Subject:       "subject-1",
+		Email:         "user@example.com",
+		Name:          "user",
+		EmailVerified: true,
+	}
+
+	t.Run("exchange should succeed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider, issuer := setupProvider(t)
+
+		verifier := oidc.GenerateVerifier()
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+		assert.Nil(t, err)
+		code, state := issuer.Authorize(authURL, user)
+		assert.Equal(t, state, "state")
+
+		claims, err := provider.Exchange(ctx, code, verifier, "nonce")
+		assert.Nil(t, err)
+		assert.Equal(t, claims.Issuer, issuer.URL)
+		assert.Equal(t, claims.Subject, user.Subject)
+		assert.Equal(t, claims.Email, user.Email)
+		assert.Equal(t, claims.Name, user.Name)
+		assert.True(t, claims.EmailVerified)
+	})
+
+	t.Run("wrong verifier should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider, issuer := setupProvider(t)
+
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", oidc.GenerateVerifier())
+		assert.Nil(t, err)
+		code, _ := issuer.Authorize(authURL, user)
+
+		_, err = provider.Exchange(ctx, code, oidc.GenerateVerifier(), "nonce")
+		assert.True(t, errors.Is(err, oidc.ErrExchange))
+	})
+
+	t.Run("wrong nonce should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider, issuer := setupProvider(t)
+
+		verifier := oidc.GenerateVerifier()
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+		assert.Nil(t, err)
+		code, _ := issuer.Authorize(authURL, user)
+
+		_, err = provider.Exchange(ctx, code, verifier, "other-nonce")
+		assert.True(t, errors.Is(err, oidc.ErrInvalidToken))
+	})
+
+	t.Run("reused code should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider, issuer := setupProvider(t)
+
+		verifier := oidc.GenerateVerifier()
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+		assert.Nil(t, err)
+		code, _ := issuer.Authorize(authURL, user)
+
+		_, err = provider.Exchange(ctx, code, verifier, "nonce")
+		assert.Nil(t, err)
+		_, err = provider.Exchange(ctx, code, verifier, "nonce")
+		assert.True(t, errors.Is(err, oidc.ErrExchange))
+	})
+
+	t.Run("token for other client should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider, issuer := setupProvider(t)
+		other := oidc.NewProvider(oidc.Config{
+			Issuer:      issuer.URL,
+			ClientID:    "other-client-id",
+			RedirectURL: "http://example.com/login/oidc/callback",
+		}, issuer.Client())
+
+		verifier := oidc.GenerateVerifier()
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+		assert.Nil(t, err)
+		// pretend the other client was given this client's code
+		u, _ := url.Parse(authURL)
+		q := u.Query()
+		q.Set("client_id", "other-client-id")
+		u.RawQuery = q.Encode()
+		code, _ := issuer.Authorize(u.String(), user)
+
+		claims, err := other.Exchange(ctx, code, verifier, "nonce")
+		assert.Nil(t, err)
+		assert.Equal(t, claims.Subject, user.Subject)
+
+		// and the same token is not accepted by this client
+		code, _ = issuer.Authorize(u.String(), user)
+		_, err = provider.Exchange(ctx, code, verifier, "nonce")
+		assert.True(t, err != nil)
+	})
+
+	t.Run("unreachable issuer should fail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		issuer := oidctest.NewIssuer()
+		issuer.Close()
+		provider := oidc.NewProvider(oidc.Config{
+			Issuer:   issuer.URL,
+			ClientID: "client-id",
+		}, nil)
+
+		_, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		assert.True(t, errors.Is(err, oidc.ErrDiscovery))
+	})
+}
+
+func TestProvider_SlowDiscovery(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	inFlight := make(chan struct{})
+	var once sync.Once
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			once.Do(func() { close(inFlight) })
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+	// cleanups run last in first out, so the handler is released
+	// before the server waits on it
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:   srv.URL,
+		ClientID: "client-id",
+	}, srv.Client())
+
+	// a login stuck waiting on the provider...
+	go func() {
+		_, _ = provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	}()
+	<-inFlight
+
+	// ...should not hold up another login past its own deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, oidc.ErrDiscovery))
+	case <-time.After(5 * time.Second):
+		t.Fatal("login blocked behind a slow discovery fetch")
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package pubsub
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+const subscriptionBufferSize = 16
+
+// MemoryBroker fans messages out within a single process.
+// Useful for tests and single instance deployments.
+type MemoryBroker struct {
+	subs    map[string]map[*memorySubscription]struct{}
+	history map[string][]*Message
+	mu      sync.RWMutex
+	seq     uint64
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs:    make(map[string]map[*memorySubscription]struct{}),
+		history: make(map[string][]*Message),
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, msg *Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	m := *msg
+	m.Cursor = strconv.FormatUint(b.seq, 10)
+
+	history := append(b.history[topic], &m)
+	if len(history) > historySize {
+		history = history[len(history)-historySize:]
+	}
+	b.history[topic] = history
+
+	for sub := range b.subs[topic] {
+		sub.send(&m)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Replay(ctx context.Context, topic string, cursor string) ([]*Message, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	history := b.history[topic]
+	idx := slices.IndexFunc(history, func(m *Message) bool {
+		return m.Cursor == cursor
+	})
+	if idx == -1 {
+		return nil, ErrCursorExpired
+	}
+	return slices.Clone(history[idx+1:]), nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, topics ...string) (Subscription, error) {
+	sub := &memorySubscription{
+		broker: b,
+		topics: topics,
+		out:    make(chan *Message, subscriptionBufferSize),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, topic := range topics {
+		if _, ok := b.subs[topic]; !ok {
+			b.subs[topic] = make(map[*memorySubscription]struct{})
+		}
+		b.subs[topic][sub] = struct{}{}
+	}
+	return sub, nil
+}
+
+func (b *MemoryBroker) unsubscribe(sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, topic := range sub.topics {
+		delete(b.subs[topic], sub)
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+	}
+}
+
+type memorySubscription struct {
+	broker *MemoryBroker
+	out    chan *Message
+	topics []string
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *memorySubscription) send(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.out <- msg:
+	default:
+		// slow consumer. drop the message rather than block
+		// every other subscriber.
+	}
+}
+
+func (s *memorySubscription) Messages() <-chan *Message {
+	return s.out
+}
+
+func (s *memorySubscription) Close() error {
+	s.broker.unsubscribe(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.out)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dropwhile/assert"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("subscriber receives messages for its topics", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		broker := NewMemoryBroker()
+		sub, err := broker.Subscribe(ctx, EventTopic(1), UserTopic(2))
+		assert.Nil(t, err)
+		defer sub.Close()
+
+		assert.Nil(t, broker.Publish(ctx, EventTopic(1),
+			&Message{Type: TypeEventUpdated, Change: ChangeItemAdded}))
+		assert.Nil(t, broker.Publish(ctx, EventTopic(3),
+			&Message{Type: TypeEventUpdated, Change: ChangeItemRemoved}))
+		assert.Nil(t, broker.Publish(ctx, UserTopic(2),
+			&Message{Type: TypeNotifCount}))
+
+		msg := <-sub.Messages()
+		assert.Equal(t, msg.Change, ChangeItemAdded)
+		msg = <-sub.Messages()
+		assert.Equal(t, msg.Type, TypeNotifCount)
+		assert.Equal(t, len(sub.Messages()), 0)
+	})
+
+	t.Run("closed subscription stops receiving", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		broker := NewMemoryBroker()
+		sub, err := broker.Subscribe(ctx, EventTopic(1))
+		assert.Nil(t, err)
+		assert.Nil(t, sub.Close())
+
+		assert.Nil(t, broker.Publish(ctx, EventTopic(1),
+			&Message{Type: TypeEventUpdated}))
+		_, ok := <-sub.Messages()
+		assert.Equal(t, ok, false)
+		assert.Equal(t, len(broker.subs), 0)
+	})
+	t.Run("replay returns messages after cursor", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		broker := NewMemoryBroker()
+		sub, err := broker.Subscribe(ctx, EventTopic(1))
+		assert.Nil(t, err)
+		defer sub.Close()
+
+		for _, change := range []string{
+			ChangeItemAdded, ChangeItemUpdated, ChangeItemRemoved,
+		} {
+			assert.Nil(t, broker.Publish(ctx, EventTopic(1),
+				&Message{Type: TypeEventUpdated, Change: change}))
+		}
+		first := <-sub.Messages()
+
+		messages, err := broker.Replay(ctx, EventTopic(1), first.Cursor)
+		assert.Nil(t, err)
+		assert.Equal(t, len(messages), 2)
+		assert.Equal(t, messages[0].Change, ChangeItemUpdated)
+		assert.Equal(t, messages[1].Change, ChangeItemRemoved)
+
+		_, err = broker.Replay(ctx, EventTopic(1), "bogus")
+		assert.True(t, errors.Is(err, ErrCursorExpired))
+		_, err = broker.Replay(ctx, EventTopic(2), first.Cursor)
+		assert.True(t, errors.Is(err, ErrCursorExpired))
+	})
+}
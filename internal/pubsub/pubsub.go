@@ -0,0 +1,75 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// ErrCursorExpired is returned by Replay when the requested cursor is no
+// longer part of the retained history of a topic.
+var ErrCursorExpired = errors.New("cursor expired")
+
+// number of messages retained per topic for replay
+const historySize = 256
+
+// message types. these double as the server-sent-event names.
+const (
+	TypeEventUpdated = "event-updated"
+	TypeNotifCount   = "notif-count"
+)
+
+// event scoped changes, carried in Message.Change
+const (
+	ChangeItemAdded      = "item-added"
+	ChangeItemUpdated    = "item-updated"
+	ChangeItemRemoved    = "item-removed"
+	ChangeEarmarkCreated = "earmark-created"
+	ChangeEarmarkDeleted = "earmark-deleted"
+	ChangeItemsSorted    = "items-sorted"
+	ChangeEventUpdated   = "event-updated"
+)
+
+// user scoped notification changes, carried in Message.Change
+const (
+	ChangeNotificationCreated  = "notification-created"
+	ChangeNotificationDeleted  = "notification-deleted"
+	ChangeNotificationRead     = "notification-read"
+	ChangeNotificationUnread   = "notification-unread"
+	ChangeNotificationsRead    = "notifications-read"
+	ChangeNotificationsDeleted = "notifications-deleted"
+)
+
+type Message struct {
+	Type   string `json:"type"`
+	Change string `json:"change,omitempty"`
+	RefID  string `json:"ref_id,omitempty"`
+	// Cursor is assigned by the broker on publish. It identifies the
+	// message within its topic, and can be handed to Replay to resume
+	// after it.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type Subscription interface {
+	Messages() <-chan *Message
+	Close() error
+}
+
+type Broker interface {
+	Publish(ctx context.Context, topic string, msg *Message) error
+	Subscribe(ctx context.Context, topics ...string) (Subscription, error)
+	// Replay returns the retained messages of topic published after
+	// cursor, oldest first.
+	Replay(ctx context.Context, topic string, cursor string) ([]*Message, error)
+}
+
+func EventTopic(eventID int) string {
+	return "event:" + strconv.Itoa(eventID)
+}
+
+func UserTopic(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisChannelPrefix = "icbt:pubsub:"
+	redisHistoryPrefix = "icbt:pubsub-history:"
+	// how long an idle topic history is retained
+	redisHistoryTTL = 24 * time.Hour
+)
+
+// redis stream entry ids are of the form <millis>-<seq>
+var redisCursorRe = regexp.MustCompile(`^[0-9]+-[0-9]+$`)
+
+// RedisBroker fans messages out through redis pub/sub, so subscribers
+// connected to any server instance receive them. A capped redis stream
+// per topic retains recent messages for replay, and its entry ids serve
+// as message cursors.
+type RedisBroker struct {
+	rdb *redis.Client
+}
+
+func NewRedisBroker(rdb *redis.Client) *RedisBroker {
+	return &RedisBroker{rdb: rdb}
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, topic string, msg *Message) error {
+	m := *msg
+	m.Cursor = ""
+	data, err := json.Marshal(&m)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	key := redisHistoryPrefix + topic
+	var xadd *redis.StringCmd
+	_, err = b.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		xadd = pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: historySize,
+			Approx: true,
+			Values: map[string]any{"msg": data},
+		})
+		pipe.Expire(ctx, key, redisHistoryTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("recording message: %w", err)
+	}
+
+	m.Cursor = xadd.Val()
+	data, err = json.Marshal(&m)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+	return b.rdb.Publish(ctx, redisChannelPrefix+topic, data).Err()
+}
+
+func (b *RedisBroker) Replay(ctx context.Context, topic string, cursor string) ([]*Message, error) {
+	if !redisCursorRe.MatchString(cursor) {
+		return nil, ErrCursorExpired
+	}
+
+	key := redisHistoryPrefix + topic
+	// the cursor entry itself must still be retained, otherwise
+	// messages may have been trimmed in between.
+	found, err := b.rdb.XRange(ctx, key, cursor, cursor).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	if len(found) == 0 {
+		return nil, ErrCursorExpired
+	}
+
+	entries, err := b.rdb.XRange(ctx, key, "("+cursor, "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	messages := make([]*Message, 0, len(entries))
+	for _, entry := range entries {
+		data, ok := entry.Values["msg"].(string)
+		if !ok {
+			continue
+		}
+		msg := &Message{}
+		if err := json.Unmarshal([]byte(data), msg); err != nil {
+			slog.Info("bad pubsub history message", "error", err)
+			continue
+		}
+		msg.Cursor = entry.ID
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, topics ...string) (Subscription, error) {
+	channels := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		channels = append(channels, redisChannelPrefix+topic)
+	}
+
+	ps := b.rdb.Subscribe(ctx, channels...)
+	// wait for subscription confirmation, so no messages published
+	// after we return are missed
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return nil, fmt.Errorf("subscribing: %w", err)
+	}
+
+	sub := &redisSubscription{
+		ps:  ps,
+		out: make(chan *Message, subscriptionBufferSize),
+	}
+	go sub.run()
+	return sub, nil
+}
+
+type redisSubscription struct {
+	ps  *redis.PubSub
+	out chan *Message
+}
+
+func (s *redisSubscription) run() {
+	defer close(s.out)
+	for rmsg := range s.ps.Channel() {
+		msg := &Message{}
+		if err := json.Unmarshal([]byte(rmsg.Payload), msg); err != nil {
+			slog.Info("bad pubsub message", "error", err)
+			continue
+		}
+		select {
+		case s.out <- msg:
+		default:
+			// slow consumer. drop the message rather than block
+			// every other subscriber.
+		}
+	}
+}
+
+func (s *redisSubscription) Messages() <-chan *Message {
+	return s.out
+}
+
+func (s *redisSubscription) Close() error {
+	return s.ps.Close()
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package qrcode renders short strings (such as otpauth:// uris) as
+// svg QR codes. Encoding is done by rsc.io/qr, at error correction
+// level M.
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+// light modules around the symbol, as required by the spec
+const quietZone = 4
+
+// Code is an encoded QR symbol.
+type Code struct {
+	code *qr.Code
+}
+
+// Size returns the width and height of the symbol in modules,
+// excluding the quiet zone.
+func (c *Code) Size() int {
+	return c.code.Size
+}
+
+// Dark reports whether the module at x, y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.code.Black(x, y)
+}
+
+// Encode returns the smallest QR code holding data.
+func Encode(data []byte) (*Code, error) {
+	code, err := qr.Encode(string(data), qr.M)
+	if err != nil {
+		return nil, fmt.Errorf("qrcode: %w", err)
+	}
+	return &Code{code: code}, nil
+}
+
+// SVG renders the code as a scalable svg image, including the quiet zone.
+func (c *Code) SVG() string {
+	var b strings.Builder
+	size := c.Size()
+	total := size + quietZone*2
+	fmt.Fprintf(&b,
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		total, total)
+	b.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y := range size {
+		for x := range size {
+			if c.Dark(x, y) {
+				fmt.Fprintf(&b, "M%d,%dh1v1h-1z", x+quietZone, y+quietZone)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.String()
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package qrcode
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dropwhile/assert"
+)
+
+// readFormatBits reads the format information next to the top left
+// finder pattern, least significant bit first as laid out in the spec.
+func readFormatBits(c *Code) int {
+	bits := 0
+	set := func(i int, dark bool) {
+		if dark {
+			bits |= 1 << i
+		}
+	}
+	for i := range 6 {
+		set(i, c.Dark(8, i))
+	}
+	set(6, c.Dark(8, 7))
+	set(7, c.Dark(8, 8))
+	set(8, c.Dark(7, 8))
+	for i := 9; i < 15; i++ {
+		set(i, c.Dark(14-i, 8))
+	}
+	return bits
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("smallest version", func(t *testing.T) {
+		c, err := Encode([]byte("hello"))
+		assert.Nil(t, err)
+		assert.Equal(t, c.Size(), 21)
+	})
+
+	t.Run("finder patterns", func(t *testing.T) {
+		c, err := Encode([]byte("otpauth://totp/Issuer:user@example.com?secret=ABC"))
+		assert.Nil(t, err)
+		size := c.Size()
+		for _, pos := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+			// outer ring dark, then light ring, then dark 3x3 center
+			assert.True(t, c.Dark(pos[0], pos[1]))
+			assert.True(t, c.Dark(pos[0]+6, pos[1]+6))
+			assert.True(t, !c.Dark(pos[0]+1, pos[1]+1))
+			assert.True(t, c.Dark(pos[0]+3, pos[1]+3))
+		}
+	})
+
+	t.Run("error correction level", func(t *testing.T) {
+		// level M format information, one entry per mask pattern
+		levelM := []int{
+			0b101010000010010, 0b101000100100101,
+			0b101111001111100, 0b101101101001011,
+			0b100010111111001, 0b100000011001110,
+			0b100111110010111, 0b100101010100000,
+		}
+		c, err := Encode([]byte("otpauth://totp/Issuer:user@example.com?secret=ABC"))
+		assert.Nil(t, err)
+		assert.True(t, slices.Contains(levelM, readFormatBits(c)))
+	})
+
+	t.Run("version information", func(t *testing.T) {
+		c, err := Encode(bytes.Repeat([]byte("a"), 110))
+		assert.Nil(t, err)
+		assert.Equal(t, c.Size(), 7*4+17)
+		bits := 0x07C94
+		for i := range 18 {
+			dark := (bits>>i)&1 != 0
+			assert.Equal(t, c.Dark(c.Size()-11+i%3, i/3), dark)
+			assert.Equal(t, c.Dark(i/3, c.Size()-11+i%3), dark)
+		}
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := Encode(bytes.Repeat([]byte("a"), 10000))
+		assert.True(t, err != nil)
+	})
+}
+
+func TestSVG(t *testing.T) {
+	c, err := Encode([]byte("hello"))
+	assert.Nil(t, err)
+	svg := c.SVG()
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.True(t, strings.Contains(svg, `viewBox="0 0 29 29"`))
+	// top left finder corner, offset by the quiet zone
+	assert.True(t, strings.Contains(svg, "M4,4h1v1h-1z"))
+}
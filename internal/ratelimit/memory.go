@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// how often stale keys are dropped from a MemoryStore
+const memorySweepInterval = time.Minute
+
+type memoryLog struct {
+	times  []time.Time
+	window time.Duration
+}
+
+// MemoryStore keeps request logs within a single process.
+// Useful for tests and single instance deployments.
+type MemoryStore struct {
+	now       func() time.Time
+	logs      map[string]*memoryLog
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:  time.Now,
+		logs: make(map[string]*memoryLog),
+	}
+}
+
+func (s *MemoryStore) Allow(
+	ctx context.Context, key string, limit int, window time.Duration,
+) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	cutoff := now.Add(-window)
+	var log []time.Time
+	if l, ok := s.logs[key]; ok {
+		log = l.times
+	}
+	i := 0
+	for i < len(log) && !log[i].After(cutoff) {
+		i++
+	}
+	log = log[i:]
+
+	if len(log) >= limit {
+		if len(log) == 0 {
+			delete(s.logs, key)
+			return false, window, nil
+		}
+		s.logs[key] = &memoryLog{times: log, window: window}
+		return false, log[0].Sub(cutoff), nil
+	}
+
+	s.logs[key] = &memoryLog{times: append(log, now), window: window}
+	return true, 0, nil
+}
+
+// sweep drops keys with no requests left inside their window, so keys
+// that are never seen again do not stay around forever.
+// The caller must hold s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, l := range s.logs {
+		if len(l.times) == 0 || !l.times[len(l.times)-1].After(now.Add(-l.window)) {
+			delete(s.logs, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requests over limit are denied until window slides", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := NewMemoryStore()
+		now := time.Date(2030, 1, 1, 3, 4, 5, 0, time.UTC)
+		store.now = func() time.Time { return now }
+
+		for range 3 {
+			allowed, _, err := store.Allow(ctx, "key", 3, time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, allowed)
+			now = now.Add(10 * time.Second)
+		}
+
+		allowed, retryAfter, err := store.Allow(ctx, "key", 3, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, !allowed)
+		assert.Equal(t, retryAfter, 30*time.Second)
+
+		// first request leaves the window
+		now = now.Add(30 * time.Second)
+		allowed, _, err = store.Allow(ctx, "key", 3, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+
+		allowed, retryAfter, err = store.Allow(ctx, "key", 3, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, !allowed)
+		assert.Equal(t, retryAfter, 10*time.Second)
+	})
+
+	t.Run("keys are limited separately", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := NewMemoryStore()
+
+		allowed, _, err := store.Allow(ctx, "key1", 1, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+		allowed, _, err = store.Allow(ctx, "key1", 1, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, !allowed)
+		allowed, _, err = store.Allow(ctx, "key2", 1, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("stale keys are swept", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := NewMemoryStore()
+		now := time.Date(2030, 1, 1, 3, 4, 5, 0, time.UTC)
+		store.now = func() time.Time { return now }
+
+		allowed, _, err := store.Allow(ctx, "key1", 1, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+		allowed, _, err = store.Allow(ctx, "key2", 1, time.Hour)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, len(store.logs), 2)
+
+		// key1 left its window, key2 is still limited
+		now = now.Add(2 * time.Minute)
+		allowed, _, err = store.Allow(ctx, "key3", 1, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, len(store.logs), 2)
+		_, ok := store.logs["key1"]
+		assert.Equal(t, ok, false)
+	})
+
+	t.Run("zero limit does not keep keys", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := NewMemoryStore()
+
+		allowed, _, err := store.Allow(ctx, "key", 0, time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, !allowed)
+		assert.Equal(t, len(store.logs), 0)
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/dropwhile/icanbringthat/internal/logger"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+// Store tracks requests per key over a sliding window.
+type Store interface {
+	// Allow records a request against key, if fewer than limit requests
+	// were recorded in the preceding window. Otherwise the request is not
+	// recorded, and the time until the oldest recorded request leaves the
+	// window is returned.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
+
+// KeyFunc identifies who a request is counted against. An empty key
+// exempts the request from the policy.
+type KeyFunc func(r *http.Request) string
+
+// Policy allows Limit requests per Window, for each distinct Key.
+type Policy struct {
+	Key    KeyFunc
+	Name   string
+	Window time.Duration
+	Limit  int
+}
+
+// Responder writes the response for a rate limited request. The
+// Retry-After header is already set when it is called.
+type Responder func(w http.ResponseWriter, r *http.Request, retryAfter time.Duration)
+
+// Limit returns middleware that applies each of policies to requests,
+// responding with respond once any of them is exceeded.
+// Store errors are logged, and the request let through.
+func Limit(store Store, respond Responder, policies ...Policy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			for _, policy := range policies {
+				key := policy.Key(r)
+				if key == "" {
+					continue
+				}
+				allowed, retryAfter, err := store.Allow(
+					ctx, policy.Name+":"+key, policy.Limit, policy.Window)
+				if err != nil {
+					slog.ErrorContext(ctx, "rate limit store error",
+						"policy", policy.Name, logger.Err(err))
+					continue
+				}
+				if !allowed {
+					slog.InfoContext(ctx, "rate limited",
+						"policy", policy.Name, "retry_after", retryAfter)
+					w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+					respond(w, r, retryAfter)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TooManyRequests responds with a plain 429 status.
+func TooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
+
+// ResourceExhausted responds with a ResourceExhausted error, in whichever
+// rpc protocol the request used.
+func ResourceExhausted(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	err := connect.NewError(connect.CodeResourceExhausted, errors.New("rate limit exceeded"))
+	err.Meta().Set("Retry-After", retryAfterSeconds(retryAfter))
+	errWriter := connect.NewErrorWriter()
+	if !errWriter.IsSupported(r) {
+		TooManyRequests(w, r, retryAfter)
+		return
+	}
+	if werr := errWriter.Write(w, r, err); werr != nil {
+		slog.ErrorContext(r.Context(), "error writing rpc error", logger.Err(werr))
+	}
+}
+
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
+
+// ByIP keys requests by client address. Use after middleware.RealIP when
+// behind a proxy.
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ByFormValue keys requests by the account named in form field, such as
+// the email address of a login attempt.
+func ByFormValue(field string) KeyFunc {
+	return func(r *http.Request) string {
+		value := strings.ToLower(strings.TrimSpace(r.PostFormValue(field)))
+		if value == "" {
+			return ""
+		}
+		return "account:" + hashKey(value)
+	}
+}
+
+// ByUser keys requests by the logged in user.
+func ByUser(r *http.Request) string {
+	user, err := auth.UserFromContext(r.Context())
+	if err != nil {
+		return ""
+	}
+	return "user:" + strconv.Itoa(user.ID)
+}
+
+// ByApiKey keys requests by the bearer token set by auth.LoadAuthToken.
+func ByApiKey(r *http.Request) string {
+	token, ok := auth.ContextGet[string](r.Context(), "api-key")
+	if !ok || token == "" {
+		return ""
+	}
+	return "api-key:" + hashKey(token)
+}
+
+// hashKey keeps credentials and personal details out of store keys
+func hashKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
+
+	"github.com/dropwhile/icanbringthat/internal/app/model"
+	"github.com/dropwhile/icanbringthat/internal/middleware/auth"
+)
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, int, time.Duration) (bool, time.Duration, error) {
+	return false, 0, errors.New("store unavailable")
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requests over limit get 429 with retry-after", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryStore()
+		mw := Limit(store, TooManyRequests,
+			Policy{Name: "test", Key: ByIP, Limit: 2, Window: time.Minute})
+		handler := mw(http.HandlerFunc(okHandler))
+
+		for range 2 {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, rr.Code, http.StatusOK)
+		}
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, rr.Code, http.StatusTooManyRequests)
+		assert.Equal(t, rr.Header().Get("Retry-After"), "60")
+
+		// another client address is not limited
+		rr = httptest.NewRecorder()
+		req = httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "192.0.2.99:1234"
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, rr.Code, http.StatusOK)
+	})
+
+	t.Run("account policy counts attempts across addresses", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryStore()
+		mw := Limit(store, TooManyRequests,
+			Policy{Name: "test", Key: ByFormValue("email"), Limit: 1, Window: time.Minute})
+		handler := mw(http.HandlerFunc(okHandler))
+
+		newRequest := func(email, addr string) *http.Request {
+			data := url.Values{"email": {email}}
+			req := httptest.NewRequest("POST", "/login", strings.NewReader(data.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.RemoteAddr = addr
+			return req
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest("user@example.com", "192.0.2.1:1234"))
+		assert.Equal(t, rr.Code, http.StatusOK)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest("USER@example.com ", "192.0.2.2:1234"))
+		assert.Equal(t, rr.Code, http.StatusTooManyRequests)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest("other@example.com", "192.0.2.2:1234"))
+		assert.Equal(t, rr.Code, http.StatusOK)
+
+		// requests without the field are exempt
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest("", "192.0.2.2:1234"))
+		assert.Equal(t, rr.Code, http.StatusOK)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest("", "192.0.2.2:1234"))
+		assert.Equal(t, rr.Code, http.StatusOK)
+	})
+
+	t.Run("user and api key policies", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryStore()
+		mw := Limit(store, TooManyRequests,
+			Policy{Name: "user", Key: ByUser, Limit: 1, Window: time.Minute},
+			Policy{Name: "api-key", Key: ByApiKey, Limit: 1, Window: time.Minute},
+		)
+		handler := mw(http.HandlerFunc(okHandler))
+
+		ctx := auth.ContextSet(context.Background(), "user", &model.User{ID: 1})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", nil).WithContext(ctx))
+		assert.Equal(t, rr.Code, http.StatusOK)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", nil).WithContext(ctx))
+		assert.Equal(t, rr.Code, http.StatusTooManyRequests)
+
+		ctx = auth.ContextSet(context.Background(), "api-key", "some-token")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", nil).WithContext(ctx))
+		assert.Equal(t, rr.Code, http.StatusOK)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", nil).WithContext(ctx))
+		assert.Equal(t, rr.Code, http.StatusTooManyRequests)
+	})
+
+	t.Run("rpc requests over limit get resource exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryStore()
+		mw := Limit(store, ResourceExhausted,
+			Policy{Name: "test", Key: ByIP, Limit: 1, Window: time.Minute})
+		handler := mw(http.HandlerFunc(okHandler))
+
+		newRequest := func() *http.Request {
+			req := httptest.NewRequest("POST", "/icbt.rpc.v1.IcbtRpcService/EventsList",
+				strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest())
+		assert.Equal(t, rr.Code, http.StatusOK)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest())
+		assert.Equal(t, rr.Code, http.StatusTooManyRequests)
+		assert.Equal(t, rr.Header().Get("Retry-After"), "60")
+		assert.True(t, strings.Contains(rr.Body.String(), `"code":"resource_exhausted"`))
+	})
+
+	t.Run("store errors let requests through", func(t *testing.T) {
+		t.Parallel()
+
+		mw := Limit(failingStore{}, TooManyRequests,
+			Policy{Name: "test", Key: ByIP, Limit: 1, Window: time.Minute})
+		handler := mw(http.HandlerFunc(okHandler))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, rr.Code, http.StatusOK)
+	})
+}
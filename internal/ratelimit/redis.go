@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Eli Janssen
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "icbt:ratelimit:"
+
+// Request logs are kept as sorted sets scored by request time, in
+// milliseconds. Entries that left the window are trimmed before
+// counting. Returns 0 when the request is allowed, or else the
+// milliseconds until the oldest entry leaves the window.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return 0
+end
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] == nil then
+	return window
+end
+return math.max(1, tonumber(oldest[2]) + window - now)
+`)
+
+// RedisStore keeps request logs in redis, so limits are shared by all
+// server instances.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+func (s *RedisStore) Allow(
+	ctx context.Context, key string, limit int, window time.Duration,
+) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	// members must be unique, or requests in the same millisecond
+	// would only be counted once
+	member := fmt.Sprintf("%d-%s", now, rand.Text())
+	retryMillis, err := slidingWindowScript.Run(ctx, s.rdb,
+		[]string{redisKeyPrefix + key},
+		now, window.Milliseconds(), limit, member,
+	).Int64()
+	if err != nil {
+		return false, 0, fmt.Errorf("running rate limit script: %w", err)
+	}
+	if retryMillis > 0 {
+		return false, time.Duration(retryMillis) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
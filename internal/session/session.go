@@ -6,6 +6,7 @@ package session
 import (
 	"context"
 	"encoding/gob"
+	"time"
 
 	"github.com/alexedwards/scs/goredisstore"
 	"github.com/alexedwards/scs/pgxstore"
@@ -24,7 +25,10 @@ type SessionMgr struct {
 }
 
 func (sm *SessionMgr) Close() {
-	if v, ok := sm.Store.(*pgxstore.PostgresStore); ok {
+	switch v := sm.Store.(type) {
+	case *pgxstore.PostgresStore:
+		v.StopCleanup()
+	case *memstore.MemStore:
 		v.StopCleanup()
 	}
 }
@@ -51,23 +55,47 @@ func (sm *SessionMgr) PopMap(ctx context.Context, key string) map[string][]strin
 	return value
 }
 
-func NewDBSessionManager(pool *pgxpool.Pool, secure bool) *SessionMgr {
-	manager := scs.New()
-	manager.Cookie.Secure = secure
-	manager.Store = pgxstore.New(pool)
-	return &SessionMgr{SessionManager: manager}
+// Config sets the lifetime policy of sessions, independent of the store
+// used to hold them.
+type Config struct {
+	// Lifetime is the absolute lifetime of a session, regardless of
+	// activity.
+	Lifetime time.Duration
+	// IdleTimeout expires a session with no activity for this long. Zero
+	// disables it.
+	IdleTimeout time.Duration
+	// RememberMe makes session cookies last only until the browser is
+	// closed, unless the user asks to be remembered when logging in.
+	RememberMe bool
+	Secure     bool
 }
 
-func NewRedisSessionManager(rdb *redis.Client, secure bool) *SessionMgr {
+func newSessionManager(store scs.Store, conf Config) *SessionMgr {
 	manager := scs.New()
-	manager.Cookie.Secure = secure
-	manager.Store = goredisstore.New(rdb)
+	manager.Cookie.Secure = conf.Secure
+	manager.Cookie.Persist = !conf.RememberMe
+	if conf.Lifetime > 0 {
+		manager.Lifetime = conf.Lifetime
+	}
+	manager.IdleTimeout = conf.IdleTimeout
+	manager.Store = store
 	return &SessionMgr{SessionManager: manager}
 }
 
+func NewDBSessionManager(pool *pgxpool.Pool, conf Config) *SessionMgr {
+	return newSessionManager(pgxstore.New(pool), conf)
+}
+
+func NewRedisSessionManager(rdb *redis.Client, conf Config) *SessionMgr {
+	return newSessionManager(goredisstore.New(rdb), conf)
+}
+
+// NewMemorySessionManager keeps sessions in process memory. Sessions are
+// lost on restart, and not shared between instances.
+func NewMemorySessionManager(conf Config) *SessionMgr {
+	return newSessionManager(memstore.New(), conf)
+}
+
 func NewTestSessionManager() *SessionMgr {
-	manager := scs.New()
-	manager.Cookie.Secure = false
-	manager.Store = memstore.NewWithCleanupInterval(0)
-	return &SessionMgr{SessionManager: manager}
+	return newSessionManager(memstore.NewWithCleanupInterval(0), Config{})
 }
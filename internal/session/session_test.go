@@ -6,7 +6,12 @@ package session
 import (
 	"bytes"
 	"flag"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/dropwhile/assert"
 
 	"github.com/dropwhile/icanbringthat/internal/logger"
 )
@@ -23,3 +28,70 @@ func TestMain(m *testing.M) {
 	)
 	m.Run()
 }
+
+func TestNewMemorySessionManager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults should persist cookies", func(t *testing.T) {
+		t.Parallel()
+
+		sm := NewMemorySessionManager(Config{})
+		t.Cleanup(sm.Close)
+
+		assert.Equal(t, sm.Lifetime, 24*time.Hour)
+		assert.Equal(t, sm.IdleTimeout, time.Duration(0))
+		assert.True(t, sm.Cookie.Persist)
+		assert.Equal(t, sm.Cookie.Secure, false)
+	})
+
+	t.Run("config should set lifetime policy", func(t *testing.T) {
+		t.Parallel()
+
+		sm := NewMemorySessionManager(Config{
+			Lifetime:    7 * 24 * time.Hour,
+			IdleTimeout: 2 * time.Hour,
+			RememberMe:  true,
+			Secure:      true,
+		})
+		t.Cleanup(sm.Close)
+
+		assert.Equal(t, sm.Lifetime, 7*24*time.Hour)
+		assert.Equal(t, sm.IdleTimeout, 2*time.Hour)
+		// cookies only persist if the user asks to be remembered
+		assert.Equal(t, sm.Cookie.Persist, false)
+		assert.True(t, sm.Cookie.Secure)
+	})
+}
+
+func TestRememberMe(t *testing.T) {
+	t.Parallel()
+
+	sm := NewMemorySessionManager(Config{RememberMe: true})
+	t.Cleanup(sm.Close)
+
+	handler := sm.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sm.Put(ctx, "user-id", 1)
+		sm.RememberMe(ctx, r.URL.Query().Get("remember") != "")
+	}))
+
+	t.Run("session cookie should not persist", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/login", nil))
+		cookies := rr.Result().Cookies()
+		assert.Equal(t, len(cookies), 1)
+		assert.True(t, cookies[0].Expires.IsZero())
+	})
+
+	t.Run("remembered cookie should persist", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/login?remember=on", nil))
+		cookies := rr.Result().Cookies()
+		assert.Equal(t, len(cookies), 1)
+		assert.True(t, cookies[0].Expires.After(time.Now()))
+	})
+}
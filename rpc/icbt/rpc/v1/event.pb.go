@@ -23,6 +23,70 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type EventChangeKind int32
+
+const (
+	EventChangeKind_EVENT_CHANGE_KIND_UNSPECIFIED     EventChangeKind = 0
+	EventChangeKind_EVENT_CHANGE_KIND_ITEM_ADDED      EventChangeKind = 1
+	EventChangeKind_EVENT_CHANGE_KIND_ITEM_UPDATED    EventChangeKind = 2
+	EventChangeKind_EVENT_CHANGE_KIND_ITEM_REMOVED    EventChangeKind = 3
+	EventChangeKind_EVENT_CHANGE_KIND_EARMARK_CREATED EventChangeKind = 4
+	EventChangeKind_EVENT_CHANGE_KIND_EARMARK_DELETED EventChangeKind = 5
+	EventChangeKind_EVENT_CHANGE_KIND_ITEMS_SORTED    EventChangeKind = 6
+	EventChangeKind_EVENT_CHANGE_KIND_EVENT_UPDATED   EventChangeKind = 7
+	// the requested cursor could not be resumed from, and changes may
+	// have been missed. clients should refetch the event details.
+	EventChangeKind_EVENT_CHANGE_KIND_RESYNC EventChangeKind = 8
+)
+
+// Enum value maps for EventChangeKind.
+var (
+	EventChangeKind_name = map[int32]string{
+		0: "EVENT_CHANGE_KIND_UNSPECIFIED",
+		1: "EVENT_CHANGE_KIND_ITEM_ADDED",
+		2: "EVENT_CHANGE_KIND_ITEM_UPDATED",
+		3: "EVENT_CHANGE_KIND_ITEM_REMOVED",
+		4: "EVENT_CHANGE_KIND_EARMARK_CREATED",
+		5: "EVENT_CHANGE_KIND_EARMARK_DELETED",
+		6: "EVENT_CHANGE_KIND_ITEMS_SORTED",
+		7: "EVENT_CHANGE_KIND_EVENT_UPDATED",
+		8: "EVENT_CHANGE_KIND_RESYNC",
+	}
+	EventChangeKind_value = map[string]int32{
+		"EVENT_CHANGE_KIND_UNSPECIFIED":     0,
+		"EVENT_CHANGE_KIND_ITEM_ADDED":      1,
+		"EVENT_CHANGE_KIND_ITEM_UPDATED":    2,
+		"EVENT_CHANGE_KIND_ITEM_REMOVED":    3,
+		"EVENT_CHANGE_KIND_EARMARK_CREATED": 4,
+		"EVENT_CHANGE_KIND_EARMARK_DELETED": 5,
+		"EVENT_CHANGE_KIND_ITEMS_SORTED":    6,
+		"EVENT_CHANGE_KIND_EVENT_UPDATED":   7,
+		"EVENT_CHANGE_KIND_RESYNC":          8,
+	}
+)
+
+func (x EventChangeKind) Enum() *EventChangeKind {
+	p := new(EventChangeKind)
+	*p = x
+	return p
+}
+
+func (x EventChangeKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventChangeKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_icbt_rpc_v1_event_proto_enumTypes[0].Descriptor()
+}
+
+func (EventChangeKind) Type() protoreflect.EnumType {
+	return &file_icbt_rpc_v1_event_proto_enumTypes[0]
+}
+
+func (x EventChangeKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
 type Event struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_RefId       string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
@@ -31,6 +95,7 @@ type Event struct {
 	xxx_hidden_When        *TimestampTZ           `protobuf:"bytes,4,opt,name=when"`
 	xxx_hidden_Archived    bool                   `protobuf:"varint,5,opt,name=archived"`
 	xxx_hidden_Created     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created"`
+	xxx_hidden_Version     string                 `protobuf:"bytes,7,opt,name=version"`
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
@@ -102,6 +167,13 @@ func (x *Event) GetCreated() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Event) GetVersion() string {
+	if x != nil {
+		return x.xxx_hidden_Version
+	}
+	return ""
+}
+
 func (x *Event) SetRefId(v string) {
 	x.xxx_hidden_RefId = v
 }
@@ -126,6 +198,10 @@ func (x *Event) SetCreated(v *timestamppb.Timestamp) {
 	x.xxx_hidden_Created = v
 }
 
+func (x *Event) SetVersion(v string) {
+	x.xxx_hidden_Version = v
+}
+
 func (x *Event) HasWhen() bool {
 	if x == nil {
 		return false
@@ -157,6 +233,8 @@ type Event_builder struct {
 	When        *TimestampTZ
 	Archived    bool
 	Created     *timestamppb.Timestamp
+	// opaque version, changed by every modification
+	Version string
 }
 
 func (b0 Event_builder) Build() *Event {
@@ -169,6 +247,7 @@ func (b0 Event_builder) Build() *Event {
 	x.xxx_hidden_When = b.When
 	x.xxx_hidden_Archived = b.Archived
 	x.xxx_hidden_Created = b.Created
+	x.xxx_hidden_Version = b.Version
 	return m0
 }
 
@@ -177,6 +256,7 @@ type EventItem struct {
 	xxx_hidden_RefId       string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
 	xxx_hidden_Description string                 `protobuf:"bytes,2,opt,name=description"`
 	xxx_hidden_Created     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created"`
+	xxx_hidden_Version     string                 `protobuf:"bytes,4,opt,name=version"`
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
@@ -227,6 +307,13 @@ func (x *EventItem) GetCreated() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *EventItem) GetVersion() string {
+	if x != nil {
+		return x.xxx_hidden_Version
+	}
+	return ""
+}
+
 func (x *EventItem) SetRefId(v string) {
 	x.xxx_hidden_RefId = v
 }
@@ -239,6 +326,10 @@ func (x *EventItem) SetCreated(v *timestamppb.Timestamp) {
 	x.xxx_hidden_Created = v
 }
 
+func (x *EventItem) SetVersion(v string) {
+	x.xxx_hidden_Version = v
+}
+
 func (x *EventItem) HasCreated() bool {
 	if x == nil {
 		return false
@@ -256,6 +347,8 @@ type EventItem_builder struct {
 	RefId       string
 	Description string
 	Created     *timestamppb.Timestamp
+	// opaque version, changed by every modification
+	Version string
 }
 
 func (b0 EventItem_builder) Build() *EventItem {
@@ -265,6 +358,7 @@ func (b0 EventItem_builder) Build() *EventItem {
 	x.xxx_hidden_RefId = b.RefId
 	x.xxx_hidden_Description = b.Description
 	x.xxx_hidden_Created = b.Created
+	x.xxx_hidden_Version = b.Version
 	return m0
 }
 
@@ -495,6 +589,7 @@ type EventUpdateRequest struct {
 	xxx_hidden_Name        *string                `protobuf:"bytes,2,opt,name=name"`
 	xxx_hidden_Description *string                `protobuf:"bytes,3,opt,name=description"`
 	xxx_hidden_When        *TimestampTZ           `protobuf:"bytes,4,opt,name=when"`
+	xxx_hidden_Version     *string                `protobuf:"bytes,5,opt,name=version"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
@@ -560,24 +655,39 @@ func (x *EventUpdateRequest) GetWhen() *TimestampTZ {
 	return nil
 }
 
+func (x *EventUpdateRequest) GetVersion() string {
+	if x != nil {
+		if x.xxx_hidden_Version != nil {
+			return *x.xxx_hidden_Version
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *EventUpdateRequest) SetRefId(v string) {
 	x.xxx_hidden_RefId = v
 }
 
 func (x *EventUpdateRequest) SetName(v string) {
 	x.xxx_hidden_Name = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 4)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
 }
 
 func (x *EventUpdateRequest) SetDescription(v string) {
 	x.xxx_hidden_Description = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 4)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
 }
 
 func (x *EventUpdateRequest) SetWhen(v *TimestampTZ) {
 	x.xxx_hidden_When = v
 }
 
+func (x *EventUpdateRequest) SetVersion(v string) {
+	x.xxx_hidden_Version = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 5)
+}
+
 func (x *EventUpdateRequest) HasName() bool {
 	if x == nil {
 		return false
@@ -599,6 +709,13 @@ func (x *EventUpdateRequest) HasWhen() bool {
 	return x.xxx_hidden_When != nil
 }
 
+func (x *EventUpdateRequest) HasVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
 func (x *EventUpdateRequest) ClearName() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
 	x.xxx_hidden_Name = nil
@@ -613,6 +730,11 @@ func (x *EventUpdateRequest) ClearWhen() {
 	x.xxx_hidden_When = nil
 }
 
+func (x *EventUpdateRequest) ClearVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Version = nil
+}
+
 type EventUpdateRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
@@ -620,6 +742,9 @@ type EventUpdateRequest_builder struct {
 	Name        *string
 	Description *string
 	When        *TimestampTZ
+	// expected event version. if set and the event has since been
+	// modified, the update fails with ABORTED.
+	Version *string
 }
 
 func (b0 EventUpdateRequest_builder) Build() *EventUpdateRequest {
@@ -628,14 +753,18 @@ func (b0 EventUpdateRequest_builder) Build() *EventUpdateRequest {
 	_, _ = b, x
 	x.xxx_hidden_RefId = b.RefId
 	if b.Name != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 4)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
 		x.xxx_hidden_Name = b.Name
 	}
 	if b.Description != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 4)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
 		x.xxx_hidden_Description = b.Description
 	}
 	x.xxx_hidden_When = b.When
+	if b.Version != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 5)
+		x.xxx_hidden_Version = b.Version
+	}
 	return m0
 }
 
@@ -1458,17 +1587,152 @@ func (b0 EventRemoveItemRequest_builder) Build() *EventRemoveItemRequest {
 	return m0
 }
 
+type EventUpdateItemSortingRequest struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_EventRefId string                 `protobuf:"bytes,1,opt,name=event_ref_id,json=eventRefId"`
+	xxx_hidden_ItemRefIds []string               `protobuf:"bytes,2,rep,name=item_ref_ids,json=itemRefIds"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *EventUpdateItemSortingRequest) Reset() {
+	*x = EventUpdateItemSortingRequest{}
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventUpdateItemSortingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventUpdateItemSortingRequest) ProtoMessage() {}
+
+func (x *EventUpdateItemSortingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *EventUpdateItemSortingRequest) GetEventRefId() string {
+	if x != nil {
+		return x.xxx_hidden_EventRefId
+	}
+	return ""
+}
+
+func (x *EventUpdateItemSortingRequest) GetItemRefIds() []string {
+	if x != nil {
+		return x.xxx_hidden_ItemRefIds
+	}
+	return nil
+}
+
+func (x *EventUpdateItemSortingRequest) SetEventRefId(v string) {
+	x.xxx_hidden_EventRefId = v
+}
+
+func (x *EventUpdateItemSortingRequest) SetItemRefIds(v []string) {
+	x.xxx_hidden_ItemRefIds = v
+}
+
+type EventUpdateItemSortingRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	EventRefId string
+	// the desired item order. items not listed are placed first.
+	ItemRefIds []string
+}
+
+func (b0 EventUpdateItemSortingRequest_builder) Build() *EventUpdateItemSortingRequest {
+	m0 := &EventUpdateItemSortingRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_EventRefId = b.EventRefId
+	x.xxx_hidden_ItemRefIds = b.ItemRefIds
+	return m0
+}
+
+type EventUpdateItemSortingResponse struct {
+	state            protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Items *[]*EventItem          `protobuf:"bytes,1,rep,name=items"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *EventUpdateItemSortingResponse) Reset() {
+	*x = EventUpdateItemSortingResponse{}
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventUpdateItemSortingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventUpdateItemSortingResponse) ProtoMessage() {}
+
+func (x *EventUpdateItemSortingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *EventUpdateItemSortingResponse) GetItems() []*EventItem {
+	if x != nil {
+		if x.xxx_hidden_Items != nil {
+			return *x.xxx_hidden_Items
+		}
+	}
+	return nil
+}
+
+func (x *EventUpdateItemSortingResponse) SetItems(v []*EventItem) {
+	x.xxx_hidden_Items = &v
+}
+
+type EventUpdateItemSortingResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// all event items, in their new order
+	Items []*EventItem
+}
+
+func (b0 EventUpdateItemSortingResponse_builder) Build() *EventUpdateItemSortingResponse {
+	m0 := &EventUpdateItemSortingResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Items = &b.Items
+	return m0
+}
+
 type EventUpdateItemRequest struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_RefId       string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
 	xxx_hidden_Description string                 `protobuf:"bytes,2,opt,name=description"`
+	xxx_hidden_Version     *string                `protobuf:"bytes,3,opt,name=version"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
 func (x *EventUpdateItemRequest) Reset() {
 	*x = EventUpdateItemRequest{}
-	mi := &file_icbt_rpc_v1_event_proto_msgTypes[17]
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1480,7 +1744,7 @@ func (x *EventUpdateItemRequest) String() string {
 func (*EventUpdateItemRequest) ProtoMessage() {}
 
 func (x *EventUpdateItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_icbt_rpc_v1_event_proto_msgTypes[17]
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1505,6 +1769,16 @@ func (x *EventUpdateItemRequest) GetDescription() string {
 	return ""
 }
 
+func (x *EventUpdateItemRequest) GetVersion() string {
+	if x != nil {
+		if x.xxx_hidden_Version != nil {
+			return *x.xxx_hidden_Version
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *EventUpdateItemRequest) SetRefId(v string) {
 	x.xxx_hidden_RefId = v
 }
@@ -1513,11 +1787,31 @@ func (x *EventUpdateItemRequest) SetDescription(v string) {
 	x.xxx_hidden_Description = v
 }
 
+func (x *EventUpdateItemRequest) SetVersion(v string) {
+	x.xxx_hidden_Version = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *EventUpdateItemRequest) HasVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *EventUpdateItemRequest) ClearVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Version = nil
+}
+
 type EventUpdateItemRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 	RefId       string
 	Description string
+	// expected event item version. if set and the item has since been
+	// modified, the update fails with ABORTED.
+	Version *string
 }
 
 func (b0 EventUpdateItemRequest_builder) Build() *EventUpdateItemRequest {
@@ -1526,6 +1820,10 @@ func (b0 EventUpdateItemRequest_builder) Build() *EventUpdateItemRequest {
 	_, _ = b, x
 	x.xxx_hidden_RefId = b.RefId
 	x.xxx_hidden_Description = b.Description
+	if b.Version != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Version = b.Version
+	}
 	return m0
 }
 
@@ -1538,7 +1836,7 @@ type EventUpdateItemResponse struct {
 
 func (x *EventUpdateItemResponse) Reset() {
 	*x = EventUpdateItemResponse{}
-	mi := &file_icbt_rpc_v1_event_proto_msgTypes[18]
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1550,7 +1848,7 @@ func (x *EventUpdateItemResponse) String() string {
 func (*EventUpdateItemResponse) ProtoMessage() {}
 
 func (x *EventUpdateItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_icbt_rpc_v1_event_proto_msgTypes[18]
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1597,22 +1895,182 @@ func (b0 EventUpdateItemResponse_builder) Build() *EventUpdateItemResponse {
 	return m0
 }
 
+type WatchEventRequest struct {
+	state             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId  string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Cursor string                 `protobuf:"bytes,2,opt,name=cursor"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchEventRequest) Reset() {
+	*x = WatchEventRequest{}
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventRequest) ProtoMessage() {}
+
+func (x *WatchEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WatchEventRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *WatchEventRequest) GetCursor() string {
+	if x != nil {
+		return x.xxx_hidden_Cursor
+	}
+	return ""
+}
+
+func (x *WatchEventRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *WatchEventRequest) SetCursor(v string) {
+	x.xxx_hidden_Cursor = v
+}
+
+type WatchEventRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+	// resume after this cursor, as returned by a previous response
+	Cursor string
+}
+
+func (b0 WatchEventRequest_builder) Build() *WatchEventRequest {
+	m0 := &WatchEventRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Cursor = b.Cursor
+	return m0
+}
+
+type WatchEventResponse struct {
+	state             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Kind   EventChangeKind        `protobuf:"varint,1,opt,name=kind,enum=icbt.rpc.v1.EventChangeKind"`
+	xxx_hidden_RefId  string                 `protobuf:"bytes,2,opt,name=ref_id,json=refId"`
+	xxx_hidden_Cursor string                 `protobuf:"bytes,3,opt,name=cursor"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchEventResponse) Reset() {
+	*x = WatchEventResponse{}
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventResponse) ProtoMessage() {}
+
+func (x *WatchEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_event_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WatchEventResponse) GetKind() EventChangeKind {
+	if x != nil {
+		return x.xxx_hidden_Kind
+	}
+	return EventChangeKind_EVENT_CHANGE_KIND_UNSPECIFIED
+}
+
+func (x *WatchEventResponse) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *WatchEventResponse) GetCursor() string {
+	if x != nil {
+		return x.xxx_hidden_Cursor
+	}
+	return ""
+}
+
+func (x *WatchEventResponse) SetKind(v EventChangeKind) {
+	x.xxx_hidden_Kind = v
+}
+
+func (x *WatchEventResponse) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *WatchEventResponse) SetCursor(v string) {
+	x.xxx_hidden_Cursor = v
+}
+
+type WatchEventResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Kind EventChangeKind
+	// ref-id of the changed event, event item, or earmark
+	RefId  string
+	Cursor string
+}
+
+func (b0 WatchEventResponse_builder) Build() *WatchEventResponse {
+	m0 := &WatchEventResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Kind = b.Kind
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Cursor = b.Cursor
+	return m0
+}
+
 var File_icbt_rpc_v1_event_proto protoreflect.FileDescriptor
 
 const file_icbt_rpc_v1_event_proto_rawDesc = "" +
 	"\n" +
-	"\x17icbt/rpc/v1/event.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1dicbt/rpc/v1/constraints.proto\x1a\x19icbt/rpc/v1/earmark.proto\x1a\x1cicbt/rpc/v1/pagination.proto\x1a\x1dicbt/rpc/v1/timestamptz.proto\"\xd4\x01\n" +
+	"\x17icbt/rpc/v1/event.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1dicbt/rpc/v1/constraints.proto\x1a\x19icbt/rpc/v1/earmark.proto\x1a\x1cicbt/rpc/v1/pagination.proto\x1a\x1dicbt/rpc/v1/timestamptz.proto\"\xee\x01\n" +
 	"\x05Event\x12\x15\n" +
 	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12,\n" +
 	"\x04when\x18\x04 \x01(\v2\x18.icbt.rpc.v1.TimestampTZR\x04when\x12\x1a\n" +
 	"\barchived\x18\x05 \x01(\bR\barchived\x124\n" +
-	"\acreated\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"z\n" +
+	"\acreated\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x12\x18\n" +
+	"\aversion\x18\a \x01(\tR\aversion\"\x94\x01\n" +
 	"\tEventItem\x12\x15\n" +
 	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x124\n" +
-	"\acreated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"\x8a\x01\n" +
+	"\acreated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\"\x8a\x01\n" +
 	"\x12EventCreateRequest\x12\x1b\n" +
 	"\x04name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x04name\x12)\n" +
 	"\vdescription\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\vdescription\x12,\n" +
@@ -1620,12 +2078,13 @@ const file_icbt_rpc_v1_event_proto_rawDesc = "" +
 	"\x13EventCreateResponse\x12(\n" +
 	"\x05event\x18\x01 \x01(\v2\x12.icbt.rpc.v1.EventR\x05event\"8\n" +
 	"\x12EventDeleteRequest\x12\"\n" +
-	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\xb1\x01\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\xd2\x01\n" +
 	"\x12EventUpdateRequest\x12\"\n" +
 	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\x12\x19\n" +
 	"\x04name\x18\x02 \x01(\tB\x05\xaa\x01\x02\b\x01R\x04name\x12'\n" +
 	"\vdescription\x18\x03 \x01(\tB\x05\xaa\x01\x02\b\x01R\vdescription\x123\n" +
-	"\x04when\x18\x04 \x01(\v2\x18.icbt.rpc.v1.TimestampTZB\x05\xaa\x01\x02\b\x01R\x04when\"<\n" +
+	"\x04when\x18\x04 \x01(\v2\x18.icbt.rpc.v1.TimestampTZB\x05\xaa\x01\x02\b\x01R\x04when\x12\x1f\n" +
+	"\aversion\x18\x05 \x01(\tB\x05\xaa\x01\x02\b\x01R\aversion\"<\n" +
 	"\x16EventGetDetailsRequest\x12\"\n" +
 	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\xa3\x01\n" +
 	"\x17EventGetDetailsResponse\x12(\n" +
@@ -1664,67 +2123,100 @@ const file_icbt_rpc_v1_event_proto_rawDesc = "" +
 	"\n" +
 	"event_item\x18\x01 \x01(\v2\x16.icbt.rpc.v1.EventItemR\teventItem\"<\n" +
 	"\x16EventRemoveItemRequest\x12\"\n" +
-	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"^\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\x84\x01\n" +
+	"\x1dEventUpdateItemSortingRequest\x12-\n" +
+	"\fevent_ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\n" +
+	"eventRefId\x124\n" +
+	"\fitem_ref_ids\x18\x02 \x03(\tB\x12\xbaH\x0f\x92\x01\f\b\x01\"\br\x06\x88\u0603\x8b\x02\x01R\n" +
+	"itemRefIds\"N\n" +
+	"\x1eEventUpdateItemSortingResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.icbt.rpc.v1.EventItemR\x05items\"\x7f\n" +
 	"\x16EventUpdateItemRequest\x12\"\n" +
 	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"P\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
+	"\aversion\x18\x03 \x01(\tB\x05\xaa\x01\x02\b\x01R\aversion\"P\n" +
 	"\x17EventUpdateItemResponse\x125\n" +
 	"\n" +
-	"event_item\x18\x01 \x01(\v2\x16.icbt.rpc.v1.EventItemR\teventItemB\xaf\x01\n" +
+	"event_item\x18\x01 \x01(\v2\x16.icbt.rpc.v1.EventItemR\teventItem\"O\n" +
+	"\x11WatchEventRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\"u\n" +
+	"\x12WatchEventResponse\x120\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1c.icbt.rpc.v1.EventChangeKindR\x04kind\x12\x15\n" +
+	"\x06ref_id\x18\x02 \x01(\tR\x05refId\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor*\xd3\x02\n" +
+	"\x0fEventChangeKind\x12!\n" +
+	"\x1dEVENT_CHANGE_KIND_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cEVENT_CHANGE_KIND_ITEM_ADDED\x10\x01\x12\"\n" +
+	"\x1eEVENT_CHANGE_KIND_ITEM_UPDATED\x10\x02\x12\"\n" +
+	"\x1eEVENT_CHANGE_KIND_ITEM_REMOVED\x10\x03\x12%\n" +
+	"!EVENT_CHANGE_KIND_EARMARK_CREATED\x10\x04\x12%\n" +
+	"!EVENT_CHANGE_KIND_EARMARK_DELETED\x10\x05\x12\"\n" +
+	"\x1eEVENT_CHANGE_KIND_ITEMS_SORTED\x10\x06\x12#\n" +
+	"\x1fEVENT_CHANGE_KIND_EVENT_UPDATED\x10\a\x12\x1c\n" +
+	"\x18EVENT_CHANGE_KIND_RESYNC\x10\bB\xaf\x01\n" +
 	"\x0fcom.icbt.rpc.v1B\n" +
 	"EventProtoP\x01Z8github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1;rpcv1\xa2\x02\x03IRX\xaa\x02\vIcbt.Rpc.V1\xca\x02\vIcbt\\Rpc\\V1\xe2\x02\x17Icbt\\Rpc\\V1\\GPBMetadata\xea\x02\rIcbt::Rpc::V1\x92\x03\a\xd2>\x02\x10\x03\b\x02b\beditionsp\xe8\a"
 
-var file_icbt_rpc_v1_event_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_icbt_rpc_v1_event_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_icbt_rpc_v1_event_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_icbt_rpc_v1_event_proto_goTypes = []any{
-	(*Event)(nil),                     // 0: icbt.rpc.v1.Event
-	(*EventItem)(nil),                 // 1: icbt.rpc.v1.EventItem
-	(*EventCreateRequest)(nil),        // 2: icbt.rpc.v1.EventCreateRequest
-	(*EventCreateResponse)(nil),       // 3: icbt.rpc.v1.EventCreateResponse
-	(*EventDeleteRequest)(nil),        // 4: icbt.rpc.v1.EventDeleteRequest
-	(*EventUpdateRequest)(nil),        // 5: icbt.rpc.v1.EventUpdateRequest
-	(*EventGetDetailsRequest)(nil),    // 6: icbt.rpc.v1.EventGetDetailsRequest
-	(*EventGetDetailsResponse)(nil),   // 7: icbt.rpc.v1.EventGetDetailsResponse
-	(*EventsListRequest)(nil),         // 8: icbt.rpc.v1.EventsListRequest
-	(*EventsListResponse)(nil),        // 9: icbt.rpc.v1.EventsListResponse
-	(*EventListItemsRequest)(nil),     // 10: icbt.rpc.v1.EventListItemsRequest
-	(*EventListItemsResponse)(nil),    // 11: icbt.rpc.v1.EventListItemsResponse
-	(*EventListEarmarksRequest)(nil),  // 12: icbt.rpc.v1.EventListEarmarksRequest
-	(*EventListEarmarksResponse)(nil), // 13: icbt.rpc.v1.EventListEarmarksResponse
-	(*EventAddItemRequest)(nil),       // 14: icbt.rpc.v1.EventAddItemRequest
-	(*EventAddItemResponse)(nil),      // 15: icbt.rpc.v1.EventAddItemResponse
-	(*EventRemoveItemRequest)(nil),    // 16: icbt.rpc.v1.EventRemoveItemRequest
-	(*EventUpdateItemRequest)(nil),    // 17: icbt.rpc.v1.EventUpdateItemRequest
-	(*EventUpdateItemResponse)(nil),   // 18: icbt.rpc.v1.EventUpdateItemResponse
-	(*TimestampTZ)(nil),               // 19: icbt.rpc.v1.TimestampTZ
-	(*timestamppb.Timestamp)(nil),     // 20: google.protobuf.Timestamp
-	(*Earmark)(nil),                   // 21: icbt.rpc.v1.Earmark
-	(*PaginationRequest)(nil),         // 22: icbt.rpc.v1.PaginationRequest
-	(*PaginationResult)(nil),          // 23: icbt.rpc.v1.PaginationResult
+	(EventChangeKind)(0),                   // 0: icbt.rpc.v1.EventChangeKind
+	(*Event)(nil),                          // 1: icbt.rpc.v1.Event
+	(*EventItem)(nil),                      // 2: icbt.rpc.v1.EventItem
+	(*EventCreateRequest)(nil),             // 3: icbt.rpc.v1.EventCreateRequest
+	(*EventCreateResponse)(nil),            // 4: icbt.rpc.v1.EventCreateResponse
+	(*EventDeleteRequest)(nil),             // 5: icbt.rpc.v1.EventDeleteRequest
+	(*EventUpdateRequest)(nil),             // 6: icbt.rpc.v1.EventUpdateRequest
+	(*EventGetDetailsRequest)(nil),         // 7: icbt.rpc.v1.EventGetDetailsRequest
+	(*EventGetDetailsResponse)(nil),        // 8: icbt.rpc.v1.EventGetDetailsResponse
+	(*EventsListRequest)(nil),              // 9: icbt.rpc.v1.EventsListRequest
+	(*EventsListResponse)(nil),             // 10: icbt.rpc.v1.EventsListResponse
+	(*EventListItemsRequest)(nil),          // 11: icbt.rpc.v1.EventListItemsRequest
+	(*EventListItemsResponse)(nil),         // 12: icbt.rpc.v1.EventListItemsResponse
+	(*EventListEarmarksRequest)(nil),       // 13: icbt.rpc.v1.EventListEarmarksRequest
+	(*EventListEarmarksResponse)(nil),      // 14: icbt.rpc.v1.EventListEarmarksResponse
+	(*EventAddItemRequest)(nil),            // 15: icbt.rpc.v1.EventAddItemRequest
+	(*EventAddItemResponse)(nil),           // 16: icbt.rpc.v1.EventAddItemResponse
+	(*EventRemoveItemRequest)(nil),         // 17: icbt.rpc.v1.EventRemoveItemRequest
+	(*EventUpdateItemSortingRequest)(nil),  // 18: icbt.rpc.v1.EventUpdateItemSortingRequest
+	(*EventUpdateItemSortingResponse)(nil), // 19: icbt.rpc.v1.EventUpdateItemSortingResponse
+	(*EventUpdateItemRequest)(nil),         // 20: icbt.rpc.v1.EventUpdateItemRequest
+	(*EventUpdateItemResponse)(nil),        // 21: icbt.rpc.v1.EventUpdateItemResponse
+	(*WatchEventRequest)(nil),              // 22: icbt.rpc.v1.WatchEventRequest
+	(*WatchEventResponse)(nil),             // 23: icbt.rpc.v1.WatchEventResponse
+	(*TimestampTZ)(nil),                    // 24: icbt.rpc.v1.TimestampTZ
+	(*timestamppb.Timestamp)(nil),          // 25: google.protobuf.Timestamp
+	(*Earmark)(nil),                        // 26: icbt.rpc.v1.Earmark
+	(*PaginationRequest)(nil),              // 27: icbt.rpc.v1.PaginationRequest
+	(*PaginationResult)(nil),               // 28: icbt.rpc.v1.PaginationResult
 }
 var file_icbt_rpc_v1_event_proto_depIdxs = []int32{
-	19, // 0: icbt.rpc.v1.Event.when:type_name -> icbt.rpc.v1.TimestampTZ
-	20, // 1: icbt.rpc.v1.Event.created:type_name -> google.protobuf.Timestamp
-	20, // 2: icbt.rpc.v1.EventItem.created:type_name -> google.protobuf.Timestamp
-	19, // 3: icbt.rpc.v1.EventCreateRequest.when:type_name -> icbt.rpc.v1.TimestampTZ
-	0,  // 4: icbt.rpc.v1.EventCreateResponse.event:type_name -> icbt.rpc.v1.Event
-	19, // 5: icbt.rpc.v1.EventUpdateRequest.when:type_name -> icbt.rpc.v1.TimestampTZ
-	0,  // 6: icbt.rpc.v1.EventGetDetailsResponse.event:type_name -> icbt.rpc.v1.Event
-	1,  // 7: icbt.rpc.v1.EventGetDetailsResponse.items:type_name -> icbt.rpc.v1.EventItem
-	21, // 8: icbt.rpc.v1.EventGetDetailsResponse.earmarks:type_name -> icbt.rpc.v1.Earmark
-	22, // 9: icbt.rpc.v1.EventsListRequest.pagination:type_name -> icbt.rpc.v1.PaginationRequest
-	0,  // 10: icbt.rpc.v1.EventsListResponse.events:type_name -> icbt.rpc.v1.Event
-	23, // 11: icbt.rpc.v1.EventsListResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
-	1,  // 12: icbt.rpc.v1.EventListItemsResponse.items:type_name -> icbt.rpc.v1.EventItem
-	23, // 13: icbt.rpc.v1.EventListItemsResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
-	21, // 14: icbt.rpc.v1.EventListEarmarksResponse.earmarks:type_name -> icbt.rpc.v1.Earmark
-	23, // 15: icbt.rpc.v1.EventListEarmarksResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
-	1,  // 16: icbt.rpc.v1.EventAddItemResponse.event_item:type_name -> icbt.rpc.v1.EventItem
-	1,  // 17: icbt.rpc.v1.EventUpdateItemResponse.event_item:type_name -> icbt.rpc.v1.EventItem
-	18, // [18:18] is the sub-list for method output_type
-	18, // [18:18] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	24, // 0: icbt.rpc.v1.Event.when:type_name -> icbt.rpc.v1.TimestampTZ
+	25, // 1: icbt.rpc.v1.Event.created:type_name -> google.protobuf.Timestamp
+	25, // 2: icbt.rpc.v1.EventItem.created:type_name -> google.protobuf.Timestamp
+	24, // 3: icbt.rpc.v1.EventCreateRequest.when:type_name -> icbt.rpc.v1.TimestampTZ
+	1,  // 4: icbt.rpc.v1.EventCreateResponse.event:type_name -> icbt.rpc.v1.Event
+	24, // 5: icbt.rpc.v1.EventUpdateRequest.when:type_name -> icbt.rpc.v1.TimestampTZ
+	1,  // 6: icbt.rpc.v1.EventGetDetailsResponse.event:type_name -> icbt.rpc.v1.Event
+	2,  // 7: icbt.rpc.v1.EventGetDetailsResponse.items:type_name -> icbt.rpc.v1.EventItem
+	26, // 8: icbt.rpc.v1.EventGetDetailsResponse.earmarks:type_name -> icbt.rpc.v1.Earmark
+	27, // 9: icbt.rpc.v1.EventsListRequest.pagination:type_name -> icbt.rpc.v1.PaginationRequest
+	1,  // 10: icbt.rpc.v1.EventsListResponse.events:type_name -> icbt.rpc.v1.Event
+	28, // 11: icbt.rpc.v1.EventsListResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
+	2,  // 12: icbt.rpc.v1.EventListItemsResponse.items:type_name -> icbt.rpc.v1.EventItem
+	28, // 13: icbt.rpc.v1.EventListItemsResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
+	26, // 14: icbt.rpc.v1.EventListEarmarksResponse.earmarks:type_name -> icbt.rpc.v1.Earmark
+	28, // 15: icbt.rpc.v1.EventListEarmarksResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
+	2,  // 16: icbt.rpc.v1.EventAddItemResponse.event_item:type_name -> icbt.rpc.v1.EventItem
+	2,  // 17: icbt.rpc.v1.EventUpdateItemSortingResponse.items:type_name -> icbt.rpc.v1.EventItem
+	2,  // 18: icbt.rpc.v1.EventUpdateItemResponse.event_item:type_name -> icbt.rpc.v1.EventItem
+	0,  // 19: icbt.rpc.v1.WatchEventResponse.kind:type_name -> icbt.rpc.v1.EventChangeKind
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_icbt_rpc_v1_event_proto_init() }
@@ -1741,13 +2233,14 @@ func file_icbt_rpc_v1_event_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_icbt_rpc_v1_event_proto_rawDesc), len(file_icbt_rpc_v1_event_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   19,
+			NumEnums:      1,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_icbt_rpc_v1_event_proto_goTypes,
 		DependencyIndexes: file_icbt_rpc_v1_event_proto_depIdxs,
+		EnumInfos:         file_icbt_rpc_v1_event_proto_enumTypes,
 		MessageInfos:      file_icbt_rpc_v1_event_proto_msgTypes,
 	}.Build()
 	File_icbt_rpc_v1_event_proto = out.File
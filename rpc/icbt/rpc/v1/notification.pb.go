@@ -23,13 +23,138 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type NotificationKind int32
+
+const (
+	NotificationKind_NOTIFICATION_KIND_UNSPECIFIED        NotificationKind = 0
+	NotificationKind_NOTIFICATION_KIND_MESSAGE            NotificationKind = 1
+	NotificationKind_NOTIFICATION_KIND_ACCOUNT_UNVERIFIED NotificationKind = 2
+	NotificationKind_NOTIFICATION_KIND_REMINDERS_DISABLED NotificationKind = 3
+	NotificationKind_NOTIFICATION_KIND_EARMARK_CLAIMED    NotificationKind = 4
+	NotificationKind_NOTIFICATION_KIND_EVENT_CHANGED      NotificationKind = 5
+	NotificationKind_NOTIFICATION_KIND_NEW_SIGN_IN        NotificationKind = 6
+	NotificationKind_NOTIFICATION_KIND_EVENT_TRANSFERRED  NotificationKind = 7
+)
+
+// Enum value maps for NotificationKind.
+var (
+	NotificationKind_name = map[int32]string{
+		0: "NOTIFICATION_KIND_UNSPECIFIED",
+		1: "NOTIFICATION_KIND_MESSAGE",
+		2: "NOTIFICATION_KIND_ACCOUNT_UNVERIFIED",
+		3: "NOTIFICATION_KIND_REMINDERS_DISABLED",
+		4: "NOTIFICATION_KIND_EARMARK_CLAIMED",
+		5: "NOTIFICATION_KIND_EVENT_CHANGED",
+		6: "NOTIFICATION_KIND_NEW_SIGN_IN",
+		7: "NOTIFICATION_KIND_EVENT_TRANSFERRED",
+	}
+	NotificationKind_value = map[string]int32{
+		"NOTIFICATION_KIND_UNSPECIFIED":        0,
+		"NOTIFICATION_KIND_MESSAGE":            1,
+		"NOTIFICATION_KIND_ACCOUNT_UNVERIFIED": 2,
+		"NOTIFICATION_KIND_REMINDERS_DISABLED": 3,
+		"NOTIFICATION_KIND_EARMARK_CLAIMED":    4,
+		"NOTIFICATION_KIND_EVENT_CHANGED":      5,
+		"NOTIFICATION_KIND_NEW_SIGN_IN":        6,
+		"NOTIFICATION_KIND_EVENT_TRANSFERRED":  7,
+	}
+)
+
+func (x NotificationKind) Enum() *NotificationKind {
+	p := new(NotificationKind)
+	*p = x
+	return p
+}
+
+func (x NotificationKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_icbt_rpc_v1_notification_proto_enumTypes[0].Descriptor()
+}
+
+func (NotificationKind) Type() protoreflect.EnumType {
+	return &file_icbt_rpc_v1_notification_proto_enumTypes[0]
+}
+
+func (x NotificationKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+type NotificationChangeKind int32
+
+const (
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_UNSPECIFIED NotificationChangeKind = 0
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_CREATED     NotificationChangeKind = 1
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_DELETED     NotificationChangeKind = 2
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_READ        NotificationChangeKind = 3
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_UNREAD      NotificationChangeKind = 4
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_ALL_READ    NotificationChangeKind = 5
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_ALL_DELETED NotificationChangeKind = 6
+	// the requested cursor could not be resumed from, and changes may
+	// have been missed. clients should refetch notifications.
+	NotificationChangeKind_NOTIFICATION_CHANGE_KIND_RESYNC NotificationChangeKind = 7
+)
+
+// Enum value maps for NotificationChangeKind.
+var (
+	NotificationChangeKind_name = map[int32]string{
+		0: "NOTIFICATION_CHANGE_KIND_UNSPECIFIED",
+		1: "NOTIFICATION_CHANGE_KIND_CREATED",
+		2: "NOTIFICATION_CHANGE_KIND_DELETED",
+		3: "NOTIFICATION_CHANGE_KIND_READ",
+		4: "NOTIFICATION_CHANGE_KIND_UNREAD",
+		5: "NOTIFICATION_CHANGE_KIND_ALL_READ",
+		6: "NOTIFICATION_CHANGE_KIND_ALL_DELETED",
+		7: "NOTIFICATION_CHANGE_KIND_RESYNC",
+	}
+	NotificationChangeKind_value = map[string]int32{
+		"NOTIFICATION_CHANGE_KIND_UNSPECIFIED": 0,
+		"NOTIFICATION_CHANGE_KIND_CREATED":     1,
+		"NOTIFICATION_CHANGE_KIND_DELETED":     2,
+		"NOTIFICATION_CHANGE_KIND_READ":        3,
+		"NOTIFICATION_CHANGE_KIND_UNREAD":      4,
+		"NOTIFICATION_CHANGE_KIND_ALL_READ":    5,
+		"NOTIFICATION_CHANGE_KIND_ALL_DELETED": 6,
+		"NOTIFICATION_CHANGE_KIND_RESYNC":      7,
+	}
+)
+
+func (x NotificationChangeKind) Enum() *NotificationChangeKind {
+	p := new(NotificationChangeKind)
+	*p = x
+	return p
+}
+
+func (x NotificationChangeKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationChangeKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_icbt_rpc_v1_notification_proto_enumTypes[1].Descriptor()
+}
+
+func (NotificationChangeKind) Type() protoreflect.EnumType {
+	return &file_icbt_rpc_v1_notification_proto_enumTypes[1]
+}
+
+func (x NotificationChangeKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
 type Notification struct {
-	state              protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_RefId   string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
-	xxx_hidden_Message string                 `protobuf:"bytes,2,opt,name=message"`
-	xxx_hidden_Created *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state                     protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId          string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Message        string                 `protobuf:"bytes,2,opt,name=message"`
+	xxx_hidden_Created        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created"`
+	xxx_hidden_Read           bool                   `protobuf:"varint,4,opt,name=read"`
+	xxx_hidden_Kind           NotificationKind       `protobuf:"varint,5,opt,name=kind,enum=icbt.rpc.v1.NotificationKind"`
+	xxx_hidden_EventRefId     string                 `protobuf:"bytes,6,opt,name=event_ref_id,json=eventRefId"`
+	xxx_hidden_EventItemRefId string                 `protobuf:"bytes,7,opt,name=event_item_ref_id,json=eventItemRefId"`
+	xxx_hidden_EarmarkRefId   string                 `protobuf:"bytes,8,opt,name=earmark_ref_id,json=earmarkRefId"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
 }
 
 func (x *Notification) Reset() {
@@ -78,6 +203,41 @@ func (x *Notification) GetCreated() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Notification) GetRead() bool {
+	if x != nil {
+		return x.xxx_hidden_Read
+	}
+	return false
+}
+
+func (x *Notification) GetKind() NotificationKind {
+	if x != nil {
+		return x.xxx_hidden_Kind
+	}
+	return NotificationKind_NOTIFICATION_KIND_UNSPECIFIED
+}
+
+func (x *Notification) GetEventRefId() string {
+	if x != nil {
+		return x.xxx_hidden_EventRefId
+	}
+	return ""
+}
+
+func (x *Notification) GetEventItemRefId() string {
+	if x != nil {
+		return x.xxx_hidden_EventItemRefId
+	}
+	return ""
+}
+
+func (x *Notification) GetEarmarkRefId() string {
+	if x != nil {
+		return x.xxx_hidden_EarmarkRefId
+	}
+	return ""
+}
+
 func (x *Notification) SetRefId(v string) {
 	x.xxx_hidden_RefId = v
 }
@@ -90,6 +250,26 @@ func (x *Notification) SetCreated(v *timestamppb.Timestamp) {
 	x.xxx_hidden_Created = v
 }
 
+func (x *Notification) SetRead(v bool) {
+	x.xxx_hidden_Read = v
+}
+
+func (x *Notification) SetKind(v NotificationKind) {
+	x.xxx_hidden_Kind = v
+}
+
+func (x *Notification) SetEventRefId(v string) {
+	x.xxx_hidden_EventRefId = v
+}
+
+func (x *Notification) SetEventItemRefId(v string) {
+	x.xxx_hidden_EventItemRefId = v
+}
+
+func (x *Notification) SetEarmarkRefId(v string) {
+	x.xxx_hidden_EarmarkRefId = v
+}
+
 func (x *Notification) HasCreated() bool {
 	if x == nil {
 		return false
@@ -104,9 +284,16 @@ func (x *Notification) ClearCreated() {
 type Notification_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	RefId   string
+	RefId string
+	// plain text rendering of the notification
 	Message string
 	Created *timestamppb.Timestamp
+	Read    bool
+	Kind    NotificationKind
+	// related entities, if any, for deep linking
+	EventRefId     string
+	EventItemRefId string
+	EarmarkRefId   string
 }
 
 func (b0 Notification_builder) Build() *Notification {
@@ -116,6 +303,11 @@ func (b0 Notification_builder) Build() *Notification {
 	x.xxx_hidden_RefId = b.RefId
 	x.xxx_hidden_Message = b.Message
 	x.xxx_hidden_Created = b.Created
+	x.xxx_hidden_Read = b.Read
+	x.xxx_hidden_Kind = b.Kind
+	x.xxx_hidden_EventRefId = b.EventRefId
+	x.xxx_hidden_EventItemRefId = b.EventItemRefId
+	x.xxx_hidden_EarmarkRefId = b.EarmarkRefId
 	return m0
 }
 
@@ -219,16 +411,174 @@ func (b0 NotificationsDeleteAllRequest_builder) Build() *NotificationsDeleteAllR
 	return m0
 }
 
+type NotificationMarkReadRequest struct {
+	state            protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NotificationMarkReadRequest) Reset() {
+	*x = NotificationMarkReadRequest{}
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationMarkReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationMarkReadRequest) ProtoMessage() {}
+
+func (x *NotificationMarkReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *NotificationMarkReadRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *NotificationMarkReadRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+type NotificationMarkReadRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+}
+
+func (b0 NotificationMarkReadRequest_builder) Build() *NotificationMarkReadRequest {
+	m0 := &NotificationMarkReadRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	return m0
+}
+
+type NotificationMarkUnreadRequest struct {
+	state            protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NotificationMarkUnreadRequest) Reset() {
+	*x = NotificationMarkUnreadRequest{}
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationMarkUnreadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationMarkUnreadRequest) ProtoMessage() {}
+
+func (x *NotificationMarkUnreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *NotificationMarkUnreadRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *NotificationMarkUnreadRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+type NotificationMarkUnreadRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+}
+
+func (b0 NotificationMarkUnreadRequest_builder) Build() *NotificationMarkUnreadRequest {
+	m0 := &NotificationMarkUnreadRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	return m0
+}
+
+type NotificationsMarkAllReadRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationsMarkAllReadRequest) Reset() {
+	*x = NotificationsMarkAllReadRequest{}
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationsMarkAllReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationsMarkAllReadRequest) ProtoMessage() {}
+
+func (x *NotificationsMarkAllReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type NotificationsMarkAllReadRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 NotificationsMarkAllReadRequest_builder) Build() *NotificationsMarkAllReadRequest {
+	m0 := &NotificationsMarkAllReadRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
 type NotificationsListRequest struct {
 	state                 protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_Pagination *PaginationRequest     `protobuf:"bytes,1,opt,name=pagination"`
+	xxx_hidden_UnreadOnly bool                   `protobuf:"varint,2,opt,name=unread_only,json=unreadOnly"`
 	unknownFields         protoimpl.UnknownFields
 	sizeCache             protoimpl.SizeCache
 }
 
 func (x *NotificationsListRequest) Reset() {
 	*x = NotificationsListRequest{}
-	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[3]
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -240,7 +590,7 @@ func (x *NotificationsListRequest) String() string {
 func (*NotificationsListRequest) ProtoMessage() {}
 
 func (x *NotificationsListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[3]
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -258,10 +608,21 @@ func (x *NotificationsListRequest) GetPagination() *PaginationRequest {
 	return nil
 }
 
+func (x *NotificationsListRequest) GetUnreadOnly() bool {
+	if x != nil {
+		return x.xxx_hidden_UnreadOnly
+	}
+	return false
+}
+
 func (x *NotificationsListRequest) SetPagination(v *PaginationRequest) {
 	x.xxx_hidden_Pagination = v
 }
 
+func (x *NotificationsListRequest) SetUnreadOnly(v bool) {
+	x.xxx_hidden_UnreadOnly = v
+}
+
 func (x *NotificationsListRequest) HasPagination() bool {
 	if x == nil {
 		return false
@@ -277,6 +638,7 @@ type NotificationsListRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 	Pagination *PaginationRequest
+	UnreadOnly bool
 }
 
 func (b0 NotificationsListRequest_builder) Build() *NotificationsListRequest {
@@ -284,6 +646,7 @@ func (b0 NotificationsListRequest_builder) Build() *NotificationsListRequest {
 	b, x := &b0, m0
 	_, _ = b, x
 	x.xxx_hidden_Pagination = b.Pagination
+	x.xxx_hidden_UnreadOnly = b.UnreadOnly
 	return m0
 }
 
@@ -297,7 +660,7 @@ type NotificationsListResponse struct {
 
 func (x *NotificationsListResponse) Reset() {
 	*x = NotificationsListResponse{}
-	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[4]
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -309,7 +672,7 @@ func (x *NotificationsListResponse) String() string {
 func (*NotificationsListResponse) ProtoMessage() {}
 
 func (x *NotificationsListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[4]
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,50 +734,241 @@ func (b0 NotificationsListResponse_builder) Build() *NotificationsListResponse {
 	return m0
 }
 
+type WatchNotificationsRequest struct {
+	state             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Cursor string                 `protobuf:"bytes,1,opt,name=cursor"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchNotificationsRequest) Reset() {
+	*x = WatchNotificationsRequest{}
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNotificationsRequest) ProtoMessage() {}
+
+func (x *WatchNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WatchNotificationsRequest) GetCursor() string {
+	if x != nil {
+		return x.xxx_hidden_Cursor
+	}
+	return ""
+}
+
+func (x *WatchNotificationsRequest) SetCursor(v string) {
+	x.xxx_hidden_Cursor = v
+}
+
+type WatchNotificationsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// resume after this cursor, as returned by a previous response
+	Cursor string
+}
+
+func (b0 WatchNotificationsRequest_builder) Build() *WatchNotificationsRequest {
+	m0 := &WatchNotificationsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Cursor = b.Cursor
+	return m0
+}
+
+type WatchNotificationsResponse struct {
+	state             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Kind   NotificationChangeKind `protobuf:"varint,1,opt,name=kind,enum=icbt.rpc.v1.NotificationChangeKind"`
+	xxx_hidden_RefId  string                 `protobuf:"bytes,2,opt,name=ref_id,json=refId"`
+	xxx_hidden_Cursor string                 `protobuf:"bytes,3,opt,name=cursor"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchNotificationsResponse) Reset() {
+	*x = WatchNotificationsResponse{}
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNotificationsResponse) ProtoMessage() {}
+
+func (x *WatchNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_notification_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WatchNotificationsResponse) GetKind() NotificationChangeKind {
+	if x != nil {
+		return x.xxx_hidden_Kind
+	}
+	return NotificationChangeKind_NOTIFICATION_CHANGE_KIND_UNSPECIFIED
+}
+
+func (x *WatchNotificationsResponse) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *WatchNotificationsResponse) GetCursor() string {
+	if x != nil {
+		return x.xxx_hidden_Cursor
+	}
+	return ""
+}
+
+func (x *WatchNotificationsResponse) SetKind(v NotificationChangeKind) {
+	x.xxx_hidden_Kind = v
+}
+
+func (x *WatchNotificationsResponse) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *WatchNotificationsResponse) SetCursor(v string) {
+	x.xxx_hidden_Cursor = v
+}
+
+type WatchNotificationsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Kind NotificationChangeKind
+	// ref-id of the changed notification, if a single one changed
+	RefId  string
+	Cursor string
+}
+
+func (b0 WatchNotificationsResponse_builder) Build() *WatchNotificationsResponse {
+	m0 := &WatchNotificationsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Kind = b.Kind
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Cursor = b.Cursor
+	return m0
+}
+
 var File_icbt_rpc_v1_notification_proto protoreflect.FileDescriptor
 
 const file_icbt_rpc_v1_notification_proto_rawDesc = "" +
 	"\n" +
-	"\x1eicbt/rpc/v1/notification.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1dicbt/rpc/v1/constraints.proto\x1a\x1cicbt/rpc/v1/pagination.proto\"u\n" +
+	"\x1eicbt/rpc/v1/notification.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1dicbt/rpc/v1/constraints.proto\x1a\x1cicbt/rpc/v1/pagination.proto\"\xaf\x02\n" +
 	"\fNotification\x12\x15\n" +
 	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
-	"\acreated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"?\n" +
+	"\acreated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x12\x12\n" +
+	"\x04read\x18\x04 \x01(\bR\x04read\x121\n" +
+	"\x04kind\x18\x05 \x01(\x0e2\x1d.icbt.rpc.v1.NotificationKindR\x04kind\x12 \n" +
+	"\fevent_ref_id\x18\x06 \x01(\tR\n" +
+	"eventRefId\x12)\n" +
+	"\x11event_item_ref_id\x18\a \x01(\tR\x0eeventItemRefId\x12$\n" +
+	"\x0eearmark_ref_id\x18\b \x01(\tR\fearmarkRefId\"?\n" +
 	"\x19NotificationDeleteRequest\x12\"\n" +
 	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\x1f\n" +
-	"\x1dNotificationsDeleteAllRequest\"a\n" +
+	"\x1dNotificationsDeleteAllRequest\"A\n" +
+	"\x1bNotificationMarkReadRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"C\n" +
+	"\x1dNotificationMarkUnreadRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"!\n" +
+	"\x1fNotificationsMarkAllReadRequest\"\x82\x01\n" +
 	"\x18NotificationsListRequest\x12E\n" +
 	"\n" +
 	"pagination\x18\x01 \x01(\v2\x1e.icbt.rpc.v1.PaginationRequestB\x05\xaa\x01\x02\b\x01R\n" +
-	"pagination\"\xa2\x01\n" +
+	"pagination\x12\x1f\n" +
+	"\vunread_only\x18\x02 \x01(\bR\n" +
+	"unreadOnly\"\xa2\x01\n" +
 	"\x19NotificationsListResponse\x12?\n" +
 	"\rnotifications\x18\x01 \x03(\v2\x19.icbt.rpc.v1.NotificationR\rnotifications\x12D\n" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x1d.icbt.rpc.v1.PaginationResultB\x05\xaa\x01\x02\b\x01R\n" +
-	"paginationB\xb6\x01\n" +
+	"pagination\"3\n" +
+	"\x19WatchNotificationsRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\"\x84\x01\n" +
+	"\x1aWatchNotificationsResponse\x127\n" +
+	"\x04kind\x18\x01 \x01(\x0e2#.icbt.rpc.v1.NotificationChangeKindR\x04kind\x12\x15\n" +
+	"\x06ref_id\x18\x02 \x01(\tR\x05refId\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor*\xc0\x02\n" +
+	"\x10NotificationKind\x12!\n" +
+	"\x1dNOTIFICATION_KIND_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19NOTIFICATION_KIND_MESSAGE\x10\x01\x12(\n" +
+	"$NOTIFICATION_KIND_ACCOUNT_UNVERIFIED\x10\x02\x12(\n" +
+	"$NOTIFICATION_KIND_REMINDERS_DISABLED\x10\x03\x12%\n" +
+	"!NOTIFICATION_KIND_EARMARK_CLAIMED\x10\x04\x12#\n" +
+	"\x1fNOTIFICATION_KIND_EVENT_CHANGED\x10\x05\x12!\n" +
+	"\x1dNOTIFICATION_KIND_NEW_SIGN_IN\x10\x06\x12'\n" +
+	"#NOTIFICATION_KIND_EVENT_TRANSFERRED\x10\a*\xcc\x02\n" +
+	"\x16NotificationChangeKind\x12(\n" +
+	"$NOTIFICATION_CHANGE_KIND_UNSPECIFIED\x10\x00\x12$\n" +
+	" NOTIFICATION_CHANGE_KIND_CREATED\x10\x01\x12$\n" +
+	" NOTIFICATION_CHANGE_KIND_DELETED\x10\x02\x12!\n" +
+	"\x1dNOTIFICATION_CHANGE_KIND_READ\x10\x03\x12#\n" +
+	"\x1fNOTIFICATION_CHANGE_KIND_UNREAD\x10\x04\x12%\n" +
+	"!NOTIFICATION_CHANGE_KIND_ALL_READ\x10\x05\x12(\n" +
+	"$NOTIFICATION_CHANGE_KIND_ALL_DELETED\x10\x06\x12#\n" +
+	"\x1fNOTIFICATION_CHANGE_KIND_RESYNC\x10\aB\xb6\x01\n" +
 	"\x0fcom.icbt.rpc.v1B\x11NotificationProtoP\x01Z8github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1;rpcv1\xa2\x02\x03IRX\xaa\x02\vIcbt.Rpc.V1\xca\x02\vIcbt\\Rpc\\V1\xe2\x02\x17Icbt\\Rpc\\V1\\GPBMetadata\xea\x02\rIcbt::Rpc::V1\x92\x03\a\xd2>\x02\x10\x03\b\x02b\beditionsp\xe8\a"
 
-var file_icbt_rpc_v1_notification_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_icbt_rpc_v1_notification_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_icbt_rpc_v1_notification_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_icbt_rpc_v1_notification_proto_goTypes = []any{
-	(*Notification)(nil),                  // 0: icbt.rpc.v1.Notification
-	(*NotificationDeleteRequest)(nil),     // 1: icbt.rpc.v1.NotificationDeleteRequest
-	(*NotificationsDeleteAllRequest)(nil), // 2: icbt.rpc.v1.NotificationsDeleteAllRequest
-	(*NotificationsListRequest)(nil),      // 3: icbt.rpc.v1.NotificationsListRequest
-	(*NotificationsListResponse)(nil),     // 4: icbt.rpc.v1.NotificationsListResponse
-	(*timestamppb.Timestamp)(nil),         // 5: google.protobuf.Timestamp
-	(*PaginationRequest)(nil),             // 6: icbt.rpc.v1.PaginationRequest
-	(*PaginationResult)(nil),              // 7: icbt.rpc.v1.PaginationResult
+	(NotificationKind)(0),                   // 0: icbt.rpc.v1.NotificationKind
+	(NotificationChangeKind)(0),             // 1: icbt.rpc.v1.NotificationChangeKind
+	(*Notification)(nil),                    // 2: icbt.rpc.v1.Notification
+	(*NotificationDeleteRequest)(nil),       // 3: icbt.rpc.v1.NotificationDeleteRequest
+	(*NotificationsDeleteAllRequest)(nil),   // 4: icbt.rpc.v1.NotificationsDeleteAllRequest
+	(*NotificationMarkReadRequest)(nil),     // 5: icbt.rpc.v1.NotificationMarkReadRequest
+	(*NotificationMarkUnreadRequest)(nil),   // 6: icbt.rpc.v1.NotificationMarkUnreadRequest
+	(*NotificationsMarkAllReadRequest)(nil), // 7: icbt.rpc.v1.NotificationsMarkAllReadRequest
+	(*NotificationsListRequest)(nil),        // 8: icbt.rpc.v1.NotificationsListRequest
+	(*NotificationsListResponse)(nil),       // 9: icbt.rpc.v1.NotificationsListResponse
+	(*WatchNotificationsRequest)(nil),       // 10: icbt.rpc.v1.WatchNotificationsRequest
+	(*WatchNotificationsResponse)(nil),      // 11: icbt.rpc.v1.WatchNotificationsResponse
+	(*timestamppb.Timestamp)(nil),           // 12: google.protobuf.Timestamp
+	(*PaginationRequest)(nil),               // 13: icbt.rpc.v1.PaginationRequest
+	(*PaginationResult)(nil),                // 14: icbt.rpc.v1.PaginationResult
 }
 var file_icbt_rpc_v1_notification_proto_depIdxs = []int32{
-	5, // 0: icbt.rpc.v1.Notification.created:type_name -> google.protobuf.Timestamp
-	6, // 1: icbt.rpc.v1.NotificationsListRequest.pagination:type_name -> icbt.rpc.v1.PaginationRequest
-	0, // 2: icbt.rpc.v1.NotificationsListResponse.notifications:type_name -> icbt.rpc.v1.Notification
-	7, // 3: icbt.rpc.v1.NotificationsListResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	12, // 0: icbt.rpc.v1.Notification.created:type_name -> google.protobuf.Timestamp
+	0,  // 1: icbt.rpc.v1.Notification.kind:type_name -> icbt.rpc.v1.NotificationKind
+	13, // 2: icbt.rpc.v1.NotificationsListRequest.pagination:type_name -> icbt.rpc.v1.PaginationRequest
+	2,  // 3: icbt.rpc.v1.NotificationsListResponse.notifications:type_name -> icbt.rpc.v1.Notification
+	14, // 4: icbt.rpc.v1.NotificationsListResponse.pagination:type_name -> icbt.rpc.v1.PaginationResult
+	1,  // 5: icbt.rpc.v1.WatchNotificationsResponse.kind:type_name -> icbt.rpc.v1.NotificationChangeKind
+	6,  // [6:6] is the sub-list for method output_type
+	6,  // [6:6] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_icbt_rpc_v1_notification_proto_init() }
@@ -429,13 +983,14 @@ func file_icbt_rpc_v1_notification_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_icbt_rpc_v1_notification_proto_rawDesc), len(file_icbt_rpc_v1_notification_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      2,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_icbt_rpc_v1_notification_proto_goTypes,
 		DependencyIndexes: file_icbt_rpc_v1_notification_proto_depIdxs,
+		EnumInfos:         file_icbt_rpc_v1_notification_proto_enumTypes,
 		MessageInfos:      file_icbt_rpc_v1_notification_proto_msgTypes,
 	}.Build()
 	File_icbt_rpc_v1_notification_proto = out.File
@@ -26,6 +26,7 @@ type PaginationRequest struct {
 	state             protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_Limit  uint32                 `protobuf:"varint,1,opt,name=limit"`
 	xxx_hidden_Offset uint32                 `protobuf:"varint,2,opt,name=offset"`
+	xxx_hidden_Cursor string                 `protobuf:"bytes,3,opt,name=cursor"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -69,6 +70,13 @@ func (x *PaginationRequest) GetOffset() uint32 {
 	return 0
 }
 
+func (x *PaginationRequest) GetCursor() string {
+	if x != nil {
+		return x.xxx_hidden_Cursor
+	}
+	return ""
+}
+
 func (x *PaginationRequest) SetLimit(v uint32) {
 	x.xxx_hidden_Limit = v
 }
@@ -77,11 +85,18 @@ func (x *PaginationRequest) SetOffset(v uint32) {
 	x.xxx_hidden_Offset = v
 }
 
+func (x *PaginationRequest) SetCursor(v string) {
+	x.xxx_hidden_Cursor = v
+}
+
 type PaginationRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 	Limit  uint32
 	Offset uint32
+	// opaque cursor, as returned in PaginationResult.next_cursor.
+	// when set, results start after the cursor and offset is ignored.
+	Cursor string
 }
 
 func (b0 PaginationRequest_builder) Build() *PaginationRequest {
@@ -90,16 +105,18 @@ func (b0 PaginationRequest_builder) Build() *PaginationRequest {
 	_, _ = b, x
 	x.xxx_hidden_Limit = b.Limit
 	x.xxx_hidden_Offset = b.Offset
+	x.xxx_hidden_Cursor = b.Cursor
 	return m0
 }
 
 type PaginationResult struct {
-	state             protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Limit  uint32                 `protobuf:"varint,1,opt,name=limit"`
-	xxx_hidden_Offset uint32                 `protobuf:"varint,2,opt,name=offset"`
-	xxx_hidden_Count  uint32                 `protobuf:"varint,3,opt,name=count"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Limit      uint32                 `protobuf:"varint,1,opt,name=limit"`
+	xxx_hidden_Offset     uint32                 `protobuf:"varint,2,opt,name=offset"`
+	xxx_hidden_Count      uint32                 `protobuf:"varint,3,opt,name=count"`
+	xxx_hidden_NextCursor string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *PaginationResult) Reset() {
@@ -148,6 +165,13 @@ func (x *PaginationResult) GetCount() uint32 {
 	return 0
 }
 
+func (x *PaginationResult) GetNextCursor() string {
+	if x != nil {
+		return x.xxx_hidden_NextCursor
+	}
+	return ""
+}
+
 func (x *PaginationResult) SetLimit(v uint32) {
 	x.xxx_hidden_Limit = v
 }
@@ -160,12 +184,18 @@ func (x *PaginationResult) SetCount(v uint32) {
 	x.xxx_hidden_Count = v
 }
 
+func (x *PaginationResult) SetNextCursor(v string) {
+	x.xxx_hidden_NextCursor = v
+}
+
 type PaginationResult_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 	Limit  uint32
 	Offset uint32
 	Count  uint32
+	// opaque cursor for the next page. empty if there are no more results.
+	NextCursor string
 }
 
 func (b0 PaginationResult_builder) Build() *PaginationResult {
@@ -175,6 +205,7 @@ func (b0 PaginationResult_builder) Build() *PaginationResult {
 	x.xxx_hidden_Limit = b.Limit
 	x.xxx_hidden_Offset = b.Offset
 	x.xxx_hidden_Count = b.Count
+	x.xxx_hidden_NextCursor = b.NextCursor
 	return m0
 }
 
@@ -182,14 +213,17 @@ var File_icbt_rpc_v1_pagination_proto protoreflect.FileDescriptor
 
 const file_icbt_rpc_v1_pagination_proto_rawDesc = "" +
 	"\n" +
-	"\x1cicbt/rpc/v1/pagination.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\"J\n" +
+	"\x1cicbt/rpc/v1/pagination.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\"b\n" +
 	"\x11PaginationRequest\x12\x1d\n" +
 	"\x05limit\x18\x01 \x01(\rB\a\xbaH\x04*\x02 \x00R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\rR\x06offset\"V\n" +
+	"\x06offset\x18\x02 \x01(\rR\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"w\n" +
 	"\x10PaginationResult\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\rR\x05limit\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\rR\x06offset\x12\x14\n" +
-	"\x05count\x18\x03 \x01(\rR\x05countB\xb4\x01\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\x12\x1f\n" +
+	"\vnext_cursor\x18\x04 \x01(\tR\n" +
+	"nextCursorB\xb4\x01\n" +
 	"\x0fcom.icbt.rpc.v1B\x0fPaginationProtoP\x01Z8github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1;rpcv1\xa2\x02\x03IRX\xaa\x02\vIcbt.Rpc.V1\xca\x02\vIcbt\\Rpc\\V1\xe2\x02\x17Icbt\\Rpc\\V1\\GPBMetadata\xea\x02\rIcbt::Rpc::V1\x92\x03\a\xd2>\x02\x10\x03\b\x02b\beditionsp\xe8\a"
 
 var file_icbt_rpc_v1_pagination_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
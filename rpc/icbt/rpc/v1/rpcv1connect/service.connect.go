@@ -67,6 +67,9 @@ const (
 	// IcbtRpcServiceEventListEarmarksProcedure is the fully-qualified name of the IcbtRpcService's
 	// EventListEarmarks RPC.
 	IcbtRpcServiceEventListEarmarksProcedure = "/icbt.rpc.v1.IcbtRpcService/EventListEarmarks"
+	// IcbtRpcServiceWatchEventProcedure is the fully-qualified name of the IcbtRpcService's WatchEvent
+	// RPC.
+	IcbtRpcServiceWatchEventProcedure = "/icbt.rpc.v1.IcbtRpcService/WatchEvent"
 	// IcbtRpcServiceEventAddItemProcedure is the fully-qualified name of the IcbtRpcService's
 	// EventAddItem RPC.
 	IcbtRpcServiceEventAddItemProcedure = "/icbt.rpc.v1.IcbtRpcService/EventAddItem"
@@ -76,6 +79,9 @@ const (
 	// IcbtRpcServiceEventRemoveItemProcedure is the fully-qualified name of the IcbtRpcService's
 	// EventRemoveItem RPC.
 	IcbtRpcServiceEventRemoveItemProcedure = "/icbt.rpc.v1.IcbtRpcService/EventRemoveItem"
+	// IcbtRpcServiceEventUpdateItemSortingProcedure is the fully-qualified name of the IcbtRpcService's
+	// EventUpdateItemSorting RPC.
+	IcbtRpcServiceEventUpdateItemSortingProcedure = "/icbt.rpc.v1.IcbtRpcService/EventUpdateItemSorting"
 	// IcbtRpcServiceFavoriteAddProcedure is the fully-qualified name of the IcbtRpcService's
 	// FavoriteAdd RPC.
 	IcbtRpcServiceFavoriteAddProcedure = "/icbt.rpc.v1.IcbtRpcService/FavoriteAdd"
@@ -94,6 +100,54 @@ const (
 	// IcbtRpcServiceNotificationsListProcedure is the fully-qualified name of the IcbtRpcService's
 	// NotificationsList RPC.
 	IcbtRpcServiceNotificationsListProcedure = "/icbt.rpc.v1.IcbtRpcService/NotificationsList"
+	// IcbtRpcServiceNotificationMarkReadProcedure is the fully-qualified name of the IcbtRpcService's
+	// NotificationMarkRead RPC.
+	IcbtRpcServiceNotificationMarkReadProcedure = "/icbt.rpc.v1.IcbtRpcService/NotificationMarkRead"
+	// IcbtRpcServiceNotificationMarkUnreadProcedure is the fully-qualified name of the IcbtRpcService's
+	// NotificationMarkUnread RPC.
+	IcbtRpcServiceNotificationMarkUnreadProcedure = "/icbt.rpc.v1.IcbtRpcService/NotificationMarkUnread"
+	// IcbtRpcServiceNotificationsMarkAllReadProcedure is the fully-qualified name of the
+	// IcbtRpcService's NotificationsMarkAllRead RPC.
+	IcbtRpcServiceNotificationsMarkAllReadProcedure = "/icbt.rpc.v1.IcbtRpcService/NotificationsMarkAllRead"
+	// IcbtRpcServiceWatchNotificationsProcedure is the fully-qualified name of the IcbtRpcService's
+	// WatchNotifications RPC.
+	IcbtRpcServiceWatchNotificationsProcedure = "/icbt.rpc.v1.IcbtRpcService/WatchNotifications"
+	// IcbtRpcServiceUserGetMeProcedure is the fully-qualified name of the IcbtRpcService's UserGetMe
+	// RPC.
+	IcbtRpcServiceUserGetMeProcedure = "/icbt.rpc.v1.IcbtRpcService/UserGetMe"
+	// IcbtRpcServiceUserUpdateProcedure is the fully-qualified name of the IcbtRpcService's UserUpdate
+	// RPC.
+	IcbtRpcServiceUserUpdateProcedure = "/icbt.rpc.v1.IcbtRpcService/UserUpdate"
+	// IcbtRpcServiceUserSettingsGetProcedure is the fully-qualified name of the IcbtRpcService's
+	// UserSettingsGet RPC.
+	IcbtRpcServiceUserSettingsGetProcedure = "/icbt.rpc.v1.IcbtRpcService/UserSettingsGet"
+	// IcbtRpcServiceUserSettingsUpdateProcedure is the fully-qualified name of the IcbtRpcService's
+	// UserSettingsUpdate RPC.
+	IcbtRpcServiceUserSettingsUpdateProcedure = "/icbt.rpc.v1.IcbtRpcService/UserSettingsUpdate"
+	// IcbtRpcServiceUserCredentialsListProcedure is the fully-qualified name of the IcbtRpcService's
+	// UserCredentialsList RPC.
+	IcbtRpcServiceUserCredentialsListProcedure = "/icbt.rpc.v1.IcbtRpcService/UserCredentialsList"
+	// IcbtRpcServiceUserCredentialDeleteProcedure is the fully-qualified name of the IcbtRpcService's
+	// UserCredentialDelete RPC.
+	IcbtRpcServiceUserCredentialDeleteProcedure = "/icbt.rpc.v1.IcbtRpcService/UserCredentialDelete"
+	// IcbtRpcServiceAccountExportProcedure is the fully-qualified name of the IcbtRpcService's
+	// AccountExport RPC.
+	IcbtRpcServiceAccountExportProcedure = "/icbt.rpc.v1.IcbtRpcService/AccountExport"
+	// IcbtRpcServiceWebhookCreateProcedure is the fully-qualified name of the IcbtRpcService's
+	// WebhookCreate RPC.
+	IcbtRpcServiceWebhookCreateProcedure = "/icbt.rpc.v1.IcbtRpcService/WebhookCreate"
+	// IcbtRpcServiceWebhookUpdateProcedure is the fully-qualified name of the IcbtRpcService's
+	// WebhookUpdate RPC.
+	IcbtRpcServiceWebhookUpdateProcedure = "/icbt.rpc.v1.IcbtRpcService/WebhookUpdate"
+	// IcbtRpcServiceWebhookDeleteProcedure is the fully-qualified name of the IcbtRpcService's
+	// WebhookDelete RPC.
+	IcbtRpcServiceWebhookDeleteProcedure = "/icbt.rpc.v1.IcbtRpcService/WebhookDelete"
+	// IcbtRpcServiceWebhooksListProcedure is the fully-qualified name of the IcbtRpcService's
+	// WebhooksList RPC.
+	IcbtRpcServiceWebhooksListProcedure = "/icbt.rpc.v1.IcbtRpcService/WebhooksList"
+	// IcbtRpcServiceWebhookListAttemptsProcedure is the fully-qualified name of the IcbtRpcService's
+	// WebhookListAttempts RPC.
+	IcbtRpcServiceWebhookListAttemptsProcedure = "/icbt.rpc.v1.IcbtRpcService/WebhookListAttempts"
 )
 
 // IcbtRpcServiceClient is a client for the icbt.rpc.v1.IcbtRpcService service.
@@ -111,10 +165,12 @@ type IcbtRpcServiceClient interface {
 	EventGetDetails(context.Context, *connect.Request[v1.EventGetDetailsRequest]) (*connect.Response[v1.EventGetDetailsResponse], error)
 	EventListItems(context.Context, *connect.Request[v1.EventListItemsRequest]) (*connect.Response[v1.EventListItemsResponse], error)
 	EventListEarmarks(context.Context, *connect.Request[v1.EventListEarmarksRequest]) (*connect.Response[v1.EventListEarmarksResponse], error)
+	WatchEvent(context.Context, *connect.Request[v1.WatchEventRequest]) (*connect.ServerStreamForClient[v1.WatchEventResponse], error)
 	// event-items
 	EventAddItem(context.Context, *connect.Request[v1.EventAddItemRequest]) (*connect.Response[v1.EventAddItemResponse], error)
 	EventUpdateItem(context.Context, *connect.Request[v1.EventUpdateItemRequest]) (*connect.Response[v1.EventUpdateItemResponse], error)
 	EventRemoveItem(context.Context, *connect.Request[v1.EventRemoveItemRequest]) (*connect.Response[emptypb.Empty], error)
+	EventUpdateItemSorting(context.Context, *connect.Request[v1.EventUpdateItemSortingRequest]) (*connect.Response[v1.EventUpdateItemSortingResponse], error)
 	// favorites
 	FavoriteAdd(context.Context, *connect.Request[v1.FavoriteAddRequest]) (*connect.Response[v1.FavoriteAddResponse], error)
 	FavoriteRemove(context.Context, *connect.Request[v1.FavoriteRemoveRequest]) (*connect.Response[emptypb.Empty], error)
@@ -123,6 +179,24 @@ type IcbtRpcServiceClient interface {
 	NotificationDelete(context.Context, *connect.Request[v1.NotificationDeleteRequest]) (*connect.Response[emptypb.Empty], error)
 	NotificationsDeleteAll(context.Context, *connect.Request[v1.NotificationsDeleteAllRequest]) (*connect.Response[emptypb.Empty], error)
 	NotificationsList(context.Context, *connect.Request[v1.NotificationsListRequest]) (*connect.Response[v1.NotificationsListResponse], error)
+	NotificationMarkRead(context.Context, *connect.Request[v1.NotificationMarkReadRequest]) (*connect.Response[emptypb.Empty], error)
+	NotificationMarkUnread(context.Context, *connect.Request[v1.NotificationMarkUnreadRequest]) (*connect.Response[emptypb.Empty], error)
+	NotificationsMarkAllRead(context.Context, *connect.Request[v1.NotificationsMarkAllReadRequest]) (*connect.Response[emptypb.Empty], error)
+	WatchNotifications(context.Context, *connect.Request[v1.WatchNotificationsRequest]) (*connect.ServerStreamForClient[v1.WatchNotificationsResponse], error)
+	// user
+	UserGetMe(context.Context, *connect.Request[v1.UserGetMeRequest]) (*connect.Response[v1.UserGetMeResponse], error)
+	UserUpdate(context.Context, *connect.Request[v1.UserUpdateRequest]) (*connect.Response[v1.UserUpdateResponse], error)
+	UserSettingsGet(context.Context, *connect.Request[v1.UserSettingsGetRequest]) (*connect.Response[v1.UserSettingsGetResponse], error)
+	UserSettingsUpdate(context.Context, *connect.Request[v1.UserSettingsUpdateRequest]) (*connect.Response[v1.UserSettingsUpdateResponse], error)
+	UserCredentialsList(context.Context, *connect.Request[v1.UserCredentialsListRequest]) (*connect.Response[v1.UserCredentialsListResponse], error)
+	UserCredentialDelete(context.Context, *connect.Request[v1.UserCredentialDeleteRequest]) (*connect.Response[emptypb.Empty], error)
+	AccountExport(context.Context, *connect.Request[v1.AccountExportRequest]) (*connect.Response[v1.AccountExportResponse], error)
+	// webhooks
+	WebhookCreate(context.Context, *connect.Request[v1.WebhookCreateRequest]) (*connect.Response[v1.WebhookCreateResponse], error)
+	WebhookUpdate(context.Context, *connect.Request[v1.WebhookUpdateRequest]) (*connect.Response[emptypb.Empty], error)
+	WebhookDelete(context.Context, *connect.Request[v1.WebhookDeleteRequest]) (*connect.Response[emptypb.Empty], error)
+	WebhooksList(context.Context, *connect.Request[v1.WebhooksListRequest]) (*connect.Response[v1.WebhooksListResponse], error)
+	WebhookListAttempts(context.Context, *connect.Request[v1.WebhookListAttemptsRequest]) (*connect.Response[v1.WebhookListAttemptsResponse], error)
 }
 
 // NewIcbtRpcServiceClient constructs a client for the icbt.rpc.v1.IcbtRpcService service. By
@@ -202,6 +276,12 @@ func NewIcbtRpcServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(icbtRpcServiceMethods.ByName("EventListEarmarks")),
 			connect.WithClientOptions(opts...),
 		),
+		watchEvent: connect.NewClient[v1.WatchEventRequest, v1.WatchEventResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceWatchEventProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WatchEvent")),
+			connect.WithClientOptions(opts...),
+		),
 		eventAddItem: connect.NewClient[v1.EventAddItemRequest, v1.EventAddItemResponse](
 			httpClient,
 			baseURL+IcbtRpcServiceEventAddItemProcedure,
@@ -220,6 +300,12 @@ func NewIcbtRpcServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(icbtRpcServiceMethods.ByName("EventRemoveItem")),
 			connect.WithClientOptions(opts...),
 		),
+		eventUpdateItemSorting: connect.NewClient[v1.EventUpdateItemSortingRequest, v1.EventUpdateItemSortingResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceEventUpdateItemSortingProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("EventUpdateItemSorting")),
+			connect.WithClientOptions(opts...),
+		),
 		favoriteAdd: connect.NewClient[v1.FavoriteAddRequest, v1.FavoriteAddResponse](
 			httpClient,
 			baseURL+IcbtRpcServiceFavoriteAddProcedure,
@@ -256,31 +342,145 @@ func NewIcbtRpcServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationsList")),
 			connect.WithClientOptions(opts...),
 		),
+		notificationMarkRead: connect.NewClient[v1.NotificationMarkReadRequest, emptypb.Empty](
+			httpClient,
+			baseURL+IcbtRpcServiceNotificationMarkReadProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationMarkRead")),
+			connect.WithClientOptions(opts...),
+		),
+		notificationMarkUnread: connect.NewClient[v1.NotificationMarkUnreadRequest, emptypb.Empty](
+			httpClient,
+			baseURL+IcbtRpcServiceNotificationMarkUnreadProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationMarkUnread")),
+			connect.WithClientOptions(opts...),
+		),
+		notificationsMarkAllRead: connect.NewClient[v1.NotificationsMarkAllReadRequest, emptypb.Empty](
+			httpClient,
+			baseURL+IcbtRpcServiceNotificationsMarkAllReadProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationsMarkAllRead")),
+			connect.WithClientOptions(opts...),
+		),
+		watchNotifications: connect.NewClient[v1.WatchNotificationsRequest, v1.WatchNotificationsResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceWatchNotificationsProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WatchNotifications")),
+			connect.WithClientOptions(opts...),
+		),
+		userGetMe: connect.NewClient[v1.UserGetMeRequest, v1.UserGetMeResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceUserGetMeProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("UserGetMe")),
+			connect.WithClientOptions(opts...),
+		),
+		userUpdate: connect.NewClient[v1.UserUpdateRequest, v1.UserUpdateResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceUserUpdateProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("UserUpdate")),
+			connect.WithClientOptions(opts...),
+		),
+		userSettingsGet: connect.NewClient[v1.UserSettingsGetRequest, v1.UserSettingsGetResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceUserSettingsGetProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("UserSettingsGet")),
+			connect.WithClientOptions(opts...),
+		),
+		userSettingsUpdate: connect.NewClient[v1.UserSettingsUpdateRequest, v1.UserSettingsUpdateResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceUserSettingsUpdateProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("UserSettingsUpdate")),
+			connect.WithClientOptions(opts...),
+		),
+		userCredentialsList: connect.NewClient[v1.UserCredentialsListRequest, v1.UserCredentialsListResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceUserCredentialsListProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("UserCredentialsList")),
+			connect.WithClientOptions(opts...),
+		),
+		userCredentialDelete: connect.NewClient[v1.UserCredentialDeleteRequest, emptypb.Empty](
+			httpClient,
+			baseURL+IcbtRpcServiceUserCredentialDeleteProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("UserCredentialDelete")),
+			connect.WithClientOptions(opts...),
+		),
+		accountExport: connect.NewClient[v1.AccountExportRequest, v1.AccountExportResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceAccountExportProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("AccountExport")),
+			connect.WithClientOptions(opts...),
+		),
+		webhookCreate: connect.NewClient[v1.WebhookCreateRequest, v1.WebhookCreateResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceWebhookCreateProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookCreate")),
+			connect.WithClientOptions(opts...),
+		),
+		webhookUpdate: connect.NewClient[v1.WebhookUpdateRequest, emptypb.Empty](
+			httpClient,
+			baseURL+IcbtRpcServiceWebhookUpdateProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookUpdate")),
+			connect.WithClientOptions(opts...),
+		),
+		webhookDelete: connect.NewClient[v1.WebhookDeleteRequest, emptypb.Empty](
+			httpClient,
+			baseURL+IcbtRpcServiceWebhookDeleteProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookDelete")),
+			connect.WithClientOptions(opts...),
+		),
+		webhooksList: connect.NewClient[v1.WebhooksListRequest, v1.WebhooksListResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceWebhooksListProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WebhooksList")),
+			connect.WithClientOptions(opts...),
+		),
+		webhookListAttempts: connect.NewClient[v1.WebhookListAttemptsRequest, v1.WebhookListAttemptsResponse](
+			httpClient,
+			baseURL+IcbtRpcServiceWebhookListAttemptsProcedure,
+			connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookListAttempts")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // icbtRpcServiceClient implements IcbtRpcServiceClient.
 type icbtRpcServiceClient struct {
-	earmarkCreate          *connect.Client[v1.EarmarkCreateRequest, v1.EarmarkCreateResponse]
-	earmarkGetDetails      *connect.Client[v1.EarmarkGetDetailsRequest, v1.EarmarkGetDetailsResponse]
-	earmarkRemove          *connect.Client[v1.EarmarkRemoveRequest, emptypb.Empty]
-	earmarksList           *connect.Client[v1.EarmarksListRequest, v1.EarmarksListResponse]
-	eventCreate            *connect.Client[v1.EventCreateRequest, v1.EventCreateResponse]
-	eventUpdate            *connect.Client[v1.EventUpdateRequest, emptypb.Empty]
-	eventDelete            *connect.Client[v1.EventDeleteRequest, emptypb.Empty]
-	eventsList             *connect.Client[v1.EventsListRequest, v1.EventsListResponse]
-	eventGetDetails        *connect.Client[v1.EventGetDetailsRequest, v1.EventGetDetailsResponse]
-	eventListItems         *connect.Client[v1.EventListItemsRequest, v1.EventListItemsResponse]
-	eventListEarmarks      *connect.Client[v1.EventListEarmarksRequest, v1.EventListEarmarksResponse]
-	eventAddItem           *connect.Client[v1.EventAddItemRequest, v1.EventAddItemResponse]
-	eventUpdateItem        *connect.Client[v1.EventUpdateItemRequest, v1.EventUpdateItemResponse]
-	eventRemoveItem        *connect.Client[v1.EventRemoveItemRequest, emptypb.Empty]
-	favoriteAdd            *connect.Client[v1.FavoriteAddRequest, v1.FavoriteAddResponse]
-	favoriteRemove         *connect.Client[v1.FavoriteRemoveRequest, emptypb.Empty]
-	favoriteListEvents     *connect.Client[v1.FavoriteListEventsRequest, v1.FavoriteListEventsResponse]
-	notificationDelete     *connect.Client[v1.NotificationDeleteRequest, emptypb.Empty]
-	notificationsDeleteAll *connect.Client[v1.NotificationsDeleteAllRequest, emptypb.Empty]
-	notificationsList      *connect.Client[v1.NotificationsListRequest, v1.NotificationsListResponse]
+	earmarkCreate            *connect.Client[v1.EarmarkCreateRequest, v1.EarmarkCreateResponse]
+	earmarkGetDetails        *connect.Client[v1.EarmarkGetDetailsRequest, v1.EarmarkGetDetailsResponse]
+	earmarkRemove            *connect.Client[v1.EarmarkRemoveRequest, emptypb.Empty]
+	earmarksList             *connect.Client[v1.EarmarksListRequest, v1.EarmarksListResponse]
+	eventCreate              *connect.Client[v1.EventCreateRequest, v1.EventCreateResponse]
+	eventUpdate              *connect.Client[v1.EventUpdateRequest, emptypb.Empty]
+	eventDelete              *connect.Client[v1.EventDeleteRequest, emptypb.Empty]
+	eventsList               *connect.Client[v1.EventsListRequest, v1.EventsListResponse]
+	eventGetDetails          *connect.Client[v1.EventGetDetailsRequest, v1.EventGetDetailsResponse]
+	eventListItems           *connect.Client[v1.EventListItemsRequest, v1.EventListItemsResponse]
+	eventListEarmarks        *connect.Client[v1.EventListEarmarksRequest, v1.EventListEarmarksResponse]
+	watchEvent               *connect.Client[v1.WatchEventRequest, v1.WatchEventResponse]
+	eventAddItem             *connect.Client[v1.EventAddItemRequest, v1.EventAddItemResponse]
+	eventUpdateItem          *connect.Client[v1.EventUpdateItemRequest, v1.EventUpdateItemResponse]
+	eventRemoveItem          *connect.Client[v1.EventRemoveItemRequest, emptypb.Empty]
+	eventUpdateItemSorting   *connect.Client[v1.EventUpdateItemSortingRequest, v1.EventUpdateItemSortingResponse]
+	favoriteAdd              *connect.Client[v1.FavoriteAddRequest, v1.FavoriteAddResponse]
+	favoriteRemove           *connect.Client[v1.FavoriteRemoveRequest, emptypb.Empty]
+	favoriteListEvents       *connect.Client[v1.FavoriteListEventsRequest, v1.FavoriteListEventsResponse]
+	notificationDelete       *connect.Client[v1.NotificationDeleteRequest, emptypb.Empty]
+	notificationsDeleteAll   *connect.Client[v1.NotificationsDeleteAllRequest, emptypb.Empty]
+	notificationsList        *connect.Client[v1.NotificationsListRequest, v1.NotificationsListResponse]
+	notificationMarkRead     *connect.Client[v1.NotificationMarkReadRequest, emptypb.Empty]
+	notificationMarkUnread   *connect.Client[v1.NotificationMarkUnreadRequest, emptypb.Empty]
+	notificationsMarkAllRead *connect.Client[v1.NotificationsMarkAllReadRequest, emptypb.Empty]
+	watchNotifications       *connect.Client[v1.WatchNotificationsRequest, v1.WatchNotificationsResponse]
+	userGetMe                *connect.Client[v1.UserGetMeRequest, v1.UserGetMeResponse]
+	userUpdate               *connect.Client[v1.UserUpdateRequest, v1.UserUpdateResponse]
+	userSettingsGet          *connect.Client[v1.UserSettingsGetRequest, v1.UserSettingsGetResponse]
+	userSettingsUpdate       *connect.Client[v1.UserSettingsUpdateRequest, v1.UserSettingsUpdateResponse]
+	userCredentialsList      *connect.Client[v1.UserCredentialsListRequest, v1.UserCredentialsListResponse]
+	userCredentialDelete     *connect.Client[v1.UserCredentialDeleteRequest, emptypb.Empty]
+	accountExport            *connect.Client[v1.AccountExportRequest, v1.AccountExportResponse]
+	webhookCreate            *connect.Client[v1.WebhookCreateRequest, v1.WebhookCreateResponse]
+	webhookUpdate            *connect.Client[v1.WebhookUpdateRequest, emptypb.Empty]
+	webhookDelete            *connect.Client[v1.WebhookDeleteRequest, emptypb.Empty]
+	webhooksList             *connect.Client[v1.WebhooksListRequest, v1.WebhooksListResponse]
+	webhookListAttempts      *connect.Client[v1.WebhookListAttemptsRequest, v1.WebhookListAttemptsResponse]
 }
 
 // EarmarkCreate calls icbt.rpc.v1.IcbtRpcService.EarmarkCreate.
@@ -338,6 +538,11 @@ func (c *icbtRpcServiceClient) EventListEarmarks(ctx context.Context, req *conne
 	return c.eventListEarmarks.CallUnary(ctx, req)
 }
 
+// WatchEvent calls icbt.rpc.v1.IcbtRpcService.WatchEvent.
+func (c *icbtRpcServiceClient) WatchEvent(ctx context.Context, req *connect.Request[v1.WatchEventRequest]) (*connect.ServerStreamForClient[v1.WatchEventResponse], error) {
+	return c.watchEvent.CallServerStream(ctx, req)
+}
+
 // EventAddItem calls icbt.rpc.v1.IcbtRpcService.EventAddItem.
 func (c *icbtRpcServiceClient) EventAddItem(ctx context.Context, req *connect.Request[v1.EventAddItemRequest]) (*connect.Response[v1.EventAddItemResponse], error) {
 	return c.eventAddItem.CallUnary(ctx, req)
@@ -353,6 +558,11 @@ func (c *icbtRpcServiceClient) EventRemoveItem(ctx context.Context, req *connect
 	return c.eventRemoveItem.CallUnary(ctx, req)
 }
 
+// EventUpdateItemSorting calls icbt.rpc.v1.IcbtRpcService.EventUpdateItemSorting.
+func (c *icbtRpcServiceClient) EventUpdateItemSorting(ctx context.Context, req *connect.Request[v1.EventUpdateItemSortingRequest]) (*connect.Response[v1.EventUpdateItemSortingResponse], error) {
+	return c.eventUpdateItemSorting.CallUnary(ctx, req)
+}
+
 // FavoriteAdd calls icbt.rpc.v1.IcbtRpcService.FavoriteAdd.
 func (c *icbtRpcServiceClient) FavoriteAdd(ctx context.Context, req *connect.Request[v1.FavoriteAddRequest]) (*connect.Response[v1.FavoriteAddResponse], error) {
 	return c.favoriteAdd.CallUnary(ctx, req)
@@ -383,6 +593,86 @@ func (c *icbtRpcServiceClient) NotificationsList(ctx context.Context, req *conne
 	return c.notificationsList.CallUnary(ctx, req)
 }
 
+// NotificationMarkRead calls icbt.rpc.v1.IcbtRpcService.NotificationMarkRead.
+func (c *icbtRpcServiceClient) NotificationMarkRead(ctx context.Context, req *connect.Request[v1.NotificationMarkReadRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.notificationMarkRead.CallUnary(ctx, req)
+}
+
+// NotificationMarkUnread calls icbt.rpc.v1.IcbtRpcService.NotificationMarkUnread.
+func (c *icbtRpcServiceClient) NotificationMarkUnread(ctx context.Context, req *connect.Request[v1.NotificationMarkUnreadRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.notificationMarkUnread.CallUnary(ctx, req)
+}
+
+// NotificationsMarkAllRead calls icbt.rpc.v1.IcbtRpcService.NotificationsMarkAllRead.
+func (c *icbtRpcServiceClient) NotificationsMarkAllRead(ctx context.Context, req *connect.Request[v1.NotificationsMarkAllReadRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.notificationsMarkAllRead.CallUnary(ctx, req)
+}
+
+// WatchNotifications calls icbt.rpc.v1.IcbtRpcService.WatchNotifications.
+func (c *icbtRpcServiceClient) WatchNotifications(ctx context.Context, req *connect.Request[v1.WatchNotificationsRequest]) (*connect.ServerStreamForClient[v1.WatchNotificationsResponse], error) {
+	return c.watchNotifications.CallServerStream(ctx, req)
+}
+
+// UserGetMe calls icbt.rpc.v1.IcbtRpcService.UserGetMe.
+func (c *icbtRpcServiceClient) UserGetMe(ctx context.Context, req *connect.Request[v1.UserGetMeRequest]) (*connect.Response[v1.UserGetMeResponse], error) {
+	return c.userGetMe.CallUnary(ctx, req)
+}
+
+// UserUpdate calls icbt.rpc.v1.IcbtRpcService.UserUpdate.
+func (c *icbtRpcServiceClient) UserUpdate(ctx context.Context, req *connect.Request[v1.UserUpdateRequest]) (*connect.Response[v1.UserUpdateResponse], error) {
+	return c.userUpdate.CallUnary(ctx, req)
+}
+
+// UserSettingsGet calls icbt.rpc.v1.IcbtRpcService.UserSettingsGet.
+func (c *icbtRpcServiceClient) UserSettingsGet(ctx context.Context, req *connect.Request[v1.UserSettingsGetRequest]) (*connect.Response[v1.UserSettingsGetResponse], error) {
+	return c.userSettingsGet.CallUnary(ctx, req)
+}
+
+// UserSettingsUpdate calls icbt.rpc.v1.IcbtRpcService.UserSettingsUpdate.
+func (c *icbtRpcServiceClient) UserSettingsUpdate(ctx context.Context, req *connect.Request[v1.UserSettingsUpdateRequest]) (*connect.Response[v1.UserSettingsUpdateResponse], error) {
+	return c.userSettingsUpdate.CallUnary(ctx, req)
+}
+
+// UserCredentialsList calls icbt.rpc.v1.IcbtRpcService.UserCredentialsList.
+func (c *icbtRpcServiceClient) UserCredentialsList(ctx context.Context, req *connect.Request[v1.UserCredentialsListRequest]) (*connect.Response[v1.UserCredentialsListResponse], error) {
+	return c.userCredentialsList.CallUnary(ctx, req)
+}
+
+// UserCredentialDelete calls icbt.rpc.v1.IcbtRpcService.UserCredentialDelete.
+func (c *icbtRpcServiceClient) UserCredentialDelete(ctx context.Context, req *connect.Request[v1.UserCredentialDeleteRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.userCredentialDelete.CallUnary(ctx, req)
+}
+
+// AccountExport calls icbt.rpc.v1.IcbtRpcService.AccountExport.
+func (c *icbtRpcServiceClient) AccountExport(ctx context.Context, req *connect.Request[v1.AccountExportRequest]) (*connect.Response[v1.AccountExportResponse], error) {
+	return c.accountExport.CallUnary(ctx, req)
+}
+
+// WebhookCreate calls icbt.rpc.v1.IcbtRpcService.WebhookCreate.
+func (c *icbtRpcServiceClient) WebhookCreate(ctx context.Context, req *connect.Request[v1.WebhookCreateRequest]) (*connect.Response[v1.WebhookCreateResponse], error) {
+	return c.webhookCreate.CallUnary(ctx, req)
+}
+
+// WebhookUpdate calls icbt.rpc.v1.IcbtRpcService.WebhookUpdate.
+func (c *icbtRpcServiceClient) WebhookUpdate(ctx context.Context, req *connect.Request[v1.WebhookUpdateRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.webhookUpdate.CallUnary(ctx, req)
+}
+
+// WebhookDelete calls icbt.rpc.v1.IcbtRpcService.WebhookDelete.
+func (c *icbtRpcServiceClient) WebhookDelete(ctx context.Context, req *connect.Request[v1.WebhookDeleteRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.webhookDelete.CallUnary(ctx, req)
+}
+
+// WebhooksList calls icbt.rpc.v1.IcbtRpcService.WebhooksList.
+func (c *icbtRpcServiceClient) WebhooksList(ctx context.Context, req *connect.Request[v1.WebhooksListRequest]) (*connect.Response[v1.WebhooksListResponse], error) {
+	return c.webhooksList.CallUnary(ctx, req)
+}
+
+// WebhookListAttempts calls icbt.rpc.v1.IcbtRpcService.WebhookListAttempts.
+func (c *icbtRpcServiceClient) WebhookListAttempts(ctx context.Context, req *connect.Request[v1.WebhookListAttemptsRequest]) (*connect.Response[v1.WebhookListAttemptsResponse], error) {
+	return c.webhookListAttempts.CallUnary(ctx, req)
+}
+
 // IcbtRpcServiceHandler is an implementation of the icbt.rpc.v1.IcbtRpcService service.
 type IcbtRpcServiceHandler interface {
 	// earmark
@@ -398,10 +688,12 @@ type IcbtRpcServiceHandler interface {
 	EventGetDetails(context.Context, *connect.Request[v1.EventGetDetailsRequest]) (*connect.Response[v1.EventGetDetailsResponse], error)
 	EventListItems(context.Context, *connect.Request[v1.EventListItemsRequest]) (*connect.Response[v1.EventListItemsResponse], error)
 	EventListEarmarks(context.Context, *connect.Request[v1.EventListEarmarksRequest]) (*connect.Response[v1.EventListEarmarksResponse], error)
+	WatchEvent(context.Context, *connect.Request[v1.WatchEventRequest], *connect.ServerStream[v1.WatchEventResponse]) error
 	// event-items
 	EventAddItem(context.Context, *connect.Request[v1.EventAddItemRequest]) (*connect.Response[v1.EventAddItemResponse], error)
 	EventUpdateItem(context.Context, *connect.Request[v1.EventUpdateItemRequest]) (*connect.Response[v1.EventUpdateItemResponse], error)
 	EventRemoveItem(context.Context, *connect.Request[v1.EventRemoveItemRequest]) (*connect.Response[emptypb.Empty], error)
+	EventUpdateItemSorting(context.Context, *connect.Request[v1.EventUpdateItemSortingRequest]) (*connect.Response[v1.EventUpdateItemSortingResponse], error)
 	// favorites
 	FavoriteAdd(context.Context, *connect.Request[v1.FavoriteAddRequest]) (*connect.Response[v1.FavoriteAddResponse], error)
 	FavoriteRemove(context.Context, *connect.Request[v1.FavoriteRemoveRequest]) (*connect.Response[emptypb.Empty], error)
@@ -410,6 +702,24 @@ type IcbtRpcServiceHandler interface {
 	NotificationDelete(context.Context, *connect.Request[v1.NotificationDeleteRequest]) (*connect.Response[emptypb.Empty], error)
 	NotificationsDeleteAll(context.Context, *connect.Request[v1.NotificationsDeleteAllRequest]) (*connect.Response[emptypb.Empty], error)
 	NotificationsList(context.Context, *connect.Request[v1.NotificationsListRequest]) (*connect.Response[v1.NotificationsListResponse], error)
+	NotificationMarkRead(context.Context, *connect.Request[v1.NotificationMarkReadRequest]) (*connect.Response[emptypb.Empty], error)
+	NotificationMarkUnread(context.Context, *connect.Request[v1.NotificationMarkUnreadRequest]) (*connect.Response[emptypb.Empty], error)
+	NotificationsMarkAllRead(context.Context, *connect.Request[v1.NotificationsMarkAllReadRequest]) (*connect.Response[emptypb.Empty], error)
+	WatchNotifications(context.Context, *connect.Request[v1.WatchNotificationsRequest], *connect.ServerStream[v1.WatchNotificationsResponse]) error
+	// user
+	UserGetMe(context.Context, *connect.Request[v1.UserGetMeRequest]) (*connect.Response[v1.UserGetMeResponse], error)
+	UserUpdate(context.Context, *connect.Request[v1.UserUpdateRequest]) (*connect.Response[v1.UserUpdateResponse], error)
+	UserSettingsGet(context.Context, *connect.Request[v1.UserSettingsGetRequest]) (*connect.Response[v1.UserSettingsGetResponse], error)
+	UserSettingsUpdate(context.Context, *connect.Request[v1.UserSettingsUpdateRequest]) (*connect.Response[v1.UserSettingsUpdateResponse], error)
+	UserCredentialsList(context.Context, *connect.Request[v1.UserCredentialsListRequest]) (*connect.Response[v1.UserCredentialsListResponse], error)
+	UserCredentialDelete(context.Context, *connect.Request[v1.UserCredentialDeleteRequest]) (*connect.Response[emptypb.Empty], error)
+	AccountExport(context.Context, *connect.Request[v1.AccountExportRequest]) (*connect.Response[v1.AccountExportResponse], error)
+	// webhooks
+	WebhookCreate(context.Context, *connect.Request[v1.WebhookCreateRequest]) (*connect.Response[v1.WebhookCreateResponse], error)
+	WebhookUpdate(context.Context, *connect.Request[v1.WebhookUpdateRequest]) (*connect.Response[emptypb.Empty], error)
+	WebhookDelete(context.Context, *connect.Request[v1.WebhookDeleteRequest]) (*connect.Response[emptypb.Empty], error)
+	WebhooksList(context.Context, *connect.Request[v1.WebhooksListRequest]) (*connect.Response[v1.WebhooksListResponse], error)
+	WebhookListAttempts(context.Context, *connect.Request[v1.WebhookListAttemptsRequest]) (*connect.Response[v1.WebhookListAttemptsResponse], error)
 }
 
 // NewIcbtRpcServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -485,6 +795,12 @@ func NewIcbtRpcServiceHandler(svc IcbtRpcServiceHandler, opts ...connect.Handler
 		connect.WithSchema(icbtRpcServiceMethods.ByName("EventListEarmarks")),
 		connect.WithHandlerOptions(opts...),
 	)
+	icbtRpcServiceWatchEventHandler := connect.NewServerStreamHandler(
+		IcbtRpcServiceWatchEventProcedure,
+		svc.WatchEvent,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WatchEvent")),
+		connect.WithHandlerOptions(opts...),
+	)
 	icbtRpcServiceEventAddItemHandler := connect.NewUnaryHandler(
 		IcbtRpcServiceEventAddItemProcedure,
 		svc.EventAddItem,
@@ -503,6 +819,12 @@ func NewIcbtRpcServiceHandler(svc IcbtRpcServiceHandler, opts ...connect.Handler
 		connect.WithSchema(icbtRpcServiceMethods.ByName("EventRemoveItem")),
 		connect.WithHandlerOptions(opts...),
 	)
+	icbtRpcServiceEventUpdateItemSortingHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceEventUpdateItemSortingProcedure,
+		svc.EventUpdateItemSorting,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("EventUpdateItemSorting")),
+		connect.WithHandlerOptions(opts...),
+	)
 	icbtRpcServiceFavoriteAddHandler := connect.NewUnaryHandler(
 		IcbtRpcServiceFavoriteAddProcedure,
 		svc.FavoriteAdd,
@@ -539,6 +861,102 @@ func NewIcbtRpcServiceHandler(svc IcbtRpcServiceHandler, opts ...connect.Handler
 		connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationsList")),
 		connect.WithHandlerOptions(opts...),
 	)
+	icbtRpcServiceNotificationMarkReadHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceNotificationMarkReadProcedure,
+		svc.NotificationMarkRead,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationMarkRead")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceNotificationMarkUnreadHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceNotificationMarkUnreadProcedure,
+		svc.NotificationMarkUnread,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationMarkUnread")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceNotificationsMarkAllReadHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceNotificationsMarkAllReadProcedure,
+		svc.NotificationsMarkAllRead,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("NotificationsMarkAllRead")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceWatchNotificationsHandler := connect.NewServerStreamHandler(
+		IcbtRpcServiceWatchNotificationsProcedure,
+		svc.WatchNotifications,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WatchNotifications")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceUserGetMeHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceUserGetMeProcedure,
+		svc.UserGetMe,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("UserGetMe")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceUserUpdateHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceUserUpdateProcedure,
+		svc.UserUpdate,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("UserUpdate")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceUserSettingsGetHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceUserSettingsGetProcedure,
+		svc.UserSettingsGet,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("UserSettingsGet")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceUserSettingsUpdateHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceUserSettingsUpdateProcedure,
+		svc.UserSettingsUpdate,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("UserSettingsUpdate")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceUserCredentialsListHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceUserCredentialsListProcedure,
+		svc.UserCredentialsList,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("UserCredentialsList")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceUserCredentialDeleteHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceUserCredentialDeleteProcedure,
+		svc.UserCredentialDelete,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("UserCredentialDelete")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceAccountExportHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceAccountExportProcedure,
+		svc.AccountExport,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("AccountExport")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceWebhookCreateHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceWebhookCreateProcedure,
+		svc.WebhookCreate,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookCreate")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceWebhookUpdateHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceWebhookUpdateProcedure,
+		svc.WebhookUpdate,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookUpdate")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceWebhookDeleteHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceWebhookDeleteProcedure,
+		svc.WebhookDelete,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookDelete")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceWebhooksListHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceWebhooksListProcedure,
+		svc.WebhooksList,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WebhooksList")),
+		connect.WithHandlerOptions(opts...),
+	)
+	icbtRpcServiceWebhookListAttemptsHandler := connect.NewUnaryHandler(
+		IcbtRpcServiceWebhookListAttemptsProcedure,
+		svc.WebhookListAttempts,
+		connect.WithSchema(icbtRpcServiceMethods.ByName("WebhookListAttempts")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/icbt.rpc.v1.IcbtRpcService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case IcbtRpcServiceEarmarkCreateProcedure:
@@ -563,12 +981,16 @@ func NewIcbtRpcServiceHandler(svc IcbtRpcServiceHandler, opts ...connect.Handler
 			icbtRpcServiceEventListItemsHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceEventListEarmarksProcedure:
 			icbtRpcServiceEventListEarmarksHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWatchEventProcedure:
+			icbtRpcServiceWatchEventHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceEventAddItemProcedure:
 			icbtRpcServiceEventAddItemHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceEventUpdateItemProcedure:
 			icbtRpcServiceEventUpdateItemHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceEventRemoveItemProcedure:
 			icbtRpcServiceEventRemoveItemHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceEventUpdateItemSortingProcedure:
+			icbtRpcServiceEventUpdateItemSortingHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceFavoriteAddProcedure:
 			icbtRpcServiceFavoriteAddHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceFavoriteRemoveProcedure:
@@ -581,6 +1003,38 @@ func NewIcbtRpcServiceHandler(svc IcbtRpcServiceHandler, opts ...connect.Handler
 			icbtRpcServiceNotificationsDeleteAllHandler.ServeHTTP(w, r)
 		case IcbtRpcServiceNotificationsListProcedure:
 			icbtRpcServiceNotificationsListHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceNotificationMarkReadProcedure:
+			icbtRpcServiceNotificationMarkReadHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceNotificationMarkUnreadProcedure:
+			icbtRpcServiceNotificationMarkUnreadHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceNotificationsMarkAllReadProcedure:
+			icbtRpcServiceNotificationsMarkAllReadHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWatchNotificationsProcedure:
+			icbtRpcServiceWatchNotificationsHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceUserGetMeProcedure:
+			icbtRpcServiceUserGetMeHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceUserUpdateProcedure:
+			icbtRpcServiceUserUpdateHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceUserSettingsGetProcedure:
+			icbtRpcServiceUserSettingsGetHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceUserSettingsUpdateProcedure:
+			icbtRpcServiceUserSettingsUpdateHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceUserCredentialsListProcedure:
+			icbtRpcServiceUserCredentialsListHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceUserCredentialDeleteProcedure:
+			icbtRpcServiceUserCredentialDeleteHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceAccountExportProcedure:
+			icbtRpcServiceAccountExportHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWebhookCreateProcedure:
+			icbtRpcServiceWebhookCreateHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWebhookUpdateProcedure:
+			icbtRpcServiceWebhookUpdateHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWebhookDeleteProcedure:
+			icbtRpcServiceWebhookDeleteHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWebhooksListProcedure:
+			icbtRpcServiceWebhooksListHandler.ServeHTTP(w, r)
+		case IcbtRpcServiceWebhookListAttemptsProcedure:
+			icbtRpcServiceWebhookListAttemptsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -634,6 +1088,10 @@ func (UnimplementedIcbtRpcServiceHandler) EventListEarmarks(context.Context, *co
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.EventListEarmarks is not implemented"))
 }
 
+func (UnimplementedIcbtRpcServiceHandler) WatchEvent(context.Context, *connect.Request[v1.WatchEventRequest], *connect.ServerStream[v1.WatchEventResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WatchEvent is not implemented"))
+}
+
 func (UnimplementedIcbtRpcServiceHandler) EventAddItem(context.Context, *connect.Request[v1.EventAddItemRequest]) (*connect.Response[v1.EventAddItemResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.EventAddItem is not implemented"))
 }
@@ -646,6 +1104,10 @@ func (UnimplementedIcbtRpcServiceHandler) EventRemoveItem(context.Context, *conn
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.EventRemoveItem is not implemented"))
 }
 
+func (UnimplementedIcbtRpcServiceHandler) EventUpdateItemSorting(context.Context, *connect.Request[v1.EventUpdateItemSortingRequest]) (*connect.Response[v1.EventUpdateItemSortingResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.EventUpdateItemSorting is not implemented"))
+}
+
 func (UnimplementedIcbtRpcServiceHandler) FavoriteAdd(context.Context, *connect.Request[v1.FavoriteAddRequest]) (*connect.Response[v1.FavoriteAddResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.FavoriteAdd is not implemented"))
 }
@@ -669,3 +1131,67 @@ func (UnimplementedIcbtRpcServiceHandler) NotificationsDeleteAll(context.Context
 func (UnimplementedIcbtRpcServiceHandler) NotificationsList(context.Context, *connect.Request[v1.NotificationsListRequest]) (*connect.Response[v1.NotificationsListResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.NotificationsList is not implemented"))
 }
+
+func (UnimplementedIcbtRpcServiceHandler) NotificationMarkRead(context.Context, *connect.Request[v1.NotificationMarkReadRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.NotificationMarkRead is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) NotificationMarkUnread(context.Context, *connect.Request[v1.NotificationMarkUnreadRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.NotificationMarkUnread is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) NotificationsMarkAllRead(context.Context, *connect.Request[v1.NotificationsMarkAllReadRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.NotificationsMarkAllRead is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) WatchNotifications(context.Context, *connect.Request[v1.WatchNotificationsRequest], *connect.ServerStream[v1.WatchNotificationsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WatchNotifications is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) UserGetMe(context.Context, *connect.Request[v1.UserGetMeRequest]) (*connect.Response[v1.UserGetMeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.UserGetMe is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) UserUpdate(context.Context, *connect.Request[v1.UserUpdateRequest]) (*connect.Response[v1.UserUpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.UserUpdate is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) UserSettingsGet(context.Context, *connect.Request[v1.UserSettingsGetRequest]) (*connect.Response[v1.UserSettingsGetResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.UserSettingsGet is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) UserSettingsUpdate(context.Context, *connect.Request[v1.UserSettingsUpdateRequest]) (*connect.Response[v1.UserSettingsUpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.UserSettingsUpdate is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) UserCredentialsList(context.Context, *connect.Request[v1.UserCredentialsListRequest]) (*connect.Response[v1.UserCredentialsListResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.UserCredentialsList is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) UserCredentialDelete(context.Context, *connect.Request[v1.UserCredentialDeleteRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.UserCredentialDelete is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) AccountExport(context.Context, *connect.Request[v1.AccountExportRequest]) (*connect.Response[v1.AccountExportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.AccountExport is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) WebhookCreate(context.Context, *connect.Request[v1.WebhookCreateRequest]) (*connect.Response[v1.WebhookCreateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WebhookCreate is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) WebhookUpdate(context.Context, *connect.Request[v1.WebhookUpdateRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WebhookUpdate is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) WebhookDelete(context.Context, *connect.Request[v1.WebhookDeleteRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WebhookDelete is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) WebhooksList(context.Context, *connect.Request[v1.WebhooksListRequest]) (*connect.Response[v1.WebhooksListResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WebhooksList is not implemented"))
+}
+
+func (UnimplementedIcbtRpcServiceHandler) WebhookListAttempts(context.Context, *connect.Request[v1.WebhookListAttemptsRequest]) (*connect.Response[v1.WebhookListAttemptsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("icbt.rpc.v1.IcbtRpcService.WebhookListAttempts is not implemented"))
+}
@@ -26,7 +26,7 @@ var File_icbt_rpc_v1_service_proto protoreflect.FileDescriptor
 
 const file_icbt_rpc_v1_service_proto_rawDesc = "" +
 	"\n" +
-	"\x19icbt/rpc/v1/service.proto\x12\vicbt.rpc.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a!google/protobuf/go_features.proto\x1a\x19icbt/rpc/v1/earmark.proto\x1a\x17icbt/rpc/v1/event.proto\x1a\x1aicbt/rpc/v1/favorite.proto\x1a\x1eicbt/rpc/v1/notification.proto2\xdd\r\n" +
+	"\x19icbt/rpc/v1/service.proto\x12\vicbt.rpc.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a!google/protobuf/go_features.proto\x1a\x19icbt/rpc/v1/earmark.proto\x1a\x17icbt/rpc/v1/event.proto\x1a\x1aicbt/rpc/v1/favorite.proto\x1a\x1eicbt/rpc/v1/notification.proto\x1a\x16icbt/rpc/v1/user.proto\x1a\x19icbt/rpc/v1/webhook.proto2\xcf\x1a\n" +
 	"\x0eIcbtRpcService\x12V\n" +
 	"\rEarmarkCreate\x12!.icbt.rpc.v1.EarmarkCreateRequest\x1a\".icbt.rpc.v1.EarmarkCreateResponse\x12b\n" +
 	"\x11EarmarkGetDetails\x12%.icbt.rpc.v1.EarmarkGetDetailsRequest\x1a&.icbt.rpc.v1.EarmarkGetDetailsResponse\x12J\n" +
@@ -39,53 +39,103 @@ const file_icbt_rpc_v1_service_proto_rawDesc = "" +
 	"EventsList\x12\x1e.icbt.rpc.v1.EventsListRequest\x1a\x1f.icbt.rpc.v1.EventsListResponse\x12\\\n" +
 	"\x0fEventGetDetails\x12#.icbt.rpc.v1.EventGetDetailsRequest\x1a$.icbt.rpc.v1.EventGetDetailsResponse\x12Y\n" +
 	"\x0eEventListItems\x12\".icbt.rpc.v1.EventListItemsRequest\x1a#.icbt.rpc.v1.EventListItemsResponse\x12b\n" +
-	"\x11EventListEarmarks\x12%.icbt.rpc.v1.EventListEarmarksRequest\x1a&.icbt.rpc.v1.EventListEarmarksResponse\x12S\n" +
+	"\x11EventListEarmarks\x12%.icbt.rpc.v1.EventListEarmarksRequest\x1a&.icbt.rpc.v1.EventListEarmarksResponse\x12O\n" +
+	"\n" +
+	"WatchEvent\x12\x1e.icbt.rpc.v1.WatchEventRequest\x1a\x1f.icbt.rpc.v1.WatchEventResponse0\x01\x12S\n" +
 	"\fEventAddItem\x12 .icbt.rpc.v1.EventAddItemRequest\x1a!.icbt.rpc.v1.EventAddItemResponse\x12\\\n" +
 	"\x0fEventUpdateItem\x12#.icbt.rpc.v1.EventUpdateItemRequest\x1a$.icbt.rpc.v1.EventUpdateItemResponse\x12N\n" +
-	"\x0fEventRemoveItem\x12#.icbt.rpc.v1.EventRemoveItemRequest\x1a\x16.google.protobuf.Empty\x12P\n" +
+	"\x0fEventRemoveItem\x12#.icbt.rpc.v1.EventRemoveItemRequest\x1a\x16.google.protobuf.Empty\x12q\n" +
+	"\x16EventUpdateItemSorting\x12*.icbt.rpc.v1.EventUpdateItemSortingRequest\x1a+.icbt.rpc.v1.EventUpdateItemSortingResponse\x12P\n" +
 	"\vFavoriteAdd\x12\x1f.icbt.rpc.v1.FavoriteAddRequest\x1a .icbt.rpc.v1.FavoriteAddResponse\x12L\n" +
 	"\x0eFavoriteRemove\x12\".icbt.rpc.v1.FavoriteRemoveRequest\x1a\x16.google.protobuf.Empty\x12e\n" +
 	"\x12FavoriteListEvents\x12&.icbt.rpc.v1.FavoriteListEventsRequest\x1a'.icbt.rpc.v1.FavoriteListEventsResponse\x12T\n" +
 	"\x12NotificationDelete\x12&.icbt.rpc.v1.NotificationDeleteRequest\x1a\x16.google.protobuf.Empty\x12\\\n" +
 	"\x16NotificationsDeleteAll\x12*.icbt.rpc.v1.NotificationsDeleteAllRequest\x1a\x16.google.protobuf.Empty\x12b\n" +
-	"\x11NotificationsList\x12%.icbt.rpc.v1.NotificationsListRequest\x1a&.icbt.rpc.v1.NotificationsListResponseB\xb1\x01\n" +
+	"\x11NotificationsList\x12%.icbt.rpc.v1.NotificationsListRequest\x1a&.icbt.rpc.v1.NotificationsListResponse\x12X\n" +
+	"\x14NotificationMarkRead\x12(.icbt.rpc.v1.NotificationMarkReadRequest\x1a\x16.google.protobuf.Empty\x12\\\n" +
+	"\x16NotificationMarkUnread\x12*.icbt.rpc.v1.NotificationMarkUnreadRequest\x1a\x16.google.protobuf.Empty\x12`\n" +
+	"\x18NotificationsMarkAllRead\x12,.icbt.rpc.v1.NotificationsMarkAllReadRequest\x1a\x16.google.protobuf.Empty\x12g\n" +
+	"\x12WatchNotifications\x12&.icbt.rpc.v1.WatchNotificationsRequest\x1a'.icbt.rpc.v1.WatchNotificationsResponse0\x01\x12J\n" +
+	"\tUserGetMe\x12\x1d.icbt.rpc.v1.UserGetMeRequest\x1a\x1e.icbt.rpc.v1.UserGetMeResponse\x12M\n" +
+	"\n" +
+	"UserUpdate\x12\x1e.icbt.rpc.v1.UserUpdateRequest\x1a\x1f.icbt.rpc.v1.UserUpdateResponse\x12\\\n" +
+	"\x0fUserSettingsGet\x12#.icbt.rpc.v1.UserSettingsGetRequest\x1a$.icbt.rpc.v1.UserSettingsGetResponse\x12e\n" +
+	"\x12UserSettingsUpdate\x12&.icbt.rpc.v1.UserSettingsUpdateRequest\x1a'.icbt.rpc.v1.UserSettingsUpdateResponse\x12h\n" +
+	"\x13UserCredentialsList\x12'.icbt.rpc.v1.UserCredentialsListRequest\x1a(.icbt.rpc.v1.UserCredentialsListResponse\x12X\n" +
+	"\x14UserCredentialDelete\x12(.icbt.rpc.v1.UserCredentialDeleteRequest\x1a\x16.google.protobuf.Empty\x12V\n" +
+	"\rAccountExport\x12!.icbt.rpc.v1.AccountExportRequest\x1a\".icbt.rpc.v1.AccountExportResponse\x12V\n" +
+	"\rWebhookCreate\x12!.icbt.rpc.v1.WebhookCreateRequest\x1a\".icbt.rpc.v1.WebhookCreateResponse\x12J\n" +
+	"\rWebhookUpdate\x12!.icbt.rpc.v1.WebhookUpdateRequest\x1a\x16.google.protobuf.Empty\x12J\n" +
+	"\rWebhookDelete\x12!.icbt.rpc.v1.WebhookDeleteRequest\x1a\x16.google.protobuf.Empty\x12S\n" +
+	"\fWebhooksList\x12 .icbt.rpc.v1.WebhooksListRequest\x1a!.icbt.rpc.v1.WebhooksListResponse\x12h\n" +
+	"\x13WebhookListAttempts\x12'.icbt.rpc.v1.WebhookListAttemptsRequest\x1a(.icbt.rpc.v1.WebhookListAttemptsResponseB\xb1\x01\n" +
 	"\x0fcom.icbt.rpc.v1B\fServiceProtoP\x01Z8github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1;rpcv1\xa2\x02\x03IRX\xaa\x02\vIcbt.Rpc.V1\xca\x02\vIcbt\\Rpc\\V1\xe2\x02\x17Icbt\\Rpc\\V1\\GPBMetadata\xea\x02\rIcbt::Rpc::V1\x92\x03\a\xd2>\x02\x10\x03\b\x02b\beditionsp\xe8\a"
 
 var file_icbt_rpc_v1_service_proto_goTypes = []any{
-	(*EarmarkCreateRequest)(nil),          // 0: icbt.rpc.v1.EarmarkCreateRequest
-	(*EarmarkGetDetailsRequest)(nil),      // 1: icbt.rpc.v1.EarmarkGetDetailsRequest
-	(*EarmarkRemoveRequest)(nil),          // 2: icbt.rpc.v1.EarmarkRemoveRequest
-	(*EarmarksListRequest)(nil),           // 3: icbt.rpc.v1.EarmarksListRequest
-	(*EventCreateRequest)(nil),            // 4: icbt.rpc.v1.EventCreateRequest
-	(*EventUpdateRequest)(nil),            // 5: icbt.rpc.v1.EventUpdateRequest
-	(*EventDeleteRequest)(nil),            // 6: icbt.rpc.v1.EventDeleteRequest
-	(*EventsListRequest)(nil),             // 7: icbt.rpc.v1.EventsListRequest
-	(*EventGetDetailsRequest)(nil),        // 8: icbt.rpc.v1.EventGetDetailsRequest
-	(*EventListItemsRequest)(nil),         // 9: icbt.rpc.v1.EventListItemsRequest
-	(*EventListEarmarksRequest)(nil),      // 10: icbt.rpc.v1.EventListEarmarksRequest
-	(*EventAddItemRequest)(nil),           // 11: icbt.rpc.v1.EventAddItemRequest
-	(*EventUpdateItemRequest)(nil),        // 12: icbt.rpc.v1.EventUpdateItemRequest
-	(*EventRemoveItemRequest)(nil),        // 13: icbt.rpc.v1.EventRemoveItemRequest
-	(*FavoriteAddRequest)(nil),            // 14: icbt.rpc.v1.FavoriteAddRequest
-	(*FavoriteRemoveRequest)(nil),         // 15: icbt.rpc.v1.FavoriteRemoveRequest
-	(*FavoriteListEventsRequest)(nil),     // 16: icbt.rpc.v1.FavoriteListEventsRequest
-	(*NotificationDeleteRequest)(nil),     // 17: icbt.rpc.v1.NotificationDeleteRequest
-	(*NotificationsDeleteAllRequest)(nil), // 18: icbt.rpc.v1.NotificationsDeleteAllRequest
-	(*NotificationsListRequest)(nil),      // 19: icbt.rpc.v1.NotificationsListRequest
-	(*EarmarkCreateResponse)(nil),         // 20: icbt.rpc.v1.EarmarkCreateResponse
-	(*EarmarkGetDetailsResponse)(nil),     // 21: icbt.rpc.v1.EarmarkGetDetailsResponse
-	(*emptypb.Empty)(nil),                 // 22: google.protobuf.Empty
-	(*EarmarksListResponse)(nil),          // 23: icbt.rpc.v1.EarmarksListResponse
-	(*EventCreateResponse)(nil),           // 24: icbt.rpc.v1.EventCreateResponse
-	(*EventsListResponse)(nil),            // 25: icbt.rpc.v1.EventsListResponse
-	(*EventGetDetailsResponse)(nil),       // 26: icbt.rpc.v1.EventGetDetailsResponse
-	(*EventListItemsResponse)(nil),        // 27: icbt.rpc.v1.EventListItemsResponse
-	(*EventListEarmarksResponse)(nil),     // 28: icbt.rpc.v1.EventListEarmarksResponse
-	(*EventAddItemResponse)(nil),          // 29: icbt.rpc.v1.EventAddItemResponse
-	(*EventUpdateItemResponse)(nil),       // 30: icbt.rpc.v1.EventUpdateItemResponse
-	(*FavoriteAddResponse)(nil),           // 31: icbt.rpc.v1.FavoriteAddResponse
-	(*FavoriteListEventsResponse)(nil),    // 32: icbt.rpc.v1.FavoriteListEventsResponse
-	(*NotificationsListResponse)(nil),     // 33: icbt.rpc.v1.NotificationsListResponse
+	(*EarmarkCreateRequest)(nil),            // 0: icbt.rpc.v1.EarmarkCreateRequest
+	(*EarmarkGetDetailsRequest)(nil),        // 1: icbt.rpc.v1.EarmarkGetDetailsRequest
+	(*EarmarkRemoveRequest)(nil),            // 2: icbt.rpc.v1.EarmarkRemoveRequest
+	(*EarmarksListRequest)(nil),             // 3: icbt.rpc.v1.EarmarksListRequest
+	(*EventCreateRequest)(nil),              // 4: icbt.rpc.v1.EventCreateRequest
+	(*EventUpdateRequest)(nil),              // 5: icbt.rpc.v1.EventUpdateRequest
+	(*EventDeleteRequest)(nil),              // 6: icbt.rpc.v1.EventDeleteRequest
+	(*EventsListRequest)(nil),               // 7: icbt.rpc.v1.EventsListRequest
+	(*EventGetDetailsRequest)(nil),          // 8: icbt.rpc.v1.EventGetDetailsRequest
+	(*EventListItemsRequest)(nil),           // 9: icbt.rpc.v1.EventListItemsRequest
+	(*EventListEarmarksRequest)(nil),        // 10: icbt.rpc.v1.EventListEarmarksRequest
+	(*WatchEventRequest)(nil),               // 11: icbt.rpc.v1.WatchEventRequest
+	(*EventAddItemRequest)(nil),             // 12: icbt.rpc.v1.EventAddItemRequest
+	(*EventUpdateItemRequest)(nil),          // 13: icbt.rpc.v1.EventUpdateItemRequest
+	(*EventRemoveItemRequest)(nil),          // 14: icbt.rpc.v1.EventRemoveItemRequest
+	(*EventUpdateItemSortingRequest)(nil),   // 15: icbt.rpc.v1.EventUpdateItemSortingRequest
+	(*FavoriteAddRequest)(nil),              // 16: icbt.rpc.v1.FavoriteAddRequest
+	(*FavoriteRemoveRequest)(nil),           // 17: icbt.rpc.v1.FavoriteRemoveRequest
+	(*FavoriteListEventsRequest)(nil),       // 18: icbt.rpc.v1.FavoriteListEventsRequest
+	(*NotificationDeleteRequest)(nil),       // 19: icbt.rpc.v1.NotificationDeleteRequest
+	(*NotificationsDeleteAllRequest)(nil),   // 20: icbt.rpc.v1.NotificationsDeleteAllRequest
+	(*NotificationsListRequest)(nil),        // 21: icbt.rpc.v1.NotificationsListRequest
+	(*NotificationMarkReadRequest)(nil),     // 22: icbt.rpc.v1.NotificationMarkReadRequest
+	(*NotificationMarkUnreadRequest)(nil),   // 23: icbt.rpc.v1.NotificationMarkUnreadRequest
+	(*NotificationsMarkAllReadRequest)(nil), // 24: icbt.rpc.v1.NotificationsMarkAllReadRequest
+	(*WatchNotificationsRequest)(nil),       // 25: icbt.rpc.v1.WatchNotificationsRequest
+	(*UserGetMeRequest)(nil),                // 26: icbt.rpc.v1.UserGetMeRequest
+	(*UserUpdateRequest)(nil),               // 27: icbt.rpc.v1.UserUpdateRequest
+	(*UserSettingsGetRequest)(nil),          // 28: icbt.rpc.v1.UserSettingsGetRequest
+	(*UserSettingsUpdateRequest)(nil),       // 29: icbt.rpc.v1.UserSettingsUpdateRequest
+	(*UserCredentialsListRequest)(nil),      // 30: icbt.rpc.v1.UserCredentialsListRequest
+	(*UserCredentialDeleteRequest)(nil),     // 31: icbt.rpc.v1.UserCredentialDeleteRequest
+	(*AccountExportRequest)(nil),            // 32: icbt.rpc.v1.AccountExportRequest
+	(*WebhookCreateRequest)(nil),            // 33: icbt.rpc.v1.WebhookCreateRequest
+	(*WebhookUpdateRequest)(nil),            // 34: icbt.rpc.v1.WebhookUpdateRequest
+	(*WebhookDeleteRequest)(nil),            // 35: icbt.rpc.v1.WebhookDeleteRequest
+	(*WebhooksListRequest)(nil),             // 36: icbt.rpc.v1.WebhooksListRequest
+	(*WebhookListAttemptsRequest)(nil),      // 37: icbt.rpc.v1.WebhookListAttemptsRequest
+	(*EarmarkCreateResponse)(nil),           // 38: icbt.rpc.v1.EarmarkCreateResponse
+	(*EarmarkGetDetailsResponse)(nil),       // 39: icbt.rpc.v1.EarmarkGetDetailsResponse
+	(*emptypb.Empty)(nil),                   // 40: google.protobuf.Empty
+	(*EarmarksListResponse)(nil),            // 41: icbt.rpc.v1.EarmarksListResponse
+	(*EventCreateResponse)(nil),             // 42: icbt.rpc.v1.EventCreateResponse
+	(*EventsListResponse)(nil),              // 43: icbt.rpc.v1.EventsListResponse
+	(*EventGetDetailsResponse)(nil),         // 44: icbt.rpc.v1.EventGetDetailsResponse
+	(*EventListItemsResponse)(nil),          // 45: icbt.rpc.v1.EventListItemsResponse
+	(*EventListEarmarksResponse)(nil),       // 46: icbt.rpc.v1.EventListEarmarksResponse
+	(*WatchEventResponse)(nil),              // 47: icbt.rpc.v1.WatchEventResponse
+	(*EventAddItemResponse)(nil),            // 48: icbt.rpc.v1.EventAddItemResponse
+	(*EventUpdateItemResponse)(nil),         // 49: icbt.rpc.v1.EventUpdateItemResponse
+	(*EventUpdateItemSortingResponse)(nil),  // 50: icbt.rpc.v1.EventUpdateItemSortingResponse
+	(*FavoriteAddResponse)(nil),             // 51: icbt.rpc.v1.FavoriteAddResponse
+	(*FavoriteListEventsResponse)(nil),      // 52: icbt.rpc.v1.FavoriteListEventsResponse
+	(*NotificationsListResponse)(nil),       // 53: icbt.rpc.v1.NotificationsListResponse
+	(*WatchNotificationsResponse)(nil),      // 54: icbt.rpc.v1.WatchNotificationsResponse
+	(*UserGetMeResponse)(nil),               // 55: icbt.rpc.v1.UserGetMeResponse
+	(*UserUpdateResponse)(nil),              // 56: icbt.rpc.v1.UserUpdateResponse
+	(*UserSettingsGetResponse)(nil),         // 57: icbt.rpc.v1.UserSettingsGetResponse
+	(*UserSettingsUpdateResponse)(nil),      // 58: icbt.rpc.v1.UserSettingsUpdateResponse
+	(*UserCredentialsListResponse)(nil),     // 59: icbt.rpc.v1.UserCredentialsListResponse
+	(*AccountExportResponse)(nil),           // 60: icbt.rpc.v1.AccountExportResponse
+	(*WebhookCreateResponse)(nil),           // 61: icbt.rpc.v1.WebhookCreateResponse
+	(*WebhooksListResponse)(nil),            // 62: icbt.rpc.v1.WebhooksListResponse
+	(*WebhookListAttemptsResponse)(nil),     // 63: icbt.rpc.v1.WebhookListAttemptsResponse
 }
 var file_icbt_rpc_v1_service_proto_depIdxs = []int32{
 	0,  // 0: icbt.rpc.v1.IcbtRpcService.EarmarkCreate:input_type -> icbt.rpc.v1.EarmarkCreateRequest
@@ -99,37 +149,73 @@ var file_icbt_rpc_v1_service_proto_depIdxs = []int32{
 	8,  // 8: icbt.rpc.v1.IcbtRpcService.EventGetDetails:input_type -> icbt.rpc.v1.EventGetDetailsRequest
 	9,  // 9: icbt.rpc.v1.IcbtRpcService.EventListItems:input_type -> icbt.rpc.v1.EventListItemsRequest
 	10, // 10: icbt.rpc.v1.IcbtRpcService.EventListEarmarks:input_type -> icbt.rpc.v1.EventListEarmarksRequest
-	11, // 11: icbt.rpc.v1.IcbtRpcService.EventAddItem:input_type -> icbt.rpc.v1.EventAddItemRequest
-	12, // 12: icbt.rpc.v1.IcbtRpcService.EventUpdateItem:input_type -> icbt.rpc.v1.EventUpdateItemRequest
-	13, // 13: icbt.rpc.v1.IcbtRpcService.EventRemoveItem:input_type -> icbt.rpc.v1.EventRemoveItemRequest
-	14, // 14: icbt.rpc.v1.IcbtRpcService.FavoriteAdd:input_type -> icbt.rpc.v1.FavoriteAddRequest
-	15, // 15: icbt.rpc.v1.IcbtRpcService.FavoriteRemove:input_type -> icbt.rpc.v1.FavoriteRemoveRequest
-	16, // 16: icbt.rpc.v1.IcbtRpcService.FavoriteListEvents:input_type -> icbt.rpc.v1.FavoriteListEventsRequest
-	17, // 17: icbt.rpc.v1.IcbtRpcService.NotificationDelete:input_type -> icbt.rpc.v1.NotificationDeleteRequest
-	18, // 18: icbt.rpc.v1.IcbtRpcService.NotificationsDeleteAll:input_type -> icbt.rpc.v1.NotificationsDeleteAllRequest
-	19, // 19: icbt.rpc.v1.IcbtRpcService.NotificationsList:input_type -> icbt.rpc.v1.NotificationsListRequest
-	20, // 20: icbt.rpc.v1.IcbtRpcService.EarmarkCreate:output_type -> icbt.rpc.v1.EarmarkCreateResponse
-	21, // 21: icbt.rpc.v1.IcbtRpcService.EarmarkGetDetails:output_type -> icbt.rpc.v1.EarmarkGetDetailsResponse
-	22, // 22: icbt.rpc.v1.IcbtRpcService.EarmarkRemove:output_type -> google.protobuf.Empty
-	23, // 23: icbt.rpc.v1.IcbtRpcService.EarmarksList:output_type -> icbt.rpc.v1.EarmarksListResponse
-	24, // 24: icbt.rpc.v1.IcbtRpcService.EventCreate:output_type -> icbt.rpc.v1.EventCreateResponse
-	22, // 25: icbt.rpc.v1.IcbtRpcService.EventUpdate:output_type -> google.protobuf.Empty
-	22, // 26: icbt.rpc.v1.IcbtRpcService.EventDelete:output_type -> google.protobuf.Empty
-	25, // 27: icbt.rpc.v1.IcbtRpcService.EventsList:output_type -> icbt.rpc.v1.EventsListResponse
-	26, // 28: icbt.rpc.v1.IcbtRpcService.EventGetDetails:output_type -> icbt.rpc.v1.EventGetDetailsResponse
-	27, // 29: icbt.rpc.v1.IcbtRpcService.EventListItems:output_type -> icbt.rpc.v1.EventListItemsResponse
-	28, // 30: icbt.rpc.v1.IcbtRpcService.EventListEarmarks:output_type -> icbt.rpc.v1.EventListEarmarksResponse
-	29, // 31: icbt.rpc.v1.IcbtRpcService.EventAddItem:output_type -> icbt.rpc.v1.EventAddItemResponse
-	30, // 32: icbt.rpc.v1.IcbtRpcService.EventUpdateItem:output_type -> icbt.rpc.v1.EventUpdateItemResponse
-	22, // 33: icbt.rpc.v1.IcbtRpcService.EventRemoveItem:output_type -> google.protobuf.Empty
-	31, // 34: icbt.rpc.v1.IcbtRpcService.FavoriteAdd:output_type -> icbt.rpc.v1.FavoriteAddResponse
-	22, // 35: icbt.rpc.v1.IcbtRpcService.FavoriteRemove:output_type -> google.protobuf.Empty
-	32, // 36: icbt.rpc.v1.IcbtRpcService.FavoriteListEvents:output_type -> icbt.rpc.v1.FavoriteListEventsResponse
-	22, // 37: icbt.rpc.v1.IcbtRpcService.NotificationDelete:output_type -> google.protobuf.Empty
-	22, // 38: icbt.rpc.v1.IcbtRpcService.NotificationsDeleteAll:output_type -> google.protobuf.Empty
-	33, // 39: icbt.rpc.v1.IcbtRpcService.NotificationsList:output_type -> icbt.rpc.v1.NotificationsListResponse
-	20, // [20:40] is the sub-list for method output_type
-	0,  // [0:20] is the sub-list for method input_type
+	11, // 11: icbt.rpc.v1.IcbtRpcService.WatchEvent:input_type -> icbt.rpc.v1.WatchEventRequest
+	12, // 12: icbt.rpc.v1.IcbtRpcService.EventAddItem:input_type -> icbt.rpc.v1.EventAddItemRequest
+	13, // 13: icbt.rpc.v1.IcbtRpcService.EventUpdateItem:input_type -> icbt.rpc.v1.EventUpdateItemRequest
+	14, // 14: icbt.rpc.v1.IcbtRpcService.EventRemoveItem:input_type -> icbt.rpc.v1.EventRemoveItemRequest
+	15, // 15: icbt.rpc.v1.IcbtRpcService.EventUpdateItemSorting:input_type -> icbt.rpc.v1.EventUpdateItemSortingRequest
+	16, // 16: icbt.rpc.v1.IcbtRpcService.FavoriteAdd:input_type -> icbt.rpc.v1.FavoriteAddRequest
+	17, // 17: icbt.rpc.v1.IcbtRpcService.FavoriteRemove:input_type -> icbt.rpc.v1.FavoriteRemoveRequest
+	18, // 18: icbt.rpc.v1.IcbtRpcService.FavoriteListEvents:input_type -> icbt.rpc.v1.FavoriteListEventsRequest
+	19, // 19: icbt.rpc.v1.IcbtRpcService.NotificationDelete:input_type -> icbt.rpc.v1.NotificationDeleteRequest
+	20, // 20: icbt.rpc.v1.IcbtRpcService.NotificationsDeleteAll:input_type -> icbt.rpc.v1.NotificationsDeleteAllRequest
+	21, // 21: icbt.rpc.v1.IcbtRpcService.NotificationsList:input_type -> icbt.rpc.v1.NotificationsListRequest
+	22, // 22: icbt.rpc.v1.IcbtRpcService.NotificationMarkRead:input_type -> icbt.rpc.v1.NotificationMarkReadRequest
+	23, // 23: icbt.rpc.v1.IcbtRpcService.NotificationMarkUnread:input_type -> icbt.rpc.v1.NotificationMarkUnreadRequest
+	24, // 24: icbt.rpc.v1.IcbtRpcService.NotificationsMarkAllRead:input_type -> icbt.rpc.v1.NotificationsMarkAllReadRequest
+	25, // 25: icbt.rpc.v1.IcbtRpcService.WatchNotifications:input_type -> icbt.rpc.v1.WatchNotificationsRequest
+	26, // 26: icbt.rpc.v1.IcbtRpcService.UserGetMe:input_type -> icbt.rpc.v1.UserGetMeRequest
+	27, // 27: icbt.rpc.v1.IcbtRpcService.UserUpdate:input_type -> icbt.rpc.v1.UserUpdateRequest
+	28, // 28: icbt.rpc.v1.IcbtRpcService.UserSettingsGet:input_type -> icbt.rpc.v1.UserSettingsGetRequest
+	29, // 29: icbt.rpc.v1.IcbtRpcService.UserSettingsUpdate:input_type -> icbt.rpc.v1.UserSettingsUpdateRequest
+	30, // 30: icbt.rpc.v1.IcbtRpcService.UserCredentialsList:input_type -> icbt.rpc.v1.UserCredentialsListRequest
+	31, // 31: icbt.rpc.v1.IcbtRpcService.UserCredentialDelete:input_type -> icbt.rpc.v1.UserCredentialDeleteRequest
+	32, // 32: icbt.rpc.v1.IcbtRpcService.AccountExport:input_type -> icbt.rpc.v1.AccountExportRequest
+	33, // 33: icbt.rpc.v1.IcbtRpcService.WebhookCreate:input_type -> icbt.rpc.v1.WebhookCreateRequest
+	34, // 34: icbt.rpc.v1.IcbtRpcService.WebhookUpdate:input_type -> icbt.rpc.v1.WebhookUpdateRequest
+	35, // 35: icbt.rpc.v1.IcbtRpcService.WebhookDelete:input_type -> icbt.rpc.v1.WebhookDeleteRequest
+	36, // 36: icbt.rpc.v1.IcbtRpcService.WebhooksList:input_type -> icbt.rpc.v1.WebhooksListRequest
+	37, // 37: icbt.rpc.v1.IcbtRpcService.WebhookListAttempts:input_type -> icbt.rpc.v1.WebhookListAttemptsRequest
+	38, // 38: icbt.rpc.v1.IcbtRpcService.EarmarkCreate:output_type -> icbt.rpc.v1.EarmarkCreateResponse
+	39, // 39: icbt.rpc.v1.IcbtRpcService.EarmarkGetDetails:output_type -> icbt.rpc.v1.EarmarkGetDetailsResponse
+	40, // 40: icbt.rpc.v1.IcbtRpcService.EarmarkRemove:output_type -> google.protobuf.Empty
+	41, // 41: icbt.rpc.v1.IcbtRpcService.EarmarksList:output_type -> icbt.rpc.v1.EarmarksListResponse
+	42, // 42: icbt.rpc.v1.IcbtRpcService.EventCreate:output_type -> icbt.rpc.v1.EventCreateResponse
+	40, // 43: icbt.rpc.v1.IcbtRpcService.EventUpdate:output_type -> google.protobuf.Empty
+	40, // 44: icbt.rpc.v1.IcbtRpcService.EventDelete:output_type -> google.protobuf.Empty
+	43, // 45: icbt.rpc.v1.IcbtRpcService.EventsList:output_type -> icbt.rpc.v1.EventsListResponse
+	44, // 46: icbt.rpc.v1.IcbtRpcService.EventGetDetails:output_type -> icbt.rpc.v1.EventGetDetailsResponse
+	45, // 47: icbt.rpc.v1.IcbtRpcService.EventListItems:output_type -> icbt.rpc.v1.EventListItemsResponse
+	46, // 48: icbt.rpc.v1.IcbtRpcService.EventListEarmarks:output_type -> icbt.rpc.v1.EventListEarmarksResponse
+	47, // 49: icbt.rpc.v1.IcbtRpcService.WatchEvent:output_type -> icbt.rpc.v1.WatchEventResponse
+	48, // 50: icbt.rpc.v1.IcbtRpcService.EventAddItem:output_type -> icbt.rpc.v1.EventAddItemResponse
+	49, // 51: icbt.rpc.v1.IcbtRpcService.EventUpdateItem:output_type -> icbt.rpc.v1.EventUpdateItemResponse
+	40, // 52: icbt.rpc.v1.IcbtRpcService.EventRemoveItem:output_type -> google.protobuf.Empty
+	50, // 53: icbt.rpc.v1.IcbtRpcService.EventUpdateItemSorting:output_type -> icbt.rpc.v1.EventUpdateItemSortingResponse
+	51, // 54: icbt.rpc.v1.IcbtRpcService.FavoriteAdd:output_type -> icbt.rpc.v1.FavoriteAddResponse
+	40, // 55: icbt.rpc.v1.IcbtRpcService.FavoriteRemove:output_type -> google.protobuf.Empty
+	52, // 56: icbt.rpc.v1.IcbtRpcService.FavoriteListEvents:output_type -> icbt.rpc.v1.FavoriteListEventsResponse
+	40, // 57: icbt.rpc.v1.IcbtRpcService.NotificationDelete:output_type -> google.protobuf.Empty
+	40, // 58: icbt.rpc.v1.IcbtRpcService.NotificationsDeleteAll:output_type -> google.protobuf.Empty
+	53, // 59: icbt.rpc.v1.IcbtRpcService.NotificationsList:output_type -> icbt.rpc.v1.NotificationsListResponse
+	40, // 60: icbt.rpc.v1.IcbtRpcService.NotificationMarkRead:output_type -> google.protobuf.Empty
+	40, // 61: icbt.rpc.v1.IcbtRpcService.NotificationMarkUnread:output_type -> google.protobuf.Empty
+	40, // 62: icbt.rpc.v1.IcbtRpcService.NotificationsMarkAllRead:output_type -> google.protobuf.Empty
+	54, // 63: icbt.rpc.v1.IcbtRpcService.WatchNotifications:output_type -> icbt.rpc.v1.WatchNotificationsResponse
+	55, // 64: icbt.rpc.v1.IcbtRpcService.UserGetMe:output_type -> icbt.rpc.v1.UserGetMeResponse
+	56, // 65: icbt.rpc.v1.IcbtRpcService.UserUpdate:output_type -> icbt.rpc.v1.UserUpdateResponse
+	57, // 66: icbt.rpc.v1.IcbtRpcService.UserSettingsGet:output_type -> icbt.rpc.v1.UserSettingsGetResponse
+	58, // 67: icbt.rpc.v1.IcbtRpcService.UserSettingsUpdate:output_type -> icbt.rpc.v1.UserSettingsUpdateResponse
+	59, // 68: icbt.rpc.v1.IcbtRpcService.UserCredentialsList:output_type -> icbt.rpc.v1.UserCredentialsListResponse
+	40, // 69: icbt.rpc.v1.IcbtRpcService.UserCredentialDelete:output_type -> google.protobuf.Empty
+	60, // 70: icbt.rpc.v1.IcbtRpcService.AccountExport:output_type -> icbt.rpc.v1.AccountExportResponse
+	61, // 71: icbt.rpc.v1.IcbtRpcService.WebhookCreate:output_type -> icbt.rpc.v1.WebhookCreateResponse
+	40, // 72: icbt.rpc.v1.IcbtRpcService.WebhookUpdate:output_type -> google.protobuf.Empty
+	40, // 73: icbt.rpc.v1.IcbtRpcService.WebhookDelete:output_type -> google.protobuf.Empty
+	62, // 74: icbt.rpc.v1.IcbtRpcService.WebhooksList:output_type -> icbt.rpc.v1.WebhooksListResponse
+	63, // 75: icbt.rpc.v1.IcbtRpcService.WebhookListAttempts:output_type -> icbt.rpc.v1.WebhookListAttemptsResponse
+	38, // [38:76] is the sub-list for method output_type
+	0,  // [0:38] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -144,6 +230,8 @@ func file_icbt_rpc_v1_service_proto_init() {
 	file_icbt_rpc_v1_event_proto_init()
 	file_icbt_rpc_v1_favorite_proto_init()
 	file_icbt_rpc_v1_notification_proto_init()
+	file_icbt_rpc_v1_user_proto_init()
+	file_icbt_rpc_v1_webhook_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,1682 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: icbt/rpc/v1/user.proto
+
+package rpcv1
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	_ "google.golang.org/protobuf/types/gofeaturespb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type User struct {
+	state                protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId     string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Email     string                 `protobuf:"bytes,2,opt,name=email"`
+	xxx_hidden_Name      string                 `protobuf:"bytes,3,opt,name=name"`
+	xxx_hidden_Verified  bool                   `protobuf:"varint,4,opt,name=verified"`
+	xxx_hidden_PwAuth    bool                   `protobuf:"varint,5,opt,name=pw_auth,json=pwAuth"`
+	xxx_hidden_Webauthn  bool                   `protobuf:"varint,6,opt,name=webauthn"`
+	xxx_hidden_ApiAccess bool                   `protobuf:"varint,7,opt,name=api_access,json=apiAccess"`
+	xxx_hidden_Created   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *User) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.xxx_hidden_Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.xxx_hidden_Name
+	}
+	return ""
+}
+
+func (x *User) GetVerified() bool {
+	if x != nil {
+		return x.xxx_hidden_Verified
+	}
+	return false
+}
+
+func (x *User) GetPwAuth() bool {
+	if x != nil {
+		return x.xxx_hidden_PwAuth
+	}
+	return false
+}
+
+func (x *User) GetWebauthn() bool {
+	if x != nil {
+		return x.xxx_hidden_Webauthn
+	}
+	return false
+}
+
+func (x *User) GetApiAccess() bool {
+	if x != nil {
+		return x.xxx_hidden_ApiAccess
+	}
+	return false
+}
+
+func (x *User) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.xxx_hidden_Created
+	}
+	return nil
+}
+
+func (x *User) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *User) SetEmail(v string) {
+	x.xxx_hidden_Email = v
+}
+
+func (x *User) SetName(v string) {
+	x.xxx_hidden_Name = v
+}
+
+func (x *User) SetVerified(v bool) {
+	x.xxx_hidden_Verified = v
+}
+
+func (x *User) SetPwAuth(v bool) {
+	x.xxx_hidden_PwAuth = v
+}
+
+func (x *User) SetWebauthn(v bool) {
+	x.xxx_hidden_Webauthn = v
+}
+
+func (x *User) SetApiAccess(v bool) {
+	x.xxx_hidden_ApiAccess = v
+}
+
+func (x *User) SetCreated(v *timestamppb.Timestamp) {
+	x.xxx_hidden_Created = v
+}
+
+func (x *User) HasCreated() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Created != nil
+}
+
+func (x *User) ClearCreated() {
+	x.xxx_hidden_Created = nil
+}
+
+type User_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId     string
+	Email     string
+	Name      string
+	Verified  bool
+	PwAuth    bool
+	Webauthn  bool
+	ApiAccess bool
+	Created   *timestamppb.Timestamp
+}
+
+func (b0 User_builder) Build() *User {
+	m0 := &User{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Email = b.Email
+	x.xxx_hidden_Name = b.Name
+	x.xxx_hidden_Verified = b.Verified
+	x.xxx_hidden_PwAuth = b.PwAuth
+	x.xxx_hidden_Webauthn = b.Webauthn
+	x.xxx_hidden_ApiAccess = b.ApiAccess
+	x.xxx_hidden_Created = b.Created
+	return m0
+}
+
+type UserSettings struct {
+	state                             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ReminderThresholdHours uint32                 `protobuf:"varint,1,opt,name=reminder_threshold_hours,json=reminderThresholdHours"`
+	xxx_hidden_EnableReminders        bool                   `protobuf:"varint,2,opt,name=enable_reminders,json=enableReminders"`
+	xxx_hidden_NudgeGuests            bool                   `protobuf:"varint,3,opt,name=nudge_guests,json=nudgeGuests"`
+	xxx_hidden_Timezone               string                 `protobuf:"bytes,4,opt,name=timezone"`
+	xxx_hidden_QuietHoursEnabled      bool                   `protobuf:"varint,5,opt,name=quiet_hours_enabled,json=quietHoursEnabled"`
+	xxx_hidden_QuietHoursStart        uint32                 `protobuf:"varint,6,opt,name=quiet_hours_start,json=quietHoursStart"`
+	xxx_hidden_QuietHoursEnd          uint32                 `protobuf:"varint,7,opt,name=quiet_hours_end,json=quietHoursEnd"`
+	xxx_hidden_DigestFrequency        string                 `protobuf:"bytes,8,opt,name=digest_frequency,json=digestFrequency"`
+	unknownFields                     protoimpl.UnknownFields
+	sizeCache                         protoimpl.SizeCache
+}
+
+func (x *UserSettings) Reset() {
+	*x = UserSettings{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSettings) ProtoMessage() {}
+
+func (x *UserSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserSettings) GetReminderThresholdHours() uint32 {
+	if x != nil {
+		return x.xxx_hidden_ReminderThresholdHours
+	}
+	return 0
+}
+
+func (x *UserSettings) GetEnableReminders() bool {
+	if x != nil {
+		return x.xxx_hidden_EnableReminders
+	}
+	return false
+}
+
+func (x *UserSettings) GetNudgeGuests() bool {
+	if x != nil {
+		return x.xxx_hidden_NudgeGuests
+	}
+	return false
+}
+
+func (x *UserSettings) GetTimezone() string {
+	if x != nil {
+		return x.xxx_hidden_Timezone
+	}
+	return ""
+}
+
+func (x *UserSettings) GetQuietHoursEnabled() bool {
+	if x != nil {
+		return x.xxx_hidden_QuietHoursEnabled
+	}
+	return false
+}
+
+func (x *UserSettings) GetQuietHoursStart() uint32 {
+	if x != nil {
+		return x.xxx_hidden_QuietHoursStart
+	}
+	return 0
+}
+
+func (x *UserSettings) GetQuietHoursEnd() uint32 {
+	if x != nil {
+		return x.xxx_hidden_QuietHoursEnd
+	}
+	return 0
+}
+
+func (x *UserSettings) GetDigestFrequency() string {
+	if x != nil {
+		return x.xxx_hidden_DigestFrequency
+	}
+	return ""
+}
+
+func (x *UserSettings) SetReminderThresholdHours(v uint32) {
+	x.xxx_hidden_ReminderThresholdHours = v
+}
+
+func (x *UserSettings) SetEnableReminders(v bool) {
+	x.xxx_hidden_EnableReminders = v
+}
+
+func (x *UserSettings) SetNudgeGuests(v bool) {
+	x.xxx_hidden_NudgeGuests = v
+}
+
+func (x *UserSettings) SetTimezone(v string) {
+	x.xxx_hidden_Timezone = v
+}
+
+func (x *UserSettings) SetQuietHoursEnabled(v bool) {
+	x.xxx_hidden_QuietHoursEnabled = v
+}
+
+func (x *UserSettings) SetQuietHoursStart(v uint32) {
+	x.xxx_hidden_QuietHoursStart = v
+}
+
+func (x *UserSettings) SetQuietHoursEnd(v uint32) {
+	x.xxx_hidden_QuietHoursEnd = v
+}
+
+func (x *UserSettings) SetDigestFrequency(v string) {
+	x.xxx_hidden_DigestFrequency = v
+}
+
+type UserSettings_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ReminderThresholdHours uint32
+	EnableReminders        bool
+	NudgeGuests            bool
+	// iana timezone name. empty means UTC.
+	Timezone          string
+	QuietHoursEnabled bool
+	QuietHoursStart   uint32
+	QuietHoursEnd     uint32
+	// one of "", "daily", "weekly"
+	DigestFrequency string
+}
+
+func (b0 UserSettings_builder) Build() *UserSettings {
+	m0 := &UserSettings{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_ReminderThresholdHours = b.ReminderThresholdHours
+	x.xxx_hidden_EnableReminders = b.EnableReminders
+	x.xxx_hidden_NudgeGuests = b.NudgeGuests
+	x.xxx_hidden_Timezone = b.Timezone
+	x.xxx_hidden_QuietHoursEnabled = b.QuietHoursEnabled
+	x.xxx_hidden_QuietHoursStart = b.QuietHoursStart
+	x.xxx_hidden_QuietHoursEnd = b.QuietHoursEnd
+	x.xxx_hidden_DigestFrequency = b.DigestFrequency
+	return m0
+}
+
+type UserCredential struct {
+	state              protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId   string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_KeyName string                 `protobuf:"bytes,2,opt,name=key_name,json=keyName"`
+	xxx_hidden_Created *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UserCredential) Reset() {
+	*x = UserCredential{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserCredential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCredential) ProtoMessage() {}
+
+func (x *UserCredential) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserCredential) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *UserCredential) GetKeyName() string {
+	if x != nil {
+		return x.xxx_hidden_KeyName
+	}
+	return ""
+}
+
+func (x *UserCredential) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.xxx_hidden_Created
+	}
+	return nil
+}
+
+func (x *UserCredential) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *UserCredential) SetKeyName(v string) {
+	x.xxx_hidden_KeyName = v
+}
+
+func (x *UserCredential) SetCreated(v *timestamppb.Timestamp) {
+	x.xxx_hidden_Created = v
+}
+
+func (x *UserCredential) HasCreated() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Created != nil
+}
+
+func (x *UserCredential) ClearCreated() {
+	x.xxx_hidden_Created = nil
+}
+
+type UserCredential_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId   string
+	KeyName string
+	Created *timestamppb.Timestamp
+}
+
+func (b0 UserCredential_builder) Build() *UserCredential {
+	m0 := &UserCredential{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_KeyName = b.KeyName
+	x.xxx_hidden_Created = b.Created
+	return m0
+}
+
+type UserGetMeRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserGetMeRequest) Reset() {
+	*x = UserGetMeRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserGetMeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserGetMeRequest) ProtoMessage() {}
+
+func (x *UserGetMeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type UserGetMeRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 UserGetMeRequest_builder) Build() *UserGetMeRequest {
+	m0 := &UserGetMeRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type UserGetMeResponse struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_User *User                  `protobuf:"bytes,1,opt,name=user"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UserGetMeResponse) Reset() {
+	*x = UserGetMeResponse{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserGetMeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserGetMeResponse) ProtoMessage() {}
+
+func (x *UserGetMeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserGetMeResponse) GetUser() *User {
+	if x != nil {
+		return x.xxx_hidden_User
+	}
+	return nil
+}
+
+func (x *UserGetMeResponse) SetUser(v *User) {
+	x.xxx_hidden_User = v
+}
+
+func (x *UserGetMeResponse) HasUser() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_User != nil
+}
+
+func (x *UserGetMeResponse) ClearUser() {
+	x.xxx_hidden_User = nil
+}
+
+type UserGetMeResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	User *User
+}
+
+func (b0 UserGetMeResponse_builder) Build() *UserGetMeResponse {
+	m0 := &UserGetMeResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_User = b.User
+	return m0
+}
+
+type UserUpdateRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Name        *string                `protobuf:"bytes,1,opt,name=name"`
+	xxx_hidden_Email       *string                `protobuf:"bytes,2,opt,name=email"`
+	xxx_hidden_Password    *string                `protobuf:"bytes,3,opt,name=password"`
+	xxx_hidden_OldPassword *string                `protobuf:"bytes,4,opt,name=old_password,json=oldPassword"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *UserUpdateRequest) Reset() {
+	*x = UserUpdateRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUpdateRequest) ProtoMessage() {}
+
+func (x *UserUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserUpdateRequest) GetName() string {
+	if x != nil {
+		if x.xxx_hidden_Name != nil {
+			return *x.xxx_hidden_Name
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserUpdateRequest) GetEmail() string {
+	if x != nil {
+		if x.xxx_hidden_Email != nil {
+			return *x.xxx_hidden_Email
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserUpdateRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserUpdateRequest) GetOldPassword() string {
+	if x != nil {
+		if x.xxx_hidden_OldPassword != nil {
+			return *x.xxx_hidden_OldPassword
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserUpdateRequest) SetName(v string) {
+	x.xxx_hidden_Name = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 4)
+}
+
+func (x *UserUpdateRequest) SetEmail(v string) {
+	x.xxx_hidden_Email = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 4)
+}
+
+func (x *UserUpdateRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 4)
+}
+
+func (x *UserUpdateRequest) SetOldPassword(v string) {
+	x.xxx_hidden_OldPassword = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 4)
+}
+
+func (x *UserUpdateRequest) HasName() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *UserUpdateRequest) HasEmail() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UserUpdateRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UserUpdateRequest) HasOldPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *UserUpdateRequest) ClearName() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Name = nil
+}
+
+func (x *UserUpdateRequest) ClearEmail() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Email = nil
+}
+
+func (x *UserUpdateRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *UserUpdateRequest) ClearOldPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_OldPassword = nil
+}
+
+type UserUpdateRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Name *string
+	// a different email starts an email change. the address is updated
+	// once confirmed from a link mailed to the new address
+	Email *string
+	// changing password requires the current password
+	Password    *string
+	OldPassword *string
+}
+
+func (b0 UserUpdateRequest_builder) Build() *UserUpdateRequest {
+	m0 := &UserUpdateRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Name != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 4)
+		x.xxx_hidden_Name = b.Name
+	}
+	if b.Email != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 4)
+		x.xxx_hidden_Email = b.Email
+	}
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 4)
+		x.xxx_hidden_Password = b.Password
+	}
+	if b.OldPassword != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 4)
+		x.xxx_hidden_OldPassword = b.OldPassword
+	}
+	return m0
+}
+
+type UserUpdateResponse struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_User *User                  `protobuf:"bytes,1,opt,name=user"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UserUpdateResponse) Reset() {
+	*x = UserUpdateResponse{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUpdateResponse) ProtoMessage() {}
+
+func (x *UserUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserUpdateResponse) GetUser() *User {
+	if x != nil {
+		return x.xxx_hidden_User
+	}
+	return nil
+}
+
+func (x *UserUpdateResponse) SetUser(v *User) {
+	x.xxx_hidden_User = v
+}
+
+func (x *UserUpdateResponse) HasUser() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_User != nil
+}
+
+func (x *UserUpdateResponse) ClearUser() {
+	x.xxx_hidden_User = nil
+}
+
+type UserUpdateResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	User *User
+}
+
+func (b0 UserUpdateResponse_builder) Build() *UserUpdateResponse {
+	m0 := &UserUpdateResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_User = b.User
+	return m0
+}
+
+type UserSettingsGetRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserSettingsGetRequest) Reset() {
+	*x = UserSettingsGetRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSettingsGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSettingsGetRequest) ProtoMessage() {}
+
+func (x *UserSettingsGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type UserSettingsGetRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 UserSettingsGetRequest_builder) Build() *UserSettingsGetRequest {
+	m0 := &UserSettingsGetRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type UserSettingsGetResponse struct {
+	state               protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Settings *UserSettings          `protobuf:"bytes,1,opt,name=settings"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UserSettingsGetResponse) Reset() {
+	*x = UserSettingsGetResponse{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSettingsGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSettingsGetResponse) ProtoMessage() {}
+
+func (x *UserSettingsGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserSettingsGetResponse) GetSettings() *UserSettings {
+	if x != nil {
+		return x.xxx_hidden_Settings
+	}
+	return nil
+}
+
+func (x *UserSettingsGetResponse) SetSettings(v *UserSettings) {
+	x.xxx_hidden_Settings = v
+}
+
+func (x *UserSettingsGetResponse) HasSettings() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Settings != nil
+}
+
+func (x *UserSettingsGetResponse) ClearSettings() {
+	x.xxx_hidden_Settings = nil
+}
+
+type UserSettingsGetResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Settings *UserSettings
+}
+
+func (b0 UserSettingsGetResponse_builder) Build() *UserSettingsGetResponse {
+	m0 := &UserSettingsGetResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Settings = b.Settings
+	return m0
+}
+
+type UserSettingsUpdateRequest struct {
+	state                             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ReminderThresholdHours uint32                 `protobuf:"varint,1,opt,name=reminder_threshold_hours,json=reminderThresholdHours"`
+	xxx_hidden_EnableReminders        bool                   `protobuf:"varint,2,opt,name=enable_reminders,json=enableReminders"`
+	xxx_hidden_NudgeGuests            bool                   `protobuf:"varint,3,opt,name=nudge_guests,json=nudgeGuests"`
+	xxx_hidden_Timezone               *string                `protobuf:"bytes,4,opt,name=timezone"`
+	xxx_hidden_QuietHoursEnabled      bool                   `protobuf:"varint,5,opt,name=quiet_hours_enabled,json=quietHoursEnabled"`
+	xxx_hidden_QuietHoursStart        uint32                 `protobuf:"varint,6,opt,name=quiet_hours_start,json=quietHoursStart"`
+	xxx_hidden_QuietHoursEnd          uint32                 `protobuf:"varint,7,opt,name=quiet_hours_end,json=quietHoursEnd"`
+	xxx_hidden_DigestFrequency        *string                `protobuf:"bytes,8,opt,name=digest_frequency,json=digestFrequency"`
+	XXX_raceDetectHookData            protoimpl.RaceDetectHookData
+	XXX_presence                      [1]uint32
+	unknownFields                     protoimpl.UnknownFields
+	sizeCache                         protoimpl.SizeCache
+}
+
+func (x *UserSettingsUpdateRequest) Reset() {
+	*x = UserSettingsUpdateRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSettingsUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSettingsUpdateRequest) ProtoMessage() {}
+
+func (x *UserSettingsUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserSettingsUpdateRequest) GetReminderThresholdHours() uint32 {
+	if x != nil {
+		return x.xxx_hidden_ReminderThresholdHours
+	}
+	return 0
+}
+
+func (x *UserSettingsUpdateRequest) GetEnableReminders() bool {
+	if x != nil {
+		return x.xxx_hidden_EnableReminders
+	}
+	return false
+}
+
+func (x *UserSettingsUpdateRequest) GetNudgeGuests() bool {
+	if x != nil {
+		return x.xxx_hidden_NudgeGuests
+	}
+	return false
+}
+
+func (x *UserSettingsUpdateRequest) GetTimezone() string {
+	if x != nil {
+		if x.xxx_hidden_Timezone != nil {
+			return *x.xxx_hidden_Timezone
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserSettingsUpdateRequest) GetQuietHoursEnabled() bool {
+	if x != nil {
+		return x.xxx_hidden_QuietHoursEnabled
+	}
+	return false
+}
+
+func (x *UserSettingsUpdateRequest) GetQuietHoursStart() uint32 {
+	if x != nil {
+		return x.xxx_hidden_QuietHoursStart
+	}
+	return 0
+}
+
+func (x *UserSettingsUpdateRequest) GetQuietHoursEnd() uint32 {
+	if x != nil {
+		return x.xxx_hidden_QuietHoursEnd
+	}
+	return 0
+}
+
+func (x *UserSettingsUpdateRequest) GetDigestFrequency() string {
+	if x != nil {
+		if x.xxx_hidden_DigestFrequency != nil {
+			return *x.xxx_hidden_DigestFrequency
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserSettingsUpdateRequest) SetReminderThresholdHours(v uint32) {
+	x.xxx_hidden_ReminderThresholdHours = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetEnableReminders(v bool) {
+	x.xxx_hidden_EnableReminders = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetNudgeGuests(v bool) {
+	x.xxx_hidden_NudgeGuests = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetTimezone(v string) {
+	x.xxx_hidden_Timezone = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetQuietHoursEnabled(v bool) {
+	x.xxx_hidden_QuietHoursEnabled = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetQuietHoursStart(v uint32) {
+	x.xxx_hidden_QuietHoursStart = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetQuietHoursEnd(v uint32) {
+	x.xxx_hidden_QuietHoursEnd = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 8)
+}
+
+func (x *UserSettingsUpdateRequest) SetDigestFrequency(v string) {
+	x.xxx_hidden_DigestFrequency = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 8)
+}
+
+func (x *UserSettingsUpdateRequest) HasReminderThresholdHours() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *UserSettingsUpdateRequest) HasEnableReminders() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UserSettingsUpdateRequest) HasNudgeGuests() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UserSettingsUpdateRequest) HasTimezone() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *UserSettingsUpdateRequest) HasQuietHoursEnabled() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *UserSettingsUpdateRequest) HasQuietHoursStart() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *UserSettingsUpdateRequest) HasQuietHoursEnd() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
+}
+
+func (x *UserSettingsUpdateRequest) HasDigestFrequency() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
+}
+
+func (x *UserSettingsUpdateRequest) ClearReminderThresholdHours() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_ReminderThresholdHours = 0
+}
+
+func (x *UserSettingsUpdateRequest) ClearEnableReminders() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_EnableReminders = false
+}
+
+func (x *UserSettingsUpdateRequest) ClearNudgeGuests() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_NudgeGuests = false
+}
+
+func (x *UserSettingsUpdateRequest) ClearTimezone() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Timezone = nil
+}
+
+func (x *UserSettingsUpdateRequest) ClearQuietHoursEnabled() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_QuietHoursEnabled = false
+}
+
+func (x *UserSettingsUpdateRequest) ClearQuietHoursStart() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_QuietHoursStart = 0
+}
+
+func (x *UserSettingsUpdateRequest) ClearQuietHoursEnd() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_QuietHoursEnd = 0
+}
+
+func (x *UserSettingsUpdateRequest) ClearDigestFrequency() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 7)
+	x.xxx_hidden_DigestFrequency = nil
+}
+
+type UserSettingsUpdateRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ReminderThresholdHours *uint32
+	EnableReminders        *bool
+	NudgeGuests            *bool
+	Timezone               *string
+	QuietHoursEnabled      *bool
+	QuietHoursStart        *uint32
+	QuietHoursEnd          *uint32
+	DigestFrequency        *string
+}
+
+func (b0 UserSettingsUpdateRequest_builder) Build() *UserSettingsUpdateRequest {
+	m0 := &UserSettingsUpdateRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.ReminderThresholdHours != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 8)
+		x.xxx_hidden_ReminderThresholdHours = *b.ReminderThresholdHours
+	}
+	if b.EnableReminders != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 8)
+		x.xxx_hidden_EnableReminders = *b.EnableReminders
+	}
+	if b.NudgeGuests != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 8)
+		x.xxx_hidden_NudgeGuests = *b.NudgeGuests
+	}
+	if b.Timezone != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 8)
+		x.xxx_hidden_Timezone = b.Timezone
+	}
+	if b.QuietHoursEnabled != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 8)
+		x.xxx_hidden_QuietHoursEnabled = *b.QuietHoursEnabled
+	}
+	if b.QuietHoursStart != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 8)
+		x.xxx_hidden_QuietHoursStart = *b.QuietHoursStart
+	}
+	if b.QuietHoursEnd != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 8)
+		x.xxx_hidden_QuietHoursEnd = *b.QuietHoursEnd
+	}
+	if b.DigestFrequency != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 8)
+		x.xxx_hidden_DigestFrequency = b.DigestFrequency
+	}
+	return m0
+}
+
+type UserSettingsUpdateResponse struct {
+	state               protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Settings *UserSettings          `protobuf:"bytes,1,opt,name=settings"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UserSettingsUpdateResponse) Reset() {
+	*x = UserSettingsUpdateResponse{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSettingsUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSettingsUpdateResponse) ProtoMessage() {}
+
+func (x *UserSettingsUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserSettingsUpdateResponse) GetSettings() *UserSettings {
+	if x != nil {
+		return x.xxx_hidden_Settings
+	}
+	return nil
+}
+
+func (x *UserSettingsUpdateResponse) SetSettings(v *UserSettings) {
+	x.xxx_hidden_Settings = v
+}
+
+func (x *UserSettingsUpdateResponse) HasSettings() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Settings != nil
+}
+
+func (x *UserSettingsUpdateResponse) ClearSettings() {
+	x.xxx_hidden_Settings = nil
+}
+
+type UserSettingsUpdateResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Settings *UserSettings
+}
+
+func (b0 UserSettingsUpdateResponse_builder) Build() *UserSettingsUpdateResponse {
+	m0 := &UserSettingsUpdateResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Settings = b.Settings
+	return m0
+}
+
+type UserCredentialsListRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserCredentialsListRequest) Reset() {
+	*x = UserCredentialsListRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserCredentialsListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCredentialsListRequest) ProtoMessage() {}
+
+func (x *UserCredentialsListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type UserCredentialsListRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 UserCredentialsListRequest_builder) Build() *UserCredentialsListRequest {
+	m0 := &UserCredentialsListRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type UserCredentialsListResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Credentials *[]*UserCredential     `protobuf:"bytes,1,rep,name=credentials"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *UserCredentialsListResponse) Reset() {
+	*x = UserCredentialsListResponse{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserCredentialsListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCredentialsListResponse) ProtoMessage() {}
+
+func (x *UserCredentialsListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserCredentialsListResponse) GetCredentials() []*UserCredential {
+	if x != nil {
+		if x.xxx_hidden_Credentials != nil {
+			return *x.xxx_hidden_Credentials
+		}
+	}
+	return nil
+}
+
+func (x *UserCredentialsListResponse) SetCredentials(v []*UserCredential) {
+	x.xxx_hidden_Credentials = &v
+}
+
+type UserCredentialsListResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Credentials []*UserCredential
+}
+
+func (b0 UserCredentialsListResponse_builder) Build() *UserCredentialsListResponse {
+	m0 := &UserCredentialsListResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Credentials = &b.Credentials
+	return m0
+}
+
+type UserCredentialDeleteRequest struct {
+	state            protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UserCredentialDeleteRequest) Reset() {
+	*x = UserCredentialDeleteRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserCredentialDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCredentialDeleteRequest) ProtoMessage() {}
+
+func (x *UserCredentialDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserCredentialDeleteRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *UserCredentialDeleteRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+type UserCredentialDeleteRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+}
+
+func (b0 UserCredentialDeleteRequest_builder) Build() *UserCredentialDeleteRequest {
+	m0 := &UserCredentialDeleteRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	return m0
+}
+
+type AccountExportRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountExportRequest) Reset() {
+	*x = AccountExportRequest{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountExportRequest) ProtoMessage() {}
+
+func (x *AccountExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type AccountExportRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 AccountExportRequest_builder) Build() *AccountExportRequest {
+	m0 := &AccountExportRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type AccountExportResponse struct {
+	state              protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId   string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Created *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *AccountExportResponse) Reset() {
+	*x = AccountExportResponse{}
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountExportResponse) ProtoMessage() {}
+
+func (x *AccountExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *AccountExportResponse) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *AccountExportResponse) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.xxx_hidden_Created
+	}
+	return nil
+}
+
+func (x *AccountExportResponse) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *AccountExportResponse) SetCreated(v *timestamppb.Timestamp) {
+	x.xxx_hidden_Created = v
+}
+
+func (x *AccountExportResponse) HasCreated() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Created != nil
+}
+
+func (x *AccountExportResponse) ClearCreated() {
+	x.xxx_hidden_Created = nil
+}
+
+type AccountExportResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// the export is built in the background. a download link is emailed
+	// to the user once it is ready.
+	RefId   string
+	Created *timestamppb.Timestamp
+}
+
+func (b0 AccountExportResponse_builder) Build() *AccountExportResponse {
+	m0 := &AccountExportResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Created = b.Created
+	return m0
+}
+
+var File_icbt_rpc_v1_user_proto protoreflect.FileDescriptor
+
+const file_icbt_rpc_v1_user_proto_rawDesc = "" +
+	"\n" +
+	"\x16icbt/rpc/v1/user.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1dicbt/rpc/v1/constraints.proto\"\xed\x01\n" +
+	"\x04User\x12\x15\n" +
+	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bverified\x18\x04 \x01(\bR\bverified\x12\x17\n" +
+	"\apw_auth\x18\x05 \x01(\bR\x06pwAuth\x12\x1a\n" +
+	"\bwebauthn\x18\x06 \x01(\bR\bwebauthn\x12\x1d\n" +
+	"\n" +
+	"api_access\x18\a \x01(\bR\tapiAccess\x124\n" +
+	"\acreated\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"\xe1\x02\n" +
+	"\fUserSettings\x128\n" +
+	"\x18reminder_threshold_hours\x18\x01 \x01(\rR\x16reminderThresholdHours\x12)\n" +
+	"\x10enable_reminders\x18\x02 \x01(\bR\x0fenableReminders\x12!\n" +
+	"\fnudge_guests\x18\x03 \x01(\bR\vnudgeGuests\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\x12.\n" +
+	"\x13quiet_hours_enabled\x18\x05 \x01(\bR\x11quietHoursEnabled\x12*\n" +
+	"\x11quiet_hours_start\x18\x06 \x01(\rR\x0fquietHoursStart\x12&\n" +
+	"\x0fquiet_hours_end\x18\a \x01(\rR\rquietHoursEnd\x12)\n" +
+	"\x10digest_frequency\x18\b \x01(\tR\x0fdigestFrequency\"x\n" +
+	"\x0eUserCredential\x12\x15\n" +
+	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x12\x19\n" +
+	"\bkey_name\x18\x02 \x01(\tR\akeyName\x124\n" +
+	"\acreated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"\x12\n" +
+	"\x10UserGetMeRequest\":\n" +
+	"\x11UserGetMeResponse\x12%\n" +
+	"\x04user\x18\x01 \x01(\v2\x11.icbt.rpc.v1.UserR\x04user\"\xad\x01\n" +
+	"\x11UserUpdateRequest\x12 \n" +
+	"\x04name\x18\x01 \x01(\tB\f\xbaH\x04r\x02\x10\x01\xaa\x01\x02\b\x01R\x04name\x12\"\n" +
+	"\x05email\x18\x02 \x01(\tB\f\xbaH\x04r\x02`\x01\xaa\x01\x02\b\x01R\x05email\x12(\n" +
+	"\bpassword\x18\x03 \x01(\tB\f\xbaH\x04r\x02\x10\x01\xaa\x01\x02\b\x01R\bpassword\x12(\n" +
+	"\fold_password\x18\x04 \x01(\tB\x05\xaa\x01\x02\b\x01R\voldPassword\";\n" +
+	"\x12UserUpdateResponse\x12%\n" +
+	"\x04user\x18\x01 \x01(\v2\x11.icbt.rpc.v1.UserR\x04user\"\x18\n" +
+	"\x16UserSettingsGetRequest\"P\n" +
+	"\x17UserSettingsGetResponse\x125\n" +
+	"\bsettings\x18\x01 \x01(\v2\x19.icbt.rpc.v1.UserSettingsR\bsettings\"\xbe\x03\n" +
+	"\x19UserSettingsUpdateRequest\x12I\n" +
+	"\x18reminder_threshold_hours\x18\x01 \x01(\rB\x0f\xbaH\a*\x05\x18\xa8\x01(\x02\xaa\x01\x02\b\x01R\x16reminderThresholdHours\x120\n" +
+	"\x10enable_reminders\x18\x02 \x01(\bB\x05\xaa\x01\x02\b\x01R\x0fenableReminders\x12(\n" +
+	"\fnudge_guests\x18\x03 \x01(\bB\x05\xaa\x01\x02\b\x01R\vnudgeGuests\x12!\n" +
+	"\btimezone\x18\x04 \x01(\tB\x05\xaa\x01\x02\b\x01R\btimezone\x125\n" +
+	"\x13quiet_hours_enabled\x18\x05 \x01(\bB\x05\xaa\x01\x02\b\x01R\x11quietHoursEnabled\x128\n" +
+	"\x11quiet_hours_start\x18\x06 \x01(\rB\f\xbaH\x04*\x02\x18\x17\xaa\x01\x02\b\x01R\x0fquietHoursStart\x124\n" +
+	"\x0fquiet_hours_end\x18\a \x01(\rB\f\xbaH\x04*\x02\x18\x17\xaa\x01\x02\b\x01R\rquietHoursEnd\x120\n" +
+	"\x10digest_frequency\x18\b \x01(\tB\x05\xaa\x01\x02\b\x01R\x0fdigestFrequency\"S\n" +
+	"\x1aUserSettingsUpdateResponse\x125\n" +
+	"\bsettings\x18\x01 \x01(\v2\x19.icbt.rpc.v1.UserSettingsR\bsettings\"\x1c\n" +
+	"\x1aUserCredentialsListRequest\"\\\n" +
+	"\x1bUserCredentialsListResponse\x12=\n" +
+	"\vcredentials\x18\x01 \x03(\v2\x1b.icbt.rpc.v1.UserCredentialR\vcredentials\"A\n" +
+	"\x1bUserCredentialDeleteRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\x16\n" +
+	"\x14AccountExportRequest\"d\n" +
+	"\x15AccountExportResponse\x12\x15\n" +
+	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x124\n" +
+	"\acreated\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\acreatedB\xae\x01\n" +
+	"\x0fcom.icbt.rpc.v1B\tUserProtoP\x01Z8github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1;rpcv1\xa2\x02\x03IRX\xaa\x02\vIcbt.Rpc.V1\xca\x02\vIcbt\\Rpc\\V1\xe2\x02\x17Icbt\\Rpc\\V1\\GPBMetadata\xea\x02\rIcbt::Rpc::V1\x92\x03\a\xd2>\x02\x10\x03\b\x02b\beditionsp\xe8\a"
+
+var file_icbt_rpc_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_icbt_rpc_v1_user_proto_goTypes = []any{
+	(*User)(nil),                        // 0: icbt.rpc.v1.User
+	(*UserSettings)(nil),                // 1: icbt.rpc.v1.UserSettings
+	(*UserCredential)(nil),              // 2: icbt.rpc.v1.UserCredential
+	(*UserGetMeRequest)(nil),            // 3: icbt.rpc.v1.UserGetMeRequest
+	(*UserGetMeResponse)(nil),           // 4: icbt.rpc.v1.UserGetMeResponse
+	(*UserUpdateRequest)(nil),           // 5: icbt.rpc.v1.UserUpdateRequest
+	(*UserUpdateResponse)(nil),          // 6: icbt.rpc.v1.UserUpdateResponse
+	(*UserSettingsGetRequest)(nil),      // 7: icbt.rpc.v1.UserSettingsGetRequest
+	(*UserSettingsGetResponse)(nil),     // 8: icbt.rpc.v1.UserSettingsGetResponse
+	(*UserSettingsUpdateRequest)(nil),   // 9: icbt.rpc.v1.UserSettingsUpdateRequest
+	(*UserSettingsUpdateResponse)(nil),  // 10: icbt.rpc.v1.UserSettingsUpdateResponse
+	(*UserCredentialsListRequest)(nil),  // 11: icbt.rpc.v1.UserCredentialsListRequest
+	(*UserCredentialsListResponse)(nil), // 12: icbt.rpc.v1.UserCredentialsListResponse
+	(*UserCredentialDeleteRequest)(nil), // 13: icbt.rpc.v1.UserCredentialDeleteRequest
+	(*AccountExportRequest)(nil),        // 14: icbt.rpc.v1.AccountExportRequest
+	(*AccountExportResponse)(nil),       // 15: icbt.rpc.v1.AccountExportResponse
+	(*timestamppb.Timestamp)(nil),       // 16: google.protobuf.Timestamp
+}
+var file_icbt_rpc_v1_user_proto_depIdxs = []int32{
+	16, // 0: icbt.rpc.v1.User.created:type_name -> google.protobuf.Timestamp
+	16, // 1: icbt.rpc.v1.UserCredential.created:type_name -> google.protobuf.Timestamp
+	0,  // 2: icbt.rpc.v1.UserGetMeResponse.user:type_name -> icbt.rpc.v1.User
+	0,  // 3: icbt.rpc.v1.UserUpdateResponse.user:type_name -> icbt.rpc.v1.User
+	1,  // 4: icbt.rpc.v1.UserSettingsGetResponse.settings:type_name -> icbt.rpc.v1.UserSettings
+	1,  // 5: icbt.rpc.v1.UserSettingsUpdateResponse.settings:type_name -> icbt.rpc.v1.UserSettings
+	2,  // 6: icbt.rpc.v1.UserCredentialsListResponse.credentials:type_name -> icbt.rpc.v1.UserCredential
+	16, // 7: icbt.rpc.v1.AccountExportResponse.created:type_name -> google.protobuf.Timestamp
+	8,  // [8:8] is the sub-list for method output_type
+	8,  // [8:8] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_icbt_rpc_v1_user_proto_init() }
+func file_icbt_rpc_v1_user_proto_init() {
+	if File_icbt_rpc_v1_user_proto != nil {
+		return
+	}
+	file_icbt_rpc_v1_constraints_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_icbt_rpc_v1_user_proto_rawDesc), len(file_icbt_rpc_v1_user_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_icbt_rpc_v1_user_proto_goTypes,
+		DependencyIndexes: file_icbt_rpc_v1_user_proto_depIdxs,
+		MessageInfos:      file_icbt_rpc_v1_user_proto_msgTypes,
+	}.Build()
+	File_icbt_rpc_v1_user_proto = out.File
+	file_icbt_rpc_v1_user_proto_goTypes = nil
+	file_icbt_rpc_v1_user_proto_depIdxs = nil
+}
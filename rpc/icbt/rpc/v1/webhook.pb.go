@@ -0,0 +1,1059 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: icbt/rpc/v1/webhook.proto
+
+package rpcv1
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	_ "google.golang.org/protobuf/types/gofeaturespb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Webhook struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId      string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Url        string                 `protobuf:"bytes,2,opt,name=url"`
+	xxx_hidden_EventTypes []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes"`
+	xxx_hidden_Enabled    bool                   `protobuf:"varint,4,opt,name=enabled"`
+	xxx_hidden_Created    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *Webhook) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.xxx_hidden_Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []string {
+	if x != nil {
+		return x.xxx_hidden_EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetEnabled() bool {
+	if x != nil {
+		return x.xxx_hidden_Enabled
+	}
+	return false
+}
+
+func (x *Webhook) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.xxx_hidden_Created
+	}
+	return nil
+}
+
+func (x *Webhook) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *Webhook) SetUrl(v string) {
+	x.xxx_hidden_Url = v
+}
+
+func (x *Webhook) SetEventTypes(v []string) {
+	x.xxx_hidden_EventTypes = v
+}
+
+func (x *Webhook) SetEnabled(v bool) {
+	x.xxx_hidden_Enabled = v
+}
+
+func (x *Webhook) SetCreated(v *timestamppb.Timestamp) {
+	x.xxx_hidden_Created = v
+}
+
+func (x *Webhook) HasCreated() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Created != nil
+}
+
+func (x *Webhook) ClearCreated() {
+	x.xxx_hidden_Created = nil
+}
+
+type Webhook_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+	Url   string
+	// an empty list means all change types are sent
+	EventTypes []string
+	Enabled    bool
+	Created    *timestamppb.Timestamp
+}
+
+func (b0 Webhook_builder) Build() *Webhook {
+	m0 := &Webhook{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Url = b.Url
+	x.xxx_hidden_EventTypes = b.EventTypes
+	x.xxx_hidden_Enabled = b.Enabled
+	x.xxx_hidden_Created = b.Created
+	return m0
+}
+
+type WebhookAttempt struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_EventType  string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType"`
+	xxx_hidden_StatusCode uint32                 `protobuf:"varint,2,opt,name=status_code,json=statusCode"`
+	xxx_hidden_Error      string                 `protobuf:"bytes,3,opt,name=error"`
+	xxx_hidden_Created    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *WebhookAttempt) Reset() {
+	*x = WebhookAttempt{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookAttempt) ProtoMessage() {}
+
+func (x *WebhookAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookAttempt) GetEventType() string {
+	if x != nil {
+		return x.xxx_hidden_EventType
+	}
+	return ""
+}
+
+func (x *WebhookAttempt) GetStatusCode() uint32 {
+	if x != nil {
+		return x.xxx_hidden_StatusCode
+	}
+	return 0
+}
+
+func (x *WebhookAttempt) GetError() string {
+	if x != nil {
+		return x.xxx_hidden_Error
+	}
+	return ""
+}
+
+func (x *WebhookAttempt) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.xxx_hidden_Created
+	}
+	return nil
+}
+
+func (x *WebhookAttempt) SetEventType(v string) {
+	x.xxx_hidden_EventType = v
+}
+
+func (x *WebhookAttempt) SetStatusCode(v uint32) {
+	x.xxx_hidden_StatusCode = v
+}
+
+func (x *WebhookAttempt) SetError(v string) {
+	x.xxx_hidden_Error = v
+}
+
+func (x *WebhookAttempt) SetCreated(v *timestamppb.Timestamp) {
+	x.xxx_hidden_Created = v
+}
+
+func (x *WebhookAttempt) HasCreated() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Created != nil
+}
+
+func (x *WebhookAttempt) ClearCreated() {
+	x.xxx_hidden_Created = nil
+}
+
+type WebhookAttempt_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	EventType string
+	// zero if no response was received
+	StatusCode uint32
+	Error      string
+	Created    *timestamppb.Timestamp
+}
+
+func (b0 WebhookAttempt_builder) Build() *WebhookAttempt {
+	m0 := &WebhookAttempt{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_EventType = b.EventType
+	x.xxx_hidden_StatusCode = b.StatusCode
+	x.xxx_hidden_Error = b.Error
+	x.xxx_hidden_Created = b.Created
+	return m0
+}
+
+type WebhookEventTypeList struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_EventTypes []string               `protobuf:"bytes,1,rep,name=event_types,json=eventTypes"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *WebhookEventTypeList) Reset() {
+	*x = WebhookEventTypeList{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookEventTypeList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookEventTypeList) ProtoMessage() {}
+
+func (x *WebhookEventTypeList) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookEventTypeList) GetEventTypes() []string {
+	if x != nil {
+		return x.xxx_hidden_EventTypes
+	}
+	return nil
+}
+
+func (x *WebhookEventTypeList) SetEventTypes(v []string) {
+	x.xxx_hidden_EventTypes = v
+}
+
+type WebhookEventTypeList_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	EventTypes []string
+}
+
+func (b0 WebhookEventTypeList_builder) Build() *WebhookEventTypeList {
+	m0 := &WebhookEventTypeList{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_EventTypes = b.EventTypes
+	return m0
+}
+
+type WebhookCreateRequest struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Url        string                 `protobuf:"bytes,1,opt,name=url"`
+	xxx_hidden_Secret     string                 `protobuf:"bytes,2,opt,name=secret"`
+	xxx_hidden_EventTypes []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *WebhookCreateRequest) Reset() {
+	*x = WebhookCreateRequest{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookCreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookCreateRequest) ProtoMessage() {}
+
+func (x *WebhookCreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookCreateRequest) GetUrl() string {
+	if x != nil {
+		return x.xxx_hidden_Url
+	}
+	return ""
+}
+
+func (x *WebhookCreateRequest) GetSecret() string {
+	if x != nil {
+		return x.xxx_hidden_Secret
+	}
+	return ""
+}
+
+func (x *WebhookCreateRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.xxx_hidden_EventTypes
+	}
+	return nil
+}
+
+func (x *WebhookCreateRequest) SetUrl(v string) {
+	x.xxx_hidden_Url = v
+}
+
+func (x *WebhookCreateRequest) SetSecret(v string) {
+	x.xxx_hidden_Secret = v
+}
+
+func (x *WebhookCreateRequest) SetEventTypes(v []string) {
+	x.xxx_hidden_EventTypes = v
+}
+
+type WebhookCreateRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Url string
+	// if empty, a secret is generated
+	Secret     string
+	EventTypes []string
+}
+
+func (b0 WebhookCreateRequest_builder) Build() *WebhookCreateRequest {
+	m0 := &WebhookCreateRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Url = b.Url
+	x.xxx_hidden_Secret = b.Secret
+	x.xxx_hidden_EventTypes = b.EventTypes
+	return m0
+}
+
+type WebhookCreateResponse struct {
+	state              protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Webhook *Webhook               `protobuf:"bytes,1,opt,name=webhook"`
+	xxx_hidden_Secret  string                 `protobuf:"bytes,2,opt,name=secret"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WebhookCreateResponse) Reset() {
+	*x = WebhookCreateResponse{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookCreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookCreateResponse) ProtoMessage() {}
+
+func (x *WebhookCreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookCreateResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.xxx_hidden_Webhook
+	}
+	return nil
+}
+
+func (x *WebhookCreateResponse) GetSecret() string {
+	if x != nil {
+		return x.xxx_hidden_Secret
+	}
+	return ""
+}
+
+func (x *WebhookCreateResponse) SetWebhook(v *Webhook) {
+	x.xxx_hidden_Webhook = v
+}
+
+func (x *WebhookCreateResponse) SetSecret(v string) {
+	x.xxx_hidden_Secret = v
+}
+
+func (x *WebhookCreateResponse) HasWebhook() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Webhook != nil
+}
+
+func (x *WebhookCreateResponse) ClearWebhook() {
+	x.xxx_hidden_Webhook = nil
+}
+
+type WebhookCreateResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Webhook *Webhook
+	// the signing secret is only returned on creation
+	Secret string
+}
+
+func (b0 WebhookCreateResponse_builder) Build() *WebhookCreateResponse {
+	m0 := &WebhookCreateResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Webhook = b.Webhook
+	x.xxx_hidden_Secret = b.Secret
+	return m0
+}
+
+type WebhookUpdateRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId       string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Url         *string                `protobuf:"bytes,2,opt,name=url"`
+	xxx_hidden_Secret      *string                `protobuf:"bytes,3,opt,name=secret"`
+	xxx_hidden_EventTypes  *WebhookEventTypeList  `protobuf:"bytes,4,opt,name=event_types,json=eventTypes"`
+	xxx_hidden_Enabled     bool                   `protobuf:"varint,5,opt,name=enabled"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *WebhookUpdateRequest) Reset() {
+	*x = WebhookUpdateRequest{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookUpdateRequest) ProtoMessage() {}
+
+func (x *WebhookUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookUpdateRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *WebhookUpdateRequest) GetUrl() string {
+	if x != nil {
+		if x.xxx_hidden_Url != nil {
+			return *x.xxx_hidden_Url
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *WebhookUpdateRequest) GetSecret() string {
+	if x != nil {
+		if x.xxx_hidden_Secret != nil {
+			return *x.xxx_hidden_Secret
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *WebhookUpdateRequest) GetEventTypes() *WebhookEventTypeList {
+	if x != nil {
+		return x.xxx_hidden_EventTypes
+	}
+	return nil
+}
+
+func (x *WebhookUpdateRequest) GetEnabled() bool {
+	if x != nil {
+		return x.xxx_hidden_Enabled
+	}
+	return false
+}
+
+func (x *WebhookUpdateRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *WebhookUpdateRequest) SetUrl(v string) {
+	x.xxx_hidden_Url = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+}
+
+func (x *WebhookUpdateRequest) SetSecret(v string) {
+	x.xxx_hidden_Secret = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+}
+
+func (x *WebhookUpdateRequest) SetEventTypes(v *WebhookEventTypeList) {
+	x.xxx_hidden_EventTypes = v
+}
+
+func (x *WebhookUpdateRequest) SetEnabled(v bool) {
+	x.xxx_hidden_Enabled = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 5)
+}
+
+func (x *WebhookUpdateRequest) HasUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *WebhookUpdateRequest) HasSecret() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *WebhookUpdateRequest) HasEventTypes() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_EventTypes != nil
+}
+
+func (x *WebhookUpdateRequest) HasEnabled() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *WebhookUpdateRequest) ClearUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Url = nil
+}
+
+func (x *WebhookUpdateRequest) ClearSecret() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Secret = nil
+}
+
+func (x *WebhookUpdateRequest) ClearEventTypes() {
+	x.xxx_hidden_EventTypes = nil
+}
+
+func (x *WebhookUpdateRequest) ClearEnabled() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Enabled = false
+}
+
+type WebhookUpdateRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId      string
+	Url        *string
+	Secret     *string
+	EventTypes *WebhookEventTypeList
+	Enabled    *bool
+}
+
+func (b0 WebhookUpdateRequest_builder) Build() *WebhookUpdateRequest {
+	m0 := &WebhookUpdateRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	if b.Url != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
+		x.xxx_hidden_Url = b.Url
+	}
+	if b.Secret != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		x.xxx_hidden_Secret = b.Secret
+	}
+	x.xxx_hidden_EventTypes = b.EventTypes
+	if b.Enabled != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 5)
+		x.xxx_hidden_Enabled = *b.Enabled
+	}
+	return m0
+}
+
+type WebhookDeleteRequest struct {
+	state            protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WebhookDeleteRequest) Reset() {
+	*x = WebhookDeleteRequest{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeleteRequest) ProtoMessage() {}
+
+func (x *WebhookDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookDeleteRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *WebhookDeleteRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+type WebhookDeleteRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+}
+
+func (b0 WebhookDeleteRequest_builder) Build() *WebhookDeleteRequest {
+	m0 := &WebhookDeleteRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	return m0
+}
+
+type WebhooksListRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhooksListRequest) Reset() {
+	*x = WebhooksListRequest{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhooksListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhooksListRequest) ProtoMessage() {}
+
+func (x *WebhooksListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type WebhooksListRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 WebhooksListRequest_builder) Build() *WebhooksListRequest {
+	m0 := &WebhooksListRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type WebhooksListResponse struct {
+	state               protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Webhooks *[]*Webhook            `protobuf:"bytes,1,rep,name=webhooks"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *WebhooksListResponse) Reset() {
+	*x = WebhooksListResponse{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhooksListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhooksListResponse) ProtoMessage() {}
+
+func (x *WebhooksListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhooksListResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		if x.xxx_hidden_Webhooks != nil {
+			return *x.xxx_hidden_Webhooks
+		}
+	}
+	return nil
+}
+
+func (x *WebhooksListResponse) SetWebhooks(v []*Webhook) {
+	x.xxx_hidden_Webhooks = &v
+}
+
+type WebhooksListResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Webhooks []*Webhook
+}
+
+func (b0 WebhooksListResponse_builder) Build() *WebhooksListResponse {
+	m0 := &WebhooksListResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Webhooks = &b.Webhooks
+	return m0
+}
+
+type WebhookListAttemptsRequest struct {
+	state            protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefId string                 `protobuf:"bytes,1,opt,name=ref_id,json=refId"`
+	xxx_hidden_Limit uint32                 `protobuf:"varint,2,opt,name=limit"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WebhookListAttemptsRequest) Reset() {
+	*x = WebhookListAttemptsRequest{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookListAttemptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookListAttemptsRequest) ProtoMessage() {}
+
+func (x *WebhookListAttemptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookListAttemptsRequest) GetRefId() string {
+	if x != nil {
+		return x.xxx_hidden_RefId
+	}
+	return ""
+}
+
+func (x *WebhookListAttemptsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.xxx_hidden_Limit
+	}
+	return 0
+}
+
+func (x *WebhookListAttemptsRequest) SetRefId(v string) {
+	x.xxx_hidden_RefId = v
+}
+
+func (x *WebhookListAttemptsRequest) SetLimit(v uint32) {
+	x.xxx_hidden_Limit = v
+}
+
+type WebhookListAttemptsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefId string
+	Limit uint32
+}
+
+func (b0 WebhookListAttemptsRequest_builder) Build() *WebhookListAttemptsRequest {
+	m0 := &WebhookListAttemptsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_RefId = b.RefId
+	x.xxx_hidden_Limit = b.Limit
+	return m0
+}
+
+type WebhookListAttemptsResponse struct {
+	state               protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Attempts *[]*WebhookAttempt     `protobuf:"bytes,1,rep,name=attempts"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *WebhookListAttemptsResponse) Reset() {
+	*x = WebhookListAttemptsResponse{}
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookListAttemptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookListAttemptsResponse) ProtoMessage() {}
+
+func (x *WebhookListAttemptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icbt_rpc_v1_webhook_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WebhookListAttemptsResponse) GetAttempts() []*WebhookAttempt {
+	if x != nil {
+		if x.xxx_hidden_Attempts != nil {
+			return *x.xxx_hidden_Attempts
+		}
+	}
+	return nil
+}
+
+func (x *WebhookListAttemptsResponse) SetAttempts(v []*WebhookAttempt) {
+	x.xxx_hidden_Attempts = &v
+}
+
+type WebhookListAttemptsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Attempts []*WebhookAttempt
+}
+
+func (b0 WebhookListAttemptsResponse_builder) Build() *WebhookListAttemptsResponse {
+	m0 := &WebhookListAttemptsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Attempts = &b.Attempts
+	return m0
+}
+
+var File_icbt_rpc_v1_webhook_proto protoreflect.FileDescriptor
+
+const file_icbt_rpc_v1_webhook_proto_rawDesc = "" +
+	"\n" +
+	"\x19icbt/rpc/v1/webhook.proto\x12\vicbt.rpc.v1\x1a\x1bbuf/validate/validate.proto\x1a!google/protobuf/go_features.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1dicbt/rpc/v1/constraints.proto\"\xa3\x01\n" +
+	"\aWebhook\x12\x15\n" +
+	"\x06ref_id\x18\x01 \x01(\tR\x05refId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x124\n" +
+	"\acreated\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"\x9c\x01\n" +
+	"\x0eWebhookAttempt\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12\x1f\n" +
+	"\vstatus_code\x18\x02 \x01(\rR\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x124\n" +
+	"\acreated\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\"7\n" +
+	"\x14WebhookEventTypeList\x12\x1f\n" +
+	"\vevent_types\x18\x01 \x03(\tR\n" +
+	"eventTypes\"w\n" +
+	"\x14WebhookCreateRequest\x12\x1c\n" +
+	"\x03url\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\x10R\x03url\x12 \n" +
+	"\x06secret\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x01R\x06secret\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\"_\n" +
+	"\x15WebhookCreateResponse\x12.\n" +
+	"\awebhook\x18\x01 \x01(\v2\x14.icbt.rpc.v1.WebhookR\awebhook\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\xde\x01\n" +
+	"\x14WebhookUpdateRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\x12\x17\n" +
+	"\x03url\x18\x02 \x01(\tB\x05\xaa\x01\x02\b\x01R\x03url\x12\x1d\n" +
+	"\x06secret\x18\x03 \x01(\tB\x05\xaa\x01\x02\b\x01R\x06secret\x12I\n" +
+	"\vevent_types\x18\x04 \x01(\v2!.icbt.rpc.v1.WebhookEventTypeListB\x05\xaa\x01\x02\b\x01R\n" +
+	"eventTypes\x12\x1f\n" +
+	"\aenabled\x18\x05 \x01(\bB\x05\xaa\x01\x02\b\x01R\aenabled\":\n" +
+	"\x14WebhookDeleteRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\"\x15\n" +
+	"\x13WebhooksListRequest\"H\n" +
+	"\x14WebhooksListResponse\x120\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x14.icbt.rpc.v1.WebhookR\bwebhooks\"_\n" +
+	"\x1aWebhookListAttemptsRequest\x12\"\n" +
+	"\x06ref_id\x18\x01 \x01(\tB\v\xbaH\br\x06\x88\u0603\x8b\x02\x01R\x05refId\x12\x1d\n" +
+	"\x05limit\x18\x02 \x01(\rB\a\xbaH\x04*\x02\x18dR\x05limit\"V\n" +
+	"\x1bWebhookListAttemptsResponse\x127\n" +
+	"\battempts\x18\x01 \x03(\v2\x1b.icbt.rpc.v1.WebhookAttemptR\battemptsB\xb1\x01\n" +
+	"\x0fcom.icbt.rpc.v1B\fWebhookProtoP\x01Z8github.com/dropwhile/icanbringthat/rpc/icbt/rpc/v1;rpcv1\xa2\x02\x03IRX\xaa\x02\vIcbt.Rpc.V1\xca\x02\vIcbt\\Rpc\\V1\xe2\x02\x17Icbt\\Rpc\\V1\\GPBMetadata\xea\x02\rIcbt::Rpc::V1\x92\x03\a\xd2>\x02\x10\x03\b\x02b\beditionsp\xe8\a"
+
+var file_icbt_rpc_v1_webhook_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_icbt_rpc_v1_webhook_proto_goTypes = []any{
+	(*Webhook)(nil),                     // 0: icbt.rpc.v1.Webhook
+	(*WebhookAttempt)(nil),              // 1: icbt.rpc.v1.WebhookAttempt
+	(*WebhookEventTypeList)(nil),        // 2: icbt.rpc.v1.WebhookEventTypeList
+	(*WebhookCreateRequest)(nil),        // 3: icbt.rpc.v1.WebhookCreateRequest
+	(*WebhookCreateResponse)(nil),       // 4: icbt.rpc.v1.WebhookCreateResponse
+	(*WebhookUpdateRequest)(nil),        // 5: icbt.rpc.v1.WebhookUpdateRequest
+	(*WebhookDeleteRequest)(nil),        // 6: icbt.rpc.v1.WebhookDeleteRequest
+	(*WebhooksListRequest)(nil),         // 7: icbt.rpc.v1.WebhooksListRequest
+	(*WebhooksListResponse)(nil),        // 8: icbt.rpc.v1.WebhooksListResponse
+	(*WebhookListAttemptsRequest)(nil),  // 9: icbt.rpc.v1.WebhookListAttemptsRequest
+	(*WebhookListAttemptsResponse)(nil), // 10: icbt.rpc.v1.WebhookListAttemptsResponse
+	(*timestamppb.Timestamp)(nil),       // 11: google.protobuf.Timestamp
+}
+var file_icbt_rpc_v1_webhook_proto_depIdxs = []int32{
+	11, // 0: icbt.rpc.v1.Webhook.created:type_name -> google.protobuf.Timestamp
+	11, // 1: icbt.rpc.v1.WebhookAttempt.created:type_name -> google.protobuf.Timestamp
+	0,  // 2: icbt.rpc.v1.WebhookCreateResponse.webhook:type_name -> icbt.rpc.v1.Webhook
+	2,  // 3: icbt.rpc.v1.WebhookUpdateRequest.event_types:type_name -> icbt.rpc.v1.WebhookEventTypeList
+	0,  // 4: icbt.rpc.v1.WebhooksListResponse.webhooks:type_name -> icbt.rpc.v1.Webhook
+	1,  // 5: icbt.rpc.v1.WebhookListAttemptsResponse.attempts:type_name -> icbt.rpc.v1.WebhookAttempt
+	6,  // [6:6] is the sub-list for method output_type
+	6,  // [6:6] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_icbt_rpc_v1_webhook_proto_init() }
+func file_icbt_rpc_v1_webhook_proto_init() {
+	if File_icbt_rpc_v1_webhook_proto != nil {
+		return
+	}
+	file_icbt_rpc_v1_constraints_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_icbt_rpc_v1_webhook_proto_rawDesc), len(file_icbt_rpc_v1_webhook_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_icbt_rpc_v1_webhook_proto_goTypes,
+		DependencyIndexes: file_icbt_rpc_v1_webhook_proto_depIdxs,
+		MessageInfos:      file_icbt_rpc_v1_webhook_proto_msgTypes,
+	}.Build()
+	File_icbt_rpc_v1_webhook_proto = out.File
+	file_icbt_rpc_v1_webhook_proto_goTypes = nil
+	file_icbt_rpc_v1_webhook_proto_depIdxs = nil
+}